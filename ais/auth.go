@@ -17,6 +17,8 @@
 package ais
 
 import (
+	"crypto/hmac"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -26,10 +28,12 @@ import (
 )
 
 type (
-	// TokenList is a list of tokens pushed by authn
+	// TokenList is a list of tokens pushed by authn: revoked tokens and
+	// S3 access keys (every key comes with its own token)
 	TokenList struct {
-		Tokens  []string `json:"tokens"`
-		Version int64    `json:"version,string"`
+		Tokens     []string             `json:"tokens"`
+		AccessKeys []*cmn.AuthAccessKey `json:"access_keys,omitempty"`
+		Version    int64                `json:"version,string"`
 	}
 
 	authList map[string]*cmn.AuthToken
@@ -41,13 +45,17 @@ type (
 		// list of invalid tokens(revoked or of deleted users)
 		// Authn sends these tokens to primary for broadcasting
 		revokedTokens map[string]bool
-		version       int64
+		// S3 access keys: access key ID => key
+		accessKeys map[string]*cmn.AuthAccessKey
+		version    int64
 	}
 )
 
 var (
 	// interface guard
 	_ revs = &TokenList{}
+
+	errAccessKeySignature = errors.New("access key is not signed by AuthN")
 )
 
 // Decrypts JWT token and returns all encrypted information.
@@ -81,6 +89,21 @@ func (a *authManager) updateRevokedList(tokens *TokenList) {
 		a.revokedTokens[token] = true
 		delete(a.tokens, token)
 	}
+	for _, key := range tokens.AccessKeys {
+		if err := a.checkAccessKey(key); err != nil {
+			glog.Errorf("access key %s rejected: %v", key.ID, err)
+			continue
+		}
+		if !a.revokedTokens[key.Token] {
+			a.accessKeys[key.ID] = key
+		}
+	}
+	// clean up the list of access keys from revoked and expired ones
+	for id, key := range a.accessKeys {
+		if a.revokedTokens[key.Token] || key.Expires.Before(time.Now()) {
+			delete(a.accessKeys, id)
+		}
+	}
 	// clean up the list from obsolete data
 	for token := range a.revokedTokens {
 		rec, err := a.extractTokenData(token)
@@ -139,6 +162,47 @@ func (a *authManager) extractTokenData(token string) (*cmn.AuthToken, error) {
 	return auth, nil
 }
 
+// Checks that every access key is signed by AuthN and none of them replaces
+// a registered key. Pushing the same key again is allowed
+func (a *authManager) checkAccessKeys(keys []*cmn.AuthAccessKey) error {
+	a.Lock()
+	defer a.Unlock()
+	for _, key := range keys {
+		if err := a.checkAccessKey(key); err != nil {
+			return fmt.Errorf("access key %s: %w", key.ID, err)
+		}
+	}
+	return nil
+}
+
+// Must be called under lock
+func (a *authManager) checkAccessKey(key *cmn.AuthAccessKey) error {
+	sig := cmn.AccessKeySignature(cmn.GCO.Get().Auth.Secret, key)
+	if key.Signature == "" || !hmac.Equal([]byte(sig), []byte(key.Signature)) {
+		return errAccessKeySignature
+	}
+	old, ok := a.accessKeys[key.ID]
+	if !ok {
+		return nil
+	}
+	if old.Secret != key.Secret || old.UserID != key.UserID || old.Token != key.Token ||
+		!old.Expires.Equal(key.Expires) {
+		return errors.New("a different key with the same ID is already registered")
+	}
+	return nil
+}
+
+// Returns the token of S3 access key and its secret to validate signature
+func (a *authManager) accessKey(keyID string) (*cmn.AuthAccessKey, error) {
+	a.Lock()
+	key, ok := a.accessKeys[keyID]
+	a.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("access key %q not found", keyID)
+	}
+	return key, nil
+}
+
 func (a *authManager) revokedTokenList() *TokenList {
 	a.Lock()
 	tlist := &TokenList{
@@ -151,6 +215,12 @@ func (a *authManager) revokedTokenList() *TokenList {
 		tlist.Tokens[idx] = token
 		idx++
 	}
+	if len(a.accessKeys) != 0 {
+		tlist.AccessKeys = make([]*cmn.AuthAccessKey, 0, len(a.accessKeys))
+		for _, key := range a.accessKeys {
+			tlist.AccessKeys = append(tlist.AccessKeys, key)
+		}
+	}
 
 	a.Unlock()
 	return tlist
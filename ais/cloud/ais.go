@@ -302,13 +302,14 @@ func (m *AisCloudProvider) remoteCluster(uuid string) (*remAisClust, error) {
 
 func (m *AisCloudProvider) ListObjects(ctx context.Context, remoteBck *cluster.Bck,
 	msg *cmn.SelectMsg) (bckList *cmn.BucketList, err error, errCode int) {
-	cmn.Assert(remoteBck.Provider == cmn.ProviderAIS)
+	cloudBck := remoteBck.CloudBck()
+	cmn.Assert(cloudBck.Provider == cmn.ProviderAIS)
 
-	aisCluster, err := m.remoteCluster(remoteBck.Ns.UUID)
+	aisCluster, err := m.remoteCluster(cloudBck.Ns.UUID)
 	if err != nil {
 		return nil, err, errCode
 	}
-	err = m.try(remoteBck.Bck, func(bck cmn.Bck) error {
+	err = m.try(cloudBck, func(bck cmn.Bck) error {
 		bckList, err = api.ListObjects(aisCluster.bp, bck, msg, 0)
 		return err
 	})
@@ -317,13 +318,14 @@ func (m *AisCloudProvider) ListObjects(ctx context.Context, remoteBck *cluster.B
 }
 
 func (m *AisCloudProvider) HeadBucket(ctx context.Context, remoteBck *cluster.Bck) (bckProps cmn.SimpleKVs, err error, errCode int) {
-	cmn.Assert(remoteBck.Provider == cmn.ProviderAIS)
+	cloudBck := remoteBck.CloudBck()
+	cmn.Assert(cloudBck.Provider == cmn.ProviderAIS)
 
-	aisCluster, err := m.remoteCluster(remoteBck.Ns.UUID)
+	aisCluster, err := m.remoteCluster(cloudBck.Ns.UUID)
 	if err != nil {
 		return nil, err, errCode
 	}
-	err = m.try(remoteBck.Bck, func(bck cmn.Bck) error {
+	err = m.try(cloudBck, func(bck cmn.Bck) error {
 		p, err := api.HeadBucket(aisCluster.bp, bck)
 		if err != nil {
 			return err
@@ -377,7 +379,7 @@ func (m *AisCloudProvider) ListBuckets(ctx context.Context, query cmn.QueryBcks)
 
 func (m *AisCloudProvider) HeadObj(ctx context.Context, lom *cluster.LOM) (objMeta cmn.SimpleKVs, err error, errCode int) {
 	var (
-		remoteBck = lom.Bck().Bck
+		remoteBck = lom.Bck().CloudBck()
 	)
 	aisCluster, err := m.remoteCluster(remoteBck.Ns.UUID)
 	if err != nil {
@@ -397,7 +399,7 @@ func (m *AisCloudProvider) HeadObj(ctx context.Context, lom *cluster.LOM) (objMe
 
 func (m *AisCloudProvider) GetObj(ctx context.Context, workFQN string, lom *cluster.LOM) (err error, errCode int) {
 	var (
-		remoteBck = lom.Bck().Bck
+		remoteBck = lom.Bck().CloudBck()
 	)
 	aisCluster, err := m.remoteCluster(remoteBck.Ns.UUID)
 	if err != nil {
@@ -438,7 +440,7 @@ func (m *AisCloudProvider) GetObj(ctx context.Context, workFQN string, lom *clus
 
 func (m *AisCloudProvider) PutObj(ctx context.Context, r io.Reader, lom *cluster.LOM) (version string, err error, errCode int) {
 	var (
-		remoteBck = lom.Bck().Bck
+		remoteBck = lom.Bck().CloudBck()
 	)
 
 	aisCluster, err := m.remoteCluster(remoteBck.Ns.UUID)
@@ -464,7 +466,7 @@ func (m *AisCloudProvider) PutObj(ctx context.Context, r io.Reader, lom *cluster
 
 func (m *AisCloudProvider) DeleteObj(ctx context.Context, lom *cluster.LOM) (err error, errCode int) {
 	var (
-		remoteBck = lom.Bck().Bck
+		remoteBck = lom.Bck().CloudBck()
 	)
 	aisCluster, err := m.remoteCluster(remoteBck.Ns.UUID)
 	if err != nil {
@@ -476,6 +478,40 @@ func (m *AisCloudProvider) DeleteObj(ctx context.Context, lom *cluster.LOM) (err
 	return extractErrCode(err)
 }
 
+// PutReplica and DeleteReplica replicate ais buckets to remote clusters
+// (see cmn.BckReplicationConf). Unlike PutObj, the content is not
+// necessarily stored as is - hence the explicit reader, size, and checksum.
+func (m *AisCloudProvider) PutReplica(remoteBck cmn.Bck, objName string, roc cmn.ReadOpenCloser,
+	size int64, cksum *cmn.Cksum) (err error, errCode int) {
+	aisCluster, err := m.remoteCluster(remoteBck.Ns.UUID)
+	if err != nil {
+		return err, errCode
+	}
+	err = m.try(remoteBck, func(bck cmn.Bck) error {
+		args := api.PutObjectArgs{
+			BaseParams: aisCluster.bp,
+			Bck:        bck,
+			Object:     objName,
+			Cksum:      cksum,
+			Reader:     roc,
+			Size:       uint64(size),
+		}
+		return api.PutObject(args)
+	})
+	return extractErrCode(err)
+}
+
+func (m *AisCloudProvider) DeleteReplica(remoteBck cmn.Bck, objName string) (err error, errCode int) {
+	aisCluster, err := m.remoteCluster(remoteBck.Ns.UUID)
+	if err != nil {
+		return err, errCode
+	}
+	err = m.try(remoteBck, func(bck cmn.Bck) error {
+		return api.DeleteObject(aisCluster.bp, bck, objName)
+	})
+	return extractErrCode(err)
+}
+
 func (m *AisCloudProvider) try(remoteBck cmn.Bck, f func(bck cmn.Bck) error) (err error) {
 	remoteBck.Ns.UUID = ""
 	for i := 0; i < aisCloudRetries+1; i++ {
@@ -10,8 +10,10 @@ import (
 	"context"
 	"io"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/NVIDIA/aistore/3rdparty/glog"
 	"github.com/NVIDIA/aistore/cluster"
@@ -33,10 +35,20 @@ type (
 	awsProvider struct {
 		t cluster.Target
 	}
+	// S3 multipart upload
+	awsUploader struct {
+		svc      *s3.S3
+		bucket   string
+		key      string
+		uploadID *string
+		mtx      sync.Mutex
+		parts    []*s3.CompletedPart
+	}
 )
 
 var (
 	_ cluster.CloudProvider = &awsProvider{}
+	_ partUploader          = &awsUploader{}
 )
 
 func NewAWS(t cluster.Target) (cluster.CloudProvider, error) { return &awsProvider{t: t}, nil }
@@ -347,33 +359,71 @@ func (awsp *awsProvider) GetObj(ctx context.Context, workFQN string, lom *cluste
 	return
 }
 
+func (awsp *awsProvider) GetObjRange(ctx context.Context, lom *cluster.LOM, offset, length int64) (r io.ReadCloser,
+	attrs cluster.CloudObjAttrs, err error, errCode int) {
+	var (
+		h   = cmn.CloudHelpers.Amazon
+		bck = lom.Bck().CloudBck()
+		svc = s3.New(createSession())
+	)
+	obj, err := svc.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bck.Name),
+		Key:    aws.String(lom.ObjName),
+		Range:  aws.String(byteRange(offset, length)),
+	})
+	if err != nil {
+		err, errCode = awsp.awsErrorToAISError(err, bck)
+		return
+	}
+	if attrs.Size, err = contentRangeSize(aws.StringValue(obj.ContentRange)); err != nil {
+		obj.Body.Close()
+		return nil, attrs, err, http.StatusBadGateway
+	}
+	if v, ok := h.EncodeVersion(obj.VersionId); ok {
+		attrs.Version = v
+	}
+	attrs.ETag = aws.StringValue(obj.ETag)
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("[get_object_range] %s [%d, %d)", lom, offset, offset+length)
+	}
+	return obj.Body, attrs, nil, 0
+}
+
 ////////////////
 // PUT OBJECT //
 ////////////////
 
-func (awsp *awsProvider) PutObj(_ context.Context, r io.Reader, lom *cluster.LOM) (version string, err error, errCode int) {
+func (awsp *awsProvider) PutObj(ctx context.Context, r io.Reader, lom *cluster.LOM) (version string, err error, errCode int) {
 	var (
-		uploadOutput          *s3manager.UploadOutput
 		h                     = cmn.CloudHelpers.Amazon
 		cksumType, cksumValue = lom.Cksum().Get()
 		cloudBck              = lom.Bck().CloudBck()
 		md                    = make(map[string]*string, 2)
+		versionID             *string
 	)
 	md[awsChecksumType] = aws.String(cksumType)
 	md[awsChecksumVal] = aws.String(cksumValue)
 
-	uploader := s3manager.NewUploader(createSession())
-	uploadOutput, err = uploader.Upload(&s3manager.UploadInput{
-		Bucket:   aws.String(cloudBck.Name),
-		Key:      aws.String(lom.ObjName),
-		Body:     r,
-		Metadata: md,
-	})
+	if conf, ok := uploadInParts(cmn.ProviderAmazon, lom); ok {
+		versionID, err = awsp.putObjParts(ctx, r, lom, md, conf)
+	} else {
+		var uploadOutput *s3manager.UploadOutput
+		uploader := s3manager.NewUploader(createSession())
+		uploadOutput, err = uploader.Upload(&s3manager.UploadInput{
+			Bucket:   aws.String(cloudBck.Name),
+			Key:      aws.String(lom.ObjName),
+			Body:     r,
+			Metadata: md,
+		})
+		if err == nil {
+			versionID = uploadOutput.VersionID
+		}
+	}
 	if err != nil {
 		err, errCode = awsp.awsErrorToAISError(err, cloudBck)
 		return
 	}
-	if v, ok := h.EncodeVersion(uploadOutput.VersionID); ok {
+	if v, ok := h.EncodeVersion(versionID); ok {
 		version = v
 	}
 	if glog.FastV(4, glog.SmoduleAIS) {
@@ -382,6 +432,82 @@ func (awsp *awsProvider) PutObj(_ context.Context, r io.Reader, lom *cluster.LOM
 	return
 }
 
+func (awsp *awsProvider) putObjParts(ctx context.Context, r io.Reader, lom *cluster.LOM, md map[string]*string,
+	conf cmn.CloudUploadConf) (versionID *string, err error) {
+	var (
+		version string
+		out     *s3.CreateMultipartUploadOutput
+		u       = &awsUploader{
+			svc:    s3.New(createSession()),
+			bucket: lom.Bck().CloudBck().Name,
+			key:    lom.ObjName,
+		}
+	)
+	out, err = u.svc.CreateMultipartUploadWithContext(ctx, &s3.CreateMultipartUploadInput{
+		Bucket:   aws.String(u.bucket),
+		Key:      aws.String(u.key),
+		Metadata: md,
+	})
+	if err != nil {
+		return
+	}
+	u.uploadID = out.UploadId
+	if version, err = uploadParts(ctx, u, r, lom.Size(), conf); err != nil {
+		return
+	}
+	if version != "" {
+		versionID = aws.String(version)
+	}
+	return
+}
+
+func (u *awsUploader) uploadPart(ctx context.Context, num int, r io.ReadSeeker, size int64) error {
+	out, err := u.svc.UploadPartWithContext(ctx, &s3.UploadPartInput{
+		Bucket:        aws.String(u.bucket),
+		Key:           aws.String(u.key),
+		UploadId:      u.uploadID,
+		PartNumber:    aws.Int64(int64(num)),
+		Body:          r,
+		ContentLength: aws.Int64(size),
+	})
+	if err != nil {
+		return err
+	}
+	u.mtx.Lock()
+	u.parts = append(u.parts, &s3.CompletedPart{ETag: out.ETag, PartNumber: aws.Int64(int64(num))})
+	u.mtx.Unlock()
+	return nil
+}
+
+func (u *awsUploader) complete(ctx context.Context, n int) (version string, err error) {
+	cmn.Assert(len(u.parts) == n)
+	sort.Slice(u.parts, func(i, j int) bool { return *u.parts[i].PartNumber < *u.parts[j].PartNumber })
+	out, err := u.svc.CompleteMultipartUploadWithContext(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(u.bucket),
+		Key:             aws.String(u.key),
+		UploadId:        u.uploadID,
+		MultipartUpload: &s3.CompletedMultipartUpload{Parts: u.parts},
+	})
+	if err != nil {
+		return
+	}
+	if out.VersionId != nil {
+		version = *out.VersionId
+	}
+	return
+}
+
+func (u *awsUploader) abort(ctx context.Context) {
+	_, err := u.svc.AbortMultipartUploadWithContext(ctx, &s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(u.bucket),
+		Key:      aws.String(u.key),
+		UploadId: u.uploadID,
+	})
+	if err != nil {
+		glog.Errorf("failed to abort multipart upload %s/%s: %v", u.bucket, u.key, err)
+	}
+}
+
 ///////////////////
 // DELETE OBJECT //
 ///////////////////
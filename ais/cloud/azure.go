@@ -8,6 +8,7 @@ package cloud
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
 	"io"
 	"net/http"
@@ -15,6 +16,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Azure/azure-storage-blob-go/azblob"
 	"github.com/NVIDIA/aistore/3rdparty/glog"
@@ -29,6 +31,11 @@ type (
 		t cluster.Target
 		s azblob.ServiceURL
 	}
+	// block blob uploaded as a list of blocks
+	azureUploader struct {
+		blobURL azblob.BlockBlobURL
+		leaseID string
+	}
 )
 
 const (
@@ -54,6 +61,7 @@ const (
 
 var (
 	_ cluster.CloudProvider = &azureProvider{}
+	_ partUploader          = &azureUploader{}
 )
 
 func azureProto() string {
@@ -384,6 +392,37 @@ func (ap *azureProvider) GetObj(ctx context.Context, workFQN string, lom *cluste
 	return
 }
 
+func (ap *azureProvider) GetObjRange(ctx context.Context, lom *cluster.LOM, offset, length int64) (r io.ReadCloser,
+	attrs cluster.CloudObjAttrs, err error, errCode int) {
+	var (
+		h        = cmn.CloudHelpers.Azure
+		cloudBck = lom.Bck().CloudBck()
+		blobURL  = ap.s.NewContainerURL(cloudBck.Name).NewBlobURL(lom.ObjName)
+	)
+	resp, err := blobURL.Download(ctx, offset, length, azblob.BlobAccessConditions{}, false)
+	if err != nil {
+		err, errCode = ap.azureErrorToAISError(err, cloudBck, lom.ObjName)
+		return
+	}
+	body := resp.Body(azblob.RetryReaderOptions{MaxRetryRequests: 3})
+	if resp.StatusCode() >= http.StatusBadRequest {
+		body.Close()
+		return nil, attrs, fmt.Errorf("failed to GET object %s/%s", cloudBck, lom.ObjName), resp.StatusCode()
+	}
+	if attrs.Size, err = contentRangeSize(resp.ContentRange()); err != nil {
+		body.Close()
+		return nil, attrs, err, http.StatusBadGateway
+	}
+	if v, ok := h.EncodeVersion(string(resp.ETag())); ok {
+		attrs.Version = v
+	}
+	attrs.ETag = string(resp.ETag())
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("[get_object_range] %s [%d, %d)", lom, offset, offset+length)
+	}
+	return body, attrs, nil, 0
+}
+
 func (ap *azureProvider) PutObj(ctx context.Context, r io.Reader, lom *cluster.LOM) (version string, err error, errCode int) {
 	var (
 		leaseID  string
@@ -405,29 +444,99 @@ func (ap *azureProvider) PutObj(ctx context.Context, r io.Reader, lom *cluster.L
 			return "", errLease, code
 		}
 	}
-	// Use BlockBlob instead of PageBlob because the latter requires
-	// object size to be divisible by 512.
-	// Without buffer options(with 0's) UploadStreamToBlockBlob hangs up
-	opts := azblob.UploadStreamToBlockBlobOptions{
-		BufferSize: 64 * 1024,
-		MaxBuffers: 3,
-	}
-	if leaseID != "" {
-		opts.AccessConditions = azblob.BlobAccessConditions{LeaseAccessConditions: azblob.LeaseAccessConditions{LeaseID: leaseID}}
-	}
-	putResp, err := azblob.UploadStreamToBlockBlob(ctx, r, blobURL, opts)
-	if err != nil {
-		err, status := ap.azureErrorToAISError(err, cloudBck, lom.ObjName)
-		return "", err, status
-	}
-	if putResp.Response().StatusCode >= http.StatusBadRequest {
-		return "", fmt.Errorf("failed to put object %s/%s", cloudBck, lom.ObjName), putResp.Response().StatusCode
-	}
-	if v, ok := h.EncodeVersion(string(putResp.ETag())); ok {
-		version = v
+	if conf, ok := uploadInParts(cmn.ProviderAzure, lom); ok {
+		u := &azureUploader{blobURL: blobURL, leaseID: leaseID}
+		if leaseID != "" {
+			stop := u.keepLease(ctx)
+			defer close(stop)
+		}
+		if etag, err := uploadParts(ctx, u, r, lom.Size(), conf); err != nil {
+			err, status := ap.azureErrorToAISError(err, cloudBck, lom.ObjName)
+			return "", err, status
+		} else if v, ok := h.EncodeVersion(etag); ok {
+			version = v
+		}
+	} else {
+		// Use BlockBlob instead of PageBlob because the latter requires
+		// object size to be divisible by 512.
+		// Without buffer options(with 0's) UploadStreamToBlockBlob hangs up
+		opts := azblob.UploadStreamToBlockBlobOptions{
+			BufferSize: 64 * 1024,
+			MaxBuffers: 3,
+		}
+		if leaseID != "" {
+			opts.AccessConditions = azblob.BlobAccessConditions{LeaseAccessConditions: azblob.LeaseAccessConditions{LeaseID: leaseID}}
+		}
+		putResp, err := azblob.UploadStreamToBlockBlob(ctx, r, blobURL, opts)
+		if err != nil {
+			err, status := ap.azureErrorToAISError(err, cloudBck, lom.ObjName)
+			return "", err, status
+		}
+		if putResp.Response().StatusCode >= http.StatusBadRequest {
+			return "", fmt.Errorf("failed to put object %s/%s", cloudBck, lom.ObjName), putResp.Response().StatusCode
+		}
+		if v, ok := h.EncodeVersion(string(putResp.ETag())); ok {
+			version = v
+		}
 	}
 	if glog.FastV(4, glog.SmoduleAIS) {
 		glog.Infof("[put_object] %s, version: %s", lom, version)
 	}
 	return version, nil, http.StatusOK
 }
+
+///////////////////
+// azureUploader //
+///////////////////
+
+// block IDs must be base64-encoded and of the same length within a given blob
+func azureBlockID(num int) string {
+	return base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%08d", num)))
+}
+
+// keepLease renews the lease for as long as the (possibly, lengthy) upload takes
+func (u *azureUploader) keepLease(ctx context.Context) (stop chan struct{}) {
+	stop = make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(leaseTime * time.Second / 2)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if _, err := u.blobURL.RenewLease(ctx, u.leaseID, azblob.ModifiedAccessConditions{}); err != nil {
+					glog.Errorf("failed to renew lease %s: %v", u.leaseID, err)
+				}
+			case <-stop:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return
+}
+
+func (u *azureUploader) uploadPart(ctx context.Context, num int, r io.ReadSeeker, _ int64) error {
+	_, err := u.blobURL.StageBlock(ctx, azureBlockID(num), r,
+		azblob.LeaseAccessConditions{LeaseID: u.leaseID}, nil)
+	return err
+}
+
+func (u *azureUploader) complete(ctx context.Context, n int) (etag string, err error) {
+	ids := make([]string, 0, n)
+	for num := 1; num <= n; num++ {
+		ids = append(ids, azureBlockID(num))
+	}
+	var cond azblob.BlobAccessConditions
+	if u.leaseID != "" {
+		cond.LeaseAccessConditions = azblob.LeaseAccessConditions{LeaseID: u.leaseID}
+	}
+	resp, err := u.blobURL.CommitBlockList(ctx, ids, azblob.BlobHTTPHeaders{}, azblob.Metadata{}, cond)
+	if err != nil {
+		return
+	}
+	return string(resp.ETag()), nil
+}
+
+// uncommitted blocks are garbage collected by Azure
+func (u *azureUploader) abort(context.Context) {}
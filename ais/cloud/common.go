@@ -6,8 +6,14 @@ package cloud
 
 import (
 	"context"
+	"fmt"
 	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
 
+	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmn"
 )
 
@@ -27,3 +33,48 @@ func setSize(ctx context.Context, size int64) {
 		v.(cmn.SetSizeFunc)(size)
 	}
 }
+
+// errReadOnly is returned by the read-only cloud providers (HDFS, HTTP) upon PUT and DELETE
+func errReadOnly(lom *cluster.LOM) (error, int) {
+	return fmt.Errorf("%s: cannot modify objects of the read-only cloud provider %q", lom, lom.Bck().Provider),
+		http.StatusMethodNotAllowed
+}
+
+// byteRange returns the value of the Range header for a given (non-empty) byte range
+func byteRange(offset, length int64) string {
+	return fmt.Sprintf("%s%d-%d", cmn.HeaderRangeValPrefix, offset, offset+length-1)
+}
+
+// contentRangeSize returns the size of the entire object given the Content-Range
+// of a partial response, e.g. "bytes 0-1023/4096"
+func contentRangeSize(contentRange string) (int64, error) {
+	i := strings.LastIndexByte(contentRange, '/')
+	if !strings.HasPrefix(contentRange, cmn.HeaderContentRangeValPrefix) || i < 0 {
+		return 0, fmt.Errorf("invalid %s %q", cmn.HeaderContentRange, contentRange)
+	}
+	size, err := strconv.ParseInt(contentRange[i+1:], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q", cmn.HeaderContentRange, contentRange)
+	}
+	return size, nil
+}
+
+// pageEntries returns the page that follows msg.PageMarker - for the providers
+// that list the entire bucket (directory tree) at once
+func pageEntries(entries []*cmn.BucketEntry, msg *cmn.SelectMsg) *cmn.BucketList {
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+	start := 0
+	if msg.PageMarker != "" {
+		start = sort.Search(len(entries), func(i int) bool { return entries[i].Name > msg.PageMarker })
+	}
+	pageSize := int(msg.PageSize)
+	if pageSize == 0 {
+		pageSize = int(cmn.DefaultListPageSize)
+	}
+	end := cmn.Min(start+pageSize, len(entries))
+	bckList := &cmn.BucketList{Entries: entries[start:end]}
+	if end < len(entries) {
+		bckList.PageMarker = entries[end-1].Name
+	}
+	return bckList
+}
@@ -13,6 +13,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 
 	"cloud.google.com/go/storage"
@@ -252,6 +253,7 @@ func (gcpp *gcpProvider) HeadObj(ctx context.Context, lom *cluster.LOM) (objMeta
 	}
 	objMeta = make(cmn.SimpleKVs)
 	objMeta[cmn.HeaderCloudProvider] = cmn.ProviderGoogle
+	objMeta[cmn.HeaderObjSize] = strconv.FormatInt(attrs.Size, 10)
 	if v, ok := h.EncodeVersion(attrs.Generation); ok {
 		objMeta[cmn.HeaderObjVersion] = v
 	}
@@ -332,6 +334,33 @@ func (gcpp *gcpProvider) GetObj(ctx context.Context, workFQN string, lom *cluste
 	return
 }
 
+func (gcpp *gcpProvider) GetObjRange(ctx context.Context, lom *cluster.LOM, offset, length int64) (r io.ReadCloser,
+	attrs cluster.CloudObjAttrs, err error, errCode int) {
+	gcpClient, gctx, err := createClient(ctx)
+	if err != nil {
+		return
+	}
+	var (
+		h        = cmn.CloudHelpers.Google
+		cloudBck = lom.Bck().CloudBck()
+	)
+	rc, err := gcpClient.Bucket(cloudBck.Name).Object(lom.ObjName).NewRangeReader(gctx, offset, length)
+	if err != nil {
+		err, errCode = gcpp.gcpErrorToAISError(err, cloudBck)
+		return
+	}
+	attrs.Size = rc.Attrs.Size
+	if v, ok := h.EncodeVersion(rc.Attrs.Generation); ok {
+		attrs.Version = v
+	}
+	// generation changes whenever the object gets overwritten
+	attrs.ETag = strconv.FormatInt(rc.Attrs.Generation, 10)
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("[get_object_range] %s [%d, %d)", lom, offset, offset+length)
+	}
+	return rc, attrs, nil, 0
+}
+
 ////////////////
 // PUT OBJECT //
 ////////////////
@@ -353,6 +382,13 @@ func (gcpp *gcpProvider) PutObj(ctx context.Context, r io.Reader, lom *cluster.L
 	md[gcpChecksumType], md[gcpChecksumVal] = lom.Cksum().Get()
 
 	wc.Metadata = md
+	// large objects are uploaded in chunks within a resumable upload session;
+	// otherwise, in a single request
+	if conf, ok := uploadInParts(cmn.ProviderGoogle, lom); ok {
+		wc.ChunkSize = int(conf.PartSize)
+	} else {
+		wc.ChunkSize = 0
+	}
 	buf, slab := gcpp.t.GetMMSA().Alloc()
 	written, err := io.CopyBuffer(wc, r, buf)
 	slab.Free(buf)
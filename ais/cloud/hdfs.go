@@ -0,0 +1,346 @@
+// Package cloud contains implementation of various cloud providers.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	jsoniter "github.com/json-iterator/go"
+)
+
+// HDFS cloud provider: read-only access via the WebHDFS REST API
+// (https://hadoop.apache.org/docs/stable/hadoop-project-dist/hadoop-hdfs/WebHDFS.html).
+// A bucket is a top-level HDFS directory; the object name is the path
+// relative to it.
+
+const (
+	hdfsAPIPath = "/webhdfs/v1"
+
+	hdfsTypeFile = "FILE"
+	hdfsTypeDir  = "DIRECTORY"
+)
+
+type (
+	hdfsProvider struct {
+		t      cluster.Target
+		client *http.Client
+	}
+
+	hdfsFileStatus struct {
+		PathSuffix       string `json:"pathSuffix"`
+		Type             string `json:"type"`
+		Length           int64  `json:"length"`
+		ModificationTime int64  `json:"modificationTime"`
+	}
+	hdfsFileStatusResp struct {
+		FileStatus hdfsFileStatus `json:"FileStatus"`
+	}
+	hdfsListStatusResp struct {
+		FileStatuses struct {
+			FileStatus []hdfsFileStatus `json:"FileStatus"`
+		} `json:"FileStatuses"`
+	}
+	hdfsRemoteExceptionResp struct {
+		RemoteException struct {
+			Exception string `json:"exception"`
+			Message   string `json:"message"`
+		} `json:"RemoteException"`
+	}
+)
+
+var (
+	_ cluster.CloudProvider = &hdfsProvider{}
+)
+
+// NOTE: no client timeout - large objects may take a while (see also ctx)
+func NewHDFS(t cluster.Target) (cluster.CloudProvider, error) {
+	return &hdfsProvider{t: t, client: cmn.NewClient(cmn.TransportArgs{UseHTTPProxyEnv: true})}, nil
+}
+
+func (hp *hdfsProvider) Provider() string { return cmn.ProviderHDFS }
+
+// the modification time serves as the object's version
+func (st *hdfsFileStatus) version() string { return strconv.FormatInt(st.ModificationTime, 10) }
+
+// url returns the WebHDFS URL of a given operation; query (optional) contains
+// the operation's parameters
+func (hp *hdfsProvider) url(op, bckName, objName string, query url.Values) string {
+	var (
+		conf, _     = cmn.GCO.Get().Cloud.ProviderConf(cmn.ProviderHDFS)
+		hdfsConf, _ = conf.(cmn.CloudConfHDFS)
+		p           = "/" + url.PathEscape(bckName)
+	)
+	if query == nil {
+		query = url.Values{}
+	}
+	if objName != "" {
+		p += "/" + escapePath(objName)
+	}
+	query.Set("op", op)
+	if hdfsConf.User != "" {
+		query.Set("user.name", hdfsConf.User)
+	}
+	return strings.TrimSuffix(hdfsConf.URL, "/") + hdfsAPIPath + p + "?" + query.Encode()
+}
+
+// do executes a given WebHDFS operation; RemoteException (if any) is converted to error
+func (hp *hdfsProvider) do(ctx context.Context, op, bckName, objName string,
+	query url.Values) (resp *http.Response, err error, errCode int) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, hp.url(op, bckName, objName, query), nil)
+	if err != nil {
+		return nil, err, http.StatusBadRequest
+	}
+	// OPEN is redirected to a DataNode
+	resp, err = hp.client.Do(req)
+	if err != nil {
+		return nil, err, http.StatusBadGateway
+	}
+	if resp.StatusCode < http.StatusBadRequest {
+		return resp, nil, 0
+	}
+	var (
+		remoteErr hdfsRemoteExceptionResp
+		name      = bckName + "/" + objName
+	)
+	if errDecode := jsoniter.NewDecoder(resp.Body).Decode(&remoteErr); errDecode == nil {
+		err = fmt.Errorf("hdfs %s %s: %s: %s", op, name, remoteErr.RemoteException.Exception,
+			remoteErr.RemoteException.Message)
+	} else {
+		err = fmt.Errorf("hdfs %s %s: %s", op, name, resp.Status)
+	}
+	resp.Body.Close()
+	return nil, err, resp.StatusCode
+}
+
+func (hp *hdfsProvider) getJSON(ctx context.Context, op, bckName, objName string, v interface{}) (err error, errCode int) {
+	resp, err, errCode := hp.do(ctx, op, bckName, objName, nil)
+	if err != nil {
+		return
+	}
+	err = jsoniter.NewDecoder(resp.Body).Decode(v)
+	resp.Body.Close()
+	if err != nil {
+		return fmt.Errorf("hdfs %s %s/%s: %v", op, bckName, objName, err), http.StatusBadGateway
+	}
+	return nil, 0
+}
+
+func (hp *hdfsProvider) stat(ctx context.Context, bck cmn.Bck, objName string) (st *hdfsFileStatus, err error, errCode int) {
+	var resp hdfsFileStatusResp
+	if err, errCode = hp.getJSON(ctx, "GETFILESTATUS", bck.Name, objName, &resp); err != nil {
+		return
+	}
+	return &resp.FileStatus, nil, 0
+}
+
+//////////////////
+// LIST OBJECTS //
+//////////////////
+
+func (hp *hdfsProvider) ListObjects(ctx context.Context, bck *cluster.Bck, msg *cmn.SelectMsg) (bckList *cmn.BucketList, err error, errCode int) {
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("list_bucket %s", bck.Name)
+	}
+	var (
+		cloudBck = bck.CloudBck()
+		entries  = make([]*cmn.BucketEntry, 0, initialBucketListSize)
+	)
+	if err, errCode = hp.walk(ctx, cloudBck, "", msg, &entries); err != nil {
+		if errCode == http.StatusNotFound {
+			err = cmn.NewErrorRemoteBucketDoesNotExist(cloudBck, hp.t.Snode().Name())
+		}
+		return
+	}
+	bckList = pageEntries(entries, msg)
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("[list_bucket] count %d", len(bckList.Entries))
+	}
+	return
+}
+
+// walk recursively lists the directories skipping the subtrees that do not match the prefix
+func (hp *hdfsProvider) walk(ctx context.Context, bck cmn.Bck, dir string, msg *cmn.SelectMsg,
+	entries *[]*cmn.BucketEntry) (err error, errCode int) {
+	var resp hdfsListStatusResp
+	if err, errCode = hp.getJSON(ctx, "LISTSTATUS", bck.Name, strings.TrimSuffix(dir, "/"), &resp); err != nil {
+		return
+	}
+	for _, st := range resp.FileStatuses.FileStatus {
+		name := dir + st.PathSuffix
+		switch st.Type {
+		case hdfsTypeDir:
+			name += "/"
+			if !strings.HasPrefix(name, msg.Prefix) && !strings.HasPrefix(msg.Prefix, name) {
+				continue
+			}
+			if err, errCode = hp.walk(ctx, bck, name, msg, entries); err != nil {
+				return
+			}
+		case hdfsTypeFile:
+			if !strings.HasPrefix(name, msg.Prefix) {
+				continue
+			}
+			entry := &cmn.BucketEntry{Name: name}
+			if strings.Contains(msg.Props, cmn.GetPropsSize) {
+				entry.Size = st.Length
+			}
+			if strings.Contains(msg.Props, cmn.GetPropsVersion) {
+				entry.Version = st.version()
+			}
+			*entries = append(*entries, entry)
+		}
+	}
+	return
+}
+
+func (hp *hdfsProvider) HeadBucket(ctx context.Context, bck *cluster.Bck) (bckProps cmn.SimpleKVs, err error, errCode int) {
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("head_bucket %s", bck.Name)
+	}
+	cloudBck := bck.CloudBck()
+	st, err, errCode := hp.stat(ctx, cloudBck, "")
+	if err != nil {
+		if errCode == http.StatusNotFound {
+			err = cmn.NewErrorRemoteBucketDoesNotExist(cloudBck, hp.t.Snode().Name())
+		}
+		return
+	}
+	if st.Type != hdfsTypeDir {
+		err, errCode = cmn.NewErrorRemoteBucketDoesNotExist(cloudBck, hp.t.Snode().Name()), http.StatusNotFound
+		return
+	}
+	bckProps = make(cmn.SimpleKVs, 2)
+	bckProps[cmn.HeaderCloudProvider] = cmn.ProviderHDFS
+	// version is the modification time
+	bckProps[cmn.HeaderBucketVerEnabled] = "true"
+	return
+}
+
+//////////////////
+// BUCKET NAMES //
+//////////////////
+
+func (hp *hdfsProvider) ListBuckets(ctx context.Context, _ cmn.QueryBcks) (buckets cmn.BucketNames, err error, errCode int) {
+	var resp hdfsListStatusResp
+	if err, errCode = hp.getJSON(ctx, "LISTSTATUS", "", "", &resp); err != nil {
+		return
+	}
+	buckets = make(cmn.BucketNames, 0, len(resp.FileStatuses.FileStatus))
+	for _, st := range resp.FileStatuses.FileStatus {
+		if st.Type != hdfsTypeDir || cmn.ValidateBckName(st.PathSuffix) != nil {
+			continue
+		}
+		buckets = append(buckets, cmn.Bck{Name: st.PathSuffix, Provider: cmn.ProviderHDFS})
+	}
+	return
+}
+
+/////////////////
+// HEAD OBJECT //
+/////////////////
+
+func (hp *hdfsProvider) HeadObj(ctx context.Context, lom *cluster.LOM) (objMeta cmn.SimpleKVs, err error, errCode int) {
+	cloudBck := lom.Bck().CloudBck()
+	st, err, errCode := hp.stat(ctx, cloudBck, lom.ObjName)
+	if err != nil {
+		return
+	}
+	if st.Type != hdfsTypeFile {
+		return nil, fmt.Errorf("%s/%s is not a file", cloudBck, lom.ObjName), http.StatusNotFound
+	}
+	objMeta = make(cmn.SimpleKVs, 3)
+	objMeta[cmn.HeaderCloudProvider] = cmn.ProviderHDFS
+	objMeta[cmn.HeaderObjSize] = strconv.FormatInt(st.Length, 10)
+	objMeta[cmn.HeaderObjVersion] = st.version()
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("[head_object] %s/%s", cloudBck, lom.ObjName)
+	}
+	return
+}
+
+////////////////
+// GET OBJECT //
+////////////////
+
+func (hp *hdfsProvider) GetObj(ctx context.Context, workFQN string, lom *cluster.LOM) (err error, errCode int) {
+	cloudBck := lom.Bck().CloudBck()
+	st, err, errCode := hp.stat(ctx, cloudBck, lom.ObjName)
+	if err != nil {
+		return
+	}
+	if st.Type != hdfsTypeFile {
+		return fmt.Errorf("%s/%s is not a file", cloudBck, lom.ObjName), http.StatusNotFound
+	}
+	resp, err, errCode := hp.do(ctx, "OPEN", cloudBck.Name, lom.ObjName, nil)
+	if err != nil {
+		return
+	}
+	lom.SetVersion(st.version())
+	lom.SetCustomMD(cmn.SimpleKVs{
+		cluster.SourceObjMD:  cluster.SourceHDFSObjMD,
+		cluster.VersionObjMD: st.version(),
+	})
+	setSize(ctx, st.Length)
+	err = hp.t.PutObject(cluster.PutObjectParams{
+		LOM:          lom,
+		Reader:       wrapReader(ctx, resp.Body),
+		WorkFQN:      workFQN,
+		RecvType:     cluster.ColdGet,
+		WithFinalize: false,
+	})
+	if err != nil {
+		return
+	}
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("[get_object] %s", lom)
+	}
+	return
+}
+
+func (hp *hdfsProvider) GetObjRange(ctx context.Context, lom *cluster.LOM, offset, length int64) (r io.ReadCloser,
+	attrs cluster.CloudObjAttrs, err error, errCode int) {
+	cloudBck := lom.Bck().CloudBck()
+	st, err, errCode := hp.stat(ctx, cloudBck, lom.ObjName)
+	if err != nil {
+		return
+	}
+	if st.Type != hdfsTypeFile {
+		return nil, attrs, fmt.Errorf("%s/%s is not a file", cloudBck, lom.ObjName), http.StatusNotFound
+	}
+	query := url.Values{}
+	query.Set("offset", strconv.FormatInt(offset, 10))
+	query.Set("length", strconv.FormatInt(length, 10))
+	resp, err, errCode := hp.do(ctx, "OPEN", cloudBck.Name, lom.ObjName, query)
+	if err != nil {
+		return
+	}
+	attrs.Size, attrs.Version, attrs.ETag = st.Length, st.version(), st.version()
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("[get_object_range] %s [%d, %d)", lom, offset, offset+length)
+	}
+	return resp.Body, attrs, nil, 0
+}
+
+/////////////////////////
+// PUT & DELETE OBJECT //
+/////////////////////////
+
+func (hp *hdfsProvider) PutObj(_ context.Context, _ io.Reader, lom *cluster.LOM) (version string, err error, errCode int) {
+	err, errCode = errReadOnly(lom)
+	return
+}
+
+func (hp *hdfsProvider) DeleteObj(_ context.Context, lom *cluster.LOM) (err error, errCode int) {
+	return errReadOnly(lom)
+}
@@ -0,0 +1,101 @@
+// Package cloud contains implementation of various cloud providers.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package cloud
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/tutils/tassert"
+	jsoniter "github.com/json-iterator/go"
+)
+
+// WebHDFS LISTSTATUS and GETFILESTATUS of a given directory tree
+func newWebHDFSServer(tb testing.TB, tree map[string][]hdfsFileStatus) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user := r.URL.Query().Get("user.name"); user != "hdfs" {
+			tb.Errorf("unexpected user.name %q", user)
+		}
+		p := strings.TrimPrefix(r.URL.Path, hdfsAPIPath)
+		switch op := r.URL.Query().Get("op"); op {
+		case "LISTSTATUS":
+			list, ok := tree[p]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"RemoteException":{"exception":"FileNotFoundException","message":"not found"}}`))
+				return
+			}
+			resp := hdfsListStatusResp{}
+			resp.FileStatuses.FileStatus = list
+			jsoniter.NewEncoder(w).Encode(resp)
+		case "GETFILESTATUS":
+			if _, ok := tree[p]; !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			jsoniter.NewEncoder(w).Encode(hdfsFileStatusResp{FileStatus: hdfsFileStatus{Type: hdfsTypeDir}})
+		default:
+			tb.Errorf("unexpected op %q", op)
+		}
+	}))
+}
+
+func TestHDFSListObjects(t *testing.T) {
+	srv := newWebHDFSServer(t, map[string][]hdfsFileStatus{
+		"/": {{PathSuffix: "data", Type: hdfsTypeDir}, {PathSuffix: "file", Type: hdfsTypeFile}},
+		"/data": {
+			{PathSuffix: "b", Type: hdfsTypeFile, Length: 2, ModificationTime: 20},
+			{PathSuffix: "a", Type: hdfsTypeFile, Length: 1, ModificationTime: 10},
+			{PathSuffix: "sub", Type: hdfsTypeDir},
+		},
+		"/data/sub": {{PathSuffix: "c", Type: hdfsTypeFile, Length: 3, ModificationTime: 30}},
+	})
+	defer srv.Close()
+
+	config := cmn.GCO.BeginUpdate()
+	if config.Cloud.Conf == nil {
+		config.Cloud.Conf = make(map[string]interface{})
+	}
+	config.Cloud.Conf[cmn.ProviderHDFS] = cmn.CloudConfHDFS{URL: srv.URL, User: "hdfs"}
+	cmn.GCO.CommitUpdate(config)
+	defer func() {
+		config := cmn.GCO.BeginUpdate()
+		delete(config.Cloud.Conf, cmn.ProviderHDFS)
+		cmn.GCO.CommitUpdate(config)
+	}()
+
+	var (
+		hp, _ = NewHDFS(nil)
+		bck   = cluster.NewBck("data", cmn.ProviderHDFS, cmn.NsGlobal)
+		ctx   = context.Background()
+		msg   = &cmn.SelectMsg{Props: cmn.GetPropsSize + "," + cmn.GetPropsVersion}
+	)
+	bckList, err, _ := hp.ListObjects(ctx, bck, msg)
+	tassert.CheckFatal(t, err)
+	expected := []*cmn.BucketEntry{
+		{Name: "a", Size: 1, Version: "10"},
+		{Name: "b", Size: 2, Version: "20"},
+		{Name: "sub/c", Size: 3, Version: "30"},
+	}
+	tassert.Errorf(t, reflect.DeepEqual(bckList.Entries, expected), "expected %v, got %v", expected, bckList.Entries)
+
+	msg = &cmn.SelectMsg{Prefix: "sub/"}
+	bckList, err, _ = hp.ListObjects(ctx, bck, msg)
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, len(bckList.Entries) == 1 && bckList.Entries[0].Name == "sub/c", "wrong entries %v", bckList.Entries)
+
+	buckets, err, _ := hp.ListBuckets(ctx, cmn.QueryBcks{Provider: cmn.ProviderHDFS})
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, len(buckets) == 1 && buckets[0].Name == "data", "wrong buckets %v", buckets)
+
+	_, err, _ = hp.HeadBucket(ctx, bck)
+	tassert.CheckFatal(t, err)
+}
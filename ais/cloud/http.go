@@ -0,0 +1,395 @@
+// Package cloud contains implementation of various cloud providers.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+// HTTP(S) cloud provider: a read-only bucket is a directory tree served by a
+// plain HTTP server (e.g., nginx `autoindex` or Apache `mod_autoindex`).
+// Objects are listed by recursively walking the HTML directory indexes in
+// lexicographic order, starting from the page marker (i.e., skipping the
+// subtrees that precede it) and stopping as soon as the page is full.
+
+const (
+	httpMaxIndexDepth = 32      // max depth of the directory tree
+	httpMaxIndexSize  = cmn.MiB // max size of a single directory index page
+)
+
+type (
+	httpProvider struct {
+		t      cluster.Target
+		client *http.Client
+	}
+	// state of a single (page) walk
+	httpWalk struct {
+		baseURL string
+		prefix  string
+		marker  string // list the objects that follow
+		limit   int    // max number of objects
+		entries []*cmn.BucketEntry
+	}
+)
+
+var (
+	_ cluster.CloudProvider = &httpProvider{}
+
+	httpHrefRegex = regexp.MustCompile(`(?i)href\s*=\s*["']([^"']+)["']`)
+)
+
+// NOTE: no client timeout - large objects may take a while (see also ctx)
+func NewHTTP(t cluster.Target) (cluster.CloudProvider, error) {
+	return &httpProvider{t: t, client: cmn.NewClient(cmn.TransportArgs{UseHTTPProxyEnv: true})}, nil
+}
+
+func (hp *httpProvider) Provider() string { return cmn.ProviderHTTP }
+
+// baseURL returns the configured base URL of a given bucket (always ending with '/')
+func (hp *httpProvider) baseURL(bck cmn.Bck) (string, error, int) {
+	conf, _ := cmn.GCO.Get().Cloud.ProviderConf(cmn.ProviderHTTP)
+	httpConf, _ := conf.(cmn.CloudConfHTTP)
+	baseURL, ok := httpConf[bck.Name]
+	if !ok {
+		return "", cmn.NewErrorRemoteBucketDoesNotExist(bck, hp.t.Snode().Name()), http.StatusNotFound
+	}
+	if !strings.HasSuffix(baseURL, "/") {
+		baseURL += "/"
+	}
+	return baseURL, nil, 0
+}
+
+// escapePath escapes each element of a given (object or directory) name
+func escapePath(name string) string {
+	elems := strings.Split(name, "/")
+	for i, elem := range elems {
+		elems[i] = url.PathEscape(elem)
+	}
+	return strings.Join(elems, "/")
+}
+
+func (hp *httpProvider) do(ctx context.Context, method, link string) (resp *http.Response, err error, errCode int) {
+	req, err := http.NewRequestWithContext(ctx, method, link, nil)
+	if err != nil {
+		return nil, err, http.StatusBadRequest
+	}
+	return hp.send(req)
+}
+
+func (hp *httpProvider) send(req *http.Request) (resp *http.Response, err error, errCode int) {
+	resp, err = hp.client.Do(req)
+	if err != nil {
+		return nil, err, http.StatusBadGateway
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s %s: %s", req.Method, req.URL, resp.Status), resp.StatusCode
+	}
+	return resp, nil, 0
+}
+
+// the ETag (if present) or the last modification time of a given object
+func httpVersion(hdr http.Header) string {
+	if etag := strings.Trim(strings.TrimPrefix(hdr.Get("ETag"), "W/"), "\""); etag != "" {
+		return etag
+	}
+	return hdr.Get("Last-Modified")
+}
+
+//////////////////
+// LIST OBJECTS //
+//////////////////
+
+func (hp *httpProvider) ListObjects(ctx context.Context, bck *cluster.Bck, msg *cmn.SelectMsg) (bckList *cmn.BucketList, err error, errCode int) {
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("list_bucket %s", bck.Name)
+	}
+	cloudBck := bck.CloudBck()
+	baseURL, err, errCode := hp.baseURL(cloudBck)
+	if err != nil {
+		return
+	}
+	pageSize := int(msg.PageSize)
+	if pageSize == 0 {
+		pageSize = int(cmn.DefaultListPageSize)
+	}
+	// one more object to tell whether the page is the last one
+	w := &httpWalk{baseURL: baseURL, prefix: msg.Prefix, marker: msg.PageMarker, limit: pageSize + 1}
+	if err, errCode = hp.walk(ctx, w, "", 0); err != nil {
+		return
+	}
+	bckList = &cmn.BucketList{Entries: w.entries}
+	if len(w.entries) > pageSize {
+		bckList.Entries = w.entries[:pageSize]
+		bckList.PageMarker = bckList.Entries[pageSize-1].Name
+	}
+
+	// the index does not (reliably) provide object properties
+	wantSize := strings.Contains(msg.Props, cmn.GetPropsSize)
+	wantVersion := strings.Contains(msg.Props, cmn.GetPropsVersion)
+	if wantSize || wantVersion {
+		for _, entry := range bckList.Entries {
+			resp, err, errCode := hp.do(ctx, http.MethodHead, baseURL+escapePath(entry.Name))
+			if err != nil {
+				return nil, err, errCode
+			}
+			resp.Body.Close()
+			if wantSize && resp.ContentLength >= 0 {
+				entry.Size = resp.ContentLength
+			}
+			if wantVersion {
+				entry.Version = httpVersion(resp.Header)
+			}
+		}
+	}
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("[list_bucket] count %d", len(bckList.Entries))
+	}
+	return
+}
+
+// walk recursively reads the directory indexes in order, skipping the subtrees
+// that do not match the prefix or precede the marker
+func (hp *httpProvider) walk(ctx context.Context, w *httpWalk, dir string, depth int) (err error, errCode int) {
+	if depth > httpMaxIndexDepth {
+		return fmt.Errorf("%s: directory tree is too deep (max %d)", w.baseURL, httpMaxIndexDepth), http.StatusBadRequest
+	}
+	links, err, errCode := hp.readIndex(ctx, w.baseURL+escapePath(dir))
+	if err != nil {
+		return
+	}
+	// (all names in a subtree start with "dir/" - sorting the latter along
+	// with the files orders the entire tree)
+	sort.Strings(links)
+	for _, link := range links {
+		if len(w.entries) >= w.limit {
+			return
+		}
+		name := dir + link
+		if strings.HasSuffix(name, "/") {
+			if !strings.HasPrefix(name, w.prefix) && !strings.HasPrefix(w.prefix, name) {
+				continue
+			}
+			if w.marker > name && !strings.HasPrefix(w.marker, name) {
+				continue
+			}
+			if err, errCode = hp.walk(ctx, w, name, depth+1); err != nil {
+				return
+			}
+		} else if strings.HasPrefix(name, w.prefix) && name > w.marker {
+			w.entries = append(w.entries, &cmn.BucketEntry{Name: name})
+		}
+	}
+	return
+}
+
+// readIndex returns the (relative) files and subdirectories of a given directory index
+func (hp *httpProvider) readIndex(ctx context.Context, link string) (links []string, err error, errCode int) {
+	resp, err, errCode := hp.do(ctx, http.MethodGet, link)
+	if err != nil {
+		return
+	}
+	b, err := ioutil.ReadAll(io.LimitReader(resp.Body, httpMaxIndexSize))
+	resp.Body.Close()
+	if err != nil {
+		return nil, err, http.StatusBadGateway
+	}
+	return parseIndex(b), nil, 0
+}
+
+func parseIndex(b []byte) (links []string) {
+	seen := make(map[string]struct{})
+	for _, match := range httpHrefRegex.FindAllSubmatch(b, -1) {
+		u, err := url.Parse(string(match[1]))
+		if err != nil || u.IsAbs() || u.Host != "" || u.RawQuery != "" || u.Path == "" {
+			continue // sorting links, links to other sites, etc.
+		}
+		// only the immediate children
+		name := strings.TrimPrefix(u.Path, "./")
+		if name == "" || strings.HasPrefix(name, "/") || strings.HasPrefix(name, "..") ||
+			strings.Contains(strings.TrimSuffix(name, "/"), "/") || path.Clean(name) == "." {
+			continue
+		}
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+		links = append(links, name)
+	}
+	return
+}
+
+func (hp *httpProvider) HeadBucket(ctx context.Context, bck *cluster.Bck) (bckProps cmn.SimpleKVs, err error, errCode int) {
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("head_bucket %s", bck.Name)
+	}
+	cloudBck := bck.CloudBck()
+	baseURL, err, errCode := hp.baseURL(cloudBck)
+	if err != nil {
+		return
+	}
+	resp, err, errCode := hp.do(ctx, http.MethodHead, baseURL)
+	if err != nil {
+		if errCode == http.StatusNotFound {
+			err = cmn.NewErrorRemoteBucketDoesNotExist(cloudBck, hp.t.Snode().Name())
+		}
+		return
+	}
+	resp.Body.Close()
+	bckProps = make(cmn.SimpleKVs, 2)
+	bckProps[cmn.HeaderCloudProvider] = cmn.ProviderHTTP
+	// version is the ETag or the last modification time
+	bckProps[cmn.HeaderBucketVerEnabled] = "true"
+	return
+}
+
+//////////////////
+// BUCKET NAMES //
+//////////////////
+
+func (hp *httpProvider) ListBuckets(_ context.Context, _ cmn.QueryBcks) (buckets cmn.BucketNames, err error, errCode int) {
+	conf, _ := cmn.GCO.Get().Cloud.ProviderConf(cmn.ProviderHTTP)
+	httpConf, _ := conf.(cmn.CloudConfHTTP)
+	buckets = make(cmn.BucketNames, 0, len(httpConf))
+	for name := range httpConf {
+		buckets = append(buckets, cmn.Bck{Name: name, Provider: cmn.ProviderHTTP})
+	}
+	return
+}
+
+/////////////////
+// HEAD OBJECT //
+/////////////////
+
+func (hp *httpProvider) HeadObj(ctx context.Context, lom *cluster.LOM) (objMeta cmn.SimpleKVs, err error, errCode int) {
+	cloudBck := lom.Bck().CloudBck()
+	baseURL, err, errCode := hp.baseURL(cloudBck)
+	if err != nil {
+		return
+	}
+	resp, err, errCode := hp.do(ctx, http.MethodHead, baseURL+escapePath(lom.ObjName))
+	if err != nil {
+		return
+	}
+	resp.Body.Close()
+	objMeta = make(cmn.SimpleKVs, 3)
+	objMeta[cmn.HeaderCloudProvider] = cmn.ProviderHTTP
+	if resp.ContentLength >= 0 {
+		objMeta[cmn.HeaderObjSize] = strconv.FormatInt(resp.ContentLength, 10)
+	}
+	if v := httpVersion(resp.Header); v != "" {
+		objMeta[cmn.HeaderObjVersion] = v
+	}
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("[head_object] %s/%s", cloudBck, lom.ObjName)
+	}
+	return
+}
+
+////////////////
+// GET OBJECT //
+////////////////
+
+func (hp *httpProvider) GetObj(ctx context.Context, workFQN string, lom *cluster.LOM) (err error, errCode int) {
+	cloudBck := lom.Bck().CloudBck()
+	baseURL, err, errCode := hp.baseURL(cloudBck)
+	if err != nil {
+		return
+	}
+	resp, err, errCode := hp.do(ctx, http.MethodGet, baseURL+escapePath(lom.ObjName))
+	if err != nil {
+		return
+	}
+	customMD := cmn.SimpleKVs{cluster.SourceObjMD: cluster.SourceHTTPObjMD}
+	if v := httpVersion(resp.Header); v != "" {
+		lom.SetVersion(v)
+		customMD[cluster.VersionObjMD] = v
+	}
+	lom.SetCustomMD(customMD)
+	if resp.ContentLength >= 0 {
+		setSize(ctx, resp.ContentLength)
+	}
+	err = hp.t.PutObject(cluster.PutObjectParams{
+		LOM:          lom,
+		Reader:       wrapReader(ctx, resp.Body),
+		WorkFQN:      workFQN,
+		RecvType:     cluster.ColdGet,
+		WithFinalize: false,
+	})
+	if err != nil {
+		return
+	}
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("[get_object] %s", lom)
+	}
+	return
+}
+
+func (hp *httpProvider) GetObjRange(ctx context.Context, lom *cluster.LOM, offset, length int64) (r io.ReadCloser,
+	attrs cluster.CloudObjAttrs, err error, errCode int) {
+	baseURL, err, errCode := hp.baseURL(lom.Bck().CloudBck())
+	if err != nil {
+		return
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+escapePath(lom.ObjName), nil)
+	if err != nil {
+		return nil, attrs, err, http.StatusBadRequest
+	}
+	req.Header.Set(cmn.HeaderRange, byteRange(offset, length))
+	resp, err, errCode := hp.send(req)
+	if err != nil {
+		return
+	}
+	attrs.Version = httpVersion(resp.Header)
+	attrs.ETag = attrs.Version
+	if resp.StatusCode == http.StatusPartialContent {
+		if attrs.Size, err = contentRangeSize(resp.Header.Get(cmn.HeaderContentRange)); err != nil {
+			resp.Body.Close()
+			return nil, attrs, err, http.StatusBadGateway
+		}
+		return resp.Body, attrs, nil, 0
+	}
+	// the server does not support ranges and sends the entire object
+	if attrs.Size = resp.ContentLength; attrs.Size < 0 {
+		resp.Body.Close()
+		return nil, attrs, fmt.Errorf("%s: unknown size", lom), http.StatusBadGateway
+	}
+	if _, err = io.CopyN(ioutil.Discard, resp.Body, offset); err != nil {
+		resp.Body.Close()
+		return nil, attrs, err, http.StatusBadGateway
+	}
+	r = struct {
+		io.Reader
+		io.Closer
+	}{io.LimitReader(resp.Body, length), resp.Body}
+	return r, attrs, nil, 0
+}
+
+/////////////////////////
+// PUT & DELETE OBJECT //
+/////////////////////////
+
+func (hp *httpProvider) PutObj(_ context.Context, _ io.Reader, lom *cluster.LOM) (version string, err error, errCode int) {
+	err, errCode = errReadOnly(lom)
+	return
+}
+
+func (hp *httpProvider) DeleteObj(_ context.Context, lom *cluster.LOM) (err error, errCode int) {
+	return errReadOnly(lom)
+}
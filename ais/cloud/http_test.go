@@ -0,0 +1,181 @@
+// Package cloud contains implementation of various cloud providers.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/tutils/tassert"
+)
+
+// directory indexes read by the server
+type indexReads struct {
+	mtx   sync.Mutex
+	paths []string
+}
+
+func (ir *indexReads) add(path string) {
+	ir.mtx.Lock()
+	ir.paths = append(ir.paths, path)
+	ir.mtx.Unlock()
+}
+
+// reset returns the indexes read so far
+func (ir *indexReads) reset() (paths []string) {
+	ir.mtx.Lock()
+	paths, ir.paths = ir.paths, nil
+	ir.mtx.Unlock()
+	return
+}
+
+// nginx-like autoindex of a given directory tree
+func newIndexServer(tree map[string][]string, reads *indexReads) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/") {
+			w.Header().Set("ETag", `"`+r.URL.Path+`"`)
+			w.Header().Set("Content-Length", fmt.Sprintf("%d", len(r.URL.Path)))
+			return
+		}
+		reads.add(r.URL.Path)
+		links, ok := tree[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprintf(w, "<html><body><h1>Index of %s</h1><a href=\"?C=N;O=D\">Name</a><a href=\"../\">../</a>", r.URL.Path)
+		for _, link := range links {
+			fmt.Fprintf(w, "<a href=\"%s\">%s</a>\n", link, link)
+		}
+		fmt.Fprint(w, "<a href=\"http://example.com/\">elsewhere</a><a href=\"/absolute\">absolute</a></body></html>")
+	}))
+}
+
+func setHTTPConf(conf cmn.CloudConfHTTP) {
+	config := cmn.GCO.BeginUpdate()
+	if config.Cloud.Conf == nil {
+		config.Cloud.Conf = make(map[string]interface{})
+	}
+	if conf == nil {
+		delete(config.Cloud.Conf, cmn.ProviderHTTP)
+	} else {
+		config.Cloud.Conf[cmn.ProviderHTTP] = conf
+	}
+	cmn.GCO.CommitUpdate(config)
+}
+
+func TestHTTPParseIndex(t *testing.T) {
+	index := `<a href="../">../</a><a href="a.txt">a.txt</a><a href='dir/'>dir/</a><a href="./b%20c.txt">b</a>
+		<a href="a.txt">again</a><a href="?C=M;O=A">sort</a><a href="#top">top</a><a href="x/y.txt">nested</a>
+		<a href="https://example.com/z">z</a><a href="/root/">root</a>`
+	links := parseIndex([]byte(index))
+	expected := []string{"a.txt", "dir/", "b c.txt"}
+	tassert.Errorf(t, reflect.DeepEqual(links, expected), "expected %v, got %v", expected, links)
+}
+
+func TestHTTPListObjects(t *testing.T) {
+	reads := &indexReads{}
+	srv := newIndexServer(map[string][]string{
+		"/data/":          {"b.txt", "a.txt", "sub/", "zzz/"},
+		"/data/sub/":      {"c.txt", "deep/"},
+		"/data/sub/deep/": {"d.txt"},
+		"/data/zzz/":      {"e.txt"},
+	}, reads)
+	defer srv.Close()
+	setHTTPConf(cmn.CloudConfHTTP{"legacy": srv.URL + "/data"})
+	defer setHTTPConf(nil)
+
+	var (
+		hp, _ = NewHTTP(nil)
+		bck   = cluster.NewBck("legacy", cmn.ProviderHTTP, cmn.NsGlobal)
+		ctx   = context.Background()
+	)
+	tests := []struct {
+		prefix   string
+		expected []string
+	}{
+		{"", []string{"a.txt", "b.txt", "sub/c.txt", "sub/deep/d.txt", "zzz/e.txt"}},
+		{"sub/", []string{"sub/c.txt", "sub/deep/d.txt"}},
+		{"sub/de", []string{"sub/deep/d.txt"}},
+		{"nothing", nil},
+	}
+	for _, test := range tests {
+		t.Run("prefix="+test.prefix, func(t *testing.T) {
+			bckList, err, _ := hp.ListObjects(ctx, bck, &cmn.SelectMsg{Prefix: test.prefix})
+			tassert.CheckFatal(t, err)
+			var names []string
+			for _, entry := range bckList.Entries {
+				names = append(names, entry.Name)
+			}
+			tassert.Errorf(t, reflect.DeepEqual(names, test.expected), "expected %v, got %v", test.expected, names)
+			tassert.Errorf(t, bckList.PageMarker == "", "unexpected page marker %q", bckList.PageMarker)
+		})
+	}
+
+	t.Run("pages", func(t *testing.T) {
+		var (
+			names []string
+			msg   = &cmn.SelectMsg{PageSize: 2, Props: cmn.GetPropsSize + "," + cmn.GetPropsVersion}
+		)
+		for {
+			bckList, err, _ := hp.ListObjects(ctx, bck, msg)
+			tassert.CheckFatal(t, err)
+			tassert.Fatalf(t, len(bckList.Entries) <= 2, "page size exceeded: %d", len(bckList.Entries))
+			for _, entry := range bckList.Entries {
+				path := "/data/" + entry.Name
+				tassert.Errorf(t, entry.Size == int64(len(path)), "%s: wrong size %d", entry.Name, entry.Size)
+				tassert.Errorf(t, entry.Version == path, "%s: wrong version %q", entry.Name, entry.Version)
+				names = append(names, entry.Name)
+			}
+			if bckList.PageMarker == "" {
+				break
+			}
+			msg.PageMarker = bckList.PageMarker
+		}
+		tassert.Errorf(t, len(names) == 5, "expected 5 objects, got %v", names)
+	})
+
+	t.Run("page-walk", func(t *testing.T) {
+		tests := []struct {
+			marker   string
+			expected []string
+			reads    []string
+		}{
+			{"", []string{"a.txt", "b.txt"}, []string{"/data/", "/data/sub/"}},
+			{"b.txt", []string{"sub/c.txt", "sub/deep/d.txt"}, []string{"/data/", "/data/sub/", "/data/sub/deep/", "/data/zzz/"}},
+			{"sub/deep/d.txt", []string{"zzz/e.txt"}, []string{"/data/", "/data/sub/", "/data/sub/deep/", "/data/zzz/"}},
+			{"zzz/", []string{"zzz/e.txt"}, []string{"/data/", "/data/zzz/"}},
+		}
+		for _, test := range tests {
+			reads.reset()
+			bckList, err, _ := hp.ListObjects(ctx, bck, &cmn.SelectMsg{PageSize: 2, PageMarker: test.marker})
+			tassert.CheckFatal(t, err)
+			var names []string
+			for _, entry := range bckList.Entries {
+				names = append(names, entry.Name)
+			}
+			tassert.Errorf(t, reflect.DeepEqual(names, test.expected), "marker %q: expected %v, got %v",
+				test.marker, test.expected, names)
+			paths := reads.reset()
+			tassert.Errorf(t, reflect.DeepEqual(paths, test.reads), "marker %q: expected to read %v, got %v",
+				test.marker, test.reads, paths)
+		}
+	})
+
+	t.Run("head-bucket", func(t *testing.T) {
+		props, err, _ := hp.HeadBucket(ctx, bck)
+		tassert.CheckFatal(t, err)
+		tassert.Errorf(t, props[cmn.HeaderCloudProvider] == cmn.ProviderHTTP, "wrong provider %q",
+			props[cmn.HeaderCloudProvider])
+	})
+}
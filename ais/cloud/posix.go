@@ -0,0 +1,388 @@
+// Package cloud contains implementation of various cloud providers.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+// Posix cloud provider: a bucket is backed by a directory - typically, an NFS
+// (or any other shared filesystem) mount that is accessible by every target.
+// The objects are cold-loaded from the directory, PUTs are written through,
+// and the object's version is its modification time.
+
+const (
+	posixTmpSuffix = ".ais-tmp" // objects that are being written
+)
+
+type (
+	posixProvider struct {
+		t cluster.Target
+	}
+)
+
+var (
+	_ cluster.CloudProvider = &posixProvider{}
+)
+
+func NewPosix(t cluster.Target) (cluster.CloudProvider, error) { return &posixProvider{t: t}, nil }
+
+func (pp *posixProvider) Provider() string { return cmn.ProviderPosix }
+
+func posixVersion(finfo os.FileInfo) string { return strconv.FormatInt(finfo.ModTime().UnixNano(), 10) }
+
+// dir returns the configured directory of a given bucket
+func (pp *posixProvider) dir(bck cmn.Bck) (string, error, int) {
+	conf, _ := cmn.GCO.Get().Cloud.ProviderConf(cmn.ProviderPosix)
+	posixConf, _ := conf.(cmn.CloudConfPosix)
+	dir, ok := posixConf[bck.Name]
+	if !ok {
+		return "", cmn.NewErrorRemoteBucketDoesNotExist(bck, pp.node()), http.StatusNotFound
+	}
+	return dir, nil, 0
+}
+
+// path returns the pathname of a given object that must reside inside the bucket's directory
+func (pp *posixProvider) path(bck cmn.Bck, objName string) (string, error, int) {
+	dir, err, errCode := pp.dir(bck)
+	if err != nil {
+		return "", err, errCode
+	}
+	path := filepath.Join(dir, objName)
+	if !strings.HasPrefix(path, dir+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid object name %q (bucket %s)", objName, bck), http.StatusBadRequest
+	}
+	return path, nil, 0
+}
+
+func (pp *posixProvider) node() string {
+	if pp.t == nil {
+		return ""
+	}
+	return pp.t.Snode().Name()
+}
+
+func posixErrorToAISError(err error) (error, int) {
+	if os.IsNotExist(err) {
+		return err, http.StatusNotFound
+	}
+	return err, http.StatusInternalServerError
+}
+
+//////////////////
+// LIST OBJECTS //
+//////////////////
+
+func (pp *posixProvider) ListObjects(_ context.Context, bck *cluster.Bck, msg *cmn.SelectMsg) (bckList *cmn.BucketList, err error, errCode int) {
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("list_bucket %s", bck.Name)
+	}
+	var (
+		cloudBck = bck.CloudBck()
+		entries  = make([]*cmn.BucketEntry, 0, initialBucketListSize)
+	)
+	dir, err, errCode := pp.dir(cloudBck)
+	if err != nil {
+		return
+	}
+	// (the directory itself may be a symlink)
+	if resolved, errEval := filepath.EvalSymlinks(dir); errEval == nil {
+		dir = resolved
+	}
+	err = filepath.Walk(dir, func(path string, finfo os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path != dir {
+				return nil // removed in the meantime
+			}
+			return err
+		}
+		if path == dir {
+			return nil
+		}
+		name := filepath.ToSlash(path[len(dir)+1:])
+		if finfo.IsDir() {
+			// skip the subtrees that do not match the prefix
+			if !strings.HasPrefix(name+"/", msg.Prefix) && !strings.HasPrefix(msg.Prefix, name+"/") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !finfo.Mode().IsRegular() || strings.HasSuffix(name, posixTmpSuffix) || !strings.HasPrefix(name, msg.Prefix) {
+			return nil
+		}
+		entry := &cmn.BucketEntry{Name: name}
+		if strings.Contains(msg.Props, cmn.GetPropsSize) {
+			entry.Size = finfo.Size()
+		}
+		if strings.Contains(msg.Props, cmn.GetPropsVersion) {
+			entry.Version = posixVersion(finfo)
+		}
+		entries = append(entries, entry)
+		return nil
+	})
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, cmn.NewErrorRemoteBucketDoesNotExist(cloudBck, pp.node()), http.StatusNotFound
+		}
+		err, errCode = posixErrorToAISError(err)
+		return
+	}
+	bckList = pageEntries(entries, msg)
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("[list_bucket] count %d", len(bckList.Entries))
+	}
+	return
+}
+
+func (pp *posixProvider) HeadBucket(_ context.Context, bck *cluster.Bck) (bckProps cmn.SimpleKVs, err error, errCode int) {
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("head_bucket %s", bck.Name)
+	}
+	cloudBck := bck.CloudBck()
+	dir, err, errCode := pp.dir(cloudBck)
+	if err != nil {
+		return
+	}
+	finfo, err := os.Stat(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, cmn.NewErrorRemoteBucketDoesNotExist(cloudBck, pp.node()), http.StatusNotFound
+		}
+		err, errCode = posixErrorToAISError(err)
+		return
+	}
+	if !finfo.IsDir() {
+		return nil, fmt.Errorf("%s: %q is not a directory", cloudBck, dir), http.StatusBadRequest
+	}
+	bckProps = make(cmn.SimpleKVs, 2)
+	bckProps[cmn.HeaderCloudProvider] = cmn.ProviderPosix
+	// version is the modification time
+	bckProps[cmn.HeaderBucketVerEnabled] = "true"
+	return
+}
+
+//////////////////
+// BUCKET NAMES //
+//////////////////
+
+func (pp *posixProvider) ListBuckets(_ context.Context, _ cmn.QueryBcks) (buckets cmn.BucketNames, err error, errCode int) {
+	conf, _ := cmn.GCO.Get().Cloud.ProviderConf(cmn.ProviderPosix)
+	posixConf, _ := conf.(cmn.CloudConfPosix)
+	buckets = make(cmn.BucketNames, 0, len(posixConf))
+	for name := range posixConf {
+		buckets = append(buckets, cmn.Bck{Name: name, Provider: cmn.ProviderPosix})
+	}
+	return
+}
+
+/////////////////
+// HEAD OBJECT //
+/////////////////
+
+func (pp *posixProvider) HeadObj(_ context.Context, lom *cluster.LOM) (objMeta cmn.SimpleKVs, err error, errCode int) {
+	cloudBck := lom.Bck().CloudBck()
+	if objMeta, err, errCode = pp.headObj(cloudBck, lom.ObjName); err != nil {
+		return
+	}
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("[head_object] %s/%s", cloudBck, lom.ObjName)
+	}
+	return
+}
+
+func (pp *posixProvider) headObj(bck cmn.Bck, objName string) (objMeta cmn.SimpleKVs, err error, errCode int) {
+	path, err, errCode := pp.path(bck, objName)
+	if err != nil {
+		return
+	}
+	finfo, err := os.Stat(path)
+	if err != nil {
+		err, errCode = posixErrorToAISError(err)
+		return
+	}
+	if !finfo.Mode().IsRegular() {
+		return nil, fmt.Errorf("%s/%s is not a file", bck, objName), http.StatusNotFound
+	}
+	objMeta = make(cmn.SimpleKVs, 3)
+	objMeta[cmn.HeaderCloudProvider] = cmn.ProviderPosix
+	objMeta[cmn.HeaderObjSize] = strconv.FormatInt(finfo.Size(), 10)
+	objMeta[cmn.HeaderObjVersion] = posixVersion(finfo)
+	return
+}
+
+////////////////
+// GET OBJECT //
+////////////////
+
+func (pp *posixProvider) GetObj(ctx context.Context, workFQN string, lom *cluster.LOM) (err error, errCode int) {
+	path, err, errCode := pp.path(lom.Bck().CloudBck(), lom.ObjName)
+	if err != nil {
+		return
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		err, errCode = posixErrorToAISError(err)
+		return
+	}
+	finfo, err := file.Stat()
+	if err != nil {
+		file.Close()
+		err, errCode = posixErrorToAISError(err)
+		return
+	}
+	if !finfo.Mode().IsRegular() {
+		file.Close()
+		return fmt.Errorf("%s: %q is not a file", lom, path), http.StatusNotFound
+	}
+	version := posixVersion(finfo)
+	lom.SetVersion(version)
+	lom.SetCustomMD(cmn.SimpleKVs{
+		cluster.SourceObjMD:  cluster.SourcePosixObjMD,
+		cluster.VersionObjMD: version,
+	})
+	setSize(ctx, finfo.Size())
+	err = pp.t.PutObject(cluster.PutObjectParams{
+		LOM:          lom,
+		Reader:       wrapReader(ctx, file),
+		WorkFQN:      workFQN,
+		RecvType:     cluster.ColdGet,
+		WithFinalize: false,
+	})
+	if err != nil {
+		return
+	}
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("[get_object] %s", lom)
+	}
+	return
+}
+
+func (pp *posixProvider) GetObjRange(_ context.Context, lom *cluster.LOM, offset, length int64) (r io.ReadCloser,
+	attrs cluster.CloudObjAttrs, err error, errCode int) {
+	if r, attrs, err, errCode = pp.getObjRange(lom.Bck().CloudBck(), lom.ObjName, offset, length); err != nil {
+		return
+	}
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("[get_object_range] %s [%d, %d)", lom, offset, offset+length)
+	}
+	return
+}
+
+func (pp *posixProvider) getObjRange(bck cmn.Bck, objName string, offset, length int64) (r io.ReadCloser,
+	attrs cluster.CloudObjAttrs, err error, errCode int) {
+	path, err, errCode := pp.path(bck, objName)
+	if err != nil {
+		return
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		err, errCode = posixErrorToAISError(err)
+		return
+	}
+	finfo, err := file.Stat()
+	if err != nil {
+		file.Close()
+		err, errCode = posixErrorToAISError(err)
+		return
+	}
+	if !finfo.Mode().IsRegular() {
+		file.Close()
+		return nil, attrs, fmt.Errorf("%s/%s: %q is not a file", bck, objName, path), http.StatusNotFound
+	}
+	attrs.Size = finfo.Size()
+	attrs.Version = posixVersion(finfo)
+	attrs.ETag = attrs.Version
+	r = struct {
+		io.Reader
+		io.Closer
+	}{io.NewSectionReader(file, offset, length), file}
+	return r, attrs, nil, 0
+}
+
+////////////////
+// PUT OBJECT //
+////////////////
+
+func (pp *posixProvider) PutObj(_ context.Context, r io.Reader, lom *cluster.LOM) (version string, err error, errCode int) {
+	if version, err, errCode = pp.putObj(r, lom.Bck().CloudBck(), lom.ObjName); err != nil {
+		return
+	}
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("[put_object] %s, version %s", lom, version)
+	}
+	return
+}
+
+// putObj writes a temporary file and renames it, so that the readers never see a partial object
+func (pp *posixProvider) putObj(r io.Reader, bck cmn.Bck, objName string) (version string, err error, errCode int) {
+	path, err, errCode := pp.path(bck, objName)
+	if err != nil {
+		return
+	}
+	var (
+		file    *os.File
+		tmpPath = filepath.Join(filepath.Dir(path), "."+filepath.Base(path)+"."+cmn.GenUUID()+posixTmpSuffix)
+	)
+	if file, err = cmn.CreateFile(tmpPath); err != nil {
+		err, errCode = posixErrorToAISError(err)
+		return
+	}
+	_, err = io.Copy(file, r)
+	if errClose := file.Close(); err == nil {
+		err = errClose
+	}
+	if err == nil {
+		err = os.Rename(tmpPath, path)
+	}
+	if err != nil {
+		if errRm := os.Remove(tmpPath); errRm != nil && !os.IsNotExist(errRm) {
+			glog.Errorf("failed to remove %q: %v", tmpPath, errRm)
+		}
+		err, errCode = posixErrorToAISError(err)
+		return
+	}
+	finfo, err := os.Stat(path)
+	if err != nil {
+		err, errCode = posixErrorToAISError(err)
+		return
+	}
+	return posixVersion(finfo), nil, 0
+}
+
+///////////////////
+// DELETE OBJECT //
+///////////////////
+
+func (pp *posixProvider) DeleteObj(_ context.Context, lom *cluster.LOM) (err error, errCode int) {
+	if err, errCode = pp.deleteObj(lom.Bck().CloudBck(), lom.ObjName); err != nil {
+		return
+	}
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("[delete_object] %s", lom)
+	}
+	return
+}
+
+func (pp *posixProvider) deleteObj(bck cmn.Bck, objName string) (err error, errCode int) {
+	path, err, errCode := pp.path(bck, objName)
+	if err != nil {
+		return
+	}
+	if err = os.Remove(path); err != nil {
+		err, errCode = posixErrorToAISError(err)
+	}
+	return
+}
@@ -0,0 +1,115 @@
+// Package cloud contains implementation of various cloud providers.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package cloud
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/tutils/tassert"
+)
+
+func setPosixConf(conf cmn.CloudConfPosix) {
+	config := cmn.GCO.BeginUpdate()
+	if config.Cloud.Conf == nil {
+		config.Cloud.Conf = make(map[string]interface{})
+	}
+	if conf == nil {
+		delete(config.Cloud.Conf, cmn.ProviderPosix)
+	} else {
+		config.Cloud.Conf[cmn.ProviderPosix] = conf
+	}
+	cmn.GCO.CommitUpdate(config)
+}
+
+func TestPosixProvider(t *testing.T) {
+	dir, err := ioutil.TempDir("", "posix")
+	tassert.CheckFatal(t, err)
+	defer os.RemoveAll(dir)
+	for _, name := range []string{"b", "a", "sub/c", "sub/deep/d", "sub-e"} {
+		path := filepath.Join(dir, name)
+		tassert.CheckFatal(t, os.MkdirAll(filepath.Dir(path), 0755))
+		tassert.CheckFatal(t, ioutil.WriteFile(path, []byte(name), 0644))
+	}
+	setPosixConf(cmn.CloudConfPosix{"shared": dir})
+	defer setPosixConf(nil)
+	cmn.InitShortID(0)
+
+	var (
+		p, _   = NewPosix(nil)
+		pp     = p.(*posixProvider)
+		bck    = cluster.NewBck("shared", cmn.ProviderPosix, cmn.NsGlobal)
+		ctx    = context.Background()
+		listFn = func(prefix string) (names []string) {
+			bckList, err, _ := pp.ListObjects(ctx, bck, &cmn.SelectMsg{Prefix: prefix, Props: cmn.GetPropsSize})
+			tassert.CheckFatal(t, err)
+			for _, entry := range bckList.Entries {
+				tassert.Errorf(t, entry.Size == int64(len(entry.Name)), "%s: wrong size %d", entry.Name, entry.Size)
+				names = append(names, entry.Name)
+			}
+			return
+		}
+	)
+
+	t.Run("list", func(t *testing.T) {
+		expected := []string{"a", "b", "sub-e", "sub/c", "sub/deep/d"}
+		names := listFn("")
+		tassert.Errorf(t, reflect.DeepEqual(names, expected), "expected %v, got %v", expected, names)
+		expected = []string{"sub/c", "sub/deep/d"}
+		names = listFn("sub/")
+		tassert.Errorf(t, reflect.DeepEqual(names, expected), "expected %v, got %v", expected, names)
+	})
+
+	t.Run("put-head-delete", func(t *testing.T) {
+		data := []byte("new object")
+		version, err, _ := pp.putObj(bytes.NewReader(data), bck.Bck, "new/obj")
+		tassert.CheckFatal(t, err)
+		b, err := ioutil.ReadFile(filepath.Join(dir, "new/obj"))
+		tassert.CheckFatal(t, err)
+		tassert.Errorf(t, bytes.Equal(b, data), "written object differs")
+
+		objMeta, err, _ := pp.headObj(bck.Bck, "new/obj")
+		tassert.CheckFatal(t, err)
+		tassert.Errorf(t, objMeta[cmn.HeaderObjVersion] == version, "expected version %q, got %q",
+			version, objMeta[cmn.HeaderObjVersion])
+		tassert.Errorf(t, objMeta[cmn.HeaderObjSize] == "10", "wrong size %q", objMeta[cmn.HeaderObjSize])
+
+		err, _ = pp.deleteObj(bck.Bck, "new/obj")
+		tassert.CheckFatal(t, err)
+		_, err, errCode := pp.headObj(bck.Bck, "new/obj")
+		tassert.Errorf(t, errCode == http.StatusNotFound, "expected %d, got %d (%v)", http.StatusNotFound, errCode, err)
+	})
+
+	t.Run("get-range", func(t *testing.T) {
+		r, attrs, err, _ := pp.getObjRange(bck.Bck, "sub/deep/d", 4, 3)
+		tassert.CheckFatal(t, err)
+		b, err := ioutil.ReadAll(r)
+		r.Close()
+		tassert.CheckFatal(t, err)
+		tassert.Errorf(t, string(b) == "dee", "expected %q, got %q", "dee", b)
+		tassert.Errorf(t, attrs.Size == int64(len("sub/deep/d")), "wrong size %d", attrs.Size)
+		tassert.Errorf(t, attrs.ETag != "" && attrs.ETag == attrs.Version, "wrong etag %q", attrs.ETag)
+	})
+
+	t.Run("outside-bucket", func(t *testing.T) {
+		_, err, errCode := pp.putObj(bytes.NewReader(nil), bck.Bck, "../escaped")
+		tassert.Errorf(t, err != nil && errCode == http.StatusBadRequest, "expected object name to be rejected")
+	})
+
+	t.Run("head-bucket", func(t *testing.T) {
+		_, err, _ := pp.HeadBucket(ctx, bck)
+		tassert.CheckFatal(t, err)
+		_, err, errCode := pp.HeadBucket(ctx, cluster.NewBck("unknown", cmn.ProviderPosix, cmn.NsGlobal))
+		tassert.Errorf(t, errCode == http.StatusNotFound, "expected %d, got %d (%v)", http.StatusNotFound, errCode, err)
+	})
+}
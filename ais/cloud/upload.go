@@ -0,0 +1,127 @@
+// Package cloud contains implementation of various cloud providers.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package cloud
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+// Uploading large objects in parts (see cmn.CloudUploadConf): the object is
+// split into parts that are uploaded concurrently and then committed. A failed
+// part is retried a few times (as opposed to restarting the entire upload).
+
+const (
+	uploadPartRetries = 3
+	uploadRetryIval   = time.Second
+)
+
+type (
+	// provider-specific upload of a given object in parts
+	partUploader interface {
+		// uploadPart uploads a part numbered from 1
+		uploadPart(ctx context.Context, num int, r io.ReadSeeker, size int64) error
+		// complete commits parts 1 through n
+		complete(ctx context.Context, n int) (version string, err error)
+		abort(ctx context.Context)
+	}
+)
+
+// uploadInParts returns the upload configuration if the object must be uploaded in parts
+func uploadInParts(provider string, lom *cluster.LOM) (conf cmn.CloudUploadConf, ok bool) {
+	conf = cmn.GCO.Get().Cloud.UploadConf(provider)
+	return conf, lom.Size() >= conf.Threshold
+}
+
+// uploadParts reads the parts from r - concurrently if r is an io.ReaderAt (e.g., *os.File),
+// sequentially otherwise - and uploads them; the upload is aborted upon failure
+func uploadParts(ctx context.Context, pu partUploader, r io.Reader, size int64,
+	conf cmn.CloudUploadConf) (version string, err error) {
+	var (
+		n            = int((size + conf.PartSize - 1) / conf.PartSize)
+		ra, isRA     = r.(io.ReaderAt)
+		wg           = &sync.WaitGroup{}
+		mtx          sync.Mutex
+		sema         = make(chan struct{}, conf.Parallelism)
+		cctx, cancel = context.WithCancel(ctx)
+	)
+	defer cancel()
+	setErr := func(e error) {
+		mtx.Lock()
+		if err == nil {
+			err = e
+		}
+		mtx.Unlock()
+		cancel()
+	}
+	for num := 1; num <= n && cctx.Err() == nil; num++ {
+		var (
+			pr       io.ReadSeeker
+			off      = int64(num-1) * conf.PartSize
+			partSize = cmn.MinI64(conf.PartSize, size-off)
+		)
+		sema <- struct{}{}
+		if isRA {
+			pr = io.NewSectionReader(ra, off, partSize)
+		} else {
+			buf := make([]byte, partSize)
+			if _, errRead := io.ReadFull(r, buf); errRead != nil {
+				<-sema
+				setErr(errRead)
+				break
+			}
+			pr = bytes.NewReader(buf)
+		}
+		wg.Add(1)
+		go func(num int, pr io.ReadSeeker, partSize int64) {
+			defer func() {
+				<-sema
+				wg.Done()
+			}()
+			if err := uploadPart(cctx, pu, num, pr, partSize); err != nil {
+				setErr(err)
+			}
+		}(num, pr, partSize)
+	}
+	wg.Wait()
+	if err == nil && ctx.Err() != nil {
+		err = ctx.Err()
+	}
+	if err == nil {
+		version, err = pu.complete(ctx, n)
+	}
+	if err != nil {
+		pu.abort(context.Background())
+	}
+	return
+}
+
+func uploadPart(ctx context.Context, pu partUploader, num int, r io.ReadSeeker, size int64) (err error) {
+	for attempt := 1; attempt <= uploadPartRetries; attempt++ {
+		if _, err = r.Seek(0, io.SeekStart); err != nil {
+			return
+		}
+		if err = pu.uploadPart(ctx, num, r, size); err == nil {
+			return
+		}
+		if attempt == uploadPartRetries {
+			break
+		}
+		glog.Warningf("failed to upload part %d (attempt %d): %v - retrying...", num, attempt, err)
+		select {
+		case <-time.After(uploadRetryIval * time.Duration(attempt)):
+		case <-ctx.Done():
+			return
+		}
+	}
+	return
+}
@@ -0,0 +1,136 @@
+// Package cloud contains implementation of various cloud providers.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package cloud
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/tutils/tassert"
+)
+
+// in-memory partUploader
+type memUploader struct {
+	mtx       sync.Mutex
+	parts     map[int][]byte
+	failures  map[int]int // part number => number of times to fail
+	attempts  int
+	completed []byte
+	aborted   bool
+}
+
+func newMemUploader() *memUploader {
+	return &memUploader{parts: make(map[int][]byte), failures: make(map[int]int)}
+}
+
+func (u *memUploader) uploadPart(_ context.Context, num int, r io.ReadSeeker, size int64) error {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if int64(len(b)) != size {
+		return errors.New("part size mismatch")
+	}
+	u.mtx.Lock()
+	defer u.mtx.Unlock()
+	u.attempts++
+	if u.failures[num] > 0 {
+		u.failures[num]--
+		return errors.New("transient error")
+	}
+	u.parts[num] = b
+	return nil
+}
+
+func (u *memUploader) complete(_ context.Context, n int) (string, error) {
+	buf := &bytes.Buffer{}
+	for num := 1; num <= n; num++ {
+		b, ok := u.parts[num]
+		if !ok {
+			return "", errors.New("missing part")
+		}
+		buf.Write(b)
+	}
+	u.completed = buf.Bytes()
+	return "1", nil
+}
+
+func (u *memUploader) abort(context.Context) { u.aborted = true }
+
+func testUploadConf() cmn.CloudUploadConf {
+	return cmn.CloudUploadConf{Threshold: cmn.KiB, PartSize: cmn.KiB, Parallelism: 3}
+}
+
+func randBytes(size int) []byte {
+	b := make([]byte, size)
+	rand.Read(b)
+	return b
+}
+
+func TestUploadParts(t *testing.T) {
+	tests := []struct {
+		name string
+		size int
+	}{
+		{"single-part", 100},
+		{"exact-parts", 4 * cmn.KiB},
+		{"last-part-smaller", 10*cmn.KiB + 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data := randBytes(test.size)
+			for _, r := range []io.Reader{bytes.NewReader(data), ioutil.NopCloser(bytes.NewReader(data))} {
+				u := newMemUploader()
+				version, err := uploadParts(context.Background(), u, r, int64(len(data)), testUploadConf())
+				tassert.CheckFatal(t, err)
+				tassert.Errorf(t, version == "1", "expected version %q, got %q", "1", version)
+				tassert.Errorf(t, bytes.Equal(u.completed, data), "uploaded object differs (reader %T)", r)
+				tassert.Errorf(t, !u.aborted, "upload must not be aborted")
+			}
+		})
+	}
+}
+
+func TestUploadPartsRetry(t *testing.T) {
+	var (
+		data = randBytes(5 * cmn.KiB)
+		u    = newMemUploader()
+	)
+	u.failures[2] = uploadPartRetries - 1
+	_, err := uploadParts(context.Background(), u, bytes.NewReader(data), int64(len(data)), testUploadConf())
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, bytes.Equal(u.completed, data), "uploaded object differs")
+	tassert.Errorf(t, u.attempts == 5+uploadPartRetries-1, "expected %d attempts, got %d", 5+uploadPartRetries-1, u.attempts)
+}
+
+func TestUploadPartsAbort(t *testing.T) {
+	var (
+		data = randBytes(5 * cmn.KiB)
+		u    = newMemUploader()
+	)
+	u.failures[3] = uploadPartRetries
+	_, err := uploadParts(context.Background(), u, bytes.NewReader(data), int64(len(data)), testUploadConf())
+	tassert.Fatalf(t, err != nil, "expected upload to fail")
+	tassert.Errorf(t, u.aborted, "expected upload to be aborted")
+	tassert.Errorf(t, u.completed == nil, "upload must not be completed")
+}
+
+func TestUploadPartsShortRead(t *testing.T) {
+	var (
+		data = randBytes(3 * cmn.KiB)
+		u    = newMemUploader()
+		r    = ioutil.NopCloser(bytes.NewReader(data))
+	)
+	_, err := uploadParts(context.Background(), u, r, int64(len(data))+cmn.KiB, testUploadConf())
+	tassert.Fatalf(t, err != nil, "expected upload to fail")
+	tassert.Errorf(t, u.aborted, "expected upload to be aborted")
+}
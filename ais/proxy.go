@@ -16,6 +16,7 @@ import (
 	"net/url"
 	"os"
 	"path"
+	"path/filepath"
 	"strings"
 	"sync"
 	"syscall"
@@ -24,11 +25,14 @@ import (
 	"github.com/NVIDIA/aistore/3rdparty/glog"
 	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/debug"
 	"github.com/NVIDIA/aistore/cmn/jsp"
 	"github.com/NVIDIA/aistore/cmn/mono"
+	"github.com/NVIDIA/aistore/dbdriver"
 	"github.com/NVIDIA/aistore/dsort"
 	"github.com/NVIDIA/aistore/hk"
 	"github.com/NVIDIA/aistore/memsys"
+	"github.com/NVIDIA/aistore/query"
 	"github.com/NVIDIA/aistore/stats"
 	"github.com/NVIDIA/aistore/sys"
 	"github.com/NVIDIA/aistore/xaction"
@@ -66,7 +70,9 @@ type (
 		rproxy     reverseProxy
 		notifs     notifs
 		jtx        *jtx
+		dbDriver   dbdriver.Driver
 		gmm        *memsys.MMSA // system pagesize-based memory manager and slab allocator
+		ratelim    rateLimiter
 	}
 	remBckAddArgs struct {
 		p        *proxyrunner
@@ -119,15 +125,29 @@ func (p *proxyrunner) Run() error {
 	p.authn = &authManager{
 		tokens:        make(map[string]*cmn.AuthToken),
 		revokedTokens: make(map[string]bool),
+		accessKeys:    make(map[string]*cmn.AuthAccessKey),
 		version:       1,
 	}
 
 	p.rproxy.init()
+	p.ratelim.init()
 	initListObjectsCache(p)
 
 	p.notifs.init(p)
 	p.jtx = newJTX(p)
 
+	// persisted queries (see `restoreQuery`)
+	driver, err := dbdriver.NewBuntDB(filepath.Join(config.Confdir, dbName))
+	if err != nil {
+		glog.Errorf("Failed to initialize DB: %v", err)
+		return err
+	}
+	p.dbDriver = driver
+	defer func() {
+		debug.AssertNoErr(driver.Close())
+	}()
+	hk.Reg(query.PurgeHkName, func() time.Duration { return query.PurgeAbandoned(driver) }, query.PurgeHkInterval)
+
 	//
 	// REST API: register proxy handlers and start listening
 	//
@@ -291,6 +311,9 @@ func (p *proxyrunner) Stop(err error) {
 
 // verb /v1/buckets/
 func (p *proxyrunner) bucketHandler(w http.ResponseWriter, r *http.Request) {
+	if p.rateLimited(w, r, cmn.Version, cmn.Buckets) {
+		return
+	}
 	switch r.Method {
 	case http.MethodGet:
 		p.httpbckget(w, r)
@@ -309,6 +332,9 @@ func (p *proxyrunner) bucketHandler(w http.ResponseWriter, r *http.Request) {
 
 // verb /v1/objects/
 func (p *proxyrunner) objectHandler(w http.ResponseWriter, r *http.Request) {
+	if p.rateLimited(w, r, cmn.Version, cmn.Objects) {
+		return
+	}
 	switch r.Method {
 	case http.MethodGet:
 		p.httpobjget(w, r)
@@ -350,7 +376,30 @@ func (p *proxyrunner) httpbckget(w http.ResponseWriter, r *http.Request) {
 		}
 		p.listBuckets(w, r, cmn.QueryBcks(bck.Bck))
 	default:
-		p.invalmsghdlrf(w, r, "Invalid route /buckets/%s", apiItems[0])
+		query := r.URL.Query()
+		if query.Get(cmn.URLParamWhat) != cmn.GetWhatVersions {
+			p.invalmsghdlrf(w, r, "Invalid route /buckets/%s", apiItems[0])
+			return
+		}
+		bck, err := newBckFromQuery(apiItems[0], query)
+		if err != nil {
+			p.invalmsghdlr(w, r, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err = bck.Init(p.owner.bmd, p.si); err != nil {
+			p.invalmsghdlr(w, r, err.Error())
+			return
+		}
+		if err := p.checkPermissions(r, &bck.Bck, cmn.AccessObjLIST); err != nil {
+			p.invalmsghdlr(w, r, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		entries, err, errCode := p.listBckVersions(bck, query.Get(cmn.URLParamPrefix))
+		if err != nil {
+			p.invalmsghdlr(w, r, err.Error(), errCode)
+			return
+		}
+		p.writeJSON(w, r, entries, "list-bck-versions")
 	}
 }
 
@@ -506,6 +555,10 @@ func (p *proxyrunner) httpobjput(w http.ResponseWriter, r *http.Request) {
 		p.invalmsghdlr(w, r, err.Error(), http.StatusForbidden)
 		return
 	}
+	if _, err = p.checkBypassGovernance(r); err != nil {
+		p.invalmsghdlr(w, r, err.Error(), http.StatusForbidden)
+		return
+	}
 
 	if nodeID == "" {
 		si, err = cluster.HrwTarget(bck.MakeUname(objName), &smap.Smap)
@@ -562,6 +615,10 @@ func (p *proxyrunner) httpobjdelete(w http.ResponseWriter, r *http.Request) {
 		p.invalmsghdlr(w, r, err.Error(), http.StatusForbidden)
 		return
 	}
+	if _, err = p.checkBypassGovernance(r); err != nil {
+		p.invalmsghdlr(w, r, err.Error(), http.StatusForbidden)
+		return
+	}
 	smap := p.owner.smap.get()
 	si, err := cluster.HrwTarget(bck.MakeUname(objName), &smap.Smap)
 	if err != nil {
@@ -616,12 +673,17 @@ func (p *proxyrunner) httpbckdelete(w http.ResponseWriter, r *http.Request) {
 			p.invalmsghdlr(w, r, err.Error(), http.StatusUnauthorized)
 			return
 		}
+		bypass, err := p.checkBypassGovernance(r)
+		if err != nil {
+			p.invalmsghdlr(w, r, err.Error(), http.StatusForbidden)
+			return
+		}
 		if bck.IsRemoteAIS() {
 			if err := p.reverseReqRemote(w, r, &msg, bck.Bck); err != nil {
 				return
 			}
 		}
-		if err := p.destroyBucket(&msg, bck); err != nil {
+		if err := p.destroyBucket(&msg, bck, bypass); err != nil {
 			if _, ok := err.(*cmn.ErrorBucketDoesNotExist); ok { // race
 				glog.Infof("%s: %s already %q-ed, nothing to do", p.si, bck, msg.Action)
 			} else {
@@ -1065,6 +1127,26 @@ func (p *proxyrunner) httpbckpost(w http.ResponseWriter, r *http.Request) {
 		}
 		w.Write([]byte(xactID))
 
+	case cmn.ActSyncBck:
+		if err := p.checkPermissions(r, &bck.Bck, cmn.AccessSYNC); err != nil {
+			p.invalmsghdlr(w, r, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if !bck.IsCloud() {
+			p.invalmsghdlrf(w, r, fmtNotCloud, bucket)
+			return
+		}
+		if err = bck.Allow(cmn.AccessSYNC); err != nil {
+			p.invalmsghdlr(w, r, err.Error(), http.StatusForbidden)
+			return
+		}
+		var xactID string
+		if xactID, err = p.syncBucket(bck, &msg, r.URL.Query()); err != nil {
+			p.invalmsghdlr(w, r, err.Error())
+			return
+		}
+		w.Write([]byte(xactID))
+
 	default:
 		p.invalmsghdlrf(w, r, fmtUnknownAct, msg)
 	}
@@ -1288,6 +1370,10 @@ func (p *proxyrunner) httpobjpost(w http.ResponseWriter, r *http.Request) {
 			p.invalmsghdlrf(w, r, "%q is not supported for erasure-coded buckets: %s", msg.Action, bck)
 			return
 		}
+		if _, err = p.checkBypassGovernance(r); err != nil {
+			p.invalmsghdlr(w, r, err.Error(), http.StatusForbidden)
+			return
+		}
 		p.objRename(w, r, bck)
 		return
 	case cmn.ActPromote:
@@ -1299,8 +1385,26 @@ func (p *proxyrunner) httpobjpost(w http.ResponseWriter, r *http.Request) {
 			p.invalmsghdlr(w, r, err.Error(), http.StatusForbidden)
 			return
 		}
+		if _, err = p.checkBypassGovernance(r); err != nil {
+			p.invalmsghdlr(w, r, err.Error(), http.StatusForbidden)
+			return
+		}
 		p.promoteFQN(w, r, bck, &msg)
 		return
+	case cmn.ActPresignObject:
+		p.presignObj(w, r, bck, &msg)
+		return
+	case cmn.ActLegalHold:
+		if err := p.checkPermissions(r, &bck.Bck, cmn.AccessPATCH); err != nil {
+			p.invalmsghdlr(w, r, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if err = bck.Allow(cmn.AccessPATCH); err != nil {
+			p.invalmsghdlr(w, r, err.Error(), http.StatusForbidden)
+			return
+		}
+		p.objLegalHold(w, r, bck)
+		return
 	default:
 		p.invalmsghdlrf(w, r, fmtUnknownAct, msg)
 	}
@@ -1705,6 +1809,18 @@ func (p *proxyrunner) listAISBucket(bck *cluster.Bck, smsg cmn.SelectMsg) (allEn
 		return nil, nil, errors.New("handle can't be empty")
 	}
 
+	if smsg.Delimiter != "" {
+		list, allOK, err := listCache.nextRollup(smap, smsg, bck, pageSize)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !allOK {
+			return nil, &cmn.InitTaskRespMsg{UUID: smsg.UUID, Handle: smsg.Handle}, nil
+		}
+		list.Handle = smsg.Handle
+		return list, nil, nil
+	}
+
 	fetchResult := listCache.next(smap, smsg, bck, pageSize)
 	if fetchResult.err != nil {
 		return nil, nil, fetchResult.err
@@ -1733,6 +1849,57 @@ func (p *proxyrunner) listObjectsRemote(bck *cluster.Bck, smsg cmn.SelectMsg) (
 		glog.Warningf("list_objects page size %d for bucket %s exceeds the default maximum %d ",
 			smsg.PageSize, bck, cmn.DefaultListPageSize)
 	}
+	allEntries, initRespMsg, status, err = p.listRemotePage(bck, smsg)
+	if err != nil || allEntries == nil || smsg.Delimiter == "" {
+		return
+	}
+	err, status = p.refillRemotePage(bck, smsg, allEntries)
+	return
+}
+
+// refillRemotePage appends the next pages to the one that got shorter than
+// requested after rolling up the pseudo-directories (the same way
+// listObjCache.nextRollup does it for ais buckets). Each next page is limited
+// to the remaining size - cloud page markers are not necessarily object names,
+// so the list cannot be cut and continued from an arbitrary entry.
+func (p *proxyrunner) refillRemotePage(bck *cluster.Bck, smsg cmn.SelectMsg, list *cmn.BucketList) (err error, status int) {
+	pageSize := smsg.PageSize
+	if pageSize == 0 {
+		pageSize = cmn.DefaultListPageSize
+	}
+	for list.PageMarker != "" && uint(len(list.Entries)) < pageSize {
+		var (
+			page    *cmn.BucketList
+			initMsg *cmn.InitTaskRespMsg
+			next    = smsg
+		)
+		next.UUID, next.PageMarker = "", list.PageMarker
+		next.PageSize = pageSize - uint(len(list.Entries))
+		for {
+			if page, initMsg, status, err = p.listRemotePage(bck, next); err != nil {
+				return
+			}
+			if page != nil {
+				break
+			}
+			next.UUID = initMsg.UUID
+			time.Sleep(time.Second)
+		}
+		entries := page.Entries
+		// the previous page may have ended inside the same pseudo-directory
+		if len(entries) > 0 && len(list.Entries) > 0 && entries[0].IsDir() &&
+			entries[0].Name == list.Entries[len(list.Entries)-1].Name {
+			entries = entries[1:]
+		}
+		list.Entries = append(list.Entries, entries...)
+		list.PageMarker = page.PageMarker
+	}
+	return
+}
+
+// listRemotePage starts (or polls) the async task that lists a single page
+func (p *proxyrunner) listRemotePage(bck *cluster.Bck, smsg cmn.SelectMsg) (
+	allEntries *cmn.BucketList, initRespMsg *cmn.InitTaskRespMsg, status int, err error) {
 	pageSize := smsg.PageSize
 	if pageSize == 0 {
 		pageSize = cmn.DefaultListPageSize
@@ -1828,12 +1995,16 @@ func (p *proxyrunner) listObjectsRemote(bck *cluster.Bck, smsg cmn.SelectMsg) (
 	} else {
 		allEntries = cmn.MergeObjLists(bckLists, maxSize)
 	}
+	cmn.RollupObjList(allEntries, smsg.Prefix, smsg.Delimiter)
 	if glog.FastV(4, glog.SmoduleAIS) {
 		glog.Errorf("Objects after merge %d, marker %s", len(allEntries.Entries), allEntries.PageMarker)
 	}
 
 	if smsg.WantProp(cmn.GetTargetURL) {
 		for _, e := range allEntries.Entries {
+			if e.IsDir() {
+				continue
+			}
 			si, err := cluster.HrwTarget(bck.MakeUname(e.Name), &smap.Smap)
 			if err == nil {
 				e.TargetURL = si.URL(cmn.NetworkPublic)
@@ -1871,6 +2042,25 @@ func (p *proxyrunner) objRename(w http.ResponseWriter, r *http.Request, bck *clu
 	p.statsT.Add(stats.RenameCount, 1)
 }
 
+func (p *proxyrunner) objLegalHold(w http.ResponseWriter, r *http.Request, bck *cluster.Bck) {
+	started := time.Now()
+	apitems, err := p.checkRESTItems(w, r, 2, false, cmn.Version, cmn.Objects)
+	if err != nil {
+		return
+	}
+	objName := apitems[1]
+	si, err := cluster.HrwTarget(bck.MakeUname(objName), &p.owner.smap.get().Smap)
+	if err != nil {
+		p.invalmsghdlr(w, r, err.Error())
+		return
+	}
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("LEGAL-HOLD %s %s/%s => %s", r.Method, bck.Name, objName, si)
+	}
+	redirectURL := p.redirectURL(r, si, started, cmn.NetworkIntraControl)
+	http.Redirect(w, r, redirectURL, http.StatusTemporaryRedirect)
+}
+
 func (p *proxyrunner) promoteFQN(w http.ResponseWriter, r *http.Request, bck *cluster.Bck, msg *cmn.ActionMsg) {
 	apiItems, err := p.checkRESTItems(w, r, 1, false, cmn.Version, cmn.Objects)
 	if err != nil {
@@ -1910,6 +2100,9 @@ func (p *proxyrunner) promoteFQN(w http.ResponseWriter, r *http.Request, bck *cl
 	// TODO -- FIXME: 2phase begin to check space, validate params, and check vs running xactions
 	//
 	query := cmn.AddBckToQuery(nil, bck.Bck)
+	if isBypassGovernance(r) {
+		query.Set(cmn.URLParamBypassGovernance, "true")
+	}
 	results := p.callTargets(http.MethodPost, cmn.URLPath(cmn.Version, cmn.Objects, bucket), cmn.MustMarshal(msg), query)
 	for res := range results {
 		if res.err != nil {
@@ -1951,6 +2144,33 @@ func (p *proxyrunner) doListRange(method, bucket string, msg *cmn.ActionMsg, que
 	return nil
 }
 
+// syncBucket starts synchronizing cached objects with the cloud on all targets
+func (p *proxyrunner) syncBucket(bck *cluster.Bck, msg *cmn.ActionMsg, query url.Values) (xactID string, err error) {
+	syncMsg := &cmn.SyncBckMsg{}
+	if msg.Value != nil {
+		if err = cmn.MorphMarshal(msg.Value, syncMsg); err != nil {
+			return
+		}
+	}
+	var (
+		smap   = p.owner.smap.get()
+		aisMsg = p.newAisMsg(&cmn.ActionMsg{Action: msg.Action, Value: syncMsg}, smap, nil, cmn.GenUUID())
+		path   = cmn.URLPath(cmn.Version, cmn.Buckets, bck.Name)
+	)
+	results := p.bcastTo(bcastArgs{
+		req:     cmn.ReqArgs{Method: http.MethodPost, Path: path, Query: query, Body: cmn.MustMarshal(aisMsg)},
+		smap:    smap,
+		timeout: cmn.DefaultTimeout,
+	})
+	for res := range results {
+		if res.err != nil {
+			err = fmt.Errorf("%s failed to %s %s: %v (%d: %s)", res.si, msg.Action, bck, res.err, res.status, res.details)
+			return
+		}
+	}
+	return aisMsg.UUID, nil
+}
+
 func (p *proxyrunner) reverseHandler(w http.ResponseWriter, r *http.Request) {
 	apiItems, err := p.checkRESTItems(w, r, 1, false, cmn.Version, cmn.Reverse)
 	if err != nil {
@@ -2462,6 +2682,8 @@ func (p *proxyrunner) tokenHandler(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 	case http.MethodDelete:
 		p.httpTokenDelete(w, r)
+	case http.MethodPut:
+		p.httpTokenPut(w, r)
 	default:
 		cmn.InvalidHandlerWithMsg(w, r, "invalid method for /token path")
 	}
@@ -2549,6 +2771,10 @@ func (p *proxyrunner) httpcluget(w http.ResponseWriter, r *http.Request) {
 		p.queryXaction(w, r, what)
 	case cmn.GetWhatMountpaths:
 		p.queryClusterMountpaths(w, r, what)
+	case cmn.GetWhatReplication:
+		if replStats := p._queryTargets(w, r); replStats != nil {
+			_ = p.writeJSON(w, r, replStats, what)
+		}
 	case cmn.GetWhatRemoteAIS:
 		config := cmn.GCO.Get()
 		smap := p.owner.smap.get()
@@ -2924,7 +3150,7 @@ func (p *proxyrunner) updateAndDistribute(nsi *cluster.Snode, msg *cmn.ActionMsg
 				rmd := p.owner.rmd.get()
 				pairs = append(pairs, revsPair{rmd, aisMsg})
 			}
-			if len(tokens.Tokens) > 0 {
+			if len(tokens.Tokens) > 0 || len(tokens.AccessKeys) > 0 {
 				pairs = append(pairs, revsPair{tokens, aisMsg})
 			}
 			_ = p.metasyncer.sync(pairs...)
@@ -5,11 +5,17 @@
 package ais
 
 import (
+	"crypto/subtle"
 	"errors"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/ais/s3compat"
+	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmn"
 )
 
@@ -33,6 +39,38 @@ func (p *proxyrunner) httpTokenDelete(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// Registers S3 access keys generated by AuthN. The request carries only
+// access keys, each signed by AuthN; a registered key cannot be replaced
+func (p *proxyrunner) httpTokenPut(w http.ResponseWriter, r *http.Request) {
+	tokenList := &TokenList{}
+	if _, err := p.checkRESTItems(w, r, 0, false, cmn.Version, cmn.Tokens); err != nil {
+		return
+	}
+	if p.forwardCP(w, r, &cmn.ActionMsg{Action: cmn.ActAddAccessKey}, "add access key", nil) {
+		return
+	}
+	if err := cmn.ReadJSON(w, r, tokenList); err != nil {
+		return
+	}
+	if len(tokenList.AccessKeys) == 0 || len(tokenList.Tokens) != 0 || tokenList.Version != 0 {
+		p.invalmsghdlr(w, r, "invalid access key list")
+		return
+	}
+	if err := p.authn.checkAccessKeys(tokenList.AccessKeys); err != nil {
+		status := http.StatusConflict
+		if errors.Is(err, errAccessKeySignature) {
+			status = http.StatusUnauthorized
+		}
+		p.invalmsghdlr(w, r, err.Error(), status)
+		return
+	}
+	p.authn.updateRevokedList(tokenList)
+	if p.owner.smap.get().isPrimary(p.si) {
+		msg := p.newAisMsgStr(cmn.ActNewPrimary, nil, nil)
+		_ = p.metasyncer.sync(revsPair{p.authn.revokedTokenList(), msg})
+	}
+}
+
 // TODO: better check for internal request - nid & pid are too insecure
 func (p *proxyrunner) isInternalReq(r *http.Request) bool {
 	pid := r.URL.Query().Get(cmn.URLParamProxyID)
@@ -51,6 +89,9 @@ func (p *proxyrunner) validateToken(r *http.Request) (*cmn.AuthToken, error) {
 	if p.isInternalReq(r) {
 		return nil, nil
 	}
+	if s3compat.IsSigV4(r) {
+		return p.validateSigV4(r)
+	}
 	authToken := r.Header.Get(cmn.HeaderAuthorization)
 	idx := strings.Index(authToken, " ")
 	if idx == -1 || authToken[:idx] != cmn.HeaderBearer {
@@ -66,11 +107,44 @@ func (p *proxyrunner) validateToken(r *http.Request) (*cmn.AuthToken, error) {
 	return auth, nil
 }
 
+// Validates AWS Signature V4 of S3 request (either header or presigned URL)
+// with the secret of the access key, and returns the token the key was
+// issued with. The token defines the permissions of the request.
+func (p *proxyrunner) validateSigV4(r *http.Request) (*cmn.AuthToken, error) {
+	sig, err := s3compat.ParseSigV4(r)
+	if err != nil {
+		glog.Errorf("invalid S3 signature: %v", err)
+		return nil, errInvalidToken
+	}
+	if err := sig.CheckTime(time.Now()); err != nil {
+		glog.Errorf("invalid S3 signature: %v", err)
+		return nil, errInvalidToken
+	}
+	key, err := p.authn.accessKey(sig.AccessKey)
+	if err != nil {
+		glog.Errorf("invalid S3 signature: %v", err)
+		return nil, errInvalidToken
+	}
+	if err := sig.Verify(r, key.Secret); err != nil {
+		glog.Errorf("S3 request with key %s: %v", sig.AccessKey, err)
+		return nil, errInvalidToken
+	}
+	auth, err := p.authn.validateToken(key.Token)
+	if err != nil {
+		glog.Errorf("access key %s: %v", sig.AccessKey, err)
+		return nil, errInvalidToken
+	}
+	return auth, nil
+}
+
 func (p *proxyrunner) checkPermissions(r *http.Request, bck *cmn.Bck, perms cmn.AccessAttrs) error {
 	cfg := cmn.GCO.Get()
 	if !cfg.Auth.Enabled {
 		return nil
 	}
+	if bck != nil && r.URL.Query().Get(cmn.URLParamSignature) != "" {
+		return p.validatePresigned(r, bck, perms)
+	}
 	token, err := p.validateToken(r)
 	if err != nil {
 		return err
@@ -78,3 +152,104 @@ func (p *proxyrunner) checkPermissions(r *http.Request, bck *cmn.Bck, perms cmn.
 	uid := p.owner.smap.Get().UUID
 	return token.CheckPermissions(uid, bck, perms)
 }
+
+// Only admins are allowed to bypass governance-mode object retention
+// (see cmn.ObjLockConf); with authentication disabled, anyone can.
+func (p *proxyrunner) checkBypassGovernance(r *http.Request) (bypass bool, err error) {
+	if !isBypassGovernance(r) {
+		return
+	}
+	if !cmn.GCO.Get().Auth.Enabled {
+		return true, nil
+	}
+	token, err := p.validateToken(r)
+	if err != nil {
+		return
+	}
+	if token == nil || !token.IsAdmin {
+		return false, errors.New("bypassing governance retention requires admin permissions")
+	}
+	return true, nil
+}
+
+// Presigned object URL grants access to a single object with a single
+// HTTP method (GET or PUT) until the URL expires.
+func presignAccess(method string) cmn.AccessAttrs {
+	switch method {
+	case http.MethodGet:
+		return cmn.AccessGET
+	case http.MethodPut:
+		return cmn.AccessPUT
+	default:
+		return 0
+	}
+}
+
+func (p *proxyrunner) validatePresigned(r *http.Request, bck *cmn.Bck, perms cmn.AccessAttrs) error {
+	query := r.URL.Query()
+	expires, err := strconv.ParseInt(query.Get(cmn.URLParamSignExpires), 10, 64)
+	if err != nil {
+		return errInvalidToken
+	}
+	if time.Now().Unix() > expires {
+		return errors.New("presigned URL expired")
+	}
+	allowed := presignAccess(r.Method)
+	if allowed == 0 || !allowed.Has(perms) {
+		return cmn.ErrNoPermissions
+	}
+	signature := cmn.PresignSignature(cmn.GCO.Get().Auth.Secret, r.Method, r.URL.Path, *bck, expires)
+	if subtle.ConstantTimeCompare([]byte(signature), []byte(query.Get(cmn.URLParamSignature))) != 1 {
+		return errInvalidToken
+	}
+	return nil
+}
+
+// POST { presign action } /v1/objects/bucket-name/object-name
+// Returns the path and query of the presigned URL; the caller must have
+// the permission the URL grants.
+func (p *proxyrunner) presignObj(w http.ResponseWriter, r *http.Request, bck *cluster.Bck, msg *cmn.ActionMsg) {
+	if _, err := p.checkRESTItems(w, r, 2, false, cmn.Version, cmn.Objects); err != nil {
+		return
+	}
+	params := cmn.ActValPresign{}
+	if err := cmn.MorphMarshal(msg.Value, &params); err != nil {
+		p.invalmsghdlr(w, r, err.Error())
+		return
+	}
+	perms := presignAccess(params.Method)
+	if perms == 0 {
+		p.invalmsghdlrf(w, r, "cannot presign %q request: only GET and PUT are supported", params.Method)
+		return
+	}
+	if err := p.checkPermissions(r, &bck.Bck, perms); err != nil {
+		p.invalmsghdlr(w, r, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	if err := bck.Allow(int(perms)); err != nil {
+		p.invalmsghdlr(w, r, err.Error(), http.StatusForbidden)
+		return
+	}
+	secret := cmn.GCO.Get().Auth.Secret
+	if secret == "" {
+		p.invalmsghdlr(w, r, "cannot presign request: authentication secret is not set")
+		return
+	}
+	lifetime := time.Duration(params.Expires)
+	if lifetime == 0 {
+		lifetime = cmn.PresignDefaultExpires
+	}
+	if lifetime < 0 || lifetime > cmn.PresignMaxExpires {
+		p.invalmsghdlrf(w, r, "invalid URL lifetime %v (max %v)", lifetime, cmn.PresignMaxExpires)
+		return
+	}
+	var (
+		expires = time.Now().Add(lifetime).Unix()
+		query   = cmn.AddBckToQuery(make(url.Values), bck.Bck)
+	)
+	// the signed URL has the same path as the request itself
+	query.Set(cmn.URLParamSignExpires, strconv.FormatInt(expires, 10))
+	query.Set(cmn.URLParamSignature, cmn.PresignSignature(secret, params.Method, r.URL.Path, bck.Bck, expires))
+	signed := &url.URL{Path: r.URL.Path, RawQuery: query.Encode()}
+	w.Write([]byte(signed.String()))
+}
@@ -174,6 +174,44 @@ func (c *listObjCache) next(smap *cluster.Smap, smsg cmn.SelectMsg, bck *cluster
 	return result
 }
 
+// nextRollup returns the next page of entries rolled up by the delimiter into
+// pseudo-directories. The cache keeps the full list of objects, and rolling
+// up shrinks a page, so the following objects are fetched until the page is
+// full, there are no more objects, or targets have not prepared them yet.
+// The latter returns an incomplete page that can be continued with its marker.
+func (c *listObjCache) nextRollup(smap *cluster.Smap, smsg cmn.SelectMsg, bck *cluster.Bck, pageSize uint) (list *cmn.BucketList, allOK bool, err error) {
+	list = &cmn.BucketList{Entries: make([]*cmn.BucketEntry, 0, pageSize)}
+	for {
+		result := c.next(smap, smsg, bck, pageSize)
+		if result.err != nil {
+			return nil, false, result.err
+		}
+		if !result.allOK {
+			if len(list.Entries) == 0 {
+				return nil, false, nil
+			}
+			break
+		}
+		page := cmn.ConcatObjLists(result.lists, pageSize)
+		cmn.RollupObjList(page, smsg.Prefix, smsg.Delimiter)
+		list.Entries = append(list.Entries, page.Entries...)
+		list.PageMarker = page.PageMarker
+		if page.PageMarker == "" || pageSize == 0 || uint(len(list.Entries)) >= pageSize {
+			break
+		}
+		smsg.PageMarker = page.PageMarker
+	}
+	if pageSize != 0 && uint(len(list.Entries)) > pageSize {
+		list.Entries = list.Entries[:pageSize]
+		last := list.Entries[pageSize-1]
+		list.PageMarker = last.Name
+		if last.IsDir() {
+			list.PageMarker = cmn.DirMarker(last.Name)
+		}
+	}
+	return list, true, nil
+}
+
 func (c *listObjCache) targetEntry(t *cluster.Snode, smsg cmn.SelectMsg, bck *cluster.Bck) *locTarget {
 	id := smsg.ListObjectsCacheID(bck.Bck)
 	c.mtx.Lock()
@@ -5,17 +5,27 @@
 package ais
 
 import (
+	"archive/tar"
 	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
+	"sort"
+	"sync"
 	"time"
 
+	"github.com/NVIDIA/aistore/3rdparty/glog"
 	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/dbdriver"
 	"github.com/NVIDIA/aistore/query"
 	jsoniter "github.com/json-iterator/go"
 )
 
+// serializes restoring persisted queries (see `restoreQuery`)
+var queryRestoreMtx sync.Mutex
+
 type (
 	// TODO: add more, like target finished, query aborted by user etc.
 	queryState struct {
@@ -41,6 +51,28 @@ func newQueryState(smap *cluster.Smap, workersCnt uint) (*queryState, error) {
 	return &queryState{initialSmap: smap, workersCnt: workersCnt, targets: targets}, nil
 }
 
+// restores the state of a persisted query (see `restoreQuery`) making sure that
+// workers keep reading from the same targets
+func restoreQueryState(smap *cluster.Smap, workersCnt uint, tids []string) (*queryState, error) {
+	targets := make([]*cluster.Snode, 0, len(tids))
+	for _, tid := range tids {
+		si := smap.GetTarget(tid)
+		if si == nil {
+			return nil, fmt.Errorf("target %s has left the cluster", tid)
+		}
+		targets = append(targets, si)
+	}
+	return &queryState{initialSmap: smap, workersCnt: workersCnt, targets: targets}, nil
+}
+
+func (q *queryState) targetIDs() []string {
+	tids := make([]string, 0, len(q.targets))
+	for _, si := range q.targets {
+		tids = append(tids, si.ID())
+	}
+	return tids
+}
+
 func (q *queryState) workersTarget(workerID uint) (*cluster.Snode, error) {
 	if q.workersCnt == 0 {
 		return nil, errors.New("query registered with 0 workers")
@@ -55,6 +87,8 @@ func (q *queryState) workersTarget(workerID uint) (*cluster.Snode, error) {
 // - Init(query) -> handle - initializes a query on proxy and targets
 // - Next(handle, n) - returns next n objects from query registered by handle.
 // Objects are returned in sorted order.
+// Plus, Select(query) merges the records selected by all targets into a single
+// tar or list (queries with InnerSelect only).
 
 func (p *proxyrunner) queryHandler(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
@@ -68,10 +102,24 @@ func (p *proxyrunner) queryHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 func (p *proxyrunner) httpquerypost(w http.ResponseWriter, r *http.Request) {
-	if _, err := p.checkRESTItems(w, r, 0, false, cmn.Version, cmn.Query, cmn.Init); err != nil {
+	apiItems, err := p.checkRESTItems(w, r, 1, false, cmn.Version, cmn.Query)
+	if err != nil {
 		return
 	}
 
+	switch apiItems[0] {
+	case cmn.Init:
+		p.httpqueryinit(w, r)
+	case cmn.Select:
+		p.httpqueryselect(w, r)
+	case cmn.Aggregate:
+		p.httpqueryaggregate(w, r)
+	default:
+		p.invalmsghdlrf(w, r, "unknown path /%s/%s/%s", cmn.Version, cmn.Query, apiItems[0])
+	}
+}
+
+func (p *proxyrunner) httpqueryinit(w http.ResponseWriter, r *http.Request) {
 	// A target will return error if given handle already exists (though is very unlikely).
 	handle := cmn.GenUUID()
 	header := http.Header{cmn.HeaderHandle: []string{handle}}
@@ -109,11 +157,186 @@ func (p *proxyrunner) httpquerypost(w http.ResponseWriter, r *http.Request) {
 		p.invalmsghdlr(w, r, err.Error())
 		return
 	}
+	pq := &query.PersistedQuery{Msg: *msg, Targets: state.targetIDs()}
+	if err := query.SavePersisted(p.dbDriver, handle, pq); err != nil {
+		glog.Errorf("%s: failed to persist query %q: %v", p.si, handle, err)
+	}
 	p.jtx.addEntry(handle, state)
 
 	w.Write([]byte(handle))
 }
 
+// restoreQuery re-registers the query persisted by this proxy (if any) when
+// the proxy has restarted and a client resumes with the same handle. Targets
+// resume their parts of the query on their own.
+func (p *proxyrunner) restoreQuery(handle string) {
+	if _, exists := p.jtx.entry(handle); exists {
+		return
+	}
+	queryRestoreMtx.Lock()
+	defer queryRestoreMtx.Unlock()
+	if _, exists := p.jtx.entry(handle); exists {
+		return
+	}
+	pq, err := query.LoadPersisted(p.dbDriver, handle)
+	if err != nil {
+		if !dbdriver.IsErrNotFound(err) {
+			glog.Error(err)
+		}
+		return
+	}
+	state, err := restoreQueryState(&p.owner.smap.get().Smap, pq.Msg.WorkersCnt, pq.Targets)
+	if err != nil {
+		glog.Errorf("%s: failed to restore query %q: %v", p.si, handle, err)
+		query.DeletePersisted(p.dbDriver, handle)
+		return
+	}
+	p.jtx.addEntry(handle, state)
+	glog.Infof("%s: restored query %q", p.si, handle)
+}
+
+// /v1/query/select
+// Unlike objects listing, selected records are not paged: all targets select
+// their records concurrently and stream them back, while the proxy merges the
+// streams - reading them one target at a time.
+func (p *proxyrunner) httpqueryselect(w http.ResponseWriter, r *http.Request) {
+	msg := &query.DefMsg{}
+	if err := cmn.ReadJSON(w, r, msg); err != nil {
+		return
+	}
+	if msg.InnerSelect == nil {
+		p.invalmsghdlr(w, r, "inner select is not defined", http.StatusBadRequest)
+		return
+	}
+	if _, err := query.NewQueryFromMsg(msg); err != nil {
+		p.invalmsghdlr(w, r, "Failed to parse query message: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var (
+		smap  = p.owner.smap.get()
+		body  = cmn.MustMarshal(msg)
+		resps = make([]*http.Response, 0, smap.CountTargets())
+	)
+	defer func() {
+		for _, resp := range resps {
+			resp.Body.Close()
+		}
+	}()
+	for _, si := range smap.Tmap {
+		reqArgs := cmn.ReqArgs{
+			Method: http.MethodPost,
+			Base:   si.URL(cmn.NetworkIntraData),
+			Path:   cmn.URLPath(cmn.Version, cmn.Query, cmn.Select),
+			Body:   body,
+		}
+		req, err := reqArgs.Req()
+		if err != nil {
+			p.invalmsghdlr(w, r, err.Error())
+			return
+		}
+		resp, err := p.httpclientGetPut.Do(req) // nolint:bodyclose // closed in defer
+		if err != nil {
+			p.invalmsghdlrf(w, r, "%s: failed to select records, err: %v", si, err)
+			return
+		}
+		resps = append(resps, resp)
+		if resp.StatusCode >= http.StatusBadRequest {
+			b, _ := ioutil.ReadAll(resp.Body)
+			p.invalmsghdlr(w, r, string(b), resp.StatusCode)
+			return
+		}
+	}
+
+	if msg.InnerSelect.NamesOnly {
+		var entries []*cmn.BucketEntry
+		for _, resp := range resps {
+			var targetEntries []*cmn.BucketEntry
+			if err := jsoniter.NewDecoder(resp.Body).Decode(&targetEntries); err != nil {
+				p.invalmsghdlr(w, r, "failed to unmarshal target select response", http.StatusInternalServerError)
+				return
+			}
+			entries = append(entries, targetEntries...)
+		}
+		sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+		w.Write(cmn.MustMarshal(entries))
+		return
+	}
+
+	tw := tar.NewWriter(w)
+	for _, resp := range resps {
+		if err := copyTarRecords(tw, resp.Body); err != nil {
+			// the response is partially written - the tar remains unterminated
+			glog.Errorf("%s: failed to merge selected records: %v", p.si, err)
+			return
+		}
+	}
+	if err := tw.Close(); err != nil {
+		glog.Errorf("%s: failed to merge selected records: %v", p.si, err)
+	}
+}
+
+// /v1/query/aggregate
+// Each target aggregates its own objects; the proxy merges the results.
+func (p *proxyrunner) httpqueryaggregate(w http.ResponseWriter, r *http.Request) {
+	msg := &query.DefMsg{}
+	if err := cmn.ReadJSON(w, r, msg); err != nil {
+		return
+	}
+	if msg.Aggregate == nil {
+		p.invalmsghdlr(w, r, "aggregate is not defined", http.StatusBadRequest)
+		return
+	}
+	if _, err := query.NewQueryFromMsg(msg); err != nil {
+		p.invalmsghdlr(w, r, "Failed to parse query message: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var (
+		result  = &query.AggregateResult{Groups: make(map[string]*query.AggregateGroup)}
+		results = p.bcastTo(bcastArgs{
+			req: cmn.ReqArgs{
+				Method: http.MethodPost,
+				Path:   cmn.URLPath(cmn.Version, cmn.Query, cmn.Aggregate),
+				Body:   cmn.MustMarshal(msg),
+			},
+			timeout: cmn.LongTimeout,
+			to:      cluster.Targets,
+		})
+	)
+	for res := range results {
+		if res.err != nil {
+			p.invalmsghdlr(w, r, res.err.Error(), res.status)
+			return
+		}
+		targetResult := &query.AggregateResult{}
+		if err := jsoniter.Unmarshal(res.outjson, targetResult); err != nil {
+			p.invalmsghdlrf(w, r, "%s: failed to unmarshal aggregate response, err: %v", res.si, err)
+			return
+		}
+		result.Merge(targetResult)
+	}
+	p.writeJSON(w, r, result, "aggregate")
+}
+
+func copyTarRecords(tw *tar.Writer, r io.Reader) error {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := io.Copy(tw, tr); err != nil {
+			return err
+		}
+	}
+}
+
 func (p *proxyrunner) httpqueryget(w http.ResponseWriter, r *http.Request) {
 	apiItems, err := p.checkRESTItems(w, r, 1, false, cmn.Version, cmn.Query)
 	if err != nil {
@@ -142,6 +365,7 @@ func (p *proxyrunner) httpquerygetworkertarget(w http.ResponseWriter, r *http.Re
 		p.invalmsghdlr(w, r, "handle cannot be empty", http.StatusBadRequest)
 		return
 	}
+	p.restoreQuery(msg.Handle)
 	if redirected := p.jtx.redirectToOwner(w, r, msg.Handle, msg); redirected {
 		return
 	}
@@ -179,6 +403,7 @@ func (p *proxyrunner) httpquerygetnext(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	p.restoreQuery(msg.Handle)
 	if redirected := p.jtx.redirectToOwner(w, r, msg.Handle, msg); redirected {
 		return
 	}
@@ -210,6 +435,7 @@ func (p *proxyrunner) httpquerygetnext(w http.ResponseWriter, r *http.Request) {
 
 	result := cmn.ConcatObjLists(lists, msg.Size)
 	if len(result.Entries) == 0 {
+		query.DeletePersisted(p.dbDriver, msg.Handle)
 		// TODO: Maybe we should just return empty response and `http.StatusNoContent`?
 		p.invalmsghdlrstatusf(w, r, http.StatusGone, "%q finished", msg.Handle)
 		return
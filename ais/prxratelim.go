@@ -0,0 +1,124 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/hk"
+	"github.com/NVIDIA/aistore/stats"
+)
+
+// Request rate limiting (see cmn.RateLimitConf and cmn.BckRateLimitConf):
+// each proxy admits user requests to buckets and objects via token buckets
+// keyed by AuthN user and by bucket; rejected requests get 429 (Too Many Requests).
+
+const (
+	ratelimHkName     = "rate-limit.gc"
+	ratelimHkInterval = 10 * time.Minute
+)
+
+type rateLimiter struct {
+	users map[string]*cmn.TokenBucket
+	bcks  map[string]*cmn.TokenBucket
+	mtx   sync.Mutex
+}
+
+func (rl *rateLimiter) init() {
+	rl.users = make(map[string]*cmn.TokenBucket)
+	rl.bcks = make(map[string]*cmn.TokenBucket)
+	hk.Reg(ratelimHkName, rl.housekeep, ratelimHkInterval)
+}
+
+func (rl *rateLimiter) get(m map[string]*cmn.TokenBucket, key string) *cmn.TokenBucket {
+	rl.mtx.Lock()
+	tb, ok := m[key]
+	if !ok {
+		tb = &cmn.TokenBucket{}
+		m[key] = tb
+	}
+	rl.mtx.Unlock()
+	return tb
+}
+
+// removes idle token buckets (a new bucket starts full - same as idle)
+func (rl *rateLimiter) housekeep() time.Duration {
+	since := time.Now().Add(-ratelimHkInterval)
+	rl.mtx.Lock()
+	for _, m := range []map[string]*cmn.TokenBucket{rl.users, rl.bcks} {
+		for key, tb := range m {
+			if tb.Idle(since) {
+				delete(m, key)
+			}
+		}
+	}
+	rl.mtx.Unlock()
+	return ratelimHkInterval
+}
+
+// rateLimited returns true if the request was rejected (and responded to);
+// `items` are the URL path items that precede the bucket name
+func (p *proxyrunner) rateLimited(w http.ResponseWriter, r *http.Request, items ...string) bool {
+	if p.isInternalReq(r) {
+		return false
+	}
+	var (
+		config = cmn.GCO.Get()
+		conf   = &config.RateLimit
+		now    = time.Now()
+	)
+	if conf.Enabled && conf.UserRate > 0 && config.Auth.Enabled {
+		// NOTE: invalid tokens are rejected later on (see checkPermissions)
+		if token, err := p.validateToken(r); err == nil && token != nil {
+			tb := p.ratelim.get(p.ratelim.users, token.UserID)
+			if ok, retryAfter := tb.Take(now, conf.UserRate, conf.UserBurst); !ok {
+				p.statsT.Add(stats.RateLimUserCount, 1)
+				p.rejectRateLimited(w, r, "user "+token.UserID, retryAfter)
+				return true
+			}
+		}
+	}
+	apiItems, err := cmn.MatchRESTItems(r.URL.Path, 0, true, items...)
+	if err != nil || len(apiItems) == 0 || apiItems[0] == "" {
+		return false
+	}
+	bck, err := newBckFromQuery(apiItems[0], r.URL.Query())
+	if err != nil {
+		return false
+	}
+	rate, burst := conf.BckRate, conf.BckBurst
+	if !conf.Enabled {
+		rate = 0
+	}
+	if err := bck.Init(p.owner.bmd, nil); err == nil && bck.Props.RateLimit.Enabled {
+		rate, burst = bck.Props.RateLimit.Rate, bck.Props.RateLimit.Burst
+	}
+	if rate == 0 {
+		return false
+	}
+	tb := p.ratelim.get(p.ratelim.bcks, bck.MakeUname(""))
+	if ok, retryAfter := tb.Take(now, rate, burst); !ok {
+		p.statsT.Add(stats.RateLimBckCount, 1)
+		p.rejectRateLimited(w, r, "bucket "+bck.String(), retryAfter)
+		return true
+	}
+	return false
+}
+
+func (p *proxyrunner) rejectRateLimited(w http.ResponseWriter, r *http.Request, what string, retryAfter time.Duration) {
+	secs := int64(math.Ceil(retryAfter.Seconds()))
+	if secs < 1 {
+		secs = 1
+	}
+	w.Header().Set(cmn.HeaderRetryAfter, strconv.FormatInt(secs, 10))
+	msg := fmt.Sprintf("%s: %s: request rate limit exceeded, retry after %ds", p.si, what, secs)
+	p.invalmsghdlrsilent(w, r, msg, http.StatusTooManyRequests)
+}
@@ -5,7 +5,6 @@
 package ais
 
 import (
-	"encoding/xml"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -26,10 +25,17 @@ func (p *proxyrunner) s3Handler(w http.ResponseWriter, r *http.Request) {
 	if glog.FastV(4, glog.SmoduleAIS) {
 		glog.Infof("S3Request: %s - %s", r.Method, r.URL)
 	}
+	if p.rateLimited(w, r, cmn.S3) {
+		return
+	}
 	apitems, err := p.checkRESTItems(w, r, 0, true, cmn.S3)
 	if err != nil {
 		return
 	}
+	if _, tagging := r.URL.Query()[s3compat.URLParamTagging]; tagging && len(apitems) > 1 {
+		p.tagObjS3(w, r, apitems)
+		return
+	}
 
 	switch r.Method {
 	case http.MethodHead:
@@ -45,7 +51,7 @@ func (p *proxyrunner) s3Handler(w http.ResponseWriter, r *http.Request) {
 	case http.MethodGet:
 		if len(apitems) == 0 {
 			// nothing  - list all the buckets
-			p.bckNamesToS3(w)
+			p.bckNamesToS3(w, r)
 			return
 		}
 		if len(apitems) == 1 {
@@ -55,6 +61,10 @@ func (p *proxyrunner) s3Handler(w http.ResponseWriter, r *http.Request) {
 				p.getBckVersioningS3(w, r, apitems[0])
 				return
 			}
+			if _, versions := q[s3compat.URLParamVersions]; versions {
+				p.listVersionsS3(w, r, apitems[0])
+				return
+			}
 			// only bucket name - list objects in the bucket
 			p.bckListS3(w, r, apitems[0])
 			return
@@ -78,11 +88,19 @@ func (p *proxyrunner) s3Handler(w http.ResponseWriter, r *http.Request) {
 		}
 		p.putObjS3(w, r, apitems)
 	case http.MethodPost:
+		q := r.URL.Query()
+		if len(apitems) > 1 {
+			_, start := q[s3compat.URLParamMptUploads]
+			_, complete := q[s3compat.URLParamMptUploadID]
+			if start || complete {
+				p.mptObjS3(w, r, apitems)
+				return
+			}
+		}
 		if len(apitems) != 1 {
 			p.invalmsghdlr(w, r, "bucket name expected")
 			return
 		}
-		q := r.URL.Query()
 		if _, multiple := q[s3compat.URLParamMultiDelete]; !multiple {
 			p.invalmsghdlr(w, r, "invalid request")
 			return
@@ -110,7 +128,11 @@ func (p *proxyrunner) s3Handler(w http.ResponseWriter, r *http.Request) {
 }
 
 // GET s3/
-func (p *proxyrunner) bckNamesToS3(w http.ResponseWriter) {
+func (p *proxyrunner) bckNamesToS3(w http.ResponseWriter, r *http.Request) {
+	if err := p.checkPermissions(r, nil, cmn.AccessBckLIST); err != nil {
+		p.invalmsghdlr(w, r, err.Error(), http.StatusUnauthorized)
+		return
+	}
 	bmd := p.owner.bmd.get()
 	query := cmn.QueryBcks{Provider: cmn.ProviderAIS}
 	cp := &query.Provider
@@ -135,6 +157,10 @@ func (p *proxyrunner) putBckS3(w http.ResponseWriter, r *http.Request, bucket st
 		p.invalmsghdlr(w, r, err.Error())
 		return
 	}
+	if err := p.checkPermissions(r, &bck.Bck, cmn.AccessBckCREATE); err != nil {
+		p.invalmsghdlr(w, r, err.Error(), http.StatusUnauthorized)
+		return
+	}
 	msg := cmn.ActionMsg{Action: cmn.ActCreateLB}
 	if p.forwardCP(w, r, &msg, bucket, nil) {
 		return
@@ -157,14 +183,23 @@ func (p *proxyrunner) delBckS3(w http.ResponseWriter, r *http.Request, bucket st
 		p.invalmsghdlr(w, r, err.Error(), http.StatusNotFound)
 		return
 	}
+	if err := p.checkPermissions(r, &bck.Bck, cmn.AccessBckDELETE); err != nil {
+		p.invalmsghdlr(w, r, err.Error(), http.StatusUnauthorized)
+		return
+	}
 	if err := bck.Allow(cmn.AccessBckDELETE); err != nil {
 		p.invalmsghdlr(w, r, err.Error(), http.StatusForbidden)
 		return
 	}
+	bypass, err := p.checkBypassGovernance(r)
+	if err != nil {
+		p.invalmsghdlr(w, r, err.Error(), http.StatusForbidden)
+		return
+	}
 	if p.forwardCP(w, r, &msg, bucket, nil) {
 		return
 	}
-	if err := p.destroyBucket(&msg, bck); err != nil {
+	if err := p.destroyBucket(&msg, bck, bypass); err != nil {
 		errCode := http.StatusInternalServerError
 		if _, ok := err.(*cmn.ErrorBucketAlreadyExists); ok {
 			glog.Infof("%s: %s already %q-ed, nothing to do", p.si, bck, msg.Action)
@@ -185,13 +220,16 @@ func (p *proxyrunner) delMultipleObjs(w http.ResponseWriter, r *http.Request, bu
 		p.invalmsghdlr(w, r, err.Error(), http.StatusNotFound)
 		return
 	}
+	if err := p.checkPermissions(r, &bck.Bck, cmn.AccessObjDELETE); err != nil {
+		p.invalmsghdlr(w, r, err.Error(), http.StatusUnauthorized)
+		return
+	}
 	if err := bck.Allow(cmn.AccessObjDELETE); err != nil {
 		p.invalmsghdlr(w, r, err.Error(), http.StatusForbidden)
 		return
 	}
-	decoder := xml.NewDecoder(r.Body)
 	objList := &s3compat.Delete{}
-	if err := decoder.Decode(objList); err != nil {
+	if err := s3compat.DecodeXML(r.Body, objList); err != nil {
 		p.invalmsghdlr(w, r, err.Error())
 		return
 	}
@@ -224,6 +262,10 @@ func (p *proxyrunner) headBckS3(w http.ResponseWriter, r *http.Request, bucket s
 		p.invalmsghdlr(w, r, err.Error(), http.StatusNotFound)
 		return
 	}
+	if err := p.checkPermissions(r, &bck.Bck, cmn.AccessBckHEAD); err != nil {
+		p.invalmsghdlr(w, r, err.Error(), http.StatusUnauthorized)
+		return
+	}
 	if err := bck.Allow(cmn.AccessBckHEAD); err != nil {
 		p.invalmsghdlr(w, r, err.Error(), http.StatusForbidden)
 		return
@@ -247,6 +289,10 @@ func (p *proxyrunner) bckListS3(w http.ResponseWriter, r *http.Request, bucket s
 		p.invalmsghdlr(w, r, err.Error())
 		return
 	}
+	if err := p.checkPermissions(r, &bck.Bck, cmn.AccessObjLIST); err != nil {
+		p.invalmsghdlr(w, r, err.Error(), http.StatusUnauthorized)
+		return
+	}
 	var (
 		bckList     *cmn.BucketList
 		initRespMsg *cmn.InitTaskRespMsg
@@ -254,7 +300,10 @@ func (p *proxyrunner) bckListS3(w http.ResponseWriter, r *http.Request, bucket s
 	)
 	smsg := cmn.SelectMsg{Fast: false, TimeFormat: time.RFC3339}
 	smsg.AddProps(cmn.GetPropsSize, cmn.GetPropsChecksum, cmn.GetPropsAtime, cmn.GetPropsVersion)
-	s3compat.FillMsgFromS3Query(r.URL.Query(), &smsg)
+	if err := s3compat.FillMsgFromS3Query(r.URL.Query(), &smsg); err != nil {
+		p.invalmsghdlr(w, r, err.Error())
+		return
+	}
 	_, initRespMsg, err = p.listAISBucket(bck, smsg)
 	if err != nil {
 		p.invalmsghdlr(w, r, err.Error())
@@ -276,14 +325,38 @@ func (p *proxyrunner) bckListS3(w http.ResponseWriter, r *http.Request, bucket s
 		smsg.Handle = initRespMsg.Handle
 		time.Sleep(time.Second)
 	}
-	resp := s3compat.NewListObjectResult()
-	resp.PageMarker = smsg.PageMarker
+	resp := s3compat.NewListObjectResult(bucket, r.URL.Query())
 	resp.FillFromAisBckList(bckList)
 	b := resp.MustMarshal()
 	w.Header().Set("Content-Type", s3compat.ContentType)
 	w.Write(b)
 }
 
+// GET s3/bckName?versions
+func (p *proxyrunner) listVersionsS3(w http.ResponseWriter, r *http.Request, bucket string) {
+	bck := cluster.NewBck(bucket, cmn.ProviderAIS, cmn.NsGlobal)
+	if err := bck.Init(p.owner.bmd, p.si); err != nil {
+		p.invalmsghdlr(w, r, err.Error())
+		return
+	}
+	if err := p.checkPermissions(r, &bck.Bck, cmn.AccessObjLIST); err != nil {
+		p.invalmsghdlr(w, r, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	query := r.URL.Query()
+	entries, err, errCode := p.listBckVersions(bck, query.Get(s3compat.URLParamPrefix))
+	if err != nil {
+		p.invalmsghdlr(w, r, err.Error(), errCode)
+		return
+	}
+	resp := s3compat.NewListVersionsResult(bucket, query)
+	for _, entry := range entries {
+		resp.Add(entry)
+	}
+	w.Header().Set("Content-Type", s3compat.ContentType)
+	w.Write(resp.MustMarshal())
+}
+
 // PUT s3/bckName/objName - with HeaderObjSrc in request header - a source
 func (p *proxyrunner) copyObjS3(w http.ResponseWriter, r *http.Request, items []string) {
 	started := time.Now()
@@ -299,6 +372,10 @@ func (p *proxyrunner) copyObjS3(w http.ResponseWriter, r *http.Request, items []
 		p.invalmsghdlr(w, r, err.Error())
 		return
 	}
+	if err := p.checkPermissions(r, &bckSrc.Bck, cmn.AccessGET); err != nil {
+		p.invalmsghdlr(w, r, err.Error(), http.StatusUnauthorized)
+		return
+	}
 	if err := bckSrc.Allow(cmn.AccessGET); err != nil {
 		p.invalmsghdlr(w, r, err.Error(), http.StatusForbidden)
 		return
@@ -308,6 +385,10 @@ func (p *proxyrunner) copyObjS3(w http.ResponseWriter, r *http.Request, items []
 		p.invalmsghdlr(w, r, err.Error())
 		return
 	}
+	if err := p.checkPermissions(r, &bckDst.Bck, cmn.AccessPUT); err != nil {
+		p.invalmsghdlr(w, r, err.Error(), http.StatusUnauthorized)
+		return
+	}
 	if len(items) < 2 {
 		p.invalmsghdlr(w, r, "object name is undefined")
 		return
@@ -351,6 +432,10 @@ func (p *proxyrunner) directPutObjS3(w http.ResponseWriter, r *http.Request, ite
 		p.invalmsghdlr(w, r, err.Error())
 		return
 	}
+	if err := p.checkPermissions(r, &bck.Bck, cmn.AccessPUT); err != nil {
+		p.invalmsghdlr(w, r, err.Error(), http.StatusUnauthorized)
+		return
+	}
 	if len(items) < 2 {
 		p.invalmsghdlr(w, r, "object name is undefined")
 		return
@@ -364,6 +449,10 @@ func (p *proxyrunner) directPutObjS3(w http.ResponseWriter, r *http.Request, ite
 		p.invalmsghdlr(w, r, err.Error(), http.StatusForbidden)
 		return
 	}
+	if _, err = p.checkBypassGovernance(r); err != nil {
+		p.invalmsghdlr(w, r, err.Error(), http.StatusForbidden)
+		return
+	}
 	objName := path.Join(items[1:]...)
 	si, err = cluster.HrwTarget(bck.MakeUname(objName), &smap.Smap)
 	if err != nil {
@@ -386,6 +475,84 @@ func (p *proxyrunner) putObjS3(w http.ResponseWriter, r *http.Request, items []s
 	p.copyObjS3(w, r, items)
 }
 
+// POST s3/bckName/objName?uploads and POST s3/bckName/objName?uploadId=ID
+// Multipart upload requests go to the target that owns the object: all parts
+// are staged there and then assembled into the object.
+func (p *proxyrunner) mptObjS3(w http.ResponseWriter, r *http.Request, items []string) {
+	started := time.Now()
+	bck := cluster.NewBck(items[0], cmn.ProviderAIS, cmn.NsGlobal)
+	if err := bck.Init(p.owner.bmd, nil); err != nil {
+		p.invalmsghdlr(w, r, err.Error())
+		return
+	}
+	if err := p.checkPermissions(r, &bck.Bck, cmn.AccessPUT); err != nil {
+		p.invalmsghdlr(w, r, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	if err := bck.Allow(cmn.AccessPUT); err != nil {
+		p.invalmsghdlr(w, r, err.Error(), http.StatusForbidden)
+		return
+	}
+	var (
+		smap    = p.owner.smap.get()
+		objName = path.Join(items[1:]...)
+	)
+	si, err := cluster.HrwTarget(bck.MakeUname(objName), &smap.Smap)
+	if err != nil {
+		p.invalmsghdlr(w, r, err.Error())
+		return
+	}
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("AISS3: %s %s/%s => %s", r.Method, bck, objName, si)
+	}
+	redirectURL := p.redirectURL(r, si, started, cmn.NetworkIntraData)
+	s3Redirect(w, redirectURL, bck.Name)
+}
+
+// GET | PUT | DEL s3/bckName/objName?tagging
+func (p *proxyrunner) tagObjS3(w http.ResponseWriter, r *http.Request, items []string) {
+	var (
+		access  cmn.AccessAttrs
+		started = time.Now()
+	)
+	switch r.Method {
+	case http.MethodGet:
+		access = cmn.AccessGET
+	case http.MethodPut, http.MethodDelete:
+		access = cmn.AccessPUT
+	default:
+		p.invalmsghdlrf(w, r, "Invalid HTTP Method: %v %s", r.Method, r.URL.Path)
+		return
+	}
+	bck := cluster.NewBck(items[0], cmn.ProviderAIS, cmn.NsGlobal)
+	if err := bck.Init(p.owner.bmd, nil); err != nil {
+		p.invalmsghdlr(w, r, err.Error())
+		return
+	}
+	if err := p.checkPermissions(r, &bck.Bck, access); err != nil {
+		p.invalmsghdlr(w, r, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	if err := bck.Allow(int(access)); err != nil {
+		p.invalmsghdlr(w, r, err.Error(), http.StatusForbidden)
+		return
+	}
+	var (
+		smap    = p.owner.smap.get()
+		objName = path.Join(items[1:]...)
+	)
+	si, err := cluster.HrwTarget(bck.MakeUname(objName), &smap.Smap)
+	if err != nil {
+		p.invalmsghdlr(w, r, err.Error())
+		return
+	}
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("AISS3: %s %s/%s?%s => %s", r.Method, bck, objName, s3compat.URLParamTagging, si)
+	}
+	redirectURL := p.redirectURL(r, si, started, cmn.NetworkIntraControl)
+	s3Redirect(w, redirectURL, bck.Name)
+}
+
 // GET s3/bckName/objName[!tf]
 func (p *proxyrunner) getObjS3(w http.ResponseWriter, r *http.Request, items []string) {
 	started := time.Now()
@@ -394,6 +561,10 @@ func (p *proxyrunner) getObjS3(w http.ResponseWriter, r *http.Request, items []s
 		p.invalmsghdlr(w, r, err.Error())
 		return
 	}
+	if err := p.checkPermissions(r, &bck.Bck, cmn.AccessGET); err != nil {
+		p.invalmsghdlr(w, r, err.Error(), http.StatusUnauthorized)
+		return
+	}
 	if len(items) < 2 {
 		p.invalmsghdlr(w, r, "object name is undefined")
 		return
@@ -433,6 +604,10 @@ func (p *proxyrunner) headObjS3(w http.ResponseWriter, r *http.Request, items []
 		p.invalmsghdlr(w, r, err.Error())
 		return
 	}
+	if err := p.checkPermissions(r, &bck.Bck, cmn.AccessObjHEAD); err != nil {
+		p.invalmsghdlr(w, r, err.Error(), http.StatusUnauthorized)
+		return
+	}
 	if err := bck.Allow(cmn.AccessObjHEAD); err != nil {
 		p.invalmsghdlr(w, r, err.Error(), http.StatusForbidden)
 		return
@@ -458,6 +633,10 @@ func (p *proxyrunner) delObjS3(w http.ResponseWriter, r *http.Request, items []s
 		p.invalmsghdlr(w, r, err.Error())
 		return
 	}
+	if err := p.checkPermissions(r, &bck.Bck, cmn.AccessObjDELETE); err != nil {
+		p.invalmsghdlr(w, r, err.Error(), http.StatusUnauthorized)
+		return
+	}
 	if len(items) < 2 {
 		p.invalmsghdlr(w, r, "object name is undefined")
 		return
@@ -471,6 +650,10 @@ func (p *proxyrunner) delObjS3(w http.ResponseWriter, r *http.Request, items []s
 		p.invalmsghdlr(w, r, err.Error(), http.StatusForbidden)
 		return
 	}
+	if _, err = p.checkBypassGovernance(r); err != nil {
+		p.invalmsghdlr(w, r, err.Error(), http.StatusForbidden)
+		return
+	}
 	objName := path.Join(items[1:]...)
 	si, err = cluster.HrwTarget(bck.MakeUname(objName), &smap.Smap)
 	if err != nil {
@@ -491,6 +674,10 @@ func (p *proxyrunner) getBckVersioningS3(w http.ResponseWriter, r *http.Request,
 		p.invalmsghdlr(w, r, err.Error())
 		return
 	}
+	if err := p.checkPermissions(r, &bck.Bck, cmn.AccessBckHEAD); err != nil {
+		p.invalmsghdlr(w, r, err.Error(), http.StatusUnauthorized)
+		return
+	}
 	props, exists := p.owner.bmd.get().Get(bck)
 	if !exists {
 		p.invalmsghdlr(w, r, "bucket does not exists", http.StatusNotFound)
@@ -513,23 +700,27 @@ func (p *proxyrunner) putBckVersioningS3(w http.ResponseWriter, r *http.Request,
 		p.invalmsghdlr(w, r, err.Error())
 		return
 	}
+	if err := p.checkPermissions(r, &bck.Bck, cmn.AccessPATCH); err != nil {
+		p.invalmsghdlr(w, r, err.Error(), http.StatusUnauthorized)
+		return
+	}
 	_, exists := p.owner.bmd.get().Get(bck)
 	if !exists {
 		p.invalmsghdlr(w, r, "bucket does not exists", http.StatusNotFound)
 		return
 	}
-	decoder := xml.NewDecoder(r.Body)
 	defer func() {
 		debug.AssertNoErr(r.Body.Close())
 	}()
 	vconf := &s3compat.VersioningConfiguration{}
-	if err := decoder.Decode(vconf); err != nil {
+	if err := s3compat.DecodeXML(r.Body, vconf); err != nil {
 		p.invalmsghdlr(w, r, err.Error())
 		return
 	}
+	// S3 versioning implies keeping noncurrent versions
 	enabled := vconf.Enabled()
 	propsToUpdate := cmn.BucketPropsToUpdate{
-		Versioning: &cmn.VersionConfToUpdate{Enabled: &enabled},
+		Versioning: &cmn.VersionConfToUpdate{Enabled: &enabled, History: &enabled},
 	}
 	if err := p.setBucketProps(msg, bck, propsToUpdate); err != nil {
 		p.invalmsghdlr(w, r, err.Error())
@@ -109,7 +109,8 @@ func (p *proxyrunner) createBucket(msg *cmn.ActionMsg, bck *cluster.Bck, cloudHe
 }
 
 // destroy AIS bucket or evict Cloud bucket
-func (p *proxyrunner) destroyBucket(msg *cmn.ActionMsg, bck *cluster.Bck) error {
+// destroy-bucket: { [object lock: begin] -- update locally -- metasync -- [object lock: commit] }
+func (p *proxyrunner) destroyBucket(msg *cmn.ActionMsg, bck *cluster.Bck, bypassGovernance bool) error {
 	nlp := bck.GetNameLockPair()
 
 	// TODO: try-lock
@@ -119,20 +120,64 @@ func (p *proxyrunner) destroyBucket(msg *cmn.ActionMsg, bck *cluster.Bck) error
 	p.owner.bmd.Lock()
 	bmd := p.owner.bmd.get()
 
-	if _, present := bmd.Get(bck); !present {
+	bprops, present := bmd.Get(bck)
+	if !present {
 		p.owner.bmd.Unlock()
 		return cmn.NewErrorBucketDoesNotExist(bck.Bck, p.si.String())
 	}
+	var c *txnClientCtx
+	if bprops.ObjLock.Enabled {
+		// NOTE: targets make sure that the bucket contains no retained objects
+		// and reject PUTs into the bucket until committed
+		p.owner.bmd.Unlock()
+		c = p.prepTxnClient(msg, bck)
+		if bypassGovernance {
+			c.req.Query.Set(cmn.URLParamBypassGovernance, "true")
+		}
+		results := p.bcastPost(bcastArgs{req: c.req, smap: c.smap, timeout: cmn.LongTimeout})
+		for res := range results {
+			if res.err != nil {
+				c.req.Path = cmn.URLPath(c.path, cmn.ActAbort)
+				_ = p.bcastPost(bcastArgs{req: c.req, smap: c.smap})
+				return res.err
+			}
+		}
+		p.owner.bmd.Lock()
+		bmd = p.owner.bmd.get()
+		if _, present = bmd.Get(bck); !present {
+			p.owner.bmd.Unlock()
+			c.req.Path = cmn.URLPath(c.path, cmn.ActAbort)
+			_ = p.bcastPost(bcastArgs{req: c.req, smap: c.smap})
+			return cmn.NewErrorBucketDoesNotExist(bck.Bck, p.si.String())
+		}
+	}
 
 	clone := bmd.clone()
 	deled := clone.del(bck)
 	cmn.Assert(deled)
 	p.owner.bmd.put(clone)
 
-	wg := p.metasyncer.sync(revsPair{clone, p.newAisMsg(msg, nil, clone)})
+	aisMsg := p.newAisMsg(msg, nil, clone)
+	if c != nil {
+		c.msg.BMDVersion = clone.version()
+		aisMsg = c.msg
+	}
+	wg := p.metasyncer.sync(revsPair{clone, aisMsg})
 	p.owner.bmd.Unlock()
 
 	wg.Wait()
+	if c == nil {
+		return nil
+	}
+
+	// commit (the bucket is already destroyed)
+	c.req.Path = cmn.URLPath(c.path, cmn.ActCommit)
+	results := p.bcastPost(bcastArgs{req: c.req, smap: c.smap, timeout: cmn.LongTimeout})
+	for res := range results {
+		if res.err != nil {
+			glog.Error(res.err)
+		}
+	}
 	return nil
 }
 
@@ -264,6 +309,7 @@ func (p *proxyrunner) setBucketProps(msg *cmn.ActionMsg, bck *cluster.Bck,
 		}
 	case cmn.ActResetBprops:
 		nprops = cmn.DefaultBucketProps()
+		nprops.ObjLock = bck.Props.ObjLock // (cannot be reset, see cmn.ObjLockConf)
 	default:
 		cmn.Assert(false)
 	}
@@ -727,6 +773,10 @@ func (p *proxyrunner) makeNprops(bck *cluster.Bck, propsToUpdate cmn.BucketProps
 		}
 		reec = true
 	}
+	if err = bprops.ObjLock.ValidateUpdate(&nprops.ObjLock); err != nil {
+		err = fmt.Errorf("%s: %s: %v", p.si, bck, err)
+		return
+	}
 	if !bprops.Mirror.Enabled && nprops.Mirror.Enabled {
 		if nprops.Mirror.Copies == 1 {
 			nprops.Mirror.Copies = cmn.MaxI64(cfg.Mirror.Copies, 2)
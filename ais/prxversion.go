@@ -0,0 +1,47 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	jsoniter "github.com/json-iterator/go"
+)
+
+// listBckVersions collects current and noncurrent versions of the objects
+// (with a given prefix) from all targets
+func (p *proxyrunner) listBckVersions(bck *cluster.Bck, prefix string) (entries []*cmn.ObjVersionEntry, err error, errCode int) {
+	if !bck.IsAIS() {
+		return nil, fmt.Errorf("%s: listing object versions is supported only for ais buckets", bck), http.StatusBadRequest
+	}
+	query := cmn.AddBckToQuery(url.Values{}, bck.Bck)
+	query.Set(cmn.URLParamWhat, cmn.GetWhatVersions)
+	query.Set(cmn.URLParamPrefix, prefix)
+	results := p.bcastTo(bcastArgs{
+		req: cmn.ReqArgs{
+			Method: http.MethodGet,
+			Path:   cmn.URLPath(cmn.Version, cmn.Buckets, bck.Name),
+			Query:  query,
+		},
+		timeout: cmn.LongTimeout,
+		to:      cluster.Targets,
+	})
+	for res := range results {
+		if res.err != nil {
+			return nil, res.err, res.status
+		}
+		var targetEntries []*cmn.ObjVersionEntry
+		if err = jsoniter.Unmarshal(res.outjson, &targetEntries); err != nil {
+			return nil, fmt.Errorf("%s: failed to unmarshal versions, err: %v", res.si, err), http.StatusInternalServerError
+		}
+		entries = append(entries, targetEntries...)
+	}
+	sortObjVersionEntries(entries)
+	return
+}
@@ -23,14 +23,42 @@ const (
 	versioningEnabled   = "Enabled"
 	versioningDisabled  = "Suspended"
 
+	// object versions
+	URLParamVersions  = "versions"  // list object versions
+	URLParamVersionID = "versionId" // GET, HEAD, or DELETE a given version
+
+	// list objects
+	URLParamListType   = "list-type"
+	URLParamPrefix     = "prefix"
+	URLParamDelimiter  = "delimiter"
+	URLParamMaxKeys    = "max-keys"
+	URLParamMarker     = "marker"             // V1
+	URLParamContToken  = "continuation-token" // V2
+	URLParamStartAfter = "start-after"        // V2
+	listTypeV2         = "2"
+
+	// multipart upload
+	URLParamMptUploads  = "uploads"
+	URLParamMptUploadID = "uploadId"
+	URLParamMptPartNo   = "partNumber"
+
+	// object tagging
+	URLParamTagging = "tagging"
+
 	s3Namespace = "http://s3.amazonaws.com/doc/2006-03-01"
 	// TODO: can it be omitted? // storageClass = "STANDARD"
 
 	// Headers
-	headerETag    = "ETag"
-	headerVersion = "x-amz-version-id"
+	HeaderETag    = "ETag"
+	HeaderVersion = "x-amz-version-id"
 	HeaderObjSrc  = "x-amz-copy-source"
 
+	HeaderBypassGovernance = "x-amz-bypass-governance-retention"
+
+	headerUserMDPrefix = "X-Amz-Meta-" // canonical form
+	headerTagging      = "x-amz-tagging"
+	headerTaggingCount = "x-amz-tagging-count"
+
 	headerAtime = "Last-Modified"
 )
 
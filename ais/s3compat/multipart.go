@@ -0,0 +1,243 @@
+// Package s3compat provides Amazon S3 compatibility layer
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package s3compat
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+type (
+	// Response for CreateMultipartUpload request
+	InitiateMptUploadResult struct {
+		Ns       string `xml:"xmlns,attr"`
+		Bucket   string `xml:"Bucket"`
+		Key      string `xml:"Key"`
+		UploadID string `xml:"UploadId"`
+	}
+
+	// Body of CompleteMultipartUpload request
+	CompleteMptUpload struct {
+		Parts []*PartInfo `xml:"Part"`
+	}
+	// Response for CompleteMultipartUpload request
+	CompleteMptUploadResult struct {
+		Ns     string `xml:"xmlns,attr"`
+		Bucket string `xml:"Bucket"`
+		Key    string `xml:"Key"`
+		ETag   string `xml:"ETag"`
+	}
+
+	// Response for ListParts request
+	ListPartsResult struct {
+		Ns       string      `xml:"xmlns,attr"`
+		Bucket   string      `xml:"Bucket"`
+		Key      string      `xml:"Key"`
+		UploadID string      `xml:"UploadId"`
+		Parts    []*PartInfo `xml:"Part"`
+	}
+	PartInfo struct {
+		PartNumber   int    `xml:"PartNumber"`
+		ETag         string `xml:"ETag"`
+		Size         int64  `xml:"Size,omitempty"`
+		LastModified string `xml:"LastModified,omitempty"`
+	}
+
+	// Uploaded part: stored as a workfile on the target that owns the object
+	MptPart struct {
+		MD5  string // MD5 of the part (S3 ETag)
+		FQN  string // workfile with the content of the part
+		Size int64
+		Num  int
+	}
+	mptUpload struct {
+		bckName string
+		objName string
+		parts   map[int]*MptPart
+		ctime   time.Time
+	}
+	// Registry of all multipart uploads in progress
+	mptUploads struct {
+		mtx sync.Mutex
+		m   map[string]*mptUpload // upload ID => upload
+	}
+)
+
+var Uploads = &mptUploads{m: make(map[string]*mptUpload)}
+
+func NewInitiateMptUploadResult(bucket, key, uploadID string) *InitiateMptUploadResult {
+	return &InitiateMptUploadResult{Ns: s3Namespace, Bucket: bucket, Key: key, UploadID: uploadID}
+}
+
+func NewCompleteMptUploadResult(bucket, key, etag string) *CompleteMptUploadResult {
+	return &CompleteMptUploadResult{Ns: s3Namespace, Bucket: bucket, Key: key, ETag: etag}
+}
+
+func NewListPartsResult(bucket, key, uploadID string) *ListPartsResult {
+	return &ListPartsResult{
+		Ns:       s3Namespace,
+		Bucket:   bucket,
+		Key:      key,
+		UploadID: uploadID,
+		Parts:    make([]*PartInfo, 0),
+	}
+}
+
+func (r *InitiateMptUploadResult) MustMarshal() []byte {
+	b, err := xml.Marshal(r)
+	cmn.AssertNoErr(err)
+	return []byte(xml.Header + string(b))
+}
+
+func (r *CompleteMptUploadResult) MustMarshal() []byte {
+	b, err := xml.Marshal(r)
+	cmn.AssertNoErr(err)
+	return []byte(xml.Header + string(b))
+}
+
+func (r *ListPartsResult) MustMarshal() []byte {
+	b, err := xml.Marshal(r)
+	cmn.AssertNoErr(err)
+	return []byte(xml.Header + string(b))
+}
+
+// Start registers a new multipart upload and returns its ID
+func (u *mptUploads) Start(bckName, objName string) (uploadID string) {
+	uploadID = cmn.GenUUID()
+	u.mtx.Lock()
+	u.m[uploadID] = &mptUpload{
+		bckName: bckName,
+		objName: objName,
+		parts:   make(map[int]*MptPart),
+		ctime:   time.Now(),
+	}
+	u.mtx.Unlock()
+	return
+}
+
+// AddPart registers an uploaded part. Re-uploading a part with the same
+// number replaces the previous one; the function returns the replaced part
+// so that the caller can remove its workfile.
+func (u *mptUploads) AddPart(uploadID string, part *MptPart) (prev *MptPart, err error) {
+	u.mtx.Lock()
+	defer u.mtx.Unlock()
+	upload, ok := u.m[uploadID]
+	if !ok {
+		return nil, fmt.Errorf("upload %q not found", uploadID)
+	}
+	prev = upload.parts[part.Num]
+	upload.parts[part.Num] = part
+	return
+}
+
+// Finish removes the upload from the registry and returns its parts
+// sorted by part number.
+func (u *mptUploads) Finish(uploadID string) (parts []*MptPart, err error) {
+	u.mtx.Lock()
+	upload, ok := u.m[uploadID]
+	if !ok {
+		u.mtx.Unlock()
+		return nil, fmt.Errorf("upload %q not found", uploadID)
+	}
+	delete(u.m, uploadID)
+	u.mtx.Unlock()
+	return upload.sortedParts(), nil
+}
+
+// Parts returns the sorted list of uploaded parts of the upload
+func (u *mptUploads) Parts(uploadID string) (parts []*MptPart, err error) {
+	u.mtx.Lock()
+	defer u.mtx.Unlock()
+	upload, ok := u.m[uploadID]
+	if !ok {
+		return nil, fmt.Errorf("upload %q not found", uploadID)
+	}
+	return upload.sortedParts(), nil
+}
+
+// ObjName returns the bucket and object names the upload was started for
+func (u *mptUploads) ObjName(uploadID string) (bckName, objName string, err error) {
+	u.mtx.Lock()
+	defer u.mtx.Unlock()
+	upload, ok := u.m[uploadID]
+	if !ok {
+		return "", "", fmt.Errorf("upload %q not found", uploadID)
+	}
+	return upload.bckName, upload.objName, nil
+}
+
+// Expired removes and returns all uploads that were started before `deadline`.
+func (u *mptUploads) Expired(deadline time.Time) (parts []*MptPart) {
+	u.mtx.Lock()
+	for id, upload := range u.m {
+		if upload.ctime.Before(deadline) {
+			parts = append(parts, upload.sortedParts()...)
+			delete(u.m, id)
+		}
+	}
+	u.mtx.Unlock()
+	return
+}
+
+func (upload *mptUpload) sortedParts() []*MptPart {
+	parts := make([]*MptPart, 0, len(upload.parts))
+	for _, part := range upload.parts {
+		parts = append(parts, part)
+	}
+	sort.Slice(parts, func(i, j int) bool { return parts[i].Num < parts[j].Num })
+	return parts
+}
+
+// ValidateParts checks that the list of parts from CompleteMultipartUpload
+// request is sorted and matches the parts that were uploaded. Returns the
+// parts to assemble the object from.
+func ValidateParts(uploaded []*MptPart, requested []*PartInfo) ([]*MptPart, error) {
+	if len(requested) == 0 {
+		return nil, fmt.Errorf("empty list of parts")
+	}
+	byNum := make(map[int]*MptPart, len(uploaded))
+	for _, part := range uploaded {
+		byNum[part.Num] = part
+	}
+	parts := make([]*MptPart, 0, len(requested))
+	for idx, req := range requested {
+		if idx > 0 && req.PartNumber <= requested[idx-1].PartNumber {
+			return nil, fmt.Errorf("invalid part order: %d after %d", req.PartNumber, requested[idx-1].PartNumber)
+		}
+		part, ok := byNum[req.PartNumber]
+		if !ok {
+			return nil, fmt.Errorf("part %d not found", req.PartNumber)
+		}
+		if etag := strings.Trim(req.ETag, "\""); etag != "" && etag != part.MD5 {
+			return nil, fmt.Errorf("part %d: ETag mismatch (%s vs %s)", req.PartNumber, etag, part.MD5)
+		}
+		parts = append(parts, part)
+	}
+	return parts, nil
+}
+
+// MptETag calculates ETag of an object assembled from the parts, the same
+// way S3 does it: MD5 of concatenated binary MD5s of all parts followed
+// by a dash and the number of parts.
+func MptETag(parts []*MptPart) string {
+	h := md5.New()
+	for _, part := range parts {
+		b, err := hex.DecodeString(part.MD5)
+		if err != nil {
+			return ""
+		}
+		h.Write(b)
+	}
+	return hex.EncodeToString(h.Sum(nil)) + "-" + strconv.Itoa(len(parts))
+}
@@ -0,0 +1,70 @@
+// Package s3compat provides Amazon S3 compatibility layer
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package s3compat
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"testing"
+
+	"github.com/NVIDIA/aistore/tutils/tassert"
+)
+
+func md5hex(s string) string {
+	sum := md5.Sum([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestValidateParts(t *testing.T) {
+	uploaded := []*MptPart{
+		{Num: 1, MD5: md5hex("one")},
+		{Num: 2, MD5: md5hex("two")},
+		{Num: 5, MD5: md5hex("five")},
+	}
+	tests := []struct {
+		name      string
+		requested []*PartInfo
+		expected  []int // part numbers; nil - error
+	}{
+		{"all", []*PartInfo{{PartNumber: 1}, {PartNumber: 2}, {PartNumber: 5}}, []int{1, 2, 5}},
+		{"subset", []*PartInfo{{PartNumber: 1}, {PartNumber: 5}}, []int{1, 5}},
+		{"quoted-etag", []*PartInfo{{PartNumber: 2, ETag: `"` + md5hex("two") + `"`}}, []int{2}},
+		{"empty", nil, nil},
+		{"unsorted", []*PartInfo{{PartNumber: 2}, {PartNumber: 1}}, nil},
+		{"duplicate", []*PartInfo{{PartNumber: 1}, {PartNumber: 1}}, nil},
+		{"missing", []*PartInfo{{PartNumber: 1}, {PartNumber: 3}}, nil},
+		{"etag-mismatch", []*PartInfo{{PartNumber: 1, ETag: md5hex("two")}}, nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			parts, err := ValidateParts(uploaded, test.requested)
+			if test.expected == nil {
+				tassert.Errorf(t, err != nil, "expected error, got %d parts", len(parts))
+				return
+			}
+			tassert.CheckFatal(t, err)
+			tassert.Fatalf(t, len(parts) == len(test.expected), "expected %d parts, got %d", len(test.expected), len(parts))
+			for i, part := range parts {
+				tassert.Errorf(t, part.Num == test.expected[i], "expected part %d, got %d", test.expected[i], part.Num)
+			}
+		})
+	}
+}
+
+func TestMptETag(t *testing.T) {
+	// MD5 of the concatenated binary MD5s, dash, number of parts
+	var (
+		parts = []*MptPart{{Num: 1, MD5: md5hex("one")}, {Num: 2, MD5: md5hex("two")}}
+		b1, _ = hex.DecodeString(md5hex("one"))
+		b2, _ = hex.DecodeString(md5hex("two"))
+		sum   = md5.Sum(append(b1, b2...))
+	)
+	etag := MptETag(parts)
+	expected := hex.EncodeToString(sum[:]) + "-2"
+	tassert.Errorf(t, etag == expected, "expected %q, got %q", expected, etag)
+
+	etag = MptETag([]*MptPart{{Num: 1, MD5: "not-hex"}})
+	tassert.Errorf(t, etag == "", "expected empty ETag for invalid MD5, got %q", etag)
+}
@@ -5,28 +5,38 @@
 package s3compat
 
 import (
+	"encoding/base64"
 	"encoding/xml"
+	"fmt"
 	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmn"
 )
 
 type (
-	// List objects response
+	// List objects response (both V1 and V2 flavors)
 	ListObjectResult struct {
-		Ns          string     `xml:"xmlns,attr"`
-		Prefix      string     `xml:"Prefix"`
-		KeyCount    int        `xml:"KeyCount"` // number of objects in the response
-		MaxKeys     int        `xml:"MaxKeys"`
-		IsTruncated bool       `xml:"IsTruncated"`           // true if there are more pages to read
-		PageMarker  string     `xml:"ContinuationToken"`     // original PageMarker
-		NextMarker  string     `xml:"NextContinuationToken"` // PageMarker to read the next page
-		Contents    []*ObjInfo `xml:"Contents"`              // list of objects
+		Ns             string          `xml:"xmlns,attr"`
+		Name           string          `xml:"Name"`
+		Prefix         string          `xml:"Prefix"`
+		Delimiter      string          `xml:"Delimiter,omitempty"`
+		KeyCount       int             `xml:"KeyCount"` // number of objects and common prefixes in the response
+		MaxKeys        int             `xml:"MaxKeys"`
+		IsTruncated    bool            `xml:"IsTruncated"`                     // true if there are more pages to read
+		Marker         string          `xml:"Marker,omitempty"`                // V1: original marker
+		NextMarker     string          `xml:"NextMarker,omitempty"`            // V1: marker to read the next page
+		PageMarker     string          `xml:"ContinuationToken,omitempty"`     // V2: original continuation token
+		NextPageMarker string          `xml:"NextContinuationToken,omitempty"` // V2: token to read the next page
+		StartAfter     string          `xml:"StartAfter,omitempty"`            // V2
+		Contents       []*ObjInfo      `xml:"Contents"`                        // list of objects
+		CommonPrefixes []*CommonPrefix `xml:"CommonPrefixes"`                  // list of pseudo-directories
+		v2             bool
 	}
 	ObjInfo struct {
 		Key          string `xml:"Key"`
@@ -35,6 +45,9 @@ type (
 		Size         int64  `xml:"Size"`
 		Class        string `xml:"StorageClass"`
 	}
+	CommonPrefix struct {
+		Prefix string `xml:"Prefix"`
+	}
 
 	// Response for object copy request
 	CopyObjectResult struct {
@@ -43,31 +56,69 @@ type (
 	}
 )
 
-func FillMsgFromS3Query(query url.Values, msg *cmn.SelectMsg) {
-	mxStr := query.Get("max-keys")
+// FillMsgFromS3Query converts ListObjects (V1 and V2) query into SelectMsg.
+// V2 continuation token is opaque for a client: it is an encoded page marker.
+func FillMsgFromS3Query(query url.Values, msg *cmn.SelectMsg) error {
+	mxStr := query.Get(URLParamMaxKeys)
 	if pageSize, err := strconv.Atoi(mxStr); err == nil && pageSize > 0 {
 		msg.PageSize = uint(pageSize)
 	}
-	if prefix := query.Get("prefix"); prefix != "" {
+	if prefix := query.Get(URLParamPrefix); prefix != "" {
 		msg.Prefix = prefix
 	}
-	var marker string
-	if marker = query.Get("continuation-token"); marker != "" {
-		msg.PageMarker = marker
+	msg.Delimiter = query.Get(URLParamDelimiter)
+	if query.Get(URLParamListType) == listTypeV2 {
+		if token := query.Get(URLParamContToken); token != "" {
+			marker, err := base64.RawURLEncoding.DecodeString(token)
+			if err != nil {
+				return fmt.Errorf("invalid continuation token %q", token)
+			}
+			msg.PageMarker = string(marker)
+			return nil
+		}
+		// start-after makes sense only on first call. For the next call,
+		// when continuation-token is set, start-after is ignored
+		msg.PageMarker = query.Get(URLParamStartAfter)
+		return nil
 	}
-	// start-after makes sense only on first call. For the next call,
-	// when continuation-token is set, start-after is ignored
-	if after := query.Get("start-after"); after != "" && marker == "" {
-		msg.PageMarker = after
+	msg.PageMarker = markerToPage(query.Get(URLParamMarker), msg.Prefix, msg.Delimiter)
+	return nil
+}
+
+// markerToPage converts V1 marker into page marker: as in S3, the marker that
+// is a common prefix skips all the objects rolled up into it.
+func markerToPage(marker, prefix, delimiter string) string {
+	if delimiter == "" || !strings.HasPrefix(marker, prefix) {
+		return marker
 	}
+	rest := marker[len(prefix):]
+	if idx := strings.Index(rest, delimiter); idx >= 0 && idx == len(rest)-len(delimiter) {
+		return cmn.DirMarker(marker)
+	}
+	return marker
 }
 
-func NewListObjectResult() *ListObjectResult {
-	return &ListObjectResult{
-		Ns:       s3Namespace,
-		MaxKeys:  1000,
-		Contents: make([]*ObjInfo, 0),
+func NewListObjectResult(bucket string, query url.Values) *ListObjectResult {
+	r := &ListObjectResult{
+		Ns:             s3Namespace,
+		Name:           bucket,
+		Prefix:         query.Get(URLParamPrefix),
+		Delimiter:      query.Get(URLParamDelimiter),
+		MaxKeys:        int(cmn.DefaultListPageSize),
+		Contents:       make([]*ObjInfo, 0),
+		CommonPrefixes: make([]*CommonPrefix, 0),
+		v2:             query.Get(URLParamListType) == listTypeV2,
+	}
+	if mx, err := strconv.Atoi(query.Get(URLParamMaxKeys)); err == nil && mx > 0 {
+		r.MaxKeys = mx
+	}
+	if r.v2 {
+		r.PageMarker = query.Get(URLParamContToken)
+		r.StartAfter = query.Get(URLParamStartAfter)
+	} else {
+		r.Marker = query.Get(URLParamMarker)
 	}
+	return r
 }
 
 func (r *ListObjectResult) MustMarshal() []byte {
@@ -90,12 +141,25 @@ func entryToS3(entry *cmn.BucketEntry) *ObjInfo {
 }
 
 func (r *ListObjectResult) FillFromAisBckList(bckList *cmn.BucketList) {
-	r.KeyCount = len(bckList.Entries)
-	r.IsTruncated = bckList.PageMarker != ""
-	r.NextMarker = bckList.PageMarker
 	for _, e := range bckList.Entries {
+		if e.IsDir() {
+			r.CommonPrefixes = append(r.CommonPrefixes, &CommonPrefix{Prefix: e.Name})
+			continue
+		}
 		r.Add(e)
 	}
+	r.KeyCount = len(bckList.Entries)
+	r.IsTruncated = bckList.PageMarker != ""
+	if !r.IsTruncated {
+		return
+	}
+	if r.v2 {
+		r.NextPageMarker = base64.RawURLEncoding.EncodeToString([]byte(bckList.PageMarker))
+	} else {
+		// the marker after a common prefix is cmn.DirMarker of the prefix -
+		// S3 clients get the prefix itself (see markerToPage)
+		r.NextMarker = strings.TrimSuffix(bckList.PageMarker, string(utf8.MaxRune))
+	}
 }
 
 func FormatTime(t time.Time) string {
@@ -104,15 +168,17 @@ func FormatTime(t time.Time) string {
 }
 
 func SetHeaderFromLOM(header http.Header, lom *cluster.LOM, size int64) {
-	if v, exists := lom.GetCustomMD(cluster.SourceObjMD); exists && v == cluster.SourceAmazonObjMD {
+	// ETag is either from Amazon or calculated by multipart upload (no source)
+	if v, exists := lom.GetCustomMD(cluster.SourceObjMD); !exists || v == cluster.SourceAmazonObjMD {
 		if v, exists := lom.GetCustomMD(cluster.MD5ObjMD); exists {
-			header.Set(headerETag, v)
+			header.Set(HeaderETag, v)
 		}
 	}
 	header.Set(headerAtime, FormatTime(lom.Atime()))
 	header.Set(cmn.HeaderContentLength, strconv.FormatInt(size, 10))
 	header.Set(cmn.HeaderContentType, GetContentType)
-	header.Set(headerVersion, lom.Version())
+	header.Set(HeaderVersion, lom.Version())
+	setUserMDHeader(header, lom)
 }
 
 func (r *CopyObjectResult) MustMarshal() []byte {
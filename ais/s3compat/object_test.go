@@ -0,0 +1,39 @@
+// Package s3compat provides Amazon S3 compatibility layer
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package s3compat
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/tutils/tassert"
+)
+
+func TestListV1Marker(t *testing.T) {
+	// the page ends inside the pseudo-directory "a/b/"
+	list := &cmn.BucketList{
+		Entries:    []*cmn.BucketEntry{{Name: "a/1"}, {Name: "a/b/2"}},
+		PageMarker: "a/b/2",
+	}
+	cmn.RollupObjList(list, "a/", "/")
+	query := url.Values{URLParamPrefix: []string{"a/"}, URLParamDelimiter: []string{"/"}}
+	r := NewListObjectResult("bucket", query)
+	r.FillFromAisBckList(list)
+	tassert.Fatalf(t, r.NextMarker == "a/b/", "expected NextMarker %q, got %q", "a/b/", r.NextMarker)
+
+	// next page skips the whole common prefix
+	query.Set(URLParamMarker, r.NextMarker)
+	msg := &cmn.SelectMsg{}
+	tassert.CheckFatal(t, FillMsgFromS3Query(query, msg))
+	tassert.Errorf(t, msg.PageMarker == cmn.DirMarker("a/b/"), "unexpected page marker %q", msg.PageMarker)
+
+	// object names are taken as is
+	for _, marker := range []string{"a/1", "a/b/2", "b/"} {
+		query.Set(URLParamMarker, marker)
+		tassert.CheckFatal(t, FillMsgFromS3Query(query, msg))
+		tassert.Errorf(t, msg.PageMarker == marker, "expected page marker %q, got %q", marker, msg.PageMarker)
+	}
+}
@@ -0,0 +1,342 @@
+// Package s3compat provides Amazon S3 compatibility layer
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package s3compat
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+// AWS Signature Version 4: both authorization header and presigned URL
+// (query string) flavors. See:
+// https://docs.aws.amazon.com/AmazonS3/latest/API/sig-v4-authenticating-requests.html
+
+const (
+	sigV4Algorithm = "AWS4-HMAC-SHA256"
+	sigV4Request   = "aws4_request"
+	sigV4TimeFmt   = "20060102T150405Z"
+
+	headerAmzDate        = "X-Amz-Date"
+	headerAmzContentHash = "X-Amz-Content-Sha256"
+	unsignedPayload      = "UNSIGNED-PAYLOAD"
+
+	// presigned URL query parameters
+	queryAlgorithm     = "X-Amz-Algorithm"
+	queryCredential    = "X-Amz-Credential"
+	queryDate          = "X-Amz-Date"
+	queryExpires       = "X-Amz-Expires"
+	querySignedHeaders = "X-Amz-SignedHeaders"
+	querySignature     = "X-Amz-Signature"
+
+	// maximum allowed difference between the time of signing and now
+	sigV4MaxSkew = 15 * time.Minute
+	// maximum lifetime of a presigned URL (AWS limit)
+	sigV4MaxExpires = 7 * 24 * time.Hour
+)
+
+type (
+	SigV4 struct {
+		AccessKey     string
+		Date          string // scope date: YYYYMMDD
+		Region        string
+		Service       string
+		SignedHeaders []string
+		Signature     string
+		signedAt      time.Time
+		expires       time.Duration // presigned URL only
+		presigned     bool
+	}
+
+	// hashes the request body while it is read, and fails the last read
+	// if the body does not match the signed payload hash
+	payloadReader struct {
+		r        io.ReadCloser
+		h        hash.Hash
+		expected string
+		size     int64 // -1 when unknown
+		read     int64
+		verified bool
+	}
+)
+
+var (
+	errSigMismatch     = errors.New("signature does not match")
+	errPayloadMismatch = errors.New("request body does not match " + headerAmzContentHash)
+)
+
+// IsSigV4 returns true if the request is signed with AWS Signature V4
+func IsSigV4(r *http.Request) bool {
+	if strings.HasPrefix(r.Header.Get(cmn.HeaderAuthorization), sigV4Algorithm) {
+		return true
+	}
+	return r.URL.Query().Get(queryAlgorithm) == sigV4Algorithm
+}
+
+// ParseSigV4 extracts signature information either from `Authorization`
+// header or from URL query (presigned URL).
+func ParseSigV4(r *http.Request) (*SigV4, error) {
+	var (
+		sig   = &SigV4{}
+		query = r.URL.Query()
+		cred  string
+		date  string
+		err   error
+	)
+	if auth := r.Header.Get(cmn.HeaderAuthorization); auth != "" {
+		// AWS4-HMAC-SHA256 Credential=..., SignedHeaders=..., Signature=...
+		if !strings.HasPrefix(auth, sigV4Algorithm+" ") {
+			return nil, fmt.Errorf("unsupported authorization algorithm")
+		}
+		for _, field := range strings.Split(auth[len(sigV4Algorithm)+1:], ",") {
+			kv := strings.SplitN(strings.TrimSpace(field), "=", 2)
+			if len(kv) != 2 {
+				return nil, fmt.Errorf("invalid authorization field %q", field)
+			}
+			switch kv[0] {
+			case "Credential":
+				cred = kv[1]
+			case "SignedHeaders":
+				sig.SignedHeaders = strings.Split(kv[1], ";")
+			case "Signature":
+				sig.Signature = kv[1]
+			}
+		}
+		date = r.Header.Get(headerAmzDate)
+	} else {
+		if query.Get(queryAlgorithm) != sigV4Algorithm {
+			return nil, fmt.Errorf("unsupported authorization algorithm")
+		}
+		sig.presigned = true
+		cred = query.Get(queryCredential)
+		sig.SignedHeaders = strings.Split(query.Get(querySignedHeaders), ";")
+		sig.Signature = query.Get(querySignature)
+		date = query.Get(queryDate)
+		secs, err := strconv.ParseInt(query.Get(queryExpires), 10, 64)
+		if err != nil || secs <= 0 {
+			return nil, fmt.Errorf("invalid %s value %q", queryExpires, query.Get(queryExpires))
+		}
+		sig.expires = time.Duration(secs) * time.Second
+		if sig.expires > sigV4MaxExpires {
+			return nil, fmt.Errorf("%s exceeds %v", queryExpires, sigV4MaxExpires)
+		}
+	}
+
+	// Credential: <access-key>/<date>/<region>/<service>/aws4_request
+	parts := strings.Split(cred, "/")
+	if len(parts) != 5 || parts[4] != sigV4Request {
+		return nil, fmt.Errorf("invalid credential %q", cred)
+	}
+	sig.AccessKey, sig.Date, sig.Region, sig.Service = parts[0], parts[1], parts[2], parts[3]
+	if sig.Signature == "" || len(sig.SignedHeaders) == 0 {
+		return nil, errors.New("incomplete signature")
+	}
+	if sig.signedAt, err = time.Parse(sigV4TimeFmt, date); err != nil {
+		return nil, fmt.Errorf("invalid request date %q", date)
+	}
+	if sig.signedAt.Format("20060102") != sig.Date {
+		return nil, fmt.Errorf("request date %q does not match credential scope", date)
+	}
+	return sig, nil
+}
+
+// CheckTime validates that the signature is neither stale nor expired
+func (sig *SigV4) CheckTime(now time.Time) error {
+	if sig.presigned {
+		if now.Before(sig.signedAt.Add(-sigV4MaxSkew)) {
+			return errors.New("request is signed in the future")
+		}
+		if now.After(sig.signedAt.Add(sig.expires)) {
+			return errors.New("presigned URL expired")
+		}
+		return nil
+	}
+	skew := now.Sub(sig.signedAt)
+	if skew > sigV4MaxSkew || skew < -sigV4MaxSkew {
+		return fmt.Errorf("request time is too skewed (%v)", skew)
+	}
+	return nil
+}
+
+// Verify recalculates the signature of the request with the given secret
+// key and compares it with the one sent by the client. The signature covers
+// the payload hash, and the body itself is checked against the hash as it
+// is read (see VerifyPayload).
+func (sig *SigV4) Verify(r *http.Request, secret string) error {
+	canonical, err := sig.canonicalRequest(r)
+	if err != nil {
+		return err
+	}
+	var (
+		scope   = strings.Join([]string{sig.Date, sig.Region, sig.Service, sigV4Request}, "/")
+		hash    = sha256.Sum256([]byte(canonical))
+		toSign  = sigV4Algorithm + "\n" + sig.signedAt.Format(sigV4TimeFmt) + "\n" + scope + "\n" + hex.EncodeToString(hash[:])
+		key     = signingKey(secret, sig.Date, sig.Region, sig.Service)
+		calcSig = hex.EncodeToString(hmacSHA256(key, toSign))
+	)
+	if subtle.ConstantTimeCompare([]byte(calcSig), []byte(sig.Signature)) != 1 {
+		return errSigMismatch
+	}
+	VerifyPayload(r)
+	return nil
+}
+
+// VerifyPayload makes the request body check itself against the payload hash
+// sent in X-Amz-Content-Sha256, unless the payload is unsigned. The read that
+// reaches the end of the body fails if the hash does not match, so a handler
+// must consume the whole body before acting on it (see DecodeXML).
+func VerifyPayload(r *http.Request) {
+	expected := r.Header.Get(headerAmzContentHash)
+	if expected == "" || expected == unsignedPayload || r.Body == nil {
+		return
+	}
+	if _, ok := r.Body.(*payloadReader); ok {
+		return
+	}
+	r.Body = &payloadReader{r: r.Body, h: sha256.New(), expected: strings.ToLower(expected), size: r.ContentLength}
+}
+
+// DecodeXML reads the entire body, so that its payload hash gets verified,
+// and only then unmarshals it.
+func DecodeXML(body io.Reader, v interface{}) error {
+	b, err := ioutil.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	return xml.Unmarshal(b, v)
+}
+
+func (pr *payloadReader) Read(p []byte) (n int, err error) {
+	n, err = pr.r.Read(p)
+	pr.h.Write(p[:n])
+	pr.read += int64(n)
+	if pr.verified {
+		return
+	}
+	if err == io.EOF || (pr.size >= 0 && pr.read >= pr.size) {
+		pr.verified = true
+		if hex.EncodeToString(pr.h.Sum(nil)) != pr.expected {
+			return n, errPayloadMismatch
+		}
+	}
+	return
+}
+
+func (pr *payloadReader) Close() error { return pr.r.Close() }
+
+func (sig *SigV4) canonicalRequest(r *http.Request) (string, error) {
+	var (
+		sb      strings.Builder
+		headers = make([]string, 0, len(sig.SignedHeaders))
+	)
+	for _, name := range sig.SignedHeaders {
+		var value string
+		if name == "host" {
+			value = r.Host
+		} else {
+			values := r.Header[http.CanonicalHeaderKey(name)]
+			if len(values) == 0 {
+				return "", fmt.Errorf("signed header %q is missing", name)
+			}
+			trimmed := make([]string, 0, len(values))
+			for _, v := range values {
+				trimmed = append(trimmed, strings.Join(strings.Fields(v), " "))
+			}
+			value = strings.Join(trimmed, ",")
+		}
+		headers = append(headers, name+":"+value+"\n")
+	}
+	payloadHash := r.Header.Get(headerAmzContentHash)
+	if payloadHash == "" {
+		payloadHash = unsignedPayload
+	}
+
+	sb.WriteString(r.Method)
+	sb.WriteByte('\n')
+	sb.WriteString(uriEncode(r.URL.Path, false))
+	sb.WriteByte('\n')
+	sb.WriteString(canonicalQuery(r.URL.Query()))
+	sb.WriteByte('\n')
+	sb.WriteString(strings.Join(headers, ""))
+	sb.WriteByte('\n')
+	sb.WriteString(strings.Join(sig.SignedHeaders, ";"))
+	sb.WriteByte('\n')
+	sb.WriteString(payloadHash)
+	return sb.String(), nil
+}
+
+// Query parameters sorted by (encoded) name, and then by value
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		if key != querySignature {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return uriEncode(keys[i], true) < uriEncode(keys[j], true) })
+	params := make([]string, 0, len(keys))
+	for _, key := range keys {
+		values := make([]string, 0, len(query[key]))
+		for _, v := range query[key] {
+			values = append(values, uriEncode(v, true))
+		}
+		sort.Strings(values)
+		encKey := uriEncode(key, true)
+		for _, v := range values {
+			params = append(params, encKey+"="+v)
+		}
+	}
+	return strings.Join(params, "&")
+}
+
+// URI-encodes a string as AWS requires: all characters except unreserved
+// ones are percent-encoded, '/' is kept as is in object paths.
+func uriEncode(s string, encodeSlash bool) string {
+	const hexUpper = "0123456789ABCDEF"
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9'),
+			c == '-', c == '_', c == '.', c == '~':
+			sb.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			sb.WriteByte(c)
+		default:
+			sb.WriteByte('%')
+			sb.WriteByte(hexUpper[c>>4])
+			sb.WriteByte(hexUpper[c&15])
+		}
+	}
+	return sb.String()
+}
+
+func signingKey(secret, date, region, service string) []byte {
+	key := hmacSHA256([]byte("AWS4"+secret), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	return hmacSHA256(key, sigV4Request)
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
@@ -0,0 +1,104 @@
+// Package s3compat provides Amazon S3 compatibility layer
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package s3compat
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+type (
+	// GET/PUT object tagging request/response body
+	Tagging struct {
+		XMLName xml.Name `xml:"Tagging"`
+		Ns      string   `xml:"xmlns,attr,omitempty"`
+		TagSet  TagSet   `xml:"TagSet"`
+	}
+	TagSet struct {
+		Tags []Tag `xml:"Tag"`
+	}
+	Tag struct {
+		Key   string `xml:"Key"`
+		Value string `xml:"Value"`
+	}
+)
+
+func NewTagging(tags cmn.SimpleKVs) *Tagging {
+	tagging := &Tagging{Ns: s3Namespace}
+	tagging.TagSet.Tags = make([]Tag, 0, len(tags))
+	for k, v := range tags {
+		tagging.TagSet.Tags = append(tagging.TagSet.Tags, Tag{Key: k, Value: v})
+	}
+	return tagging
+}
+
+func (r *Tagging) MustMarshal() []byte {
+	b, err := xml.Marshal(r)
+	cmn.AssertNoErr(err)
+	return []byte(xml.Header + string(b))
+}
+
+// Tags converts (and validates) the tag set
+func (r *Tagging) Tags() (cmn.SimpleKVs, error) {
+	tags := make(cmn.SimpleKVs, len(r.TagSet.Tags))
+	for _, tag := range r.TagSet.Tags {
+		if _, ok := tags[tag.Key]; ok {
+			return nil, fmt.Errorf("duplicate tag key %q", tag.Key)
+		}
+		tags[tag.Key] = tag.Value
+	}
+	return tags, cmn.ValidateObjTags(tags)
+}
+
+// TagsFromHeader parses URL-encoded `x-amz-tagging` header, e.g.: "k1=v1&k2=v2"
+func TagsFromHeader(header http.Header) (cmn.SimpleKVs, error) {
+	hval := header.Get(headerTagging)
+	if hval == "" {
+		return nil, nil
+	}
+	query, err := url.ParseQuery(hval)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s header: %v", headerTagging, err)
+	}
+	tags := make(cmn.SimpleKVs, len(query))
+	for k, vals := range query {
+		if len(vals) > 1 {
+			return nil, fmt.Errorf("duplicate tag key %q", k)
+		}
+		tags[k] = vals[0]
+	}
+	return tags, cmn.ValidateObjTags(tags)
+}
+
+// UserMDFromHeader collects `x-amz-meta-*` headers (keys are lowercased as in S3)
+func UserMDFromHeader(header http.Header) (cmn.SimpleKVs, error) {
+	var md cmn.SimpleKVs
+	for k, vals := range header {
+		if !strings.HasPrefix(k, headerUserMDPrefix) {
+			continue
+		}
+		if md == nil {
+			md = make(cmn.SimpleKVs, 4)
+		}
+		md[strings.ToLower(strings.TrimPrefix(k, headerUserMDPrefix))] = strings.Join(vals, ",")
+	}
+	return md, cmn.ValidateUserMD(md)
+}
+
+func setUserMDHeader(header http.Header, lom *cluster.LOM) {
+	for k, v := range lom.UserMD() {
+		header.Set(headerUserMDPrefix+k, v)
+	}
+	if tags := lom.Tags(); len(tags) > 0 {
+		header.Set(headerTaggingCount, strconv.Itoa(len(tags)))
+	}
+}
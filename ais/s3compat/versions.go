@@ -0,0 +1,62 @@
+// Package s3compat provides Amazon S3 compatibility layer
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package s3compat
+
+import (
+	"encoding/xml"
+	"net/url"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+type (
+	// List object versions response
+	// NOTE: all versions are returned at once (IsTruncated is always false)
+	ListVersionsResult struct {
+		Ns          string        `xml:"xmlns,attr"`
+		Name        string        `xml:"Name"`
+		Prefix      string        `xml:"Prefix"`
+		MaxKeys     int           `xml:"MaxKeys"`
+		IsTruncated bool          `xml:"IsTruncated"`
+		Versions    []*ObjVersion `xml:"Version"`
+	}
+	ObjVersion struct {
+		Key          string `xml:"Key"`
+		VersionID    string `xml:"VersionId"`
+		IsLatest     bool   `xml:"IsLatest"`
+		LastModified string `xml:"LastModified"`
+		ETag         string `xml:"ETag"`
+		Size         int64  `xml:"Size"`
+		Class        string `xml:"StorageClass"`
+	}
+)
+
+func NewListVersionsResult(bucket string, query url.Values) *ListVersionsResult {
+	return &ListVersionsResult{
+		Ns:       s3Namespace,
+		Name:     bucket,
+		Prefix:   query.Get(URLParamPrefix),
+		MaxKeys:  int(cmn.DefaultListPageSize),
+		Versions: make([]*ObjVersion, 0),
+	}
+}
+
+func (r *ListVersionsResult) Add(entry *cmn.ObjVersionEntry) {
+	r.Versions = append(r.Versions, &ObjVersion{
+		Key:          entry.Name,
+		VersionID:    entry.Version,
+		IsLatest:     entry.Current,
+		LastModified: time.Unix(0, entry.Mtime).UTC().Format(time.RFC3339),
+		ETag:         entry.Checksum,
+		Size:         entry.Size,
+	})
+}
+
+func (r *ListVersionsResult) MustMarshal() []byte {
+	b, err := xml.Marshal(r)
+	cmn.AssertNoErr(err)
+	return []byte(xml.Header + string(b))
+}
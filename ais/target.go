@@ -29,9 +29,14 @@ import (
 	"github.com/NVIDIA/aistore/dsort"
 	"github.com/NVIDIA/aistore/ec"
 	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/hk"
+	"github.com/NVIDIA/aistore/lifecycle"
 	"github.com/NVIDIA/aistore/memsys"
 	"github.com/NVIDIA/aistore/mirror"
+	"github.com/NVIDIA/aistore/query"
 	"github.com/NVIDIA/aistore/reb"
+	"github.com/NVIDIA/aistore/replication"
+	"github.com/NVIDIA/aistore/sse"
 	"github.com/NVIDIA/aistore/stats"
 	"github.com/NVIDIA/aistore/transport"
 	"github.com/NVIDIA/aistore/xaction"
@@ -83,6 +88,7 @@ type (
 		fsprg        fsprungroup
 		rebManager   *reb.Manager
 		dbDriver     dbdriver.Driver
+		replicator   *replication.Manager
 		transactions transactions
 		gfn          struct {
 			local  localGFN
@@ -184,13 +190,20 @@ func (t *targetrunner) Run() error {
 
 	t.checkRestarted()
 
-	// register object type and workfile type
+	// register object, workfile, and (noncurrent) version types
 	if err := fs.CSM.RegisterContentType(fs.ObjectType, &fs.ObjectContentResolver{}); err != nil {
 		cmn.ExitLogf("%v", err)
 	}
 	if err := fs.CSM.RegisterContentType(fs.WorkfileType, &fs.WorkfileContentResolver{}); err != nil {
 		cmn.ExitLogf("%v", err)
 	}
+	if err := fs.CSM.RegisterContentType(fs.VersionType, &fs.VersionContentResolver{}); err != nil {
+		cmn.ExitLogf("%v", err)
+	}
+
+	if err := sse.Init(config); err != nil {
+		cmn.ExitLogf("%s: failed to initialize server-side encryption: %v", t.si, err)
+	}
 
 	dryRunInit()
 	t.gfn.local.tag, t.gfn.global.tag = "local GFN", "global GFN"
@@ -250,6 +263,7 @@ func (t *targetrunner) Run() error {
 	t.authn = &authManager{
 		tokens:        make(map[string]*cmn.AuthToken),
 		revokedTokens: make(map[string]bool),
+		accessKeys:    make(map[string]*cmn.AuthAccessKey),
 		version:       1,
 	}
 	driver, err := dbdriver.NewBuntDB(filepath.Join(config.Confdir, dbName))
@@ -265,6 +279,31 @@ func (t *targetrunner) Run() error {
 	// transactions
 	t.transactions.init(t)
 
+	// persisted queries (see `queryXact`)
+	hk.Reg(query.PurgeHkName, func() time.Duration { return query.PurgeAbandoned(driver) }, query.PurgeHkInterval)
+
+	// S3 multipart uploads
+	go t.removeStaleMptParts()
+	hk.Reg(mptHkCleanName, housekeepMpt, mptHkInterval)
+
+	// bucket lifecycle rules
+	hk.Reg(lifecycle.HkName, t.lifecycleHk, lifecycle.HkInterval)
+
+	// bucket quotas
+	hk.Reg(quotaHkName, t.quotaHk, quotaHkInterval)
+	hk.Reg(quotaPeersHkName, t.quotaPeersHk, quotaPeersHkInterval)
+
+	// write-back of cloud buckets
+	hk.Reg(writeBackHkName, t.writeBackHk, writeBackHkInterval)
+
+	// replication to remote clusters
+	if t.replicator, err = replication.NewManager(driver, t.sendReplica); err != nil {
+		glog.Errorf("Failed to load replication queue: %v", err)
+		return err
+	}
+	go t.replicator.Run()
+	defer t.replicator.Stop(nil)
+
 	//
 	// REST API: register storage target's handler(s) and start listening
 	//
@@ -314,6 +353,12 @@ func (c *clouds) init(t *targetrunner, config *cmn.Config) {
 		c.ext, err = cloud.NewGCP(t)
 	case cmn.ProviderAzure:
 		c.ext, err = cloud.NewAzure(t)
+	case cmn.ProviderHDFS:
+		c.ext, err = cloud.NewHDFS(t)
+	case cmn.ProviderHTTP:
+		c.ext, err = cloud.NewHTTP(t)
+	case cmn.ProviderPosix:
+		c.ext, err = cloud.NewPosix(t)
 	case "":
 		c.ext, err = cloud.NewDummyCloud(t)
 	default:
@@ -345,7 +390,7 @@ func (t *targetrunner) initRecvHandlers() {
 			{r: cmn.Sort, h: dsort.SortHandler, net: []string{cmn.NetworkIntraControl, cmn.NetworkIntraData}},
 
 			{r: cmn.Tar2Tf, h: t.tar2tfHandler, net: []string{cmn.NetworkPublic}},
-			{r: cmn.Query, h: t.queryHandler, net: []string{cmn.NetworkPublic, cmn.NetworkIntraControl}},
+			{r: cmn.Query, h: t.queryHandler, net: []string{cmn.NetworkPublic, cmn.NetworkIntraControl, cmn.NetworkIntraData}},
 			{r: "/" + cmn.S3, h: t.s3Handler, net: []string{cmn.NetworkPublic, cmn.NetworkIntraData}},
 
 			{r: "/", h: cmn.InvalidHandler, net: []string{cmn.NetworkPublic, cmn.NetworkIntraControl, cmn.NetworkIntraData}},
@@ -443,7 +488,26 @@ func (t *targetrunner) httpbckget(w http.ResponseWriter, r *http.Request) {
 			t.listBuckets(w, r, cmn.QueryBcks(bck.Bck))
 		}
 	default:
-		t.invalmsghdlrf(w, r, "Invalid route /buckets/%s", apiItems[0])
+		query := r.URL.Query()
+		what := query.Get(cmn.URLParamWhat)
+		if what != cmn.GetWhatVersions && what != cmn.GetWhatQuotaUsage {
+			t.invalmsghdlrf(w, r, "Invalid route /buckets/%s", apiItems[0])
+			return
+		}
+		bck, err := newBckFromQuery(apiItems[0], query)
+		if err != nil {
+			t.invalmsghdlr(w, r, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err = bck.Init(t.owner.bmd, t.si); err != nil {
+			t.invalmsghdlr(w, r, err.Error())
+			return
+		}
+		if what == cmn.GetWhatQuotaUsage {
+			t.getQuotaUsage(w, r, bck)
+			return
+		}
+		t.listBckVersions(w, r, bck, query.Get(cmn.URLParamPrefix))
 	}
 }
 
@@ -600,6 +664,10 @@ func (t *targetrunner) httpobjget(w http.ResponseWriter, r *http.Request) {
 		t.doTransform(w, r, transformID, bck, objName)
 		return
 	}
+	if query.Get(cmn.URLParamWhat) == cmn.GetWhatVersions {
+		t.listObjVersions(w, r, lom)
+		return
+	}
 	goi := &getObjInfo{
 		started: started,
 		t:       t,
@@ -609,6 +677,7 @@ func (t *targetrunner) httpobjget(w http.ResponseWriter, r *http.Request) {
 		ranges:  cmn.RangesQuery{Range: r.Header.Get(cmn.HeaderRange), Size: 0},
 		isGFN:   isGFNRequest,
 		chunked: config.Net.HTTP.Chunked,
+		version: query.Get(cmn.URLParamVersion),
 	}
 	if err, errCode := goi.getObject(); err != nil {
 		if cmn.IsErrConnectionReset(err) {
@@ -677,6 +746,12 @@ func (t *targetrunner) httpobjput(w http.ResponseWriter, r *http.Request) {
 	lom.SetAtimeUnix(started.UnixNano())
 	appendTy := query.Get(cmn.URLParamAppendType)
 	if appendTy == "" {
+		userMD, err := cmn.UserMDFromHdr(r.Header)
+		if err != nil {
+			t.invalmsghdlr(w, r, err.Error())
+			return
+		}
+		lom.SetUserMD(userMD)
 		if err, errCode := t.doPut(r, lom, started); err != nil {
 			t.fshc(err, lom.FQN)
 			t.invalmsghdlr(w, r, err.Error(), errCode)
@@ -793,7 +868,13 @@ func (t *targetrunner) httpobjdelete(w http.ResponseWriter, r *http.Request) {
 		t.invalmsghdlr(w, r, err.Error())
 		return
 	}
-	err, errCode := t.objDelete(context.Background(), lom, evict)
+	if ver := query.Get(cmn.URLParamVersion); ver != "" && !evict {
+		if err, errCode := t.delObjVersion(lom, ver, isBypassGovernance(r)); err != nil {
+			t.invalmsghdlr(w, r, err.Error(), errCode)
+		}
+		return
+	}
+	err, errCode := t.objDelete(context.Background(), lom, evict, isBypassGovernance(r))
 	if err != nil {
 		if errCode == http.StatusNotFound {
 			t.invalmsghdlrsilent(w, r,
@@ -887,6 +968,18 @@ func (t *targetrunner) httpbckpost(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 		go xact.Run()
+	case cmn.ActSyncBck:
+		syncMsg := &cmn.SyncBckMsg{}
+		if err := cmn.MorphMarshal(msg.Value, syncMsg); err != nil {
+			t.invalmsghdlrf(w, r, "invalid %s action message: %v", msg.Action, err)
+			return
+		}
+		xact, err := xaction.Registry.RenewSyncBck(t, bck, msg.UUID, syncMsg)
+		if err != nil {
+			t.invalmsghdlr(w, r, err.Error())
+			return
+		}
+		go xact.Run()
 	case cmn.ActListObjects:
 		// list the bucket and return
 		begin := mono.NanoTime()
@@ -922,6 +1015,8 @@ func (t *targetrunner) httpobjpost(w http.ResponseWriter, r *http.Request) {
 		t.renameObject(w, r, &msg)
 	case cmn.ActPromote:
 		t.promoteFQN(w, r, &msg)
+	case cmn.ActLegalHold:
+		t.setLegalHold(w, r, &msg)
 	default:
 		t.invalmsghdlrf(w, r, fmtUnknownAct, msg)
 	}
@@ -1021,6 +1116,19 @@ func (t *targetrunner) httpobjhead(w http.ResponseWriter, r *http.Request) {
 		invalidHandler(w, r, err.Error())
 		return
 	}
+	if ver := query.Get(cmn.URLParamVersion); ver != "" {
+		vlom, err, errCode := t.lookupVersion(lom, ver)
+		if err != nil {
+			invalidHandler(w, r, err.Error(), errCode)
+			return
+		}
+		vlom.PopulateHdr(hdr)
+		origCksumToHdr(hdr, vlom)
+		cmn.AddUserMDToHdr(hdr, vlom.UserMD())
+		objProps := cmn.ObjectProps{Name: objName, Bck: lom.Bck().Bck, Size: vlom.LogicalSize(), Present: true}
+		t.objPropsToHdr(hdr, &objProps)
+		return
+	}
 
 	lom.Lock(false)
 	if err = lom.Load(true); err != nil && !cmn.IsObjNotExist(err) { // (doesnotexist -> ok, other)
@@ -1059,6 +1167,8 @@ func (t *targetrunner) httpobjhead(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 		lom.PopulateHdr(hdr)
+		origCksumToHdr(hdr, lom)
+		cmn.AddUserMDToHdr(hdr, lom.UserMD())
 	} else {
 		var objMeta cmn.SimpleKVs
 		objMeta, err, errCode = t.Cloud(lom.Bck()).HeadObj(context.Background(), lom)
@@ -1077,7 +1187,7 @@ func (t *targetrunner) httpobjhead(w http.ResponseWriter, r *http.Request) {
 		Present: exists,
 	}
 	if exists {
-		objProps.Size = lom.Size()
+		objProps.Size = lom.LogicalSize()
 		objProps.NumCopies = lom.NumCopies()
 		if lom.Bck().Props.EC.Enabled {
 			if md, err := ec.ObjectMetadata(lom.Bck(), objName); err == nil {
@@ -1085,7 +1195,11 @@ func (t *targetrunner) httpobjhead(w http.ResponseWriter, r *http.Request) {
 			}
 		}
 	}
-	err = cmn.IterFields(objProps, func(tag string, field cmn.IterField) (err error, b bool) {
+	t.objPropsToHdr(hdr, &objProps)
+}
+
+func (t *targetrunner) objPropsToHdr(hdr http.Header, objProps *cmn.ObjectProps) {
+	err := cmn.IterFields(objProps, func(tag string, field cmn.IterField) (err error, b bool) {
 		if hdr.Get(tag) == "" {
 			hdr.Set(tag, fmt.Sprintf("%v", field.Value()))
 		}
@@ -1215,6 +1329,8 @@ func (t *targetrunner) doPut(r *http.Request, lom *cluster.LOM, started time.Tim
 		cksumToCheck: cmn.NewCksum(cksumType, cksumValue),
 		ctx:          context.Background(),
 		workFQN:      fs.CSM.GenContentParsedFQN(lom.ParsedFQN, fs.WorkfileType, fs.WorkfilePut),
+
+		bypassGovernance: isBypassGovernance(r),
 	}
 	if recvType != "" {
 		n, err := strconv.Atoi(recvType)
@@ -1222,6 +1338,7 @@ func (t *targetrunner) doPut(r *http.Request, lom *cluster.LOM, started time.Tim
 			return err, http.StatusBadRequest
 		}
 		poi.migrated = cluster.RecvType(n) == cluster.Migrated
+		poi.userWrite = cmn.IsParseBool(r.URL.Query().Get(cmn.URLParamUserWrite))
 	}
 	sizeStr := header.Get("Content-Length")
 	if sizeStr != "" {
@@ -1262,7 +1379,7 @@ func (t *targetrunner) putMirror(lom *cluster.LOM) {
 	}
 }
 
-func (t *targetrunner) objDelete(ctx context.Context, lom *cluster.LOM, evict bool) (error, int) {
+func (t *targetrunner) objDelete(ctx context.Context, lom *cluster.LOM, evict, bypassGovernance bool) (error, int) {
 	var (
 		cloudErr     error
 		cloudErrCode int
@@ -1274,6 +1391,12 @@ func (t *targetrunner) objDelete(ctx context.Context, lom *cluster.LOM, evict bo
 
 	delFromCloud := lom.Bck().IsRemote() && !evict
 	if err := lom.Load(false); err == nil {
+		if err := lom.ObjLockErr(bypassGovernance); err != nil {
+			return err, http.StatusForbidden
+		}
+		if evict && lom.IsDirty() {
+			return fmt.Errorf("%s: cannot evict - not yet written to the cloud", lom), http.StatusConflict
+		}
 		delFromAIS = true
 	} else if !cmn.IsObjNotExist(err) {
 		return err, 0
@@ -1282,7 +1405,8 @@ func (t *targetrunner) objDelete(ctx context.Context, lom *cluster.LOM, evict bo
 	}
 
 	if delFromCloud {
-		if err, errCode := t.Cloud(lom.Bck()).DeleteObj(ctx, lom); err != nil {
+		err, errCode := t.Cloud(lom.Bck()).DeleteObj(ctx, lom)
+		if err != nil && !(errCode == http.StatusNotFound && delFromAIS && lom.IsDirty()) {
 			cloudErr = err
 			cloudErrCode = errCode
 			t.statsT.Add(stats.DeleteCount, 1)
@@ -1304,6 +1428,8 @@ func (t *targetrunner) objDelete(ctx context.Context, lom *cluster.LOM, evict bo
 				stats.NamedVal64{Name: stats.LruEvictCount, Value: 1},
 				stats.NamedVal64{Name: stats.LruEvictSize, Value: lom.Size()},
 			)
+		} else {
+			t.replicateDelete(lom)
 		}
 	}
 	if cloudErr != nil {
@@ -1340,19 +1466,39 @@ func (t *targetrunner) renameObject(w http.ResponseWriter, r *http.Request, msg
 		t.invalmsghdlrf(w, r, "%s: cannot rename erasure-coded object", lom)
 		return
 	}
+	bypassGovernance := isBypassGovernance(r)
+	if lom.Bprops().ObjLock.Enabled {
+		lom.Lock(false)
+		err = lom.OverwriteErr(bypassGovernance)
+		lom.Unlock(false)
+		if err != nil {
+			t.invalmsghdlr(w, r, err.Error(), http.StatusForbidden)
+			return
+		}
+	}
 
 	buf, slab := t.gmm.Alloc()
-	ri := &replicInfo{smap: t.owner.smap.get(), t: t, bckTo: lom.Bck(), buf: buf, localOnly: false, finalize: true}
+	ri := &replicInfo{smap: t.owner.smap.get(), t: t, bckTo: lom.Bck(), buf: buf, localOnly: false, finalize: true,
+		objLock: true, bypassGovernance: bypassGovernance}
 	copied, err := ri.copyObject(lom, msg.Name /* new object name */)
 	slab.Free(buf)
 	if err != nil {
-		t.invalmsghdlr(w, r, err.Error())
+		if cmn.IsErrObjLocked(err) {
+			t.invalmsghdlr(w, r, err.Error(), http.StatusForbidden)
+		} else {
+			t.invalmsghdlr(w, r, err.Error())
+		}
 		return
 	}
 	if copied {
 		lom.Lock(true)
-		if err = lom.Remove(); err != nil {
+		// the source may have been locked while being copied
+		if err = lom.OverwriteErr(bypassGovernance); err != nil {
+			t.invalmsghdlr(w, r, err.Error(), http.StatusForbidden)
+		} else if err = lom.Remove(); err != nil {
 			t.invalmsghdlr(w, r, err.Error())
+		} else {
+			t.replicateDelete(lom)
 		}
 		lom.Unlock(true)
 	}
@@ -1413,7 +1559,7 @@ func (t *targetrunner) promoteFQN(w http.ResponseWriter, r *http.Request, msg *c
 			glog.Infof("%s: promote %+v", t.si, params)
 		}
 		var xact *mirror.XactDirPromote
-		xact, err = xaction.Registry.RenewDirPromote(srcFQN, bck, t, &params)
+		xact, err = xaction.Registry.RenewDirPromote(srcFQN, bck, t, &params, isBypassGovernance(r))
 		if err != nil {
 			t.invalmsghdlr(w, r, err.Error())
 			return
@@ -1427,7 +1573,7 @@ func (t *targetrunner) promoteFQN(w http.ResponseWriter, r *http.Request, msg *c
 		objName = filepath.Base(srcFQN)
 	}
 	if _, err = t.PromoteFile(srcFQN, bck, objName, nil, /*expectedCksum*/
-		params.Overwrite, true /*safe*/, params.Verbose); err != nil {
+		params.Overwrite, true /*safe*/, params.Verbose, isBypassGovernance(r)); err != nil {
 		t.invalmsghdlrf(w, r, fmtErr+" %s", t.si, msg.Action, err.Error())
 	}
 	// TODO: inc stats
@@ -0,0 +1,164 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+// Backend chaining (see cmn.BackendChainConf). An ais bucket with more than one
+// backend is served by backendChain: cold GET, HEAD, and list requests try the
+// backends in order, so that a miss (or an error) in one falls through to the
+// next; PUTs and deletes go to the backends selected by the write policy.
+
+type backendChain struct {
+	t   *targetrunner
+	bck *cluster.Bck
+}
+
+var (
+	_ cluster.CloudProvider    = &backendChain{}
+	_ cluster.CloudRangeReader = &backendChain{}
+)
+
+// try calls f for each backend in order until the first success. When all
+// backends fail, the error of the first backend that did not simply miss is
+// returned (or else, "not found").
+func (bc *backendChain) try(op string, f func(backend cmn.Bck) (error, int)) (err error, errCode int) {
+	for _, backend := range bc.bck.Props.BackendChain.Bcks {
+		e, code := f(backend)
+		if e == nil {
+			return nil, 0
+		}
+		if glog.FastV(4, glog.SmoduleAIS) {
+			glog.Infof("%s %s: backend %s failed (%d: %v) - falling through", op, bc.bck, backend, code, e)
+		}
+		if err == nil || (errCode == http.StatusNotFound && code != http.StatusNotFound) {
+			err, errCode = e, code
+		}
+	}
+	return
+}
+
+func (bc *backendChain) Provider() string {
+	return bc.t.cloudProvider(bc.bck.CloudBck()).Provider()
+}
+
+func (bc *backendChain) ListObjects(ctx context.Context, bck *cluster.Bck,
+	msg *cmn.SelectMsg) (bckList *cmn.BucketList, err error, errCode int) {
+	err, errCode = bc.try("list", func(backend cmn.Bck) (err error, errCode int) {
+		bckList, err, errCode = bc.t.cloudProvider(backend).ListObjects(ctx, bck.WithBackend(backend), msg)
+		return
+	})
+	return
+}
+
+func (bc *backendChain) HeadBucket(ctx context.Context, bck *cluster.Bck) (bckProps cmn.SimpleKVs, err error, errCode int) {
+	err, errCode = bc.try("head", func(backend cmn.Bck) (err error, errCode int) {
+		bckProps, err, errCode = bc.t.cloudProvider(backend).HeadBucket(ctx, bck.WithBackend(backend))
+		return
+	})
+	return
+}
+
+func (bc *backendChain) ListBuckets(ctx context.Context, query cmn.QueryBcks) (buckets cmn.BucketNames, err error, errCode int) {
+	return bc.t.cloudProvider(bc.bck.CloudBck()).ListBuckets(ctx, query)
+}
+
+func (bc *backendChain) HeadObj(ctx context.Context, lom *cluster.LOM) (objMeta cmn.SimpleKVs, err error, errCode int) {
+	err, errCode = bc.try("head", func(backend cmn.Bck) (err error, errCode int) {
+		objMeta, err, errCode = bc.t.cloudProvider(backend).HeadObj(ctx, lom.CloneBackend(backend))
+		return
+	})
+	return
+}
+
+func (bc *backendChain) GetObj(ctx context.Context, workFQN string, lom *cluster.LOM) (err error, errCode int) {
+	return bc.try("get", func(backend cmn.Bck) (err error, errCode int) {
+		blom := lom.CloneBackend(backend)
+		if err, errCode = bc.t.cloudProvider(backend).GetObj(ctx, workFQN, blom); err == nil {
+			lom.CopyMd(blom)
+		}
+		return
+	})
+}
+
+// GetObjRange falls through the backends that are capable of reading byte ranges
+func (bc *backendChain) GetObjRange(ctx context.Context, lom *cluster.LOM, offset, length int64) (r io.ReadCloser,
+	attrs cluster.CloudObjAttrs, err error, errCode int) {
+	err, errCode = bc.try("get-range", func(backend cmn.Bck) (err error, errCode int) {
+		rr, ok := bc.t.cloudProvider(backend).(cluster.CloudRangeReader)
+		if !ok {
+			return fmt.Errorf("%s: backend %s does not support byte-range reads", bc.bck, backend),
+				http.StatusNotImplemented
+		}
+		r, attrs, err, errCode = rr.GetObjRange(ctx, lom.CloneBackend(backend), offset, length)
+		return
+	})
+	return
+}
+
+// PutObj writes the object to each backend selected by the write policy and
+// returns the version assigned by the first one. When the reader is a (named)
+// file, the content is re-read from the file for each backend; otherwise, the
+// reader is taken as given, which only works for a single (3rd party) backend.
+func (bc *backendChain) PutObj(ctx context.Context, r io.Reader, lom *cluster.LOM) (version string, err error, errCode int) {
+	bcks := bc.bck.Props.BackendChain.WriteBcks()
+	file, ok := r.(interface{ Name() string })
+	if !ok && len(bcks) > 0 {
+		// (remote ais requires cmn.FileHandle)
+		if len(bcks) > 1 || bcks[0].IsRemoteAIS() {
+			err = fmt.Errorf("%s: cannot put %s to %d backend(s) from a %T", bc.bck, lom, len(bcks), r)
+			return "", err, http.StatusNotImplemented
+		}
+		return bc.t.cloudProvider(bcks[0]).PutObj(ctx, r, lom.CloneBackend(bcks[0]))
+	}
+	for i, backend := range bcks {
+		var (
+			ver string
+			fh  *cmn.FileHandle
+		)
+		if fh, err = cmn.NewFileHandle(file.Name()); err != nil {
+			return "", err, http.StatusInternalServerError
+		}
+		ver, err, errCode = bc.t.cloudProvider(backend).PutObj(ctx, fh, lom.CloneBackend(backend))
+		fh.Close() // (remote ais closes it as well)
+		if err != nil {
+			return
+		}
+		if i == 0 {
+			version = ver
+		}
+	}
+	return
+}
+
+// DeleteObj removes the object from each backend selected by the write policy;
+// not finding the object in some of them is not an error.
+func (bc *backendChain) DeleteObj(ctx context.Context, lom *cluster.LOM) (err error, errCode int) {
+	var found bool
+	for _, backend := range bc.bck.Props.BackendChain.WriteBcks() {
+		e, code := bc.t.cloudProvider(backend).DeleteObj(ctx, lom.CloneBackend(backend))
+		if e == nil {
+			found = true
+			continue
+		}
+		if code != http.StatusNotFound {
+			return e, code
+		}
+		err, errCode = e, code
+	}
+	if found {
+		return nil, 0
+	}
+	return
+}
@@ -6,13 +6,16 @@ package ais
 
 import (
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/url"
 	"strconv"
 
+	"github.com/NVIDIA/aistore/3rdparty/glog"
 	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/cmn/debug"
+	"github.com/NVIDIA/aistore/dedup"
 )
 
 type replicInfo struct {
@@ -23,6 +26,9 @@ type replicInfo struct {
 	localOnly bool // copy locally with no HRW=>target
 	uncache   bool // uncache the source
 	finalize  bool // copies and EC (as in poi.finalize())
+	// do not overwrite retained destination (see cmn.ObjLockConf)
+	objLock          bool
+	bypassGovernance bool
 }
 
 //
@@ -46,6 +52,14 @@ func (ri *replicInfo) copyObject(lom *cluster.LOM, objNameTo string) (copied boo
 		lom.Unlock(false)
 		return
 	}
+	// (the missing chunks of a partially cached object can only be fetched from its own bucket)
+	if lom.IsPartial() {
+		if glog.FastV(4, glog.SmoduleAIS) {
+			glog.Infof("%s is cached partially - not copying", lom)
+		}
+		lom.Unlock(false)
+		return
+	}
 	if ri.uncache {
 		defer lom.Uncache()
 	}
@@ -53,6 +67,13 @@ func (ri *replicInfo) copyObject(lom *cluster.LOM, objNameTo string) (copied boo
 	if si.ID() != ri.t.si.ID() {
 		copied, err := ri.putRemote(lom, objNameTo, si)
 		lom.Unlock(false)
+		if copied && err == nil {
+			// (queued here and sent from the destination target - see sendReplica)
+			dst := &cluster.LOM{T: ri.t, ObjName: objNameTo}
+			if dst.Init(ri.bckTo.Bck) == nil {
+				ri.t.replicatePut(dst)
+			}
+		}
 		return copied, err
 	}
 
@@ -81,6 +102,11 @@ func (ri *replicInfo) copyObject(lom *cluster.LOM, objNameTo string) (copied boo
 		dst.Lock(true)
 		defer dst.Unlock(true)
 	}
+	if ri.objLock {
+		if err = dst.OverwriteErr(ri.bypassGovernance); err != nil {
+			return
+		}
+	}
 
 	// If before initializing the `dst` all mountpaths would be removed except
 	// the one on which the `lom` is placed then both `lom` and `dst` will have
@@ -89,10 +115,12 @@ func (ri *replicInfo) copyObject(lom *cluster.LOM, objNameTo string) (copied boo
 		return
 	}
 
+	objs, size := int64(1), lom.Size()
 	if err = dst.Load(false); err == nil {
 		if lom.Cksum().Equal(dst.Cksum()) {
 			return
 		}
+		objs, size = 0, size-dst.Size()
 	} else if cmn.IsErrBucketNought(err) {
 		return
 	}
@@ -100,30 +128,49 @@ func (ri *replicInfo) copyObject(lom *cluster.LOM, objNameTo string) (copied boo
 	dst, err = lom.CopyObject(dst.FQN, ri.buf)
 	if err == nil {
 		copied = true
+		cluster.BckUsage.Add(dst.Bck(), objs, size)
 		dst.ReCache()
 		if ri.finalize {
 			ri.t.putMirror(dst)
 		}
+		if !ri.localOnly {
+			ri.t.replicatePut(dst)
+		}
 	}
 	return
 }
 
 // TODO: reuse rebalancing code and streams
 func (ri *replicInfo) putRemote(lom *cluster.LOM, objNameTo string, si *cluster.Snode) (copied bool, err error) {
-	var file *cmn.FileHandle // Closed by `.Do()`
-	if file, err = cmn.NewFileHandle(lom.FQN); err != nil {
+	var (
+		file   cmn.ReadOpenCloser // Closed by `.Do()`
+		query  = url.Values{}
+		header = lom.PopulateHdr(nil)
+	)
+	if lom.IsDeduped() {
+		// the recipe is only meaningful locally - send the content (see tgtdedup.go)
+		var recipe *dedup.Recipe
+		if recipe, err = dedup.ReadRecipe(lom.FQN); err != nil {
+			return
+		}
+		file = recipe.NewHandle()
+		header.Del(cmn.HeaderObjCksumType)
+		header.Del(cmn.HeaderObjCksumVal)
+	} else if file, err = cmn.NewFileHandle(lom.FQN); err != nil {
 		err = fmt.Errorf("failed to open %s, err: %v", lom.FQN, err)
 		return
 	}
 
 	// PUT object into different target
-	var (
-		query  = url.Values{}
-		header = lom.PopulateHdr(nil)
-	)
 	query = cmn.AddBckToQuery(query, ri.bckTo.Bck)
 	query.Add(cmn.URLParamTargetID, ri.t.si.ID())
 	query.Add(cmn.URLParamRecvType, strconv.Itoa(int(cluster.Migrated)))
+	if ri.objLock {
+		query.Add(cmn.URLParamUserWrite, "true")
+		if ri.bypassGovernance {
+			query.Add(cmn.URLParamBypassGovernance, "true")
+		}
+	}
 	reqArgs := cmn.ReqArgs{
 		Method: http.MethodPut,
 		Base:   si.URL(cmn.NetworkIntraData),
@@ -142,6 +189,15 @@ func (ri *replicInfo) putRemote(lom *cluster.LOM, objNameTo string, si *cluster.
 	resp, err1 := ri.t.httpclientGetPut.Do(req)
 	if err1 != nil {
 		err = fmt.Errorf("failed to PUT to %s, err: %v", reqArgs.URL(), err1)
+		return
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		b, _ := ioutil.ReadAll(resp.Body)
+		if resp.StatusCode == http.StatusForbidden && ri.objLock {
+			err = cmn.NewObjLockedErr(ri.bckTo.Name+"/"+objNameTo, string(b))
+		} else {
+			err = fmt.Errorf("failed to PUT to %s, status %d: %s", reqArgs.URL(), resp.StatusCode, string(b))
+		}
 	} else {
 		copied = true
 	}
@@ -0,0 +1,185 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/compression"
+	"github.com/NVIDIA/aistore/dedup"
+	"github.com/NVIDIA/aistore/sse"
+)
+
+// At-rest compression (see cmn.BckCompressionConf and package compression).
+// Same as encrypted, compressed objects are marked as such in their metadata -
+// along with the original size and checksum (see cluster.LOM.SetCompressed) -
+// and get copied as is.
+
+type (
+	// decoded (decrypted or decompressed) content of a stored object
+	decodedReader interface {
+		Size() int64
+		NewRangeReader(off, length int64) io.Reader
+	}
+	// io.ReaderAt of the decoded content that is efficient when read
+	// sequentially (e.g., via io.SectionReader); not safe for concurrent use
+	decodedReaderAt struct {
+		dr  decodedReader
+		r   io.Reader // reads the content that starts at `off`
+		off int64
+	}
+)
+
+// compress returns true if the object is to be compressed when written
+func (poi *putObjInfo) compress() bool {
+	return poi.lom.Bprops().Compression.Enabled && !poi.migrated && !poi.cold
+}
+
+// transform returns true if the object is not to be stored as is
+func (poi *putObjInfo) transform() bool { return poi.encrypt() || poi.compress() || poi.dedup() }
+
+// writeCompressed compresses the content while writing it out; the checksum
+// to store is computed over the compressed content while the one to validate
+// (if any) and the original one - over the original content
+func (poi *putObjInfo) writeCompressed(file io.Writer, reader io.Reader, buf []byte,
+	store, given *cmn.CksumHash) (int64, error) {
+	var (
+		orig *cmn.CksumHash
+		conf = poi.lom.Bprops().Compression
+		w    = file
+	)
+	if store != nil {
+		w = cmn.NewWriterMulti(store.H, file)
+		orig = cmn.NewCksumHash(store.Type())
+	}
+	cw, err := compression.NewWriter(w, conf.Algorithm, conf.MinRatio)
+	if err != nil {
+		return 0, err
+	}
+	writers := []io.Writer{cw}
+	if orig != nil {
+		writers = append(writers, orig.H)
+	}
+	if given != nil {
+		writers = append(writers, given.H)
+	}
+	if _, err = io.CopyBuffer(cmn.NewWriterMulti(writers...), reader, buf); err == nil {
+		err = cw.Close()
+	}
+	if err != nil {
+		return cw.Written(), err
+	}
+	if !cw.Compressed() {
+		poi.lom.SetCompressed("", 0, nil)
+		return cw.Written(), nil
+	}
+	var origCksum *cmn.Cksum
+	if orig != nil {
+		orig.Finalize()
+		origCksum = &orig.Cksum
+	}
+	poi.lom.SetCompressed(conf.Algorithm, cw.Size(), origCksum)
+	return cw.Written(), nil
+}
+
+// writeFromFile writes (encrypts, compresses, or deduplicates) a given file into a work
+// file validating its checksum, if given
+func (poi *putObjInfo) writeFromFile(srcFQN, workFQN string, cksum *cmn.Cksum) (err error) {
+	if poi.r, err = os.Open(srcFQN); err != nil {
+		return
+	}
+	poi.workFQN, poi.cksumToCheck = workFQN, cksum
+	return poi.writeToFile()
+}
+
+// only ais buckets can be configured to encrypt, compress, or dedup - see cmn.BucketProps.Validate
+func mayBeDecoded(lom *cluster.LOM) bool { return lom.Bck().IsAIS() }
+
+// newDecodedReader returns nil if the object is stored as is or else if the
+// stored content is to be sent as is (the latter is never the case with dedup)
+func newDecodedReader(file *os.File, lom *cluster.LOM, asIs bool) (decodedReader, error) {
+	if lom.IsDeduped() {
+		recipe, err := dedup.ParseRecipe(file, lom.Size())
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", lom, err)
+		}
+		return recipe.NewReader(), nil
+	}
+	if asIs {
+		return nil, nil
+	}
+	if lom.IsEncrypted() {
+		sr, err := sse.NewReader(file, lom.Size())
+		if err != nil {
+			return nil, fmt.Errorf("%s: failed to decrypt: %w", lom, err)
+		}
+		return sr, nil
+	}
+	if _, _, _, ok := lom.Compressed(); ok {
+		cr, err := compression.NewReader(file, lom.Size())
+		if err != nil {
+			return nil, fmt.Errorf("%s: failed to decompress: %w", lom, err)
+		}
+		return cr, nil
+	}
+	return nil, nil
+}
+
+// newSectionReader returns the reader of the object's (decoded) content
+func newSectionReader(file *os.File, lom *cluster.LOM) (*io.SectionReader, error) {
+	if mayBeDecoded(lom) {
+		dr, err := newDecodedReader(file, lom, false /*as is*/)
+		if err != nil {
+			return nil, err
+		}
+		if dr != nil {
+			return io.NewSectionReader(&decodedReaderAt{dr: dr}, 0, dr.Size()), nil
+		}
+	}
+	return io.NewSectionReader(file, 0, lom.Size()), nil
+}
+
+func (dra *decodedReaderAt) ReadAt(b []byte, off int64) (n int, err error) {
+	size := dra.dr.Size()
+	if off >= size {
+		return 0, io.EOF
+	}
+	if dra.r == nil || dra.off != off {
+		dra.r, dra.off = dra.dr.NewRangeReader(off, size-off), off
+	}
+	l := cmn.MinI64(int64(len(b)), size-off)
+	n, err = io.ReadFull(dra.r, b[:l])
+	dra.off += int64(n)
+	if err != nil {
+		dra.r = nil
+		return
+	}
+	if n < len(b) {
+		err = io.EOF
+	}
+	return
+}
+
+// origCksumToHdr replaces the checksum of the compressed content with the
+// original one or, if the latter is unknown (e.g., encrypted or deduplicated
+// content), removes it
+func origCksumToHdr(hdr http.Header, lom *cluster.LOM) {
+	_, _, cksum, ok := lom.Compressed()
+	if !ok && !lom.IsDeduped() && !lom.IsEncrypted() {
+		return
+	}
+	if cksum == nil {
+		hdr.Del(cmn.HeaderObjCksumType)
+		hdr.Del(cmn.HeaderObjCksumVal)
+		return
+	}
+	hdr.Set(cmn.HeaderObjCksumType, cksum.Type())
+	hdr.Set(cmn.HeaderObjCksumVal, cksum.Value())
+}
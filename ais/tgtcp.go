@@ -342,6 +342,8 @@ func (t *targetrunner) httpdaeget(w http.ResponseWriter, r *http.Request) {
 		cmn.Assert(ok)
 		aisCloudInfo := t.cloud.ais.GetInfo(clusterConf)
 		t.writeJSON(w, r, aisCloudInfo, httpdaeWhat)
+	case cmn.GetWhatReplication:
+		t.writeJSON(w, r, t.replicator.Stats(), httpdaeWhat)
 	default:
 		t.httprunner.httpdaeget(w, r)
 	}
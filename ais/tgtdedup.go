@@ -0,0 +1,78 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"io"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/dedup"
+)
+
+// Deduplication (see cmn.BckDedupConf and package dedup). Unlike encrypted
+// and compressed objects, deduplicated ones are never sent to other nodes as
+// is: the recipe is only meaningful on the target that stores the chunks.
+// Instead, the content gets "hydrated" when rebalanced or replicated, and
+// deduplicated (again) by the receiving target.
+
+// dedup returns true if the object is to be deduplicated when written;
+// unlike encryption and compression, this includes migrated objects
+func (poi *putObjInfo) dedup() bool {
+	return poi.lom.Bprops().Dedup.Enabled && !poi.cold
+}
+
+// writeDeduped splits the content into chunks, stores the latter in the
+// chunk store, and writes out the recipe; the checksum to store is computed
+// over the recipe while the one to validate (if any) - over the content.
+// Migrated objects that were encrypted or compressed in the past get stored
+// as is (and nil recipe is returned).
+func (poi *putObjInfo) writeDeduped(file io.Writer, reader io.Reader, buf []byte,
+	store, given *cmn.CksumHash) (int64, *dedup.Recipe, error) {
+	if poi.migrated && poi.lom.IsEncoded() {
+		written, err := writeAsIs(file, reader, buf, store, given)
+		return written, nil, err
+	}
+	w := file
+	if store != nil {
+		w = cmn.NewWriterMulti(store.H, file)
+	}
+	dw := dedup.NewWriter(w)
+	w = dw
+	if given != nil {
+		w = cmn.NewWriterMulti(given.H, dw)
+	}
+	_, err := io.CopyBuffer(w, reader, buf)
+	if err == nil {
+		err = dw.Close()
+	}
+	if err != nil {
+		dw.Abort()
+		return dw.Written(), nil, err
+	}
+	return dw.Written(), dw.Recipe(), nil
+}
+
+func writeAsIs(file io.Writer, reader io.Reader, buf []byte, store, given *cmn.CksumHash) (int64, error) {
+	writers := []io.Writer{file}
+	if store != nil {
+		writers = append(writers, store.H)
+	}
+	if given != nil {
+		writers = append(writers, given.H)
+	}
+	return io.CopyBuffer(cmn.NewWriterMulti(writers...), reader, buf)
+}
+
+// releaseRecipe releases the chunks referenced by the recipe that is stored
+// in a given (work) file that is about to be removed
+func releaseRecipe(fqn string) {
+	recipe, err := dedup.ReadRecipe(fqn)
+	if err != nil {
+		glog.Errorf("%s: %v", fqn, err)
+		return
+	}
+	recipe.Release()
+}
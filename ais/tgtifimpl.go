@@ -20,6 +20,7 @@ import (
 	"github.com/NVIDIA/aistore/dbdriver"
 	"github.com/NVIDIA/aistore/fs"
 	"github.com/NVIDIA/aistore/ios"
+	"github.com/NVIDIA/aistore/lifecycle"
 	"github.com/NVIDIA/aistore/lru"
 	"github.com/NVIDIA/aistore/memsys"
 	"github.com/NVIDIA/aistore/reb"
@@ -44,15 +45,27 @@ func (t *targetrunner) Cloud(bck *cluster.Bck) cluster.CloudProvider {
 		return t.cloud.ais
 	}
 	if bck.Props != nil {
-		if t.cloud.ext.Provider() == bck.CloudBck().Provider {
-			return t.cloud.ext
+		if bck.HasBackendChain() {
+			return &backendChain{t: t, bck: bck}
 		}
-		c, _ := cloud.NewDummyCloud(t)
-		return c
+		return t.cloudProvider(bck.CloudBck())
 	}
 	return t.cloud.ext
 }
 
+// cloudProvider returns the provider of a given cloud or remote ais bucket
+func (t *targetrunner) cloudProvider(cloudBck cmn.Bck) cluster.CloudProvider {
+	if cloudBck.IsRemoteAIS() {
+		return t.cloud.ais
+	}
+	if t.cloud.ext.Provider() == cloudBck.Provider {
+		return t.cloud.ext
+	}
+	// not configured (backend chains are validated against the configured provider)
+	c, _ := cloud.NewDummyCloud(t)
+	return c
+}
+
 func (t *targetrunner) GetGFN(gfnType cluster.GFNType) cluster.GFN {
 	switch gfnType {
 	case cluster.GFNLocal:
@@ -96,6 +109,26 @@ func (t *targetrunner) RunLRU(id string) {
 	xlru.Finish()
 }
 
+// runs periodically (see `lifecycleHk`) and upon user request
+func (t *targetrunner) RunLifecycle(id string) {
+	if t.RebalanceInfo().IsRebalancing {
+		glog.Infoln("Warning: rebalancing (local or global) is in progress, skipping lifecycle run")
+		return
+	}
+	xlc := xaction.Registry.RenewLifecycle(id)
+	if xlc == nil {
+		return
+	}
+	lifecycle.Run(&lifecycle.InitLifecycle{T: t, Xaction: xlc, StatsT: t.statsT}) // blocking
+
+	xlc.Finish()
+}
+
+func (t *targetrunner) lifecycleHk() time.Duration {
+	go t.RunLifecycle(cmn.GenUUID())
+	return lifecycle.HkInterval
+}
+
 // slight variation vs t.httpobjget()
 func (t *targetrunner) GetObject(w io.Writer, lom *cluster.LOM, started time.Time) error {
 	goi := &getObjInfo{
@@ -138,10 +171,13 @@ func (t *targetrunner) PutObject(params cluster.PutObjectParams) error {
 
 func (t *targetrunner) EvictObject(lom *cluster.LOM) error {
 	ctx := context.Background()
-	err, _ := t.objDelete(ctx, lom, true /*evict*/)
+	err, _ := t.objDelete(ctx, lom, true /*evict*/, false /*bypass governance*/)
 	return err
 }
 
+func (t *targetrunner) ObjectDeleted(lom *cluster.LOM) { t.replicateDelete(lom) }
+func (t *targetrunner) ObjectWritten(lom *cluster.LOM) { t.replicatePut(lom) }
+
 func (t *targetrunner) CopyObject(lom *cluster.LOM, bckTo *cluster.Bck, buf []byte, localOnly bool) (copied bool, err error) {
 	ri := &replicInfo{smap: t.owner.smap.get(),
 		bckTo:     bckTo,
@@ -157,11 +193,19 @@ func (t *targetrunner) CopyObject(lom *cluster.LOM, bckTo *cluster.Bck, buf []by
 
 // FIXME: recomputes checksum if called with a bad one (optimize)
 func (t *targetrunner) GetCold(ctx context.Context, lom *cluster.LOM, prefetch bool) (err error, errCode int) {
+	if err = t.waitQuota(lom.Bck(), true /*enforce*/); err != nil {
+		return err, http.StatusServiceUnavailable
+	}
 	if prefetch {
 		if !lom.TryLock(true) {
 			glog.Infof("prefetch: cold GET race: %s - skipping", lom)
 			return cmn.ErrSkip, 0
 		}
+		// never overwrite the object that is yet to be written back (see xaction/writeback.go)
+		if lom.Load(false) == nil && lom.IsDirty() {
+			lom.Unlock(true)
+			return cmn.ErrSkip, 0
+		}
 	} else {
 		lom.Lock(true) // one cold-GET at a time
 	}
@@ -173,8 +217,13 @@ func (t *targetrunner) GetCold(ctx context.Context, lom *cluster.LOM, prefetch b
 		err = fmt.Errorf("%s: GET failed %d, err: %v", lom, errCode, err)
 		return
 	}
+	objs, size, err := reserveQuota(lom, true /*enforce*/)
+	if err != nil {
+		errCode = http.StatusInsufficientStorage
+	}
 	defer func() {
 		if err != nil {
+			cluster.BckUsage.Add(lom.Bck(), -objs, -size)
 			lom.Unlock(true)
 			if errRemove := cmn.RemoveFile(workFQN); errRemove != nil {
 				glog.Errorf("Nested error %s => (remove %s => err: %v)", err, workFQN, errRemove)
@@ -182,6 +231,9 @@ func (t *targetrunner) GetCold(ctx context.Context, lom *cluster.LOM, prefetch b
 			}
 		}
 	}()
+	if err != nil {
+		return
+	}
 	if err = cmn.Rename(workFQN, lom.FQN); err != nil {
 		err = fmt.Errorf("unexpected failure to rename %s => %s, err: %v", workFQN, lom.FQN, err)
 		t.fshc(err, lom.FQN)
@@ -206,7 +258,7 @@ func (t *targetrunner) GetCold(ctx context.Context, lom *cluster.LOM, prefetch b
 }
 
 func (t *targetrunner) PromoteFile(srcFQN string, bck *cluster.Bck, objName string,
-	computedCksum *cmn.Cksum, overwrite, safe, verbose bool) (nlom *cluster.LOM, err error) {
+	computedCksum *cmn.Cksum, overwrite, safe, verbose, bypassGovernance bool) (nlom *cluster.LOM, err error) {
 	lom := &cluster.LOM{T: t, ObjName: objName}
 	if err = lom.Init(bck.Bck); err != nil {
 		return
@@ -224,13 +276,30 @@ func (t *targetrunner) PromoteFile(srcFQN string, bck *cluster.Bck, objName stri
 		if verbose {
 			glog.Infof("promote/PUT %s => %s @ %s", srcFQN, lom, si.ID())
 		}
-		buf, slab := t.gmm.Alloc()
 		lom.FQN = srcFQN
-		ri := &replicInfo{smap: smap, t: t, bckTo: lom.Bck(), buf: buf, localOnly: false}
+		// NOTE: not deduplicating - the recipe would be meaningless at the destination
+		if poi := (&putObjInfo{t: t, lom: lom}); poi.encrypt() || poi.compress() {
+			workFQN := fs.CSM.GenContentParsedFQN(lom.ParsedFQN, fs.WorkfileType, fs.WorkfilePut)
+			if err = poi.writeFromFile(srcFQN, workFQN, computedCksum); err != nil {
+				return
+			}
+			lom.FQN = workFQN
+			defer func() {
+				if errRemove := cmn.RemoveFile(workFQN); errRemove != nil {
+					glog.Errorf("failed to remove %s: %v", workFQN, errRemove)
+				}
+			}()
+		}
+		buf, slab := t.gmm.Alloc()
+		ri := &replicInfo{smap: smap, t: t, bckTo: lom.Bck(), buf: buf, localOnly: false,
+			objLock: true, bypassGovernance: bypassGovernance}
 
 		// TODO -- FIXME: handle overwrite (lookup first)
 		_, err = ri.putRemote(lom, lom.ObjName, si)
 		slab.Free(buf)
+		if err == nil {
+			t.replicatePut(lom)
+		}
 		return
 	}
 
@@ -240,6 +309,12 @@ func (t *targetrunner) PromoteFile(srcFQN string, bck *cluster.Bck, objName stri
 		err = fmt.Errorf("%s already exists", lom)
 		return
 	}
+	if err == nil {
+		// NOTE: check prior to finalizing that'd otherwise remove the (unsafe) source
+		if err = lom.ObjLockErr(bypassGovernance); err != nil {
+			return
+		}
+	}
 	if verbose {
 		s := ""
 		if overwrite {
@@ -248,14 +323,22 @@ func (t *targetrunner) PromoteFile(srcFQN string, bck *cluster.Bck, objName stri
 		glog.Infof("promote%s %s => %s", s, srcFQN, lom)
 	}
 	var (
-		cksum   *cmn.CksumHash
-		fi      os.FileInfo
-		written int64
-		workFQN string
-		poi     = &putObjInfo{t: t, lom: lom}
-		conf    = lom.CksumConf()
+		cksum     *cmn.CksumHash
+		fi        os.FileInfo
+		written   int64
+		workFQN   string
+		poi       = &putObjInfo{t: t, lom: lom}
+		conf      = lom.CksumConf()
+		transform = poi.transform()
 	)
-	if safe {
+	if transform {
+		// encrypting, compressing, or deduplicating is copying - the source (if unsafe) gets removed once finalized
+		workFQN = fs.CSM.GenContentParsedFQN(lom.ParsedFQN, fs.WorkfileType, fs.WorkfilePut)
+		if err = poi.writeFromFile(srcFQN, workFQN, computedCksum); err != nil {
+			return
+		}
+		written = lom.Size()
+	} else if safe {
 		workFQN = fs.CSM.GenContentParsedFQN(lom.ParsedFQN, fs.WorkfileType, fs.WorkfilePut)
 
 		buf, slab := t.gmm.Alloc()
@@ -297,6 +380,11 @@ func (t *targetrunner) PromoteFile(srcFQN string, bck *cluster.Bck, objName stri
 	err, _ = poi.finalize()
 	if err == nil {
 		nlom = lom
+		if transform && !safe {
+			if errRemove := cmn.RemoveFile(srcFQN); errRemove != nil {
+				glog.Errorf("%s: failed to remove promoted %s: %v", lom, srcFQN, errRemove)
+			}
+		}
 	}
 	return
 }
@@ -22,11 +22,13 @@ import (
 	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/cmn/debug"
+	"github.com/NVIDIA/aistore/dedup"
 	"github.com/NVIDIA/aistore/ec"
 	"github.com/NVIDIA/aistore/fs"
 	"github.com/NVIDIA/aistore/memsys"
 	"github.com/NVIDIA/aistore/reb"
 	"github.com/NVIDIA/aistore/stats"
+	"github.com/NVIDIA/aistore/xaction"
 )
 
 type (
@@ -52,6 +54,11 @@ type (
 		migrated bool
 		// Determines if the recv is cold recv: either from another cluster or cloud.
 		cold bool
+		// true: bypass governance-mode object retention (see cmn.ObjLockConf)
+		bypassGovernance bool
+		// true: migrated on behalf of the user (rename, promote) - subject to
+		// the object lock and quota
+		userWrite bool
 		// if true, poi won't erasure-encode an object when finalizing
 		skipEC bool
 	}
@@ -73,6 +80,8 @@ type (
 		isGFN bool
 		// true: chunked transfer (en)coding as per https://tools.ietf.org/html/rfc7230#page-36
 		chunked bool
+		// specific (current or noncurrent) version of the object, if requested
+		version string
 	}
 
 	// Contains information packed in append handle.
@@ -118,6 +127,22 @@ func (poi *putObjInfo) putObject() (err error, errCode int) {
 		}
 	}
 
+	// fail early, prior to receiving the content (and check again when finalizing)
+	if (!poi.migrated || poi.userWrite) && lom.Bprops().ObjLock.Enabled {
+		lom.Lock(false)
+		err = lom.OverwriteErr(poi.bypassGovernance)
+		lom.Unlock(false)
+		if err != nil {
+			cmn.DrainReader(poi.r)
+			return err, http.StatusForbidden
+		}
+	}
+	if poi.enforceQuota() && lom.Bprops().Quota.Enabled {
+		if err, errCode := poi.checkQuota(); err != nil {
+			cmn.DrainReader(poi.r)
+			return err, errCode
+		}
+	}
 	if !daemon.dryRun.disk {
 		if err := poi.writeToFile(); err != nil {
 			return err, http.StatusInternalServerError
@@ -146,6 +171,9 @@ func (poi *putObjInfo) finalize() (err error, errCode int) {
 				err1 = err
 			}
 			poi.t.fshc(err, poi.workFQN)
+			if poi.lom.IsDeduped() {
+				releaseRecipe(poi.workFQN)
+			}
 			if err = cmn.RemoveFile(poi.workFQN); err != nil {
 				glog.Errorf("Nested error: %s => (remove %s => err: %v)", err1, poi.workFQN, err)
 			}
@@ -161,6 +189,12 @@ func (poi *putObjInfo) finalize() (err error, errCode int) {
 	}
 
 	poi.t.putMirror(poi.lom)
+	if poi.writeBack() {
+		poi.t.writeBack(poi.lom)
+	}
+	if !poi.migrated && !poi.cold {
+		poi.t.replicatePut(poi.lom)
+	}
 	return
 }
 
@@ -170,9 +204,12 @@ func (poi *putObjInfo) tryFinalize() (err error, errCode int) {
 		lom = poi.lom
 		bck = lom.Bck()
 	)
-	if bck.IsRemote() && !poi.migrated {
+	if err = poi.t.waitQuota(bck, poi.enforceQuota()); err != nil {
+		return err, http.StatusServiceUnavailable
+	}
+	if bck.IsRemote() && !poi.migrated && !poi.writeBack() {
 		var version string
-		if bck.IsCloud() {
+		if !bck.CloudBck().IsRemoteAIS() {
 			version, err, errCode = poi.putCloud()
 		} else {
 			version, err, errCode = poi.putRemoteAIS()
@@ -200,14 +237,77 @@ func (poi *putObjInfo) tryFinalize() (err error, errCode int) {
 	lom.Lock(true)
 	defer lom.Unlock(true)
 
+	// (the object being overwritten is accounted for under the lock)
+	objs, size, err := reserveQuota(lom, poi.enforceQuota())
+	if err != nil {
+		return err, http.StatusInsufficientStorage
+	}
+	defer func() {
+		if err != nil {
+			cluster.BckUsage.Add(bck, -objs, -size)
+		}
+	}()
+
+	if conf := &lom.Bprops().ObjLock; conf.Enabled {
+		// the bucket is not being destroyed (see destroyBucket) - until renamed
+		poi.t.transactions.RLock()
+		defer poi.t.transactions.RUnlock()
+		if poi.t.transactions.destroying(bck) {
+			return fmt.Errorf("%s: PUT failed, bucket %s is being destroyed", lom, bck), http.StatusConflict
+		}
+		if !poi.migrated || poi.userWrite {
+			if err = lom.OverwriteErr(poi.bypassGovernance); err != nil {
+				return err, http.StatusForbidden
+			}
+		}
+		if !poi.migrated {
+			var until time.Time
+			if conf.RetentionDays != 0 {
+				until = time.Now().Add(conf.Retention())
+			}
+			lom.SetRetainUntil(until)
+		}
+	}
+	var (
+		vfqn      string
+		oldRecipe *dedup.Recipe
+	)
 	if bck.IsAIS() && lom.VerConf().Enabled && !poi.migrated {
+		if lom.VerConf().History {
+			if vfqn, err = lom.ArchiveVersion(); err != nil {
+				return
+			}
+			defer func() {
+				if err == nil || vfqn == "" {
+					return
+				}
+				if errRestore := lom.UnarchiveVersion(vfqn); errRestore != nil {
+					glog.Errorf("Nested error: %s => (restore %s => err: %v)", err, vfqn, errRestore)
+				}
+			}()
+		}
 		if err = lom.IncVersion(); err != nil {
 			return
 		}
 	}
+	if vfqn == "" {
+		oldRecipe = lom.StoredRecipe() // overwritten (as opposed to archived)
+	}
+	if poi.writeBack() {
+		if err = xaction.MarkDirty(lom); err != nil {
+			return
+		}
+	}
 	if err := cmn.Rename(poi.workFQN, lom.FQN); err != nil {
 		return fmt.Errorf("rename failed => %s: %w", lom, err), 0
 	}
+	if vfqn != "" {
+		lom.CommitVersion(vfqn)
+		vfqn = "" // (the version stays archived)
+	}
+	if oldRecipe != nil {
+		oldRecipe.Release()
+	}
 	if lom.HasCopies() {
 		if err = lom.DelAllCopies(); err != nil {
 			return
@@ -240,7 +340,7 @@ func (poi *putObjInfo) putCloud() (ver string, err error, errCode int) {
 	if ver != "" {
 		customMD[cluster.VersionObjMD] = ver
 	}
-	lom.SetCustomMD(customMD)
+	lom.SetSysMD(customMD)
 	debug.AssertNoErr(file.Close())
 	return
 }
@@ -250,7 +350,7 @@ func (poi *putObjInfo) putRemoteAIS() (ver string, err error, errCode int) {
 		lom = poi.lom
 		bck = lom.Bck()
 	)
-	cmn.Assert(bck.IsRemoteAIS())
+	cmn.Assert(bck.CloudBck().IsRemoteAIS())
 	fh, errOpen := cmn.NewFileHandle(poi.workFQN) // Closed by `PutObj`.
 	if errOpen != nil {
 		err = fmt.Errorf("failed to open %s err: %w", poi.workFQN, errOpen)
@@ -270,6 +370,7 @@ func (poi *putObjInfo) writeToFile() (err error) {
 		slab    *memsys.Slab
 		reader  = poi.r
 		writer  io.Writer
+		recipe  *dedup.Recipe
 		writers = make([]io.Writer, 0, 4)
 		cksums  = struct {
 			store *cmn.CksumHash // store with LOM
@@ -299,6 +400,9 @@ func (poi *putObjInfo) writeToFile() (err error) {
 			if nestedErr := file.Close(); nestedErr != nil {
 				glog.Errorf("Nested (%v): failed to close received object %s, err: %v", err, poi.workFQN, nestedErr)
 			}
+			if recipe != nil {
+				recipe.Release()
+			}
 			if nestedErr := cmn.RemoveFile(poi.workFQN); nestedErr != nil {
 				glog.Errorf("Nested (%v): failed to remove %s, err: %v", err, poi.workFQN, nestedErr)
 			}
@@ -319,7 +423,8 @@ func (poi *putObjInfo) writeToFile() (err error) {
 			writers = append(writers, cksums.given.H)
 		}
 	} else {
-		if !poi.migrated || conf.ValidateObjMove {
+		// NOTE: migrated object's checksum is not the one to store if the object gets deduplicated
+		if !poi.migrated || conf.ValidateObjMove || poi.dedup() {
 			cksums.store = cmn.NewCksumHash(conf.Type)
 			writers = append(writers, cksums.store.H)
 			if poi.cksumToCheck != nil && poi.cksumToCheck.Type() != cmn.ChecksumNone {
@@ -338,7 +443,13 @@ func (poi *putObjInfo) writeToFile() (err error) {
 		}
 	}
 write:
-	if len(writers) == 0 {
+	if poi.encrypt() {
+		written, err = writeEncrypted(file, reader, buf, cksums.store, cksums.given)
+	} else if poi.compress() {
+		written, err = poi.writeCompressed(file, reader, buf, cksums.store, cksums.given)
+	} else if poi.dedup() {
+		written, recipe, err = poi.writeDeduped(file, reader, buf, cksums.store, cksums.given)
+	} else if len(writers) == 0 {
 		written, err = io.CopyBuffer(writer, reader, buf)
 	} else {
 		writers = append(writers, writer)
@@ -361,6 +472,13 @@ write:
 	}
 	// ok
 	poi.lom.SetSize(written)
+	poi.lom.SetDeduped(recipe != nil)
+	if !poi.migrated { // otherwise, arrives with the object
+		poi.lom.SetEncrypted(poi.encrypt())
+		if !poi.compress() {
+			poi.lom.SetCompressed("", 0, nil)
+		}
+	}
 	if cksums.store != nil {
 		cksums.store.Finalize()
 		poi.lom.SetCksum(&cksums.store.Cksum)
@@ -379,9 +497,12 @@ write:
 
 func (goi *getObjInfo) getObject() (err error, errCode int) {
 	var (
-		cs                                            fs.CapStatus
-		doubleCheck, retry, retried, coldGet, capRead bool
+		cs                                                       fs.CapStatus
+		doubleCheck, retry, retried, coldGet, capRead, notCached bool
 	)
+	if goi.version != "" {
+		return goi.getVersion()
+	}
 	// under lock: lom init, restore from cluster
 	goi.lom.Lock(false)
 do:
@@ -392,6 +513,7 @@ do:
 	err = goi.lom.Load()
 	if err != nil {
 		coldGet = cmn.IsObjNotExist(err)
+		notCached = coldGet
 		if !coldGet {
 			goi.lom.Unlock(false)
 			return err, http.StatusInternalServerError
@@ -428,7 +550,7 @@ do:
 	}
 	// exists && remote|cloud: check ver if requested
 	if !coldGet && goi.lom.Bck().IsRemote() {
-		if goi.lom.Version() != "" && goi.lom.VerConf().ValidateWarmGet {
+		if goi.lom.Version() != "" && goi.lom.VerConf().ValidateWarmGet && !goi.lom.IsDirty() {
 			goi.lom.Unlock(false)
 			if coldGet, err, errCode = goi.t.CheckCloudVersion(goi.ctx, goi.lom); err != nil {
 				goi.lom.Uncache()
@@ -438,6 +560,29 @@ do:
 		}
 	}
 
+	// partial caching: a byte range of a cloud object that is either not cached
+	// or cached partially (and is still current) - see tgtpartial.go
+	if goi.partialRange() && (notCached || (!coldGet && goi.lom.IsPartial())) {
+		var fallback bool
+		goi.lom.Unlock(false)
+		if cs = fs.GetCapStatus(); cs.OOS {
+			return cs.Err, http.StatusBadRequest
+		}
+		capRead = true
+		if fallback, err, errCode = goi.getPartial(); !fallback {
+			if err != nil {
+				return
+			}
+			coldGet = true
+			goto get
+		}
+		goi.lom.Lock(false)
+	}
+	// otherwise, a partially cached object gets replaced by the entire one
+	if !coldGet && goi.lom.IsPartial() {
+		coldGet = true
+	}
+
 	// checksum validation, if requested
 	if !coldGet && goi.lom.CksumConf().ValidateWarmGet {
 		err, errCode, coldGet = goi.tryRecoverObject()
@@ -483,6 +628,32 @@ get:
 	return
 }
 
+// getVersion streams back a given version of the object: the current one
+// (same as regular GET of an existing object) or one of the noncurrent versions
+func (goi *getObjInfo) getVersion() (err error, errCode int) {
+	lom := goi.lom
+	lom.Lock(false)
+	defer lom.Unlock(false)
+	if err = lom.Load(); err != nil && !cmn.IsObjNotExist(err) {
+		return err, http.StatusInternalServerError
+	}
+	if err == nil && lom.Version() == goi.version {
+		_, err, errCode = goi.finalize(false)
+		return
+	}
+	vlom, err := lom.LoadVersion(goi.version)
+	if err != nil {
+		if cmn.IsObjNotExist(err) {
+			return fmt.Errorf("%s version %s %s", lom, goi.version, cmn.DoesNotExist), http.StatusNotFound
+		}
+		return err, http.StatusInternalServerError
+	}
+	// noncurrent versions are neither cached nor load-balanced (same as cold GET)
+	goi.lom = vlom
+	_, err, errCode = goi.finalize(true)
+	return
+}
+
 // validate checksum; if corrupted try to recover from other replicas or EC slices
 func (goi *getObjInfo) tryRecoverObject() (err error, code int, coldGet bool) {
 	var (
@@ -746,11 +917,27 @@ func (goi *getObjInfo) finalize(coldGet bool) (retry bool, err error, errCode in
 
 	var (
 		r    *cmn.HTTPRange
+		dr   decodedReader
 		size = goi.lom.Size()
 	)
 	if goi.ranges.Size > 0 {
 		size = goi.ranges.Size
 	}
+	// GFN receives encrypted (compressed) content as is - but not the recipe of the deduplicated one
+	if mayBeDecoded(goi.lom) {
+		if dr, err = newDecodedReader(file, goi.lom, goi.isGFN); err != nil {
+			errCode = http.StatusInternalServerError
+			return
+		}
+		if dr != nil {
+			if goi.tag != "" {
+				err = fmt.Errorf("%s: tar-to-tfrecord is not supported for encrypted, compressed, or deduplicated objects", goi.lom)
+				errCode = http.StatusBadRequest
+				return
+			}
+			size = dr.Size()
+		}
+	}
 
 	if hdr != nil {
 		ranges, err := cmn.ParseMultiRange(goi.ranges.Range, size)
@@ -778,17 +965,24 @@ func (goi *getObjInfo) finalize(coldGet bool) (retry bool, err error, errCode in
 	cksumRange := cksumConf.Type != cmn.ChecksumNone && r != nil && cksumConf.EnableReadRange
 
 	if hdr != nil {
-		if goi.lom.Cksum() != nil && !cksumRange {
+		// (the stored checksum is that of the encrypted or compressed content)
+		if goi.lom.Cksum() != nil && !cksumRange && dr == nil {
 			cksumType, cksumValue := goi.lom.Cksum().Get()
 			if cksumType != cmn.ChecksumNone {
 				hdr.Set(cmn.HeaderObjCksumType, cksumType)
 				hdr.Set(cmn.HeaderObjCksumVal, cksumValue)
 			}
+		} else if !cksumRange && dr != nil {
+			origCksumToHdr(hdr, goi.lom)
 		}
 		if goi.lom.Version() != "" {
 			hdr.Set(cmn.HeaderObjVersion, goi.lom.Version())
 		}
-		hdr.Set(cmn.HeaderObjSize, strconv.FormatInt(goi.lom.Size(), 10))
+		if dr != nil {
+			hdr.Set(cmn.HeaderObjSize, strconv.FormatInt(dr.Size(), 10))
+		} else {
+			hdr.Set(cmn.HeaderObjSize, strconv.FormatInt(goi.lom.Size(), 10))
+		}
 		hdr.Set(cmn.HeaderObjAtime, cmn.UnixNano2S(goi.lom.AtimeUnix()))
 		if r != nil {
 			hdr.Set(cmn.HeaderContentLength, strconv.FormatInt(r.Length, 10))
@@ -798,7 +992,25 @@ func (goi *getObjInfo) finalize(coldGet bool) (retry bool, err error, errCode in
 	}
 
 	w := goi.w
-	if goi.tag == "" {
+	if dr != nil {
+		start, length := int64(0), size
+		if r != nil {
+			start, length = r.Start, r.Length
+		}
+		buf, slab = goi.t.gmm.Alloc(length)
+		reader = dr.NewRangeReader(start, length)
+		if cksumRange {
+			var cksum *cmn.CksumHash
+			sgl = slab.MMSA().NewSGL(length, slab.Size())
+			if _, cksum, err = cmn.CopyAndChecksum(sgl, reader, buf, cksumConf.Type); err != nil {
+				return
+			}
+			hdr.Set(cmn.HeaderObjCksumVal, cksum.Value())
+			hdr.Set(cmn.HeaderObjCksumType, cksumConf.Type)
+			reader = sgl
+		}
+		written, err = io.CopyBuffer(w, reader, buf)
+	} else if goi.tag == "" {
 		if r == nil {
 			reader = file
 			if goi.chunked {
@@ -926,7 +1138,7 @@ func (aoi *appendObjInfo) appendObject() (newHandle string, err error, errCode i
 			return
 		}
 		if _, err := aoi.t.PromoteFile(filePath, aoi.lom.Bck(), aoi.lom.ObjName, partialCksum,
-			true /*overwrite*/, false /*safe*/, false /*verbose*/); err != nil {
+			true /*overwrite*/, false /*safe*/, false /*verbose*/, false /*bypassGovernance*/); err != nil {
 			return "", err, 0
 		}
 	default:
@@ -0,0 +1,102 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/NVIDIA/aistore/ais/s3compat"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/fs"
+)
+
+// Object lock (see cmn.ObjLockConf)
+
+// NOTE: the proxy validates that only admins request to bypass governance
+// (see proxyrunner.checkBypassGovernance)
+func isBypassGovernance(r *http.Request) bool {
+	return cmn.IsParseBool(r.URL.Query().Get(cmn.URLParamBypassGovernance)) ||
+		cmn.IsParseBool(r.Header.Get(s3compat.HeaderBypassGovernance))
+}
+
+// POST { action: legalhold, value: true|false } /v1/objects/bucket-name/object-name
+func (t *targetrunner) setLegalHold(w http.ResponseWriter, r *http.Request, msg *cmn.ActionMsg) {
+	apitems, err := t.checkRESTItems(w, r, 2, false, cmn.Version, cmn.Objects)
+	if err != nil {
+		return
+	}
+	on, ok := msg.Value.(bool)
+	if !ok {
+		t.invalmsghdlrf(w, r, "%s: invalid value %v (expecting true or false)", msg.Action, msg.Value)
+		return
+	}
+	bck, err := newBckFromQuery(apitems[0], r.URL.Query())
+	if err != nil {
+		t.invalmsghdlr(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+	lom := &cluster.LOM{T: t, ObjName: apitems[1]}
+	if err = lom.Init(bck.Bck); err != nil {
+		t.invalmsghdlr(w, r, err.Error())
+		return
+	}
+	if !lom.Bprops().ObjLock.Enabled {
+		t.invalmsghdlrf(w, r, "%s: object lock is not enabled", lom.Bck())
+		return
+	}
+	lom.Lock(true)
+	defer lom.Unlock(true)
+	if err = lom.Load(false); err != nil {
+		if cmn.IsObjNotExist(err) {
+			t.invalmsghdlrstatusf(w, r, http.StatusNotFound, "%s %s", lom, cmn.DoesNotExist)
+		} else {
+			t.invalmsghdlr(w, r, err.Error())
+		}
+		return
+	}
+	lom.SetLegalHold(on)
+	if err = lom.Persist(); err != nil {
+		t.invalmsghdlr(w, r, err.Error())
+		return
+	}
+	lom.ReCache()
+}
+
+// checkRetained returns an error if the bucket contains at least one retained object
+func (t *targetrunner) checkRetained(bck *cluster.Bck, bypassGovernance bool) (err error) {
+	var (
+		retained          error
+		config            = cmn.GCO.Get()
+		availablePaths, _ = fs.Get()
+	)
+	cb := func(fqn string, de fs.DirEntry) error {
+		if de.IsDir() {
+			return nil
+		}
+		lom := &cluster.LOM{T: t, FQN: fqn}
+		if err := lom.Init(bck.Bck, config); err != nil {
+			return nil
+		}
+		if err := lom.Load(false); err != nil || lom.IsCopy() {
+			return nil
+		}
+		if retained = lom.ObjLockErr(bypassGovernance); retained != nil {
+			return cmn.NewAbortedError("check-retained")
+		}
+		return nil
+	}
+	for _, mpathInfo := range availablePaths {
+		opts := &fs.Options{Mpath: mpathInfo, Bck: bck.Bck, CTs: []string{fs.ObjectType}, Callback: cb}
+		if err = fs.Walk(opts); err != nil {
+			if retained != nil {
+				err = fmt.Errorf("%s: %s contains retained objects: %v", t.si, bck, retained)
+			}
+			return
+		}
+	}
+	return
+}
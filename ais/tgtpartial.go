@@ -0,0 +1,217 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/stats"
+)
+
+// Partial caching (see cmn.BckPartialCacheConf). A GET of a byte range of a
+// large cloud object fetches only the missing chunks that contain the range
+// (plus read-ahead) and writes them in place, into a sparse file of the size
+// of the entire object. The present chunks are tracked by cluster.PartialMD;
+// the object becomes regular (complete) once all its chunks are present.
+// A GET of the entire object (as well as the object's version change)
+// replaces a partially cached object by way of the regular cold GET.
+
+// partialRange returns true if the GET may be served by partial caching
+func (goi *getObjInfo) partialRange() bool {
+	bck := goi.lom.Bck()
+	if goi.ranges.Range == "" || goi.tag != "" || goi.isGFN {
+		return false
+	}
+	return bck.Props != nil && bck.Props.PartialCache.Enabled && bck.IsCloud()
+}
+
+// getPartial makes sure that the requested range is cached and returns with the
+// object read-locked. Fallback means that the object must be cold-GET in its
+// entirety (the lock is not held in this case).
+func (goi *getObjInfo) getPartial() (fallback bool, err error, errCode int) {
+	var (
+		pmd     *cluster.PartialMD
+		attrs   cluster.CloudObjAttrs
+		file    *os.File
+		fetched int64
+		created bool
+		lom     = goi.lom
+		conf    = lom.Bck().Props.PartialCache
+		cloud   = goi.t.Cloud(lom.Bck())
+	)
+	rr, ok := cloud.(cluster.CloudRangeReader)
+	if !ok {
+		return true, nil, 0
+	}
+	lom.Lock(true)
+	defer func() {
+		if file != nil {
+			file.Close()
+		}
+		if err == nil && !fallback {
+			return
+		}
+		if created {
+			if errRemove := cmn.RemoveFile(lom.FQN); errRemove != nil {
+				glog.Errorf("%s: failed to remove partial object: %v", lom, errRemove)
+			}
+		}
+		lom.Unlock(true)
+	}()
+
+	// load (and double-check) under exclusive lock
+	if err = lom.Load(); err != nil {
+		if !cmn.IsObjNotExist(err) {
+			return false, err, http.StatusInternalServerError
+		}
+		if pmd, fallback = goi.newPartialMD(cloud, conf); fallback {
+			return true, nil, 0
+		}
+		if file, err = cmn.CreateFile(lom.FQN); err != nil {
+			return false, err, http.StatusInternalServerError
+		}
+		created = true
+		if err = file.Truncate(pmd.Size); err != nil { // sparse
+			return false, err, http.StatusInternalServerError
+		}
+	} else {
+		if pmd, err = lom.PartialMD(); err != nil {
+			glog.Errorf("%v - proceeding to execute cold GET", err)
+			return true, nil, 0
+		}
+		if pmd == nil { // completed in the meantime
+			lom.DowngradeLock()
+			return false, nil, 0
+		}
+		if file, err = os.OpenFile(lom.FQN, os.O_WRONLY, 0); err != nil {
+			goi.t.fshc(err, lom.FQN)
+			return false, err, http.StatusInternalServerError
+		}
+	}
+
+	ranges, err := cmn.ParseMultiRange(goi.ranges.Range, pmd.Size)
+	if err != nil {
+		return false, err, http.StatusRequestedRangeNotSatisfiable
+	}
+	if len(ranges) != 1 {
+		return true, nil, 0
+	}
+	first, last := pmd.Chunks(ranges[0].Start, ranges[0].Length)
+	last = cmn.Min(last+conf.ReadAhead, pmd.NumChunks()-1)
+
+	// fetch contiguous runs of missing chunks
+	buf, slab := goi.t.gmm.Alloc()
+	defer slab.Free(buf)
+	for i := first; i <= last; i++ {
+		if pmd.Has(i) {
+			continue
+		}
+		j := i
+		for j < last && !pmd.Has(j+1) {
+			j++
+		}
+		var n int64
+		if n, attrs, err, errCode = goi.fetchChunks(rr, file, pmd, i, j, buf); err != nil {
+			if errCode == http.StatusConflict {
+				glog.Warningf("%v - proceeding to execute cold GET", err)
+				return true, nil, 0
+			}
+			return
+		}
+		fetched += n
+		i = j
+	}
+
+	// update metadata
+	if fetched > 0 {
+		lom.SetSize(pmd.Size)
+		lom.SetCksum(nil)
+		lom.SetVersion(attrs.Version)
+		sysMD := cmn.SimpleKVs{cluster.SourceObjMD: cloud.Provider()}
+		if attrs.Version != "" {
+			sysMD[cluster.VersionObjMD] = attrs.Version
+		}
+		lom.SetSysMD(sysMD)
+	}
+	if pmd.Complete() {
+		lom.SetPartialMD(nil)
+	} else {
+		lom.SetPartialMD(pmd)
+	}
+	lom.SetAtimeUnix(goi.started.UnixNano())
+	if err = lom.Persist(); err != nil {
+		return false, err, http.StatusInternalServerError
+	}
+	lom.ReCache()
+	created = false
+	if fetched > 0 {
+		goi.t.statsT.AddMany(
+			stats.NamedVal64{Name: stats.GetColdCount, Value: 1},
+			stats.NamedVal64{Name: stats.GetColdSize, Value: fetched},
+		)
+	}
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("GET %s: chunks [%d, %d], fetched %s, cached %s", lom, first, last,
+			cmn.B2S(fetched, 1), cmn.B2S(pmd.Cached(), 1))
+	}
+	lom.DowngradeLock()
+	return
+}
+
+// newPartialMD returns nil (and fallback) if the object is too small to be cached partially
+func (goi *getObjInfo) newPartialMD(cloud cluster.CloudProvider,
+	conf cmn.BckPartialCacheConf) (pmd *cluster.PartialMD, fallback bool) {
+	objMeta, err, _ := cloud.HeadObj(goi.ctx, goi.lom)
+	if err != nil {
+		return nil, true // (cold GET will fail as well)
+	}
+	size, err := strconv.ParseInt(objMeta[cmn.HeaderObjSize], 10, 64)
+	if err != nil || size < conf.MinObjSize || size == 0 {
+		return nil, true
+	}
+	return cluster.NewPartialMD(size, conf.ChunkSize, ""), false
+}
+
+// fetchChunks reads a given run of chunks from the cloud and writes it in place;
+// StatusConflict is returned if the cloud object has changed in the meantime
+func (goi *getObjInfo) fetchChunks(rr cluster.CloudRangeReader, file *os.File, pmd *cluster.PartialMD,
+	first, last int, buf []byte) (n int64, attrs cluster.CloudObjAttrs, err error, errCode int) {
+	var (
+		r              io.ReadCloser
+		offset, _      = pmd.Chunk(first)
+		lastOff, lsize = pmd.Chunk(last)
+		length         = lastOff + lsize - offset
+	)
+	if r, attrs, err, errCode = rr.GetObjRange(goi.ctx, goi.lom, offset, length); err != nil {
+		return
+	}
+	defer r.Close()
+	if attrs.Size != pmd.Size || (pmd.ETag != "" && attrs.ETag != pmd.ETag) {
+		err = fmt.Errorf("%s: cloud object has changed (size %d, etag %q) since partially cached (size %d, etag %q)",
+			goi.lom, attrs.Size, attrs.ETag, pmd.Size, pmd.ETag)
+		return 0, attrs, err, http.StatusConflict
+	}
+	if _, err = file.Seek(offset, io.SeekStart); err == nil {
+		n, err = io.CopyBuffer(file, io.LimitReader(r, length), buf)
+		if err == nil && n != length {
+			err = fmt.Errorf("%s: short read at offset %d: %d (expected %d)", goi.lom, offset, n, length)
+		}
+	}
+	if err != nil {
+		return 0, attrs, err, http.StatusInternalServerError
+	}
+	for i := first; i <= last; i++ {
+		pmd.Set(i)
+	}
+	pmd.ETag = attrs.ETag
+	return
+}
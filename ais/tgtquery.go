@@ -5,20 +5,33 @@
 package ais
 
 import (
+	"archive/tar"
 	"io"
 	"net/http"
+	"os"
+	"sync"
 
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/debug"
+	"github.com/NVIDIA/aistore/dbdriver"
 	"github.com/NVIDIA/aistore/objwalk/walkinfo"
 	"github.com/NVIDIA/aistore/query"
 	"github.com/NVIDIA/aistore/xaction"
 )
 
+// serializes resuming persisted queries (see `queryXact`)
+var queryResumeMtx sync.Mutex
+
 // There are 3 methods exposed by targets:
 // * Peek(n): get next n objects from a target query, but keep the results in memory.
 //   Subsequent Peek(n) request returns the same objects.
 // * Discard(n): forget first n elements from a target query.
 // * Next(n): Peek(n) + Discard(n)
+// Plus, Select(query) streams the records of the local shards selected by
+// a query with InnerSelect (see `httpqueryselect`), and Aggregate(query)
+// computes local object counts and histograms (see `httpqueryaggregate`).
 
 func (t *targetrunner) queryHandler(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
@@ -34,10 +47,24 @@ func (t *targetrunner) queryHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 func (t *targetrunner) httpquerypost(w http.ResponseWriter, r *http.Request) {
-	if _, err := t.checkRESTItems(w, r, 0, false, cmn.Version, cmn.Query, cmn.Init); err != nil {
+	apiItems, err := t.checkRESTItems(w, r, 1, false, cmn.Version, cmn.Query)
+	if err != nil {
 		return
 	}
 
+	switch apiItems[0] {
+	case cmn.Init:
+		t.httpqueryinit(w, r)
+	case cmn.Select:
+		t.httpqueryselect(w, r)
+	case cmn.Aggregate:
+		t.httpqueryaggregate(w, r)
+	default:
+		t.invalmsghdlrf(w, r, "unknown path /%s/%s/%s", cmn.Version, cmn.Query, apiItems[0])
+	}
+}
+
+func (t *targetrunner) httpqueryinit(w http.ResponseWriter, r *http.Request) {
 	var (
 		handle = r.Header.Get(cmn.HeaderHandle) // TODO: should it be from header or from body?
 		owner  = r.Header.Get(cmn.HeaderCallerID)
@@ -46,34 +73,208 @@ func (t *targetrunner) httpquerypost(w http.ResponseWriter, r *http.Request) {
 	if err := cmn.ReadJSON(w, r, msg); err != nil {
 		return
 	}
+	if _, err := t.startQuery(handle, &query.PersistedQuery{Msg: *msg, Owner: owner}); err != nil {
+		t.invalmsghdlr(w, r, err.Error())
+	}
+}
 
-	q, err := query.NewQueryFromMsg(&msg.QueryMsg)
+// startQuery starts a new (or resumes a persisted) query
+func (t *targetrunner) startQuery(handle string, pq *query.PersistedQuery) (*query.ObjectsListingXact, error) {
+	q, err := query.NewQueryFromMsg(&pq.Msg.QueryMsg)
 	if err != nil {
-		t.invalmsghdlr(w, r, err.Error())
-		return
+		return nil, err
 	}
 
-	wi := walkinfo.NewDefaultWalkInfo(t, msg.QueryMsg.From.Bck.Name)
+	wi := walkinfo.NewDefaultWalkInfo(t, pq.Msg.QueryMsg.From.Bck.Name)
 	wi.SetObjectFilter(q.Filter())
 
 	xact, isNew, err := xaction.Registry.RenewObjectsListingXact(t, q, wi, handle)
-	if err != nil {
-		t.invalmsghdlr(w, r, err.Error())
-		return
+	if err != nil || !isNew {
+		return xact, err
 	}
-	if !isNew {
-		return
+	if err := xact.Persist(t.dbDriver, pq); err != nil {
+		// the query still works but it won't survive a restart
+		glog.Errorf("%s: failed to persist query %q: %v", t.si, handle, err)
 	}
 
 	xact.AddNotif(&cmn.NotifXact{
 		NotifBase: cmn.NotifBase{
 			When: cmn.UponTerm,
-			Dsts: []string{owner},
+			Dsts: []string{pq.Owner},
 			F:    t.xactCallerNotify,
 		},
 	})
 
+	query.Registry.Put(handle, xact) // so that it is immediately visible (see `queryXact`)
 	go xact.Start()
+	return xact, nil
+}
+
+// queryXact returns the query registered under the handle. If there is none
+// (e.g., the target has restarted or the query has been idle for too long),
+// the persisted query, if any, gets resumed from its cursor.
+func (t *targetrunner) queryXact(handle string) *query.ObjectsListingXact {
+	if xact := query.Registry.Get(handle); xact != nil {
+		return xact
+	}
+	queryResumeMtx.Lock()
+	defer queryResumeMtx.Unlock()
+	if xact := query.Registry.Get(handle); xact != nil {
+		return xact
+	}
+	pq, err := query.LoadPersisted(t.dbDriver, handle)
+	if err != nil {
+		if !dbdriver.IsErrNotFound(err) {
+			glog.Error(err)
+		}
+		return nil
+	}
+	xact, err := t.startQuery(handle, pq)
+	if err != nil {
+		glog.Errorf("%s: failed to resume query %q: %v", t.si, handle, err)
+		return nil
+	}
+	glog.Infof("%s: resumed query %q after %q", t.si, handle, pq.Cursor)
+	return xact
+}
+
+// /v1/query/select
+// Records are either streamed as a single tar (named "<shard>/<record>") or,
+// if requested, listed.
+func (t *targetrunner) httpqueryselect(w http.ResponseWriter, r *http.Request) {
+	var (
+		tw       *tar.Writer
+		entries  []*cmn.BucketEntry
+		selected int
+		msg      = &query.DefMsg{}
+	)
+	if err := cmn.ReadJSON(w, r, msg); err != nil {
+		return
+	}
+	if msg.InnerSelect == nil {
+		t.invalmsghdlr(w, r, "inner select is not defined", http.StatusBadRequest)
+		return
+	}
+	q, err := query.NewQueryFromMsg(msg)
+	if err != nil {
+		t.invalmsghdlr(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+	bck := cluster.NewBckEmbed(msg.From.Bck)
+	if err := bck.Init(t.owner.bmd, t.si); err != nil {
+		t.invalmsghdlr(w, r, err.Error())
+		return
+	}
+
+	if !msg.InnerSelect.NamesOnly {
+		tw = tar.NewWriter(w)
+	}
+	err = t.queryForEachLOM(q, bck, func(lom *cluster.LOM) error {
+		file, err := os.Open(lom.FQN)
+		if err != nil {
+			return err
+		}
+		defer func() { debug.AssertNoErr(file.Close()) }()
+		sr, err := newSectionReader(file, lom)
+		if err != nil {
+			return err
+		}
+		return query.SelectRecords(lom, sr, q.RecordFilter(), func(rec *query.Record, r io.Reader) error {
+			selected++
+			if tw == nil {
+				entries = append(entries, &cmn.BucketEntry{Name: rec.FullName(), Size: rec.Size})
+				return nil
+			}
+			hdr := &tar.Header{
+				Typeflag: tar.TypeReg,
+				Name:     rec.FullName(),
+				Size:     rec.Size,
+				Mode:     0644,
+				ModTime:  lom.Atime(),
+			}
+			if err := tw.WriteHeader(hdr); err != nil {
+				return err
+			}
+			_, err := io.Copy(tw, r)
+			return err
+		})
+	})
+	if err != nil {
+		if selected == 0 || tw == nil {
+			t.invalmsghdlr(w, r, err.Error())
+		} else {
+			// the response is partially written - the tar remains unterminated
+			glog.Errorf("%s: failed to select records: %v", t.si, err)
+		}
+		return
+	}
+	if tw == nil {
+		w.Write(cmn.MustMarshal(entries))
+		return
+	}
+	if err := tw.Close(); err != nil {
+		glog.Errorf("%s: failed to select records: %v", t.si, err)
+	}
+}
+
+// /v1/query/aggregate
+func (t *targetrunner) httpqueryaggregate(w http.ResponseWriter, r *http.Request) {
+	msg := &query.DefMsg{}
+	if err := cmn.ReadJSON(w, r, msg); err != nil {
+		return
+	}
+	if msg.Aggregate == nil {
+		t.invalmsghdlr(w, r, "aggregate is not defined", http.StatusBadRequest)
+		return
+	}
+	q, err := query.NewQueryFromMsg(msg)
+	if err != nil {
+		t.invalmsghdlr(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+	bck := cluster.NewBckEmbed(msg.From.Bck)
+	if err := bck.Init(t.owner.bmd, t.si); err != nil {
+		t.invalmsghdlr(w, r, err.Error())
+		return
+	}
+
+	aggr := query.NewAggregator(msg.Aggregate)
+	err = t.queryForEachLOM(q, bck, func(lom *cluster.LOM) error {
+		aggr.Add(lom)
+		return nil
+	})
+	if err != nil {
+		t.invalmsghdlr(w, r, err.Error())
+		return
+	}
+	w.Write(cmn.MustMarshal(aggr.Result()))
+}
+
+// queryForEachLOM runs the query locally (not registering it with xaction.Registry)
+// and calls `cb` for each selected object that is loaded and read-locked.
+func (t *targetrunner) queryForEachLOM(q *query.ObjectsQuery, bck *cluster.Bck, cb func(lom *cluster.LOM) error) error {
+	config := cmn.GCO.Get()
+	wi := walkinfo.NewDefaultWalkInfo(t, bck.Name)
+	wi.SetObjectFilter(q.Filter())
+	xact := query.NewObjectsListing(t, q, wi, cmn.GenUUID())
+	go xact.Start()
+	defer query.Registry.Delete(xact.ID().String())
+
+	return xact.ForEach(func(entry *cmn.BucketEntry) error {
+		lom := &cluster.LOM{T: t, ObjName: entry.Name}
+		if err := lom.Init(bck.Bck, config); err != nil {
+			return err
+		}
+		lom.Lock(false)
+		defer lom.Unlock(false)
+		if err := lom.Load(); err != nil {
+			if cmn.IsObjNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		return cb(lom)
+	})
 }
 
 func (t *targetrunner) httpqueryget(w http.ResponseWriter, r *http.Request) {
@@ -116,7 +317,7 @@ func (t *targetrunner) httpquerygetobjects(w http.ResponseWriter, r *http.Reques
 		t.invalmsghdlr(w, r, "handle cannot be empty", http.StatusBadRequest)
 		return
 	}
-	resultSet := query.Registry.Get(msg.Handle)
+	resultSet := t.queryXact(msg.Handle)
 	if resultSet == nil {
 		t.queryDoesntExist(w, r, msg.Handle)
 		return
@@ -148,7 +349,7 @@ func (t *targetrunner) httpqueryput(w http.ResponseWriter, r *http.Request) {
 	}
 
 	handle, value := apiItems[0], apiItems[1]
-	resultSet := query.Registry.Get(handle)
+	resultSet := t.queryXact(handle)
 	if resultSet == nil {
 		t.queryDoesntExist(w, r, handle)
 		return
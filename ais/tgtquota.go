@@ -0,0 +1,268 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/xaction"
+	jsoniter "github.com/json-iterator/go"
+)
+
+// Bucket quotas (see cmn.QuotaConf and cluster.BckUsage)
+
+const (
+	quotaHkName          = "bucket-quota"
+	quotaHkInterval      = 10 * time.Minute
+	quotaPeersHkName     = "bucket-quota-peers"
+	quotaPeersHkInterval = 30 * time.Second
+	quotaPollIval        = time.Second
+	quotaWaitTimeout     = time.Minute
+)
+
+var errQuotaUsageUnknown = errors.New("bucket usage is not known yet")
+
+// enforceQuota returns false for the objects that are moved or copied within
+// the cluster (rebalance, mirroring, erasure coding) - those are accounted but
+// never rejected.
+func (poi *putObjInfo) enforceQuota() bool { return !poi.migrated || poi.userWrite || poi.cold }
+
+// storedSize returns the size of the object that is about to be overwritten
+// (if any) - the same size that gets subtracted upon removal (see LOM.Remove).
+// Must be called under the object's lock.
+func storedSize(lom *cluster.LOM) (size int64, exists bool) {
+	stored := lom.Clone(lom.FQN)
+	if err := stored.Load(false); err != nil {
+		return 0, false
+	}
+	return stored.Size(), true
+}
+
+// checkQuota fails early - prior to receiving the content - if writing
+// the object (of the size specified by Content-Length) would exceed the quota.
+func (poi *putObjInfo) checkQuota() (err error, errCode int) {
+	var (
+		lom = poi.lom
+		bck = lom.Bck()
+	)
+	if err = poi.t.waitQuotaUsage(bck, true /*peers*/); err != nil {
+		return fmt.Errorf("%s: %v", bck, err), http.StatusServiceUnavailable
+	}
+	objs, size := int64(1), poi.size
+	lom.Lock(false)
+	if stored, exists := storedSize(lom); exists {
+		objs, size = 0, size-stored // overwrite
+	}
+	lom.Unlock(false)
+	if err = cluster.BckUsage.Check(bck, objs, size); err != nil {
+		return err, http.StatusInsufficientStorage
+	}
+	return nil, 0
+}
+
+// waitQuota makes sure that the bucket's usage is known prior to taking the
+// object's lock (see reserveQuota)
+func (t *targetrunner) waitQuota(bck *cluster.Bck, enforce bool) (err error) {
+	if !bck.Props.Quota.Enabled {
+		return
+	}
+	if enforce {
+		if err = t.waitQuotaUsage(bck, true /*peers*/); err != nil {
+			err = fmt.Errorf("%s: %v", bck, err)
+		}
+	} else if _, _, reconcile := cluster.BckUsage.Init(bck); reconcile {
+		go t.reconcileQuota(bck)
+	}
+	return
+}
+
+// reserveQuota accounts for the object that is about to be written and
+// returns the accounted delta (to be released if the write fails).
+// Must be called under the object's write lock.
+func reserveQuota(lom *cluster.LOM, enforce bool) (objs, size int64, err error) {
+	bck := lom.Bck()
+	if !bck.Props.Quota.Enabled {
+		return
+	}
+	objs, size = 1, lom.Size()
+	if stored, exists := storedSize(lom); exists {
+		objs, size = 0, size-stored // overwrite
+	}
+	if err = cluster.BckUsage.Reserve(bck, objs, size, enforce); err != nil {
+		objs, size = 0, 0
+	}
+	return
+}
+
+// waitQuotaUsage makes sure that the bucket's usage is known - reconciles it,
+// if need be - and waits for it to be known (for a limited time).
+// NOTE: waiting for the usage on other targets must be avoided when serving
+// their requests (see getQuotaUsage) - they may be waiting for ours.
+func (t *targetrunner) waitQuotaUsage(bck *cluster.Bck, peers bool) error {
+	ready, peersReady, reconcile := cluster.BckUsage.Init(bck)
+	if reconcile {
+		go t.reconcileQuota(bck)
+	}
+	timer := time.NewTimer(quotaWaitTimeout)
+	defer timer.Stop()
+	select {
+	case <-ready:
+	case <-timer.C:
+		return errQuotaUsageUnknown
+	}
+	if _, _, ok := cluster.BckUsage.Get(bck); !ok {
+		return errQuotaUsageUnknown
+	}
+	if !peers {
+		return nil
+	}
+	select {
+	case <-peersReady:
+		return nil
+	case <-timer.C:
+		return errQuotaUsageUnknown
+	}
+}
+
+// GET /v1/buckets/<bucket-name>?what=quota_usage
+func (t *targetrunner) getQuotaUsage(w http.ResponseWriter, r *http.Request, bck *cluster.Bck) {
+	if !bck.Props.Quota.Enabled {
+		t.invalmsghdlrf(w, r, "%s: quota is not enabled", bck)
+		return
+	}
+	if err := t.waitQuotaUsage(bck, false /*peers*/); err != nil {
+		t.invalmsghdlrstatusf(w, r, http.StatusServiceUnavailable, "%s: %v", bck, err)
+		return
+	}
+	objs, size, _ := cluster.BckUsage.Get(bck)
+	t.writeJSON(w, r, &cmn.QuotaUsage{Objs: objs, Size: size}, "quota-usage")
+}
+
+func (t *targetrunner) quotaHk() time.Duration {
+	go t.reconcileQuotas()
+	return quotaHkInterval
+}
+
+func (t *targetrunner) quotaPeersHk() time.Duration {
+	go t.refreshPeersQuotas()
+	return quotaPeersHkInterval
+}
+
+func (t *targetrunner) quotaBuckets() (bcks []*cluster.Bck) {
+	t.owner.bmd.get().Range(nil, nil, func(bck *cluster.Bck) bool {
+		if bck.Props.Quota.Enabled {
+			bcks = append(bcks, bck)
+		}
+		return false
+	})
+	return
+}
+
+// reconcileQuotas runs periodically to correct the drift (if any) between
+// the incrementally tracked and the actual usage
+func (t *targetrunner) reconcileQuotas() {
+	var (
+		bcks = t.quotaBuckets()
+		bids = make(map[uint64]struct{}, len(bcks))
+	)
+	for _, bck := range bcks {
+		bids[bck.Props.BID] = struct{}{}
+	}
+	cluster.BckUsage.Prune(func(bid uint64) bool {
+		_, ok := bids[bid]
+		return ok
+	})
+	for _, bck := range bcks {
+		t.reconcileQuota(bck)
+	}
+}
+
+func (t *targetrunner) reconcileQuota(bck *cluster.Bck) {
+	objs, size, err := t.bckUsage(bck)
+	if err != nil {
+		glog.Errorf("%s: failed to reconcile %s quota usage: %v", t.si, bck, err)
+		if _, _, ok := cluster.BckUsage.Get(bck); !ok {
+			cluster.BckUsage.Del(bck) // to retry
+		}
+		return
+	}
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("%s: %s usage: %d objects, %s", t.si, bck, objs, cmn.B2S(size, 2))
+	}
+	cluster.BckUsage.Set(bck, objs, size)
+	t.refreshPeersQuota(bck)
+}
+
+// refreshPeersQuotas runs periodically to update the usage of the buckets
+// on the other targets
+func (t *targetrunner) refreshPeersQuotas() {
+	for _, bck := range t.quotaBuckets() {
+		if _, _, ok := cluster.BckUsage.Get(bck); ok {
+			t.refreshPeersQuota(bck)
+		}
+	}
+}
+
+// refreshPeersQuota sums up the usage of the bucket on all other targets;
+// the targets that fail to respond are logged and not counted
+func (t *targetrunner) refreshPeersQuota(bck *cluster.Bck) {
+	var (
+		objs, size int64
+		query      = cmn.AddBckToQuery(url.Values{cmn.URLParamWhat: []string{cmn.GetWhatQuotaUsage}}, bck.Bck)
+		results    = t.bcastGet(bcastArgs{
+			req: cmn.ReqArgs{
+				Path:  cmn.URLPath(cmn.Version, cmn.Buckets, bck.Name),
+				Query: query,
+			},
+			timeout: quotaWaitTimeout,
+			to:      cluster.Targets,
+		})
+	)
+	for res := range results {
+		if res.err != nil {
+			glog.Errorf("%s: failed to get %s quota usage from %s: %v", t.si, bck, res.si, res.err)
+			continue
+		}
+		usage := &cmn.QuotaUsage{}
+		if err := jsoniter.Unmarshal(res.outjson, usage); err != nil {
+			glog.Errorf("%s: invalid %s quota usage from %s: %v", t.si, bck, res.si, err)
+			continue
+		}
+		objs += usage.Objs
+		size += usage.Size
+	}
+	cluster.BckUsage.SetPeers(bck, objs, size)
+}
+
+// bckUsage computes the local usage of the bucket via (fast) bucket summary
+func (t *targetrunner) bckUsage(bck *cluster.Bck) (objs, size int64, err error) {
+	smsg := &cmn.SelectMsg{UUID: cmn.GenUUID(), Fast: true, Cached: true}
+	xact, err := xaction.Registry.RenewBckSummaryXact(context.Background(), t, bck, smsg)
+	if err != nil {
+		return
+	}
+	for !xact.Finished() {
+		time.Sleep(quotaPollIval)
+	}
+	result, err := xact.Result()
+	if err != nil {
+		return
+	}
+	for _, summary := range result.(cmn.BucketsSummaries) {
+		if summary.Bck.Equal(bck.Bck) {
+			return int64(summary.ObjCount), int64(summary.Size), nil
+		}
+	}
+	err = fmt.Errorf("%s: no summary", bck)
+	return
+}
@@ -0,0 +1,209 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/debug"
+	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/replication"
+	"github.com/NVIDIA/aistore/stats"
+)
+
+// Replication of ais buckets to remote AIS clusters (see cmn.BckReplicationConf
+// and package replication). PUTs and deletes are queued by the target that
+// stores (or removes) the object; the object's current content gets sent when
+// the entry reaches the head of the queue - decoded if encrypted, compressed,
+// or deduplicated. An object that is no longer stored locally (e.g., moved by
+// rebalance or copied to another target) is fetched from its HRW target.
+
+type (
+	// re-openable (see cmn.ReadOpenCloser) content of an open object; the
+	// object is not locked while being sent
+	sectionHandle struct {
+		sr     *io.SectionReader
+		reader *io.SectionReader
+	}
+)
+
+// interface guard
+var _ cmn.ReadOpenCloser = &sectionHandle{}
+
+func replicated(lom *cluster.LOM) bool {
+	return lom.Bck().IsAIS() && lom.Bprops().Replication.Enabled
+}
+
+func (t *targetrunner) replicatePut(lom *cluster.LOM) {
+	if replicated(lom) {
+		t.replicator.Enqueue(lom.Bck().Bck, lom.ObjName, replication.OpPut)
+	}
+}
+
+func (t *targetrunner) replicateDelete(lom *cluster.LOM) {
+	if replicated(lom) {
+		t.replicator.Enqueue(lom.Bck().Bck, lom.ObjName, replication.OpDelete)
+	}
+}
+
+// sendReplica is the replication.SendFunc
+func (t *targetrunner) sendReplica(e *replication.Entry) (size int64, err error) {
+	lom := &cluster.LOM{T: t, ObjName: e.ObjName}
+	if err = lom.Init(e.Bck); err != nil {
+		if cmn.IsErrBucketNought(err) {
+			glog.Warningf("%s: %v - skipping", e, err)
+			err = nil
+		}
+		return
+	}
+	conf := &lom.Bprops().Replication
+	if !conf.Enabled {
+		return // disabled since
+	}
+	remoteBck := conf.RemoteBck(e.Bck)
+	if e.Op == replication.OpDelete {
+		err, errCode := t.cloud.ais.DeleteReplica(remoteBck, e.ObjName)
+		if errCode == http.StatusNotFound {
+			err = nil
+		}
+		if err != nil {
+			t.statsT.Add(stats.ErrReplCount, 1)
+			return 0, err
+		}
+		t.statsT.Add(stats.ReplDelCount, 1)
+		return 0, nil
+	}
+
+	file, sr, cksum, err := openReplica(lom)
+	if err != nil {
+		if cmn.IsObjNotExist(err) {
+			return t.sendMovedReplica(lom, remoteBck)
+		}
+		return 0, err
+	}
+	defer func() {
+		debug.AssertNoErr(file.Close())
+	}()
+	return t.putReplica(remoteBck, lom.ObjName, newSectionHandle(sr), sr.Size(), cksum)
+}
+
+// openReplica opens the object under the read lock and returns its (decoded)
+// content along with the checksum of the latter, if known
+func openReplica(lom *cluster.LOM) (file *os.File, sr *io.SectionReader, cksum *cmn.Cksum, err error) {
+	lom.Lock(false)
+	defer lom.Unlock(false)
+	if err = lom.Load(false); err != nil {
+		return
+	}
+	if file, err = os.Open(lom.FQN); err != nil {
+		return
+	}
+	if sr, err = newSectionReader(file, lom); err != nil {
+		debug.AssertNoErr(file.Close())
+		return nil, nil, nil, err
+	}
+	cksum = lom.Cksum()
+	if _, _, origCksum, ok := lom.Compressed(); ok {
+		cksum = origCksum
+	} else if lom.IsEncrypted() || lom.IsDeduped() {
+		cksum = nil
+	}
+	return
+}
+
+// sendMovedReplica sends the object that is not stored locally: fetches it
+// from its current (HRW) target unless this target is the one
+func (t *targetrunner) sendMovedReplica(lom *cluster.LOM, remoteBck cmn.Bck) (size int64, err error) {
+	tsi, err := cluster.HrwTarget(lom.Uname(), &t.owner.smap.get().Smap)
+	if err != nil {
+		return 0, err
+	}
+	if tsi.ID() == t.si.ID() {
+		return 0, nil // deleted since (and the delete is queued)
+	}
+	workFQN := fs.CSM.GenContentParsedFQN(lom.ParsedFQN, fs.WorkfileType, fs.WorkfileRemote)
+	if size, err = t.fetchReplica(lom, tsi, workFQN); err != nil || size < 0 {
+		return 0, err
+	}
+	defer func() {
+		if errRemove := cmn.RemoveFile(workFQN); errRemove != nil {
+			glog.Errorf("failed to remove %s: %v", workFQN, errRemove)
+		}
+	}()
+	roc, err := cmn.NewFileHandle(workFQN)
+	if err != nil {
+		return 0, err
+	}
+	return t.putReplica(remoteBck, lom.ObjName, roc, size, nil)
+}
+
+// fetchReplica GETs the (decoded) object from a given target into a work file;
+// returns -1 if the object does not exist there either (deleted since)
+func (t *targetrunner) fetchReplica(lom *cluster.LOM, tsi *cluster.Snode, workFQN string) (size int64, err error) {
+	reqArgs := cmn.ReqArgs{
+		Method: http.MethodGet,
+		Base:   tsi.URL(cmn.NetworkIntraData),
+		Path:   cmn.URLPath(cmn.Version, cmn.Objects, lom.BckName(), lom.ObjName),
+		Query:  cmn.AddBckToQuery(nil, lom.Bck().Bck),
+	}
+	req, _, cancel, err := reqArgs.ReqWithTimeout(lom.Config().Timeout.SendFile)
+	if err != nil {
+		return 0, err
+	}
+	defer cancel()
+	resp, err := t.httpclientGetPut.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return -1, nil
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		return 0, fmt.Errorf("%s: failed to GET %s from %s, status %d", t.si, lom, tsi, resp.StatusCode)
+	}
+	buf, slab := t.gmm.Alloc()
+	_, err = cmn.SaveReader(workFQN, resp.Body, buf, cmn.ChecksumNone, resp.ContentLength, "")
+	slab.Free(buf)
+	if err != nil {
+		return 0, err
+	}
+	finfo, err := os.Stat(workFQN)
+	if err != nil {
+		return 0, err
+	}
+	return finfo.Size(), nil
+}
+
+func (t *targetrunner) putReplica(remoteBck cmn.Bck, objName string, roc cmn.ReadOpenCloser,
+	size int64, cksum *cmn.Cksum) (int64, error) {
+	if err, _ := t.cloud.ais.PutReplica(remoteBck, objName, roc, size, cksum); err != nil {
+		t.statsT.Add(stats.ErrReplCount, 1)
+		return 0, err
+	}
+	t.statsT.AddMany(
+		stats.NamedVal64{Name: stats.ReplPutCount, Value: 1},
+		stats.NamedVal64{Name: stats.ReplPutSize, Value: size},
+	)
+	return size, nil
+}
+
+///////////////////
+// sectionHandle //
+///////////////////
+
+func newSectionHandle(sr *io.SectionReader) *sectionHandle {
+	return &sectionHandle{sr: sr, reader: io.NewSectionReader(sr, 0, sr.Size())}
+}
+
+func (sh *sectionHandle) Read(p []byte) (int, error)   { return sh.reader.Read(p) }
+func (sh *sectionHandle) Close() error                 { return nil } // the file is closed by the sender
+func (sh *sectionHandle) Open() (io.ReadCloser, error) { return newSectionHandle(sh.sr), nil }
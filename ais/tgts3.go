@@ -16,6 +16,7 @@ import (
 	"github.com/NVIDIA/aistore/ais/s3compat"
 	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/debug"
 	"github.com/NVIDIA/aistore/ec"
 	"github.com/NVIDIA/aistore/fs"
 	"github.com/NVIDIA/aistore/tar2tf"
@@ -28,14 +29,53 @@ func (t *targetrunner) s3Handler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if cmn.GCO.Get().Auth.Enabled {
+		// the proxy has checked the signature; the body is checked here
+		s3compat.VerifyPayload(r)
+	}
+	q := r.URL.Query()
+	if _, tagging := q[s3compat.URLParamTagging]; tagging {
+		switch r.Method {
+		case http.MethodGet:
+			t.getObjTaggingS3(w, r, apitems)
+		case http.MethodPut, http.MethodDelete:
+			t.setObjTaggingS3(w, r, apitems)
+		default:
+			t.invalmsghdlrf(w, r, "Invalid HTTP Method: %v %s", r.Method, r.URL.Path)
+		}
+		return
+	}
+	_, mpt := q[s3compat.URLParamMptUploadID]
 	switch r.Method {
 	case http.MethodHead:
 		t.headObjS3(w, r, apitems)
 	case http.MethodGet:
+		if mpt {
+			t.listMptPartsS3(w, r, apitems)
+			return
+		}
 		t.getObjS3(w, r, apitems)
 	case http.MethodPut:
+		if mpt {
+			t.putMptPartS3(w, r, apitems)
+			return
+		}
 		t.putObjS3(w, r, apitems)
+	case http.MethodPost:
+		if mpt {
+			t.completeMptS3(w, r, apitems)
+			return
+		}
+		if _, start := q[s3compat.URLParamMptUploads]; start {
+			t.startMptS3(w, r, apitems)
+			return
+		}
+		t.invalmsghdlr(w, r, "invalid request")
 	case http.MethodDelete:
+		if mpt {
+			t.abortMptS3(w, r, apitems)
+			return
+		}
 		t.delObjS3(w, r, apitems)
 	default:
 		t.invalmsghdlrf(w, r, "Invalid HTTP Method: %v %s", r.Method, r.URL.Path)
@@ -142,11 +182,27 @@ func (t *targetrunner) directPutObjS3(w http.ResponseWriter, r *http.Request, it
 
 	// TODO: lom.SetCustomMD(cluster.AmazonMD5ObjMD, checksum)
 
+	userMD, err := s3compat.UserMDFromHeader(r.Header)
+	if err != nil {
+		t.invalmsghdlr(w, r, err.Error())
+		return
+	}
+	tags, err := s3compat.TagsFromHeader(r.Header)
+	if err != nil {
+		t.invalmsghdlr(w, r, err.Error())
+		return
+	}
+	lom.SetUserMD(userMD)
+	lom.SetTags(tags)
+
 	if err, errCode := t.doPut(r, lom, started); err != nil {
 		t.fshc(err, lom.FQN)
 		t.invalmsghdlr(w, r, err.Error(), errCode)
 		return
 	}
+	if lom.Version() != "" {
+		w.Header().Set(s3compat.HeaderVersion, lom.Version())
+	}
 }
 
 // PUT s3/bckName/objName
@@ -200,6 +256,28 @@ func (t *targetrunner) getObjS3(w http.ResponseWriter, r *http.Request, items []
 		}
 		return
 	}
+	version := r.URL.Query().Get(s3compat.URLParamVersionID)
+	if version != "" {
+		vlom, err, errCode := t.lookupVersion(lom, version)
+		if err != nil {
+			t.invalmsghdlr(w, r, err.Error(), errCode)
+			return
+		}
+		s3compat.SetHeaderFromLOM(w.Header(), vlom, vlom.Size())
+		goi := &getObjInfo{
+			started: started,
+			t:       t,
+			lom:     lom,
+			w:       w,
+			ctx:     context.Background(),
+			ranges:  cmn.RangesQuery{Range: r.Header.Get(cmn.HeaderRange), Size: vlom.Size()},
+			version: version,
+		}
+		if err, errCode := goi.getObject(); err != nil && !cmn.IsErrConnectionReset(err) {
+			t.invalmsghdlr(w, r, err.Error(), errCode)
+		}
+		return
+	}
 	if err = lom.Load(true); err != nil {
 		t.invalmsghdlr(w, r, err.Error())
 		return
@@ -259,6 +337,15 @@ func (t *targetrunner) headObjS3(w http.ResponseWriter, r *http.Request, items [
 		}
 		return
 	}
+	if version := r.URL.Query().Get(s3compat.URLParamVersionID); version != "" {
+		vlom, err, errCode := t.lookupVersion(lom, version)
+		if err != nil {
+			t.invalmsghdlr(w, r, err.Error(), errCode)
+			return
+		}
+		s3compat.SetHeaderFromLOM(w.Header(), vlom, vlom.LogicalSize())
+		return
+	}
 
 	lom.Lock(false)
 	if err = lom.Load(true); err != nil && !cmn.IsObjNotExist(err) { // (doesnotexist -> ok, other)
@@ -273,7 +360,7 @@ func (t *targetrunner) headObjS3(w http.ResponseWriter, r *http.Request, items [
 		t.invalmsghdlrstatusf(w, r, http.StatusNotFound, "%s/%s %s", bucket, objName, cmn.DoesNotExist)
 		return
 	}
-	s3compat.SetHeaderFromLOM(w.Header(), lom, lom.Size())
+	s3compat.SetHeaderFromLOM(w.Header(), lom, lom.LogicalSize())
 }
 
 // DEL s3/bckName/objName
@@ -296,7 +383,15 @@ func (t *targetrunner) delObjS3(w http.ResponseWriter, r *http.Request, items []
 		t.invalmsghdlr(w, r, err.Error())
 		return
 	}
-	err, errCode := t.objDelete(context.Background(), lom, false)
+	if version := r.URL.Query().Get(s3compat.URLParamVersionID); version != "" {
+		if err, errCode := t.delObjVersion(lom, version, isBypassGovernance(r)); err != nil {
+			t.invalmsghdlr(w, r, err.Error(), errCode)
+			return
+		}
+		w.Header().Set(s3compat.HeaderVersion, version)
+		return
+	}
+	err, errCode := t.objDelete(context.Background(), lom, false, isBypassGovernance(r))
 	if err != nil {
 		if errCode == http.StatusNotFound {
 			t.invalmsghdlrsilent(w, r,
@@ -311,3 +406,84 @@ func (t *targetrunner) delObjS3(w http.ResponseWriter, r *http.Request, items []
 	// EC cleanup if EC is enabled
 	ec.ECM.CleanupObject(lom)
 }
+
+// GET s3/bckName/objName?tagging
+func (t *targetrunner) getObjTaggingS3(w http.ResponseWriter, r *http.Request, items []string) {
+	lom := t.initLomS3(w, r, items)
+	if lom == nil {
+		return
+	}
+	lom.Lock(false)
+	err := lom.Load(true)
+	lom.Unlock(false)
+	if err != nil {
+		if cmn.IsObjNotExist(err) {
+			t.invalmsghdlrstatusf(w, r, http.StatusNotFound, "%s %s", lom, cmn.DoesNotExist)
+		} else {
+			t.invalmsghdlr(w, r, err.Error())
+		}
+		return
+	}
+	w.Header().Set(cmn.HeaderContentType, s3compat.ContentType)
+	w.Write(s3compat.NewTagging(lom.Tags()).MustMarshal())
+}
+
+// PUT s3/bckName/objName?tagging
+// DEL s3/bckName/objName?tagging
+func (t *targetrunner) setObjTaggingS3(w http.ResponseWriter, r *http.Request, items []string) {
+	var tags cmn.SimpleKVs
+	if r.Method == http.MethodPut {
+		tagging := &s3compat.Tagging{}
+		err := s3compat.DecodeXML(r.Body, tagging)
+		debug.AssertNoErr(r.Body.Close())
+		if err != nil {
+			t.invalmsghdlr(w, r, err.Error())
+			return
+		}
+		if tags, err = tagging.Tags(); err != nil {
+			t.invalmsghdlr(w, r, err.Error())
+			return
+		}
+	}
+	lom := t.initLomS3(w, r, items)
+	if lom == nil {
+		return
+	}
+	lom.Lock(true)
+	defer lom.Unlock(true)
+	if err := lom.Load(false); err != nil {
+		if cmn.IsObjNotExist(err) {
+			t.invalmsghdlrstatusf(w, r, http.StatusNotFound, "%s %s", lom, cmn.DoesNotExist)
+		} else {
+			t.invalmsghdlr(w, r, err.Error())
+		}
+		return
+	}
+	lom.SetTags(tags)
+	if err := lom.Persist(); err != nil {
+		t.invalmsghdlr(w, r, err.Error())
+		return
+	}
+	lom.ReCache()
+	if r.Method == http.MethodDelete {
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (t *targetrunner) initLomS3(w http.ResponseWriter, r *http.Request, items []string) *cluster.LOM {
+	if len(items) < 2 {
+		t.invalmsghdlr(w, r, "object name is undefined")
+		return nil
+	}
+	bck := cluster.NewBck(items[0], cmn.ProviderAIS, cmn.NsGlobal)
+	if err := bck.Init(t.owner.bmd, nil); err != nil {
+		t.invalmsghdlr(w, r, err.Error())
+		return nil
+	}
+	lom := &cluster.LOM{T: t, ObjName: path.Join(items[1:]...)}
+	if err := lom.Init(bck.Bck, cmn.GCO.Get()); err != nil {
+		t.invalmsghdlr(w, r, err.Error())
+		return nil
+	}
+	return lom
+}
@@ -0,0 +1,376 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/ais/s3compat"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/debug"
+	"github.com/NVIDIA/aistore/fs"
+)
+
+const (
+	// multipart uploads that are neither completed nor aborted within
+	// the interval are removed along with all their parts
+	mptUploadTTL   = 24 * time.Hour
+	mptHkInterval  = time.Hour
+	mptMaxPartNum  = 10000
+	mptHkCleanName = "s3.mpt.gc"
+)
+
+// Initializes LOM for the object of multipart upload request
+func (t *targetrunner) mptLOM(r *http.Request, items []string) (*cluster.LOM, error) {
+	config := cmn.GCO.Get()
+	bck := cluster.NewBck(items[0], cmn.ProviderAIS, cmn.NsGlobal)
+	if err := bck.Init(t.owner.bmd, nil); err != nil {
+		return nil, err
+	}
+	lom := &cluster.LOM{T: t, ObjName: path.Join(items[1:]...)}
+	if err := lom.Init(bck.Bck, config); err != nil {
+		if _, ok := err.(*cmn.ErrorRemoteBucketDoesNotExist); ok {
+			t.BMDVersionFixup(r, cmn.Bck{}, true /* sleep */)
+			err = lom.Init(bck.Bck, config)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return lom, nil
+}
+
+// Checks that the upload exists and was started for the same object
+func mptCheckUpload(uploadID string, lom *cluster.LOM) (int, error) {
+	bckName, objName, err := s3compat.Uploads.ObjName(uploadID)
+	if err != nil {
+		return http.StatusNotFound, err
+	}
+	if bckName != lom.BckName() || objName != lom.ObjName {
+		return http.StatusBadRequest, fmt.Errorf("upload %q was not started for %s", uploadID, lom)
+	}
+	return 0, nil
+}
+
+// POST s3/bckName/objName?uploads
+func (t *targetrunner) startMptS3(w http.ResponseWriter, r *http.Request, items []string) {
+	if len(items) < 2 {
+		t.invalmsghdlr(w, r, "object name is undefined")
+		return
+	}
+	lom, err := t.mptLOM(r, items)
+	if err != nil {
+		t.invalmsghdlr(w, r, err.Error())
+		return
+	}
+	uploadID := s3compat.Uploads.Start(lom.BckName(), lom.ObjName)
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("%s: started multipart upload %s", lom, uploadID)
+	}
+	result := s3compat.NewInitiateMptUploadResult(lom.BckName(), lom.ObjName, uploadID)
+	w.Header().Set(cmn.HeaderContentType, s3compat.ContentType)
+	w.Write(result.MustMarshal())
+}
+
+// PUT s3/bckName/objName?partNumber=N&uploadId=ID
+// The part is saved as a workfile next to the object and its MD5 is
+// returned to the client as ETag.
+func (t *targetrunner) putMptPartS3(w http.ResponseWriter, r *http.Request, items []string) {
+	if len(items) < 2 {
+		t.invalmsghdlr(w, r, "object name is undefined")
+		return
+	}
+	if cs := fs.GetCapStatus(); cs.OOS {
+		t.invalmsghdlr(w, r, cs.Err.Error())
+		return
+	}
+	var (
+		query    = r.URL.Query()
+		uploadID = query.Get(s3compat.URLParamMptUploadID)
+	)
+	partNum, err := strconv.Atoi(query.Get(s3compat.URLParamMptPartNo))
+	if err != nil || partNum < 1 || partNum > mptMaxPartNum {
+		t.invalmsghdlrf(w, r, "invalid part number %q", query.Get(s3compat.URLParamMptPartNo))
+		return
+	}
+	lom, err := t.mptLOM(r, items)
+	if err != nil {
+		t.invalmsghdlr(w, r, err.Error())
+		return
+	}
+	if errCode, err := mptCheckUpload(uploadID, lom); err != nil {
+		t.invalmsghdlr(w, r, err.Error(), errCode)
+		return
+	}
+
+	var (
+		size    int64 = -1
+		partFQN       = fs.CSM.GenContentParsedFQN(lom.ParsedFQN, fs.WorkfileType, fs.WorkfileMpt)
+		dir           = lom.ParsedFQN.MpathInfo.MakePathBck(lom.Bck().Bck)
+	)
+	if sizeStr := r.Header.Get(cmn.HeaderContentLength); sizeStr != "" {
+		if size, err = strconv.ParseInt(sizeStr, 10, 64); err != nil {
+			t.invalmsghdlrf(w, r, "invalid content length %q", sizeStr)
+			return
+		}
+	}
+	buf, slab := t.gmm.Alloc()
+	cksum, err := cmn.SaveReader(partFQN, r.Body, buf, cmn.ChecksumMD5, size, dir)
+	slab.Free(buf)
+	debug.AssertNoErr(r.Body.Close())
+	if err != nil {
+		t.fshc(err, partFQN)
+		t.invalmsghdlr(w, r, err.Error())
+		return
+	}
+	part := &s3compat.MptPart{
+		MD5:  cksum.Value(),
+		FQN:  partFQN,
+		Size: size,
+		Num:  partNum,
+	}
+	if size < 0 {
+		if finfo, err := os.Stat(partFQN); err == nil {
+			part.Size = finfo.Size()
+		}
+	}
+	prev, err := s3compat.Uploads.AddPart(uploadID, part)
+	if err != nil {
+		// the upload was completed or aborted in the meantime
+		removeMptParts([]*s3compat.MptPart{part})
+		t.invalmsghdlr(w, r, err.Error(), http.StatusNotFound)
+		return
+	}
+	if prev != nil {
+		removeMptParts([]*s3compat.MptPart{prev})
+	}
+	w.Header().Set(s3compat.HeaderETag, cksum.Value())
+}
+
+// POST s3/bckName/objName?uploadId=ID
+// Concatenates the uploaded parts into a workfile and finalizes it as
+// a regular PUT does, so the object appears atomically.
+func (t *targetrunner) completeMptS3(w http.ResponseWriter, r *http.Request, items []string) {
+	started := time.Now()
+	if len(items) < 2 {
+		t.invalmsghdlr(w, r, "object name is undefined")
+		return
+	}
+	if cs := fs.GetCapStatus(); cs.OOS {
+		t.invalmsghdlr(w, r, cs.Err.Error())
+		return
+	}
+	uploadID := r.URL.Query().Get(s3compat.URLParamMptUploadID)
+	lom, err := t.mptLOM(r, items)
+	if err != nil {
+		t.invalmsghdlr(w, r, err.Error())
+		return
+	}
+	if errCode, err := mptCheckUpload(uploadID, lom); err != nil {
+		t.invalmsghdlr(w, r, err.Error(), errCode)
+		return
+	}
+	req := &s3compat.CompleteMptUpload{}
+	err = s3compat.DecodeXML(r.Body, req)
+	debug.AssertNoErr(r.Body.Close())
+	if err != nil {
+		t.invalmsghdlr(w, r, err.Error())
+		return
+	}
+	uploaded, err := s3compat.Uploads.Parts(uploadID)
+	if err != nil {
+		t.invalmsghdlr(w, r, err.Error(), http.StatusNotFound)
+		return
+	}
+	parts, err := s3compat.ValidateParts(uploaded, req.Parts)
+	if err != nil {
+		t.invalmsghdlr(w, r, err.Error())
+		return
+	}
+
+	var (
+		size    int64
+		readers = make([]io.Reader, 0, len(parts))
+		files   = make([]*os.File, 0, len(parts))
+	)
+	defer func() {
+		for _, file := range files {
+			debug.AssertNoErr(file.Close())
+		}
+	}()
+	for _, part := range parts {
+		file, err := os.Open(part.FQN)
+		if err != nil {
+			t.invalmsghdlr(w, r, err.Error())
+			return
+		}
+		files = append(files, file)
+		readers = append(readers, file)
+		size += part.Size
+	}
+
+	if lom.Bck().IsAIS() && lom.VerConf().Enabled {
+		lom.Load() // need to know the current version if versioning enabled
+	}
+	etag := s3compat.MptETag(parts)
+	lom.SetAtimeUnix(started.UnixNano())
+	lom.SetCustomMD(cmn.SimpleKVs{cluster.MD5ObjMD: etag})
+	poi := &putObjInfo{
+		started: started,
+		t:       t,
+		lom:     lom,
+		r:       ioutil.NopCloser(io.MultiReader(readers...)),
+		size:    size,
+		ctx:     context.Background(),
+		workFQN: fs.CSM.GenContentParsedFQN(lom.ParsedFQN, fs.WorkfileType, fs.WorkfilePut),
+	}
+	if err, errCode := poi.putObject(); err != nil {
+		t.invalmsghdlr(w, r, err.Error(), errCode)
+		return
+	}
+
+	// the object is in place - the upload and all its parts are not needed anymore
+	if all, err := s3compat.Uploads.Finish(uploadID); err == nil {
+		removeMptParts(all)
+	}
+	result := s3compat.NewCompleteMptUploadResult(lom.BckName(), lom.ObjName, etag)
+	w.Header().Set(cmn.HeaderContentType, s3compat.ContentType)
+	w.Write(result.MustMarshal())
+}
+
+// DELETE s3/bckName/objName?uploadId=ID
+func (t *targetrunner) abortMptS3(w http.ResponseWriter, r *http.Request, items []string) {
+	if len(items) < 2 {
+		t.invalmsghdlr(w, r, "object name is undefined")
+		return
+	}
+	uploadID := r.URL.Query().Get(s3compat.URLParamMptUploadID)
+	lom, err := t.mptLOM(r, items)
+	if err != nil {
+		t.invalmsghdlr(w, r, err.Error())
+		return
+	}
+	if errCode, err := mptCheckUpload(uploadID, lom); err != nil {
+		t.invalmsghdlr(w, r, err.Error(), errCode)
+		return
+	}
+	parts, err := s3compat.Uploads.Finish(uploadID)
+	if err != nil {
+		t.invalmsghdlr(w, r, err.Error(), http.StatusNotFound)
+		return
+	}
+	removeMptParts(parts)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GET s3/bckName/objName?uploadId=ID
+func (t *targetrunner) listMptPartsS3(w http.ResponseWriter, r *http.Request, items []string) {
+	if len(items) < 2 {
+		t.invalmsghdlr(w, r, "object name is undefined")
+		return
+	}
+	uploadID := r.URL.Query().Get(s3compat.URLParamMptUploadID)
+	lom, err := t.mptLOM(r, items)
+	if err != nil {
+		t.invalmsghdlr(w, r, err.Error())
+		return
+	}
+	if errCode, err := mptCheckUpload(uploadID, lom); err != nil {
+		t.invalmsghdlr(w, r, err.Error(), errCode)
+		return
+	}
+	parts, err := s3compat.Uploads.Parts(uploadID)
+	if err != nil {
+		t.invalmsghdlr(w, r, err.Error(), http.StatusNotFound)
+		return
+	}
+	result := s3compat.NewListPartsResult(lom.BckName(), lom.ObjName, uploadID)
+	for _, part := range parts {
+		info := &s3compat.PartInfo{PartNumber: part.Num, ETag: part.MD5, Size: part.Size}
+		if finfo, err := os.Stat(part.FQN); err == nil {
+			info.LastModified = s3compat.FormatTime(finfo.ModTime())
+		}
+		result.Parts = append(result.Parts, info)
+	}
+	w.Header().Set(cmn.HeaderContentType, s3compat.ContentType)
+	w.Write(result.MustMarshal())
+}
+
+func removeMptParts(parts []*s3compat.MptPart) {
+	for _, part := range parts {
+		if err := cmn.RemoveFile(part.FQN); err != nil {
+			glog.Errorf("failed to remove part %d (%s): %v", part.Num, part.FQN, err)
+		}
+	}
+}
+
+// housekeeping: cleans up multipart uploads abandoned by clients
+func housekeepMpt() time.Duration {
+	parts := s3compat.Uploads.Expired(time.Now().Add(-mptUploadTTL))
+	if len(parts) != 0 {
+		glog.Infof("removing %d parts of expired multipart uploads", len(parts))
+		removeMptParts(parts)
+	}
+	return mptHkInterval
+}
+
+// removeStaleMptParts removes the parts of the multipart uploads that were in
+// progress when the target stopped: the uploads are registered in memory only
+// (see s3compat.Uploads) and cannot be completed after restart
+func (t *targetrunner) removeStaleMptParts() {
+	var (
+		cnt               int
+		availablePaths, _ = fs.Get()
+		prefix            = fs.WorkfileMpt + "."
+		resolver          = fs.CSM.RegisteredContentTypes[fs.WorkfileType]
+	)
+	for _, mpathInfo := range availablePaths {
+		opts := &fs.Options{Mpath: mpathInfo, Bck: cmn.Bck{Provider: cmn.ProviderAIS, Ns: cmn.NsGlobal}}
+		bcks, err := fs.AllMpathBcks(opts)
+		if err != nil {
+			glog.Errorf("%s: %v", t.si, err)
+			continue
+		}
+		for _, bck := range bcks {
+			cb := func(fqn string, de fs.DirEntry) error {
+				if de.IsDir() {
+					return nil
+				}
+				base := filepath.Base(fqn)
+				if !strings.HasPrefix(base, prefix) {
+					return nil
+				}
+				if _, old, ok := resolver.ParseUniqueFQN(base); !ok || !old {
+					return nil
+				}
+				if err := cmn.RemoveFile(fqn); err != nil {
+					glog.Errorf("failed to remove stale part %s: %v", fqn, err)
+				} else {
+					cnt++
+				}
+				return nil
+			}
+			opts := &fs.Options{Mpath: mpathInfo, Bck: bck, CTs: []string{fs.WorkfileType}, Callback: cb}
+			if err := fs.Walk(opts); err != nil {
+				glog.Errorf("%s: %v", t.si, err)
+			}
+		}
+	}
+	if cnt > 0 {
+		glog.Infof("%s: removed %d parts of multipart uploads interrupted by restart", t.si, cnt)
+	}
+}
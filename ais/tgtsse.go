@@ -0,0 +1,46 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"io"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/sse"
+)
+
+// Server-side encryption (see cmn.BckSSEConf and package sse). Encrypted
+// objects are marked as such in their metadata (see cluster.LOM.IsEncrypted):
+// they are decrypted on GET regardless of the current bucket settings and,
+// otherwise, copied (rebalanced, mirrored, erasure coded, etc.) as is along
+// with the metadata.
+
+// encrypt returns true if the object is to be encrypted when written;
+// migrated objects arrive already encrypted (if they are)
+func (poi *putObjInfo) encrypt() bool {
+	return poi.lom.Bprops().SSE.Enabled && !poi.migrated && !poi.cold
+}
+
+// writeEncrypted encrypts the content while writing it out; the checksum to
+// store is computed over the ciphertext while the one to validate (if any) -
+// over the plaintext
+func writeEncrypted(file io.Writer, reader io.Reader, buf []byte, store, given *cmn.CksumHash) (int64, error) {
+	w := file
+	if store != nil {
+		w = cmn.NewWriterMulti(store.H, file)
+	}
+	ew, err := sse.NewWriter(w)
+	if err != nil {
+		return 0, err
+	}
+	w = ew
+	if given != nil {
+		w = cmn.NewWriterMulti(given.H, ew)
+	}
+	if _, err = io.CopyBuffer(w, reader, buf); err == nil {
+		err = ew.Close()
+	}
+	return ew.Written(), err
+}
@@ -87,6 +87,10 @@ func (t *targetrunner) txnHandler(w http.ResponseWriter, r *http.Request) {
 		if err = t.ecEncode(c); err != nil {
 			t.invalmsghdlr(w, r, err.Error())
 		}
+	case cmn.ActDestroyLB:
+		if err = t.destroyBucket(c); err != nil {
+			t.invalmsghdlr(w, r, err.Error(), http.StatusForbidden)
+		}
 	default:
 		t.invalmsghdlrf(w, r, fmtUnknownAct, msg)
 	}
@@ -516,6 +520,45 @@ func (t *targetrunner) validateEcEncode(bck *cluster.Bck, msg *aisMsg) (err erro
 	return
 }
 
+///////////////////
+// destroyBucket //
+///////////////////
+
+// destroying a bucket with object lock: the begin phase makes sure that the bucket
+// contains no retained objects and, from this point on, fails PUTs into the bucket
+// (see putObjInfo.tryFinalize); the bucket itself gets destroyed upon receiving
+// the updated BMD (see proxyrunner.destroyBucket)
+func (t *targetrunner) destroyBucket(c *txnServerCtx) error {
+	switch c.phase {
+	case cmn.ActBegin:
+		if err := c.bck.Init(t.owner.bmd, t.si); err != nil {
+			return err
+		}
+		txn := newTxnDestroyBucket(c)
+		if err := t.transactions.begin(txn); err != nil {
+			return err
+		}
+		if err := t.checkRetained(c.bck, cmn.IsParseBool(c.query.Get(cmn.URLParamBypassGovernance))); err != nil {
+			t.transactions.find(c.uuid, true /* remove */)
+			return err
+		}
+	case cmn.ActAbort:
+		t.transactions.find(c.uuid, true /* remove */)
+	case cmn.ActCommit:
+		txn, err := t.transactions.find(c.uuid, false)
+		if err != nil {
+			return fmt.Errorf("%s %s: %v", t.si, txn, err)
+		}
+		// wait for newBMD w/timeout
+		if err = t.transactions.wait(txn, c.timeout); err != nil {
+			return fmt.Errorf("%s %s: %v", t.si, txn, err)
+		}
+	default:
+		cmn.Assert(false)
+	}
+	return nil
+}
+
 //////////
 // misc //
 //////////
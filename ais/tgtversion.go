@@ -0,0 +1,215 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/fs"
+)
+
+// Object versions: current and noncurrent (see cmn.VersionConf.History)
+
+// GET /v1/objects/bucket-name/object-name?what=versions
+func (t *targetrunner) listObjVersions(w http.ResponseWriter, r *http.Request, lom *cluster.LOM) {
+	entries, err := t.objVersionEntries(lom)
+	if err != nil {
+		t.invalmsghdlr(w, r, err.Error())
+		return
+	}
+	if len(entries) == 0 {
+		t.invalmsghdlrsilent(w, r, fmt.Sprintf("%s %s", lom, cmn.DoesNotExist), http.StatusNotFound)
+		return
+	}
+	t.writeJSON(w, r, entries, "list-obj-versions")
+}
+
+// GET /v1/buckets/bucket-name?what=versions&prefix=...
+func (t *targetrunner) listBckVersions(w http.ResponseWriter, r *http.Request, bck *cluster.Bck, prefix string) {
+	entries, err := t.bckVersionEntries(bck, prefix)
+	if err != nil {
+		t.invalmsghdlr(w, r, err.Error())
+		return
+	}
+	t.writeJSON(w, r, entries, "list-bck-versions")
+}
+
+// returns the current (if exists) and all noncurrent versions of the object, latest first
+func (t *targetrunner) objVersionEntries(lom *cluster.LOM) (entries []*cmn.ObjVersionEntry, err error) {
+	lom.Lock(false)
+	defer lom.Unlock(false)
+	if err = lom.Load(false); err == nil {
+		entries = append(entries, newObjVersionEntry(lom, true))
+	} else if !cmn.IsObjNotExist(err) {
+		return
+	}
+	vloms, err := lom.ListVersions()
+	if err != nil {
+		return
+	}
+	for _, vlom := range vloms {
+		entries = append(entries, newObjVersionEntry(vlom, false))
+	}
+	return
+}
+
+func (t *targetrunner) bckVersionEntries(bck *cluster.Bck, prefix string) (entries []*cmn.ObjVersionEntry, err error) {
+	var (
+		config            = cmn.GCO.Get()
+		availablePaths, _ = fs.Get()
+	)
+	for _, mpathInfo := range availablePaths {
+		mpathInfo := mpathInfo
+		cb := func(fqn string, de fs.DirEntry) error {
+			if de.IsDir() {
+				return nil
+			}
+			parsedFQN, err := fs.ParseFQN(fqn)
+			if err != nil {
+				return nil
+			}
+			objName, ver := parsedFQN.ObjName, ""
+			if parsedFQN.ContentType == fs.VersionType {
+				var ok bool
+				if objName, ver, ok = fs.SplitVersion(objName); !ok {
+					return nil
+				}
+			}
+			if !strings.HasPrefix(objName, prefix) {
+				return nil
+			}
+			lom := &cluster.LOM{T: t, ObjName: objName}
+			if err := lom.Init(bck.Bck, config); err != nil {
+				return nil
+			}
+			if ver == "" {
+				if lom.FQN != fqn || lom.Load(false) != nil {
+					return nil // (copy or misplaced)
+				}
+				entries = append(entries, newObjVersionEntry(lom, true))
+				return nil
+			}
+			vlom, err := lom.VersionLOM(fqn, mpathInfo)
+			if err != nil {
+				glog.Errorf("%s: %v", t.si, err)
+				return nil
+			}
+			entries = append(entries, newObjVersionEntry(vlom, false))
+			return nil
+		}
+		opts := &fs.Options{
+			Mpath:    mpathInfo,
+			Bck:      bck.Bck,
+			CTs:      []string{fs.ObjectType, fs.VersionType},
+			Callback: cb,
+		}
+		if err = fs.Walk(opts); err != nil {
+			return
+		}
+	}
+	sortObjVersionEntries(entries)
+	return
+}
+
+func newObjVersionEntry(lom *cluster.LOM, current bool) *cmn.ObjVersionEntry {
+	entry := &cmn.ObjVersionEntry{
+		Name:    lom.ObjName,
+		Version: lom.Version(),
+		Size:    lom.Size(),
+		Current: current,
+	}
+	if cksum := lom.Cksum(); cksum != nil {
+		entry.Checksum = cksum.Value()
+	}
+	if finfo, err := os.Stat(lom.FQN); err == nil {
+		entry.Mtime = finfo.ModTime().UnixNano()
+	}
+	return entry
+}
+
+// by name and then latest version first
+func sortObjVersionEntries(entries []*cmn.ObjVersionEntry) {
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Name != entries[j].Name {
+			return entries[i].Name < entries[j].Name
+		}
+		if entries[i].Current != entries[j].Current {
+			return entries[i].Current
+		}
+		return len(entries[i].Version) > len(entries[j].Version) ||
+			(len(entries[i].Version) == len(entries[j].Version) && entries[i].Version > entries[j].Version)
+	})
+}
+
+// lookupVersion returns the LOM of a given version: either the (loaded) current
+// object or one of its noncurrent versions
+func (t *targetrunner) lookupVersion(lom *cluster.LOM, ver string) (*cluster.LOM, error, int) {
+	lom.Lock(false)
+	defer lom.Unlock(false)
+	err := lom.Load(false)
+	if err == nil && lom.Version() == ver {
+		return lom, nil, 0
+	}
+	if err != nil && !cmn.IsObjNotExist(err) {
+		return nil, err, http.StatusInternalServerError
+	}
+	vlom, err := lom.LoadVersion(ver)
+	if err != nil {
+		if cmn.IsObjNotExist(err) {
+			return nil, fmt.Errorf("%s version %s %s", lom, ver, cmn.DoesNotExist), http.StatusNotFound
+		}
+		return nil, err, http.StatusInternalServerError
+	}
+	return vlom, nil, 0
+}
+
+// delObjVersion deletes a given version of the object. Deleting the current
+// version makes the latest noncurrent version (if any) current.
+func (t *targetrunner) delObjVersion(lom *cluster.LOM, ver string, bypassGovernance bool) (error, int) {
+	if !lom.Bck().IsAIS() {
+		return fmt.Errorf("%s: deleting specific versions is supported only for ais buckets", lom), http.StatusBadRequest
+	}
+	lom.Lock(true)
+	defer lom.Unlock(true)
+	err := lom.Load(false)
+	if err != nil && !cmn.IsObjNotExist(err) {
+		return err, http.StatusInternalServerError
+	}
+	if err != nil || lom.Version() != ver {
+		if err = lom.DelVersion(ver, bypassGovernance); err != nil {
+			if cmn.IsErrObjLocked(err) {
+				return err, http.StatusForbidden
+			}
+			if cmn.IsObjNotExist(err) {
+				return fmt.Errorf("%s version %s %s", lom, ver, cmn.DoesNotExist), http.StatusNotFound
+			}
+			return err, http.StatusInternalServerError
+		}
+		return nil, 0
+	}
+	if err = lom.ObjLockErr(bypassGovernance); err != nil {
+		return err, http.StatusForbidden
+	}
+	if err = lom.Remove(); err != nil {
+		return err, http.StatusInternalServerError
+	}
+	restored, err := lom.RestoreLatestVersion()
+	if err != nil {
+		glog.Errorf("%s: failed to restore the latest noncurrent version: %v", lom, err)
+	}
+	if restored {
+		t.replicatePut(lom)
+	} else {
+		t.replicateDelete(lom)
+	}
+	return nil, 0
+}
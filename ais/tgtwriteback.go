@@ -0,0 +1,58 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/xaction"
+)
+
+// Write-back caching of cloud buckets (see cmn.BckWriteBackConf and
+// xaction.WriteBack). The PUT is acknowledged as soon as the object is stored
+// locally and marked dirty; the object gets written to the cloud
+// asynchronously.
+
+const (
+	writeBackHkName     = "write-back"
+	writeBackHkInterval = time.Minute
+)
+
+func (poi *putObjInfo) writeBack() bool {
+	bck := poi.lom.Bck()
+	return bck.IsCloud() && bck.Props.WriteBack.Enabled && !poi.migrated && !poi.cold
+}
+
+func (t *targetrunner) writeBack(lom *cluster.LOM) {
+	const retries = 2
+	for i := 0; i < retries; i++ {
+		x := xaction.Registry.RenewWriteBack(t, lom.Bck())
+		if x == nil {
+			break
+		}
+		x.Poke()
+		if !x.Finished() {
+			return
+		}
+		// retry upon race vs (just finished/timed_out)
+	}
+	glog.Errorf("%s: failed to initiate write-back (will retry in %v)", lom, writeBackHkInterval)
+}
+
+// writeBackHk (re)starts writing back the dirty objects that remain after
+// restart, timeout, or failure
+func (t *targetrunner) writeBackHk() time.Duration {
+	go func() {
+		t.owner.bmd.get().Range(nil, nil, func(bck *cluster.Bck) bool {
+			if bck.IsCloud() && xaction.HasDirty(t, bck) {
+				xaction.Registry.RenewWriteBack(t, bck)
+			}
+			return false
+		})
+	}()
+	return writeBackHkInterval
+}
@@ -129,6 +129,11 @@ func (t *targetrunner) cmdXactStart(xactMsg cmn.XactReqMsg, bck *cluster.Bck) er
 			glog.Errorf(erfmb, xactMsg.Kind, bck)
 		}
 		go t.RunLRU(xactMsg.ID)
+	case cmn.ActLifecycle:
+		if bck != nil {
+			glog.Errorf(erfmb, xactMsg.Kind, bck)
+		}
+		go t.RunLifecycle(xactMsg.ID)
 	case cmn.ActResilver:
 		if bck != nil {
 			glog.Errorf(erfmb, xactMsg.Kind, bck)
@@ -148,9 +153,38 @@ func (t *targetrunner) cmdXactStart(xactMsg cmn.XactReqMsg, bck *cluster.Bck) er
 			return err
 		}
 		go xact.Run()
+	case cmn.ActDedup:
+		if bck == nil {
+			return fmt.Errorf(erfmn, xactMsg.Kind)
+		}
+		xact, err := xaction.Registry.RenewDedup(t, bck, xactMsg.ID)
+		if err != nil {
+			return err
+		}
+		go xact.Run()
+	case cmn.ActFlushBck:
+		if bck == nil {
+			return fmt.Errorf(erfmn, xactMsg.Kind)
+		}
+		xact, err := xaction.Registry.RenewFlushBck(t, bck, xactMsg.ID)
+		if err != nil {
+			return err
+		}
+		go xact.Run()
+	case cmn.ActSyncBck:
+		if bck == nil {
+			return fmt.Errorf(erfmn, xactMsg.Kind)
+		}
+		xact, err := xaction.Registry.RenewSyncBck(t, bck, xactMsg.ID, &cmn.SyncBckMsg{})
+		if err != nil {
+			return err
+		}
+		go xact.Run()
 	// 3. cannot start
 	case cmn.ActPutCopies:
 		return fmt.Errorf("cannot start xaction %q - it is invoked automatically by PUTs into mirrored bucket", xactMsg.Kind)
+	case cmn.ActWriteBack:
+		return fmt.Errorf("cannot start xaction %q - it is invoked automatically by PUTs into write-back bucket", xactMsg.Kind)
 	case cmn.ActDownload, cmn.ActEvictObjects, cmn.ActDelete, cmn.ActMakeNCopies, cmn.ActECEncode:
 		return fmt.Errorf("initiating xaction %q must be done via a separate documented API", xactMsg.Kind)
 	// 4. unknown
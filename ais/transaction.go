@@ -93,6 +93,9 @@ type (
 		bckFrom *cluster.Bck
 		bckTo   *cluster.Bck
 	}
+	txnDestroyBucket struct {
+		txnBckBase
+	}
 )
 
 //////////////////
@@ -130,6 +133,17 @@ func (txns *transactions) find(uuid string, remove bool) (txn txn, err error) {
 	return
 }
 
+// returns true if the bucket is being destroyed (see destroyBucket);
+// must be called under lock
+func (txns *transactions) destroying(bck *cluster.Bck) bool {
+	for _, txn := range txns.m {
+		if txn, ok := txn.(*txnDestroyBucket); ok && txn.bck.Equal(bck, false /*same BID*/) {
+			return true
+		}
+	}
+	return false
+}
+
 func (txns *transactions) commitBefore(caller string, msg *aisMsg) error {
 	var (
 		rndzvs rndzvs
@@ -443,3 +457,16 @@ func newTxnCopyBucket(c *txnServerCtx, bckFrom, bckTo *cluster.Bck) (txn *txnCop
 	txn.fillFromCtx(c)
 	return
 }
+
+//////////////////////
+// txnDestroyBucket //
+//////////////////////
+
+var _ txn = &txnDestroyBucket{}
+
+// c-tor
+func newTxnDestroyBucket(c *txnServerCtx) (txn *txnDestroyBucket) {
+	txn = &txnDestroyBucket{txnBckBase{txnBase{kind: "dsb"}, *c.bck}}
+	txn.fillFromCtx(c)
+	return
+}
@@ -177,3 +177,44 @@ func DeleteRoleAuthN(baseParams BaseParams, role string) error {
 		Path:       cmn.URLPath(cmn.Version, cmn.Roles, role),
 	})
 }
+
+// AddAccessKeyAuthN generates a new S3 access key for the user. The secret
+// is returned only once - keep it safe.
+func AddAccessKeyAuthN(baseParams BaseParams, userID, pass string) (*cmn.AuthAccessKey, error) {
+	baseParams.Method = http.MethodPost
+	key := &cmn.AuthAccessKey{}
+	err := DoHTTPRequest(ReqParams{
+		BaseParams: baseParams,
+		Path:       cmn.URLPath(cmn.Version, cmn.AccessKeys, userID),
+		Body:       cmn.MustMarshal(loginRec{Password: pass}),
+	}, key)
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func DeleteAccessKeyAuthN(baseParams BaseParams, keyID string) error {
+	baseParams.Method = http.MethodDelete
+	return DoHTTPRequest(ReqParams{
+		BaseParams: baseParams,
+		Path:       cmn.URLPath(cmn.Version, cmn.AccessKeys, keyID),
+	})
+}
+
+// GetAccessKeysAuthN returns access keys of the user, or all keys if userID is empty
+func GetAccessKeysAuthN(baseParams BaseParams, userID string) ([]*cmn.AuthAccessKey, error) {
+	baseParams.Method = http.MethodGet
+	path := cmn.URLPath(cmn.Version, cmn.AccessKeys)
+	if userID != "" {
+		path = cmn.URLPath(path, userID)
+	}
+	keys := make([]*cmn.AuthAccessKey, 0)
+	err := DoHTTPRequest(ReqParams{
+		BaseParams: baseParams,
+		Path:       path,
+	}, &keys)
+	less := func(i, j int) bool { return keys[i].ID < keys[j].ID }
+	sort.Slice(keys, less)
+	return keys, err
+}
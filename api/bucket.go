@@ -224,6 +224,22 @@ func PrefetchRange(baseParams BaseParams, bck cmn.Bck, rng string) error {
 	return doListRangeRequest(baseParams, bck, cmn.ActPrefetch, http.MethodPost, prefetchMsg)
 }
 
+// SyncBucket API
+//
+// SyncBucket starts synchronizing the cached objects of a cloud bucket with the
+// cloud: evicts objects deleted in the cloud, re-fetches (or evicts) changed
+// objects, and optionally fetches new ones
+func SyncBucket(baseParams BaseParams, bck cmn.Bck, msg *cmn.SyncBckMsg) (xactID string, err error) {
+	baseParams.Method = http.MethodPost
+	err = DoHTTPRequest(ReqParams{
+		BaseParams: baseParams,
+		Path:       cmn.URLPath(cmn.Version, cmn.Buckets, bck.Name),
+		Body:       cmn.MustMarshal(cmn.ActionMsg{Action: cmn.ActSyncBck, Value: msg}),
+		Query:      cmn.AddBckToQuery(nil, bck),
+	}, &xactID)
+	return
+}
+
 // EvictList API
 //
 // EvictList sends a HTTP request to evict a list of objects from a cloud bucket
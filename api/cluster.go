@@ -88,6 +88,20 @@ func GetRemoteAIS(baseParams BaseParams) (aisInfo cmn.CloudInfoAIS, err error) {
 	return
 }
 
+// GetReplicationStats API
+//
+// Returns the state of replication to remote clusters (see cmn.BckReplicationConf),
+// per target.
+func GetReplicationStats(baseParams BaseParams) (stats map[string]*cmn.ReplicationStats, err error) {
+	baseParams.Method = http.MethodGet
+	err = DoHTTPRequest(ReqParams{
+		BaseParams: baseParams,
+		Path:       cmn.URLPath(cmn.Version, cmn.Cluster),
+		Query:      url.Values{cmn.URLParamWhat: []string{cmn.GetWhatReplication}},
+	}, &stats)
+	return
+}
+
 // RegisterNode API
 //
 // Registers an existing node to the clustermap.
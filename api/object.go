@@ -13,6 +13,7 @@ import (
 	"net/url"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/NVIDIA/aistore/cmn"
@@ -47,7 +48,8 @@ type PutObjectArgs struct {
 	Object     string
 	Cksum      *cmn.Cksum
 	Reader     cmn.ReadOpenCloser
-	Size       uint64 // optional
+	Size       uint64        // optional
+	UserMD     cmn.SimpleKVs // optional user-defined metadata
 }
 
 type PromoteArgs struct {
@@ -119,6 +121,9 @@ func HeadObject(baseParams BaseParams, bck cmn.Bck, object string, checkExists .
 	if err != nil {
 		return nil, err
 	}
+	if objProps.UserMD, err = cmn.UserMDFromHdr(resp.Header); err != nil {
+		return nil, err
+	}
 	return objProps, nil
 }
 
@@ -134,6 +139,36 @@ func DeleteObject(baseParams BaseParams, bck cmn.Bck, object string) error {
 	})
 }
 
+// ListObjectVersions API
+//
+// Returns the current (if exists) and all noncurrent versions of the object,
+// latest first. Noncurrent versions are kept only in ais buckets with
+// versioning.history enabled.
+func ListObjectVersions(baseParams BaseParams, bck cmn.Bck, object string) (entries []*cmn.ObjVersionEntry, err error) {
+	baseParams.Method = http.MethodGet
+	query := cmn.AddBckToQuery(url.Values{cmn.URLParamWhat: []string{cmn.GetWhatVersions}}, bck)
+	err = DoHTTPRequest(ReqParams{
+		BaseParams: baseParams,
+		Path:       cmn.URLPath(cmn.Version, cmn.Objects, bck.Name, object),
+		Query:      query,
+	}, &entries)
+	return
+}
+
+// DeleteObjectVersion API
+//
+// Deletes a given version of the object. If the version is current, the
+// latest noncurrent version (if any) becomes current.
+func DeleteObjectVersion(baseParams BaseParams, bck cmn.Bck, object, version string) error {
+	baseParams.Method = http.MethodDelete
+	query := cmn.AddBckToQuery(url.Values{cmn.URLParamVersion: []string{version}}, bck)
+	return DoHTTPRequest(ReqParams{
+		BaseParams: baseParams,
+		Path:       cmn.URLPath(cmn.Version, cmn.Objects, bck.Name, object),
+		Query:      query,
+	})
+}
+
 // EvictObject API
 //
 // Evicts an object specified by bucket/object
@@ -288,6 +323,7 @@ func PutObject(args PutObjectArgs) (err error) {
 			}
 			req.Header.Set(cmn.HeaderObjCksumVal, ckVal)
 		}
+		cmn.AddUserMDToHdr(req.Header, args.UserMD)
 		if args.Size != 0 {
 			req.ContentLength = int64(args.Size) // as per https://tools.ietf.org/html/rfc7230#section-3.3.2
 		}
@@ -433,6 +469,47 @@ func RenameObject(baseParams BaseParams, bck cmn.Bck, oldName, newName string) e
 	})
 }
 
+// SetLegalHold API
+//
+// Places (or removes) a legal hold on the object in a bucket with enabled
+// object lock. An object under legal hold can be neither overwritten nor
+// deleted regardless of the bucket's retention.
+func SetLegalHold(baseParams BaseParams, bck cmn.Bck, object string, on bool) error {
+	baseParams.Method = http.MethodPost
+	return DoHTTPRequest(ReqParams{
+		BaseParams: baseParams,
+		Path:       cmn.URLPath(cmn.Version, cmn.Objects, bck.Name, object),
+		Body:       cmn.MustMarshal(cmn.ActionMsg{Action: cmn.ActLegalHold, Value: on}),
+		Query:      cmn.AddBckToQuery(nil, bck),
+	})
+}
+
+// PresignObject API
+//
+// Returns a URL that grants GET or PUT (as per `method`) access to the object
+// without an AuthN token. The URL expires after `expires` (zero value means
+// the default lifetime).
+func PresignObject(baseParams BaseParams, bck cmn.Bck, object, method string, expires time.Duration) (string, error) {
+	var (
+		signed string
+		actMsg = cmn.ActionMsg{
+			Action: cmn.ActPresignObject,
+			Value:  &cmn.ActValPresign{Method: method, Expires: cmn.DurationJSON(expires)},
+		}
+	)
+	baseParams.Method = http.MethodPost
+	err := DoHTTPRequest(ReqParams{
+		BaseParams: baseParams,
+		Path:       cmn.URLPath(cmn.Version, cmn.Objects, bck.Name, object),
+		Body:       cmn.MustMarshal(actMsg),
+		Query:      cmn.AddBckToQuery(nil, bck),
+	}, &signed)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(baseParams.URL, "/") + signed, nil
+}
+
 // PromoteFileOrDir API
 //
 // promote AIS-colocated files and directories to objects (NOTE: advanced usage only)
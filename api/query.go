@@ -5,12 +5,16 @@
 package api
 
 import (
+	"io"
 	"net/http"
 
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/query"
 )
 
+// InitQuery starts a query and returns its handle. Queries are persisted by
+// the cluster, so the handle remains valid (and the query resumes from where
+// it stopped) across proxy and target restarts.
 func InitQuery(baseParams BaseParams, objectsTemplate string, bck cmn.Bck, filter *query.FilterMsg, workersCnts ...uint) (string, error) {
 	var (
 		outerSelectMsg = query.OuterSelectMsg{Template: objectsTemplate}
@@ -55,6 +59,88 @@ func NextQueryResults(baseParams BaseParams, handle string, size uint) ([]*cmn.B
 	return objectsNames, err
 }
 
+// QueryObjects runs the query to completion and returns all matching objects.
+// The filter is built with `query` package helpers (e.g., `query.CustomMDFilterMsg`).
+func QueryObjects(baseParams BaseParams, bck cmn.Bck, objectsTemplate string, filter *query.FilterMsg) ([]*cmn.BucketEntry, error) {
+	handle, err := InitQuery(baseParams, objectsTemplate, bck, filter)
+	if err != nil {
+		return nil, err
+	}
+	var entries []*cmn.BucketEntry
+	for {
+		page, err := NextQueryResults(baseParams, handle, cmn.DefaultListPageSize)
+		if err != nil {
+			if httpErr, ok := err.(*cmn.HTTPError); ok && httpErr.Status == http.StatusGone {
+				return entries, nil // the query has finished
+			}
+			return entries, err
+		}
+		entries = append(entries, page...)
+	}
+}
+
+// SelectRecords looks into the shards (tar, tgz, zip) selected by the objects
+// template and filter, and writes the records matching the record filter to `w`
+// as a single tar. Records are named "<shard>/<record>".
+func SelectRecords(baseParams BaseParams, bck cmn.Bck, objectsTemplate string, filter, recordFilter *query.FilterMsg,
+	w io.Writer) (int64, error) {
+	baseParams.Method = http.MethodPost
+	resp, err := doHTTPRequestGetResp(ReqParams{
+		BaseParams: baseParams,
+		Path:       cmn.URLPath(cmn.Version, cmn.Query, cmn.Select),
+		Body:       cmn.MustMarshal(newSelectMsg(bck, objectsTemplate, filter, recordFilter, false)),
+	}, w)
+	if err != nil {
+		return 0, err
+	}
+	return resp.n, nil
+}
+
+// ListRecords is the same as SelectRecords but returns the names and sizes of
+// the matching records instead of their contents.
+func ListRecords(baseParams BaseParams, bck cmn.Bck, objectsTemplate string, filter, recordFilter *query.FilterMsg) ([]*cmn.BucketEntry, error) {
+	var entries []*cmn.BucketEntry
+	baseParams.Method = http.MethodPost
+	err := DoHTTPRequest(ReqParams{
+		BaseParams: baseParams,
+		Path:       cmn.URLPath(cmn.Version, cmn.Query, cmn.Select),
+		Body:       cmn.MustMarshal(newSelectMsg(bck, objectsTemplate, filter, recordFilter, true)),
+	}, &entries)
+	return entries, err
+}
+
+func newSelectMsg(bck cmn.Bck, objectsTemplate string, filter, recordFilter *query.FilterMsg, namesOnly bool) *query.DefMsg {
+	return &query.DefMsg{
+		OuterSelect: query.OuterSelectMsg{Template: objectsTemplate},
+		InnerSelect: &query.InnerSelectMsg{Filter: recordFilter, NamesOnly: namesOnly},
+		From:        query.FromMsg{Bck: bck},
+		Where:       query.WhereMsg{Filter: filter},
+	}
+}
+
+// AggregateQuery computes the count, total size, and (optionally) size and
+// atime histograms of the objects selected by the objects template and filter.
+// Objects are aggregated in place, by the targets that store them.
+func AggregateQuery(baseParams BaseParams, bck cmn.Bck, objectsTemplate string, filter *query.FilterMsg,
+	aggrMsg *query.AggregateMsg) (*query.AggregateResult, error) {
+	var (
+		result = &query.AggregateResult{}
+		msg    = &query.DefMsg{
+			OuterSelect: query.OuterSelectMsg{Template: objectsTemplate},
+			From:        query.FromMsg{Bck: bck},
+			Where:       query.WhereMsg{Filter: filter},
+			Aggregate:   aggrMsg,
+		}
+	)
+	baseParams.Method = http.MethodPost
+	err := DoHTTPRequest(ReqParams{
+		BaseParams: baseParams,
+		Path:       cmn.URLPath(cmn.Version, cmn.Query, cmn.Aggregate),
+		Body:       cmn.MustMarshal(msg),
+	}, result)
+	return result, err
+}
+
 func QueryWorkerTarget(baseParams BaseParams, handle string, workerID uint) (daemonID string, err error) {
 	baseParams.Method = http.MethodGet
 	err = DoHTTPRequest(ReqParams{
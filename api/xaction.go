@@ -268,3 +268,27 @@ func MakeNCopies(baseParams BaseParams, bck cmn.Bck, copies int) (xactID string,
 	}, &xactID)
 	return
 }
+
+// FlushBucket API
+//
+// FlushBucket writes to the cloud all objects that were PUT into a given
+// write-back bucket before the call, and waits for it to complete.
+func FlushBucket(baseParams BaseParams, bck cmn.Bck, timeout time.Duration) error {
+	args := XactReqArgs{Kind: cmn.ActFlushBck, Bck: bck, Timeout: timeout}
+	id, err := StartXaction(baseParams, args)
+	if err != nil {
+		return err
+	}
+	args.ID = id
+	if err := WaitForXaction(baseParams, args); err != nil {
+		return err
+	}
+	xactStat, err := GetXactionStatsByID(baseParams, id)
+	if err != nil {
+		return err
+	}
+	if xactStat.Aborted() {
+		return fmt.Errorf("failed to flush bucket %s", bck)
+	}
+	return nil
+}
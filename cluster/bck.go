@@ -163,13 +163,24 @@ func (b *Bck) HasBackendBck() bool { return !b.Props.BackendBck.IsEmpty() }
 func (b *Bck) CloudBck() cmn.Bck {
 	// NOTE: It's required that props are initialized for AIS bucket. It
 	//  might not be the case for cloud buckets (see: `HeadBucket`).
-	if b.Provider == cmn.ProviderAIS && b.HasBackendBck() {
+	if b.Bck.IsAIS() && b.HasBackendBck() {
 		return b.Props.BackendBck
 	}
-	cmn.Assert(b.Bck.IsCloud())
+	cmn.Assert(b.Bck.IsRemote())
 	return b.Bck
 }
 
+// HasBackendChain returns true if the bucket falls through more than one backend
+func (b *Bck) HasBackendChain() bool { return b.Props != nil && len(b.Props.BackendChain.Bcks) > 1 }
+
+// WithBackend returns a copy of the bucket that points to a given backend
+// (one of the backends in the chain - see cmn.BackendChainConf)
+func (b *Bck) WithBackend(backend cmn.Bck) *Bck {
+	props := *b.Props
+	props.BackendBck = backend
+	return &Bck{Bck: b.Bck, Props: &props}
+}
+
 // NOTE: when the specified bucket is not present in the BMD:
 // - always returns the corresponding *DoesNotExist error
 // - for Cloud bucket - fills in the props with defaults from config
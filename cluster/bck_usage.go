@@ -0,0 +1,186 @@
+// Package cluster provides common interfaces and local access to cluster-level metadata
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package cluster
+
+import (
+	"sync"
+
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+//
+// Usage of the buckets with enabled quota (see cmn.QuotaConf). The local usage
+// is updated incrementally - when objects get written and removed - and is
+// periodically reconciled with the actual (on-disk) usage. The usage of the
+// same bucket on the other targets is refreshed periodically as well, so that
+// writes are checked against the cluster-wide limits. Buckets are keyed
+// by their IDs so that a destroyed and re-created bucket starts from scratch.
+//
+
+type (
+	bckUsage struct {
+		objs, size         int64         // local
+		peerObjs, peerSize int64         // other targets
+		ready              chan struct{} // closed when the local usage is known
+		peersReady         chan struct{} // ditto, the usage on other targets
+		known              bool          // false: being reconciled for the first time
+		peers              bool          // false: the usage on other targets is not known yet
+	}
+	BckUsageTracker struct {
+		m   map[uint64]*bckUsage
+		mtx sync.Mutex
+	}
+)
+
+var BckUsage = &BckUsageTracker{m: make(map[uint64]*bckUsage)}
+
+// Init returns channels that get closed once the local usage of the bucket
+// and, respectively, its usage on the other targets is known (or will never
+// be - see Del). The first caller gets `reconcile` set to indicate that
+// it must reconcile the usage.
+func (u *BckUsageTracker) Init(bck *Bck) (ready, peersReady <-chan struct{}, reconcile bool) {
+	u.mtx.Lock()
+	usage, ok := u.m[bck.Props.BID]
+	if !ok {
+		usage = newBckUsage()
+		u.m[bck.Props.BID] = usage
+		reconcile = true
+	}
+	u.mtx.Unlock()
+	return usage.ready, usage.peersReady, reconcile
+}
+
+// Check returns an error if adding the given delta to the usage of the bucket
+// would exceed the quota (the usage is not updated).
+func (u *BckUsageTracker) Check(bck *Bck, objs, size int64) error {
+	u.mtx.Lock()
+	defer u.mtx.Unlock()
+	usage, ok := u.m[bck.Props.BID]
+	if !ok || !usage.known {
+		return nil
+	}
+	return usage.check(bck, objs, size)
+}
+
+// Reserve adds the given delta to the usage of the bucket; if `enforce` is true
+// and the delta is positive, fails when the result exceeds the quota.
+func (u *BckUsageTracker) Reserve(bck *Bck, objs, size int64, enforce bool) error {
+	u.mtx.Lock()
+	defer u.mtx.Unlock()
+	usage, ok := u.m[bck.Props.BID]
+	if !ok || !usage.known {
+		return nil
+	}
+	if enforce {
+		if err := usage.check(bck, objs, size); err != nil {
+			return err
+		}
+	}
+	usage.objs += objs
+	usage.size += size
+	return nil
+}
+
+// Add updates the usage of the bucket (if tracked) with no checks.
+func (u *BckUsageTracker) Add(bck *Bck, objs, size int64) {
+	if bck.Props == nil {
+		return
+	}
+	u.mtx.Lock()
+	if usage, ok := u.m[bck.Props.BID]; ok && usage.known {
+		usage.objs += objs
+		usage.size += size
+	}
+	u.mtx.Unlock()
+}
+
+// Set sets the reconciled local usage of the bucket.
+func (u *BckUsageTracker) Set(bck *Bck, objs, size int64) {
+	u.mtx.Lock()
+	usage, ok := u.m[bck.Props.BID]
+	if !ok {
+		usage = newBckUsage()
+		u.m[bck.Props.BID] = usage
+	}
+	usage.objs, usage.size = objs, size
+	if !usage.known {
+		usage.known = true
+		close(usage.ready)
+	}
+	u.mtx.Unlock()
+}
+
+// SetPeers sets the total usage of the bucket on all other targets.
+func (u *BckUsageTracker) SetPeers(bck *Bck, objs, size int64) {
+	u.mtx.Lock()
+	if usage, ok := u.m[bck.Props.BID]; ok {
+		usage.peerObjs, usage.peerSize = objs, size
+		if !usage.peers {
+			usage.peers = true
+			close(usage.peersReady)
+		}
+	}
+	u.mtx.Unlock()
+}
+
+// Get returns the local usage of the bucket.
+func (u *BckUsageTracker) Get(bck *Bck) (objs, size int64, ok bool) {
+	u.mtx.Lock()
+	if usage, exists := u.m[bck.Props.BID]; exists && usage.known {
+		objs, size, ok = usage.objs, usage.size, true
+	}
+	u.mtx.Unlock()
+	return
+}
+
+// Prune stops tracking the buckets that do not satisfy the `keep` predicate.
+func (u *BckUsageTracker) Prune(keep func(bid uint64) bool) {
+	u.mtx.Lock()
+	for bid, usage := range u.m {
+		if !keep(bid) {
+			usage.del()
+			delete(u.m, bid)
+		}
+	}
+	u.mtx.Unlock()
+}
+
+func (u *BckUsageTracker) Del(bck *Bck) {
+	u.mtx.Lock()
+	if usage, ok := u.m[bck.Props.BID]; ok {
+		usage.del()
+		delete(u.m, bck.Props.BID)
+	}
+	u.mtx.Unlock()
+}
+
+func newBckUsage() *bckUsage {
+	return &bckUsage{ready: make(chan struct{}), peersReady: make(chan struct{})}
+}
+
+// must be called under lock
+func (usage *bckUsage) check(bck *Bck, objs, size int64) error {
+	conf := &bck.Props.Quota
+	if !conf.Enabled {
+		return nil
+	}
+	if conf.MaxObjects > 0 && objs > 0 && usage.objs+usage.peerObjs+objs > conf.MaxObjects {
+		return cmn.NewQuotaExceededErr(bck.String(), "number of objects", conf.MaxObjects)
+	}
+	if conf.MaxBytes > 0 && size > 0 && usage.size+usage.peerSize+size > conf.MaxBytes {
+		return cmn.NewQuotaExceededErr(bck.String(), "size", conf.MaxBytes)
+	}
+	return nil
+}
+
+// wakes up those waiting for the usage that will never be known
+func (usage *bckUsage) del() {
+	if !usage.known {
+		close(usage.ready)
+	}
+	if !usage.peers {
+		close(usage.peersReady)
+	}
+}
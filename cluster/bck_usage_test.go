@@ -0,0 +1,96 @@
+// Package cluster provides common interfaces and local access to cluster-level metadata
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package cluster
+
+import (
+	"github.com/NVIDIA/aistore/cmn"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("BckUsage", func() {
+	var (
+		u   *BckUsageTracker
+		bck *Bck
+	)
+
+	BeforeEach(func() {
+		u = &BckUsageTracker{m: make(map[uint64]*bckUsage)}
+		bck = NewBck("quota", cmn.ProviderAIS, cmn.NsGlobal)
+		bck.Props = &cmn.BucketProps{
+			BID:   1,
+			Quota: cmn.QuotaConf{MaxBytes: 1000, MaxObjects: 4, Enabled: true},
+		}
+	})
+
+	It("should request reconciling only once", func() {
+		ready, peersReady, reconcile := u.Init(bck)
+		Expect(reconcile).To(BeTrue())
+		_, _, reconcile = u.Init(bck)
+		Expect(reconcile).To(BeFalse())
+		Expect(ready).NotTo(BeClosed())
+
+		u.Set(bck, 0, 0)
+		Expect(ready).To(BeClosed())
+		Expect(peersReady).NotTo(BeClosed())
+		_, _, ok := u.Get(bck)
+		Expect(ok).To(BeTrue())
+
+		u.SetPeers(bck, 0, 0)
+		Expect(peersReady).To(BeClosed())
+	})
+
+	It("should wake up waiters when the bucket is no longer tracked", func() {
+		ready, peersReady, _ := u.Init(bck)
+		u.Del(bck)
+		Expect(ready).To(BeClosed())
+		Expect(peersReady).To(BeClosed())
+		_, _, ok := u.Get(bck)
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should enforce the quota", func() {
+		u.Set(bck, 3, 900)
+		Expect(cmn.IsErrQuotaExceeded(u.Check(bck, 1, 200))).To(BeTrue())
+		Expect(cmn.IsErrQuotaExceeded(u.Reserve(bck, 1, 200, true))).To(BeTrue())
+		Expect(u.Reserve(bck, 1, 100, true)).NotTo(HaveOccurred())
+		Expect(cmn.IsErrQuotaExceeded(u.Reserve(bck, 1, 0, true))).To(BeTrue())
+
+		// overwrite with a smaller object and not enforced
+		Expect(u.Reserve(bck, 0, -500, true)).NotTo(HaveOccurred())
+		Expect(u.Reserve(bck, 1, 0, false)).NotTo(HaveOccurred())
+
+		objs, size, ok := u.Get(bck)
+		Expect(ok).To(BeTrue())
+		Expect(objs).To(Equal(int64(5)))
+		Expect(size).To(Equal(int64(500)))
+
+		u.Add(bck, -2, -100)
+		objs, size, _ = u.Get(bck)
+		Expect(objs).To(Equal(int64(3)))
+		Expect(size).To(Equal(int64(400)))
+	})
+
+	It("should enforce the cluster-wide quota", func() {
+		u.Set(bck, 1, 0)
+		u.SetPeers(bck, 2, 900)
+		Expect(u.Check(bck, 1, 100)).NotTo(HaveOccurred())
+		Expect(cmn.IsErrQuotaExceeded(u.Check(bck, 0, 101))).To(BeTrue())
+		Expect(u.Reserve(bck, 1, 0, true)).NotTo(HaveOccurred())
+		Expect(cmn.IsErrQuotaExceeded(u.Reserve(bck, 1, 0, true))).To(BeTrue())
+
+		// local usage only
+		objs, size, _ := u.Get(bck)
+		Expect(objs).To(Equal(int64(2)))
+		Expect(size).To(Equal(int64(0)))
+	})
+
+	It("should prune", func() {
+		u.Set(bck, 1, 1)
+		u.Prune(func(bid uint64) bool { return bid != bck.Props.BID })
+		_, _, ok := u.Get(bck)
+		Expect(ok).To(BeFalse())
+	})
+})
@@ -21,10 +21,12 @@ import (
 	"github.com/NVIDIA/aistore/3rdparty/glog"
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/cmn/debug"
+	"github.com/NVIDIA/aistore/dedup"
 	"github.com/NVIDIA/aistore/fs"
 	"github.com/NVIDIA/aistore/hk"
 	"github.com/NVIDIA/aistore/ios"
 	"github.com/NVIDIA/aistore/memsys"
+	"github.com/NVIDIA/aistore/sse"
 )
 
 //
@@ -98,6 +100,10 @@ func (lom *LOM) GetCustomMD(key string) (string, bool) {
 	value, exists := lom.md.customMD[key]
 	return value, exists
 }
+func (lom *LOM) UserMD() cmn.SimpleKVs      { return lom.customMDByPrefix(UserObjMDPrefix) }
+func (lom *LOM) SetUserMD(md cmn.SimpleKVs) { lom.setCustomMDByPrefix(UserObjMDPrefix, md) }
+func (lom *LOM) Tags() cmn.SimpleKVs        { return lom.customMDByPrefix(TagObjMDPrefix) }
+func (lom *LOM) SetTags(tags cmn.SimpleKVs) { lom.setCustomMDByPrefix(TagObjMDPrefix, tags) }
 func (lom *LOM) ECEnabled() bool            { return lom.Bprops().EC.Enabled }
 func (lom *LOM) IsHRW() bool                { return lom.HrwFQN == lom.FQN } // subj to resilvering
 func (lom *LOM) Bck() *Bck                  { return lom.bck }
@@ -116,6 +122,36 @@ func (lom *LOM) MirrorConf() *cmn.MirrorConf { return &lom.Bprops().Mirror }
 func (lom *LOM) CksumConf() *cmn.CksumConf   { return lom.bck.CksumConf() }
 func (lom *LOM) VerConf() *cmn.VersionConf   { return &lom.Bprops().Versioning }
 
+// LogicalSize returns the size of the object's decoded content (the size that
+// GET and HEAD report) - different from the stored size (see Size) if the
+// object is compressed, deduplicated, or encrypted
+func (lom *LOM) LogicalSize() int64 {
+	if !lom.Bck().IsAIS() {
+		return lom.Size()
+	}
+	if _, size, _, ok := lom.Compressed(); ok {
+		return size
+	}
+	if lom.IsDeduped() {
+		if recipe, err := dedup.ReadRecipe(lom.FQN); err == nil {
+			return recipe.Size()
+		}
+		return lom.Size()
+	}
+	if !lom.IsEncrypted() {
+		return lom.Size()
+	}
+	file, err := os.Open(lom.FQN)
+	if err != nil {
+		return lom.Size()
+	}
+	defer func() { debug.AssertNoErr(file.Close()) }()
+	if size, err := sse.PlainSize(file, lom.Size()); err == nil {
+		return size
+	}
+	return lom.Size()
+}
+
 func (lom *LOM) CopyMetadata(from *LOM) {
 	lom.md.copies = nil
 	if lom.MirrorConf().Enabled && lom.Bck().Equal(from.Bck(), true /* must have same BID*/) {
@@ -132,6 +168,52 @@ func (lom *LOM) CopyMetadata(from *LOM) {
 	lom.md.atime = from.md.atime
 }
 
+// SetSysMD replaces system-defined custom metadata (source, cloud version, etc.)
+// while keeping user metadata and tags intact.
+func (lom *LOM) SetSysMD(md cmn.SimpleKVs) {
+	for k, v := range lom.md.customMD {
+		if isUserCustomKey(k) {
+			if md == nil {
+				md = make(cmn.SimpleKVs, 4)
+			}
+			md[k] = v
+		}
+	}
+	lom.md.customMD = md
+}
+
+func (lom *LOM) customMDByPrefix(prefix string) (md cmn.SimpleKVs) {
+	for k, v := range lom.md.customMD {
+		if strings.HasPrefix(k, prefix) {
+			if md == nil {
+				md = make(cmn.SimpleKVs, 4)
+			}
+			md[strings.TrimPrefix(k, prefix)] = v
+		}
+	}
+	return
+}
+
+func (lom *LOM) setCustomMDByPrefix(prefix string, md cmn.SimpleKVs) {
+	custom := make(cmn.SimpleKVs, len(lom.md.customMD)+len(md))
+	for k, v := range lom.md.customMD {
+		if !strings.HasPrefix(k, prefix) {
+			custom[k] = v
+		}
+	}
+	for k, v := range md {
+		custom[prefix+k] = v
+	}
+	if len(custom) == 0 {
+		custom = nil
+	}
+	lom.md.customMD = custom
+}
+
+func isUserCustomKey(k string) bool {
+	return strings.HasPrefix(k, UserObjMDPrefix) || strings.HasPrefix(k, TagObjMDPrefix)
+}
+
 func (lom *LOM) CloneCopiesMd() int {
 	var (
 		num    = len(lom.md.copies)
@@ -397,12 +479,34 @@ func (lom *LOM) CopyObject(dstFQN string, buf []byte) (dst *LOM, err error) {
 	}
 	dst.CopyMetadata(lom)
 
+	// a copy of the recipe that represents another object references the same chunks
+	var recipe, oldRecipe *dedup.Recipe
+	if lom.IsDeduped() {
+		dst.SetDeduped(true)
+		if dst.Uname() != lom.Uname() {
+			if recipe = lom.StoredRecipe(); recipe != nil {
+				if err = recipe.AddRef(); err != nil {
+					return
+				}
+				defer func() {
+					if err != nil {
+						recipe.Release()
+					}
+				}()
+			}
+			oldRecipe = dst.StoredRecipe()
+		}
+	}
+
 	if err = cmn.Rename(workFQN, dstFQN); err != nil {
 		if errRemove := cmn.RemoveFile(workFQN); errRemove != nil {
 			glog.Errorf(fmtNestedErr, errRemove)
 		}
 		return
 	}
+	if oldRecipe != nil {
+		oldRecipe.Release()
+	}
 
 	if cksumType != cmn.ChecksumNone {
 		if !dstCksum.Equal(lom.Cksum()) {
@@ -468,6 +572,13 @@ func (lom *LOM) _string(b string) string {
 func (lom *LOM) IncVersion() error {
 	cmn.Assert(lom.Bck().IsAIS())
 	if lom.Version() == "" {
+		// with history enabled, (re)created object continues the versions of the deleted one
+		if lom.VerConf().History {
+			if latest := lom.latestVersion(); latest > 0 {
+				lom.SetVersion(strconv.FormatUint(latest+1, 10))
+				return nil
+			}
+		}
 		lom.SetVersion(lomInitialVersion)
 		return nil
 	}
@@ -643,6 +754,16 @@ func (lom *LOM) Clone(fqn string) *LOM {
 	return dst
 }
 
+// CloneBackend returns a shallow copy of the LOM that resolves (see Bck.CloudBck)
+// to a given backend bucket; use CopyMd to take over the resulting metadata.
+func (lom *LOM) CloneBackend(backend cmn.Bck) *LOM {
+	dst := lom.Clone(lom.FQN)
+	dst.bck = lom.bck.WithBackend(backend)
+	return dst
+}
+
+func (lom *LOM) CopyMd(src *LOM) { lom.md = src.md }
+
 // Local Object Metadata (LOM) - is cached. Respectively, lifecycle of any given LOM
 // instance includes the following steps:
 // 1) construct LOM instance and initialize its runtime state: lom = LOM{...}.Init()
@@ -833,8 +954,21 @@ beg:
 }
 
 func (lom *LOM) Remove() (err error) {
-	lom.Uncache()
+	// noncurrent versions (see VersionLOM) are neither cached nor accounted
+	version := lom.ParsedFQN.ContentType == fs.VersionType
+	if !version {
+		lom.Uncache()
+	}
+	recipe := lom.StoredRecipe()
 	err = cmn.RemoveFile(lom.FQN)
+	if err == nil {
+		if !version {
+			BckUsage.Add(lom.bck, -1, -lom.Size())
+		}
+		if recipe != nil {
+			recipe.Release()
+		}
+	}
 	for copyFQN := range lom.md.copies {
 		if err := cmn.RemoveFile(copyFQN); err != nil {
 			glog.Error(err)
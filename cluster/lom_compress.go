@@ -0,0 +1,58 @@
+// Package cluster provides common interfaces and local access to cluster-level metadata
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package cluster
+
+import (
+	"strconv"
+
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+//
+// At-rest compression (see cmn.BckCompressionConf): the size and checksum of
+// a compressed object are those of its compressed (on-disk) content; the
+// original ones are kept in the custom metadata.
+//
+
+const (
+	compressedAlgo      = "algo"
+	compressedSize      = "size"
+	compressedCksumType = "cksum_type"
+	compressedCksumVal  = "cksum_value"
+)
+
+// SetCompressed records the compression algorithm along with the original size
+// and checksum of the object; empty algorithm removes the record.
+func (lom *LOM) SetCompressed(algo string, size int64, cksum *cmn.Cksum) {
+	if algo == "" {
+		lom.setCustomMDByPrefix(CompressedObjMDPrefix, nil)
+		return
+	}
+	md := cmn.SimpleKVs{
+		compressedAlgo: algo,
+		compressedSize: strconv.FormatInt(size, 10),
+	}
+	if cksum != nil && cksum.Type() != cmn.ChecksumNone {
+		md[compressedCksumType], md[compressedCksumVal] = cksum.Get()
+	}
+	lom.setCustomMDByPrefix(CompressedObjMDPrefix, md)
+}
+
+// Compressed returns the compression algorithm and the original size and
+// checksum (nil if not computed) of the object if the latter is compressed.
+func (lom *LOM) Compressed() (algo string, size int64, cksum *cmn.Cksum, ok bool) {
+	md := lom.customMDByPrefix(CompressedObjMDPrefix)
+	if algo, ok = md[compressedAlgo]; !ok {
+		return
+	}
+	var err error
+	if size, err = strconv.ParseInt(md[compressedSize], 10, 64); err != nil {
+		return "", 0, nil, false
+	}
+	if ty, ok := md[compressedCksumType]; ok {
+		cksum = cmn.NewCksum(ty, md[compressedCksumVal])
+	}
+	return
+}
@@ -0,0 +1,189 @@
+// Package cluster provides common interfaces and local access to cluster-level metadata
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package cluster
+
+import (
+	"io"
+	"os"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/debug"
+	"github.com/NVIDIA/aistore/dedup"
+	"github.com/NVIDIA/aistore/fs"
+)
+
+//
+// Deduplication (see cmn.BckDedupConf): the file of a deduplicated object
+// contains its recipe that references the chunks stored in the per-target
+// chunk store. Unlike encrypted and compressed content, the recipe is
+// recognized by the custom metadata rather than by the content itself - the
+// latter may not be trusted to reference (and release) the chunks.
+//
+
+func (lom *LOM) IsDeduped() bool {
+	_, ok := lom.md.customMD[DedupObjMD]
+	return ok
+}
+
+func (lom *LOM) SetDeduped(deduped bool) {
+	if deduped == lom.IsDeduped() {
+		return
+	}
+	custom := make(cmn.SimpleKVs, len(lom.md.customMD)+1)
+	for k, v := range lom.md.customMD {
+		custom[k] = v
+	}
+	if deduped {
+		custom[DedupObjMD] = "1"
+	} else {
+		delete(custom, DedupObjMD)
+	}
+	if len(custom) == 0 {
+		custom = nil
+	}
+	lom.md.customMD = custom
+}
+
+// StoredRecipe returns the recipe of the object that is currently stored at
+// lom.FQN (which is not necessarily the one that lom describes, e.g. when the
+// object is being overwritten) or nil if the stored object is not deduplicated.
+func (lom *LOM) StoredRecipe() *dedup.Recipe {
+	if !lom.Bck().IsAIS() {
+		return nil
+	}
+	md, err := lom.lmfs(false)
+	if err != nil {
+		return nil
+	}
+	if _, ok := md.customMD[DedupObjMD]; !ok {
+		return nil
+	}
+	recipe, err := dedup.ReadRecipe(lom.FQN)
+	if err != nil {
+		glog.Errorf("%s: %v", lom, err)
+		return nil
+	}
+	return recipe
+}
+
+// Dedup replaces the content of a loaded and write-locked object with its
+// recipe; returns the number of bytes of the content that was not already
+// stored in the chunk store.
+func (lom *LOM) Dedup(buf []byte) (stored int64, err error) {
+	var (
+		src, dst *os.File
+		cksum    *cmn.CksumHash
+		w        io.Writer
+		dw       *dedup.Writer
+		copies   []string
+		oldSize  = lom.Size()
+		workFQN  = fs.CSM.GenContentParsedFQN(lom.ParsedFQN, fs.WorkfileType, fs.WorkfilePut)
+	)
+	for copyFQN := range lom.GetCopies() {
+		if copyFQN != lom.FQN {
+			copies = append(copies, copyFQN)
+		}
+	}
+	if src, err = os.Open(lom.FQN); err != nil {
+		return
+	}
+	defer func() { debug.AssertNoErr(src.Close()) }()
+	if dst, err = cmn.CreateFile(workFQN); err != nil {
+		return
+	}
+	w = dst
+	if ty := lom.CksumConf().Type; ty != cmn.ChecksumNone {
+		cksum = cmn.NewCksumHash(ty)
+		w = cmn.NewWriterMulti(cksum.H, dst)
+	}
+	dw = dedup.NewWriter(w)
+	if _, err = io.CopyBuffer(dw, src, buf); err == nil {
+		err = dw.Close()
+	}
+	if errClose := dst.Close(); err == nil {
+		err = errClose
+	}
+	if err == nil {
+		err = cmn.Rename(workFQN, lom.FQN)
+	}
+	if err != nil {
+		dw.Abort()
+		if errRemove := cmn.RemoveFile(workFQN); errRemove != nil {
+			glog.Errorf(fmtNestedErr, errRemove)
+		}
+		return
+	}
+	lom.SetSize(dw.Written())
+	if cksum != nil {
+		cksum.Finalize()
+		lom.SetCksum(&cksum.Cksum)
+	} else {
+		lom.SetCksum(cmn.NewCksum(cmn.ChecksumNone, ""))
+	}
+	lom.SetDeduped(true)
+	BckUsage.Add(lom.bck, 0, lom.Size()-oldSize)
+
+	// replace the copies (if any) with the copies of the recipe
+	if lom.HasCopies() {
+		if err = lom.DelAllCopies(); err != nil {
+			return
+		}
+	}
+	if err = lom.Persist(); err != nil {
+		return
+	}
+	for _, copyFQN := range copies {
+		if _, err = lom.CopyObject(copyFQN, buf); err != nil {
+			return
+		}
+	}
+	return dw.Stored(), nil
+}
+
+// ReleaseTrashed releases the chunks referenced by the object of a destroyed
+// (or evicted) bucket - the object that resides in the trash of a given
+// mountpath (see fs.DestroyBuckets) - if the object is deduplicated.
+// The copies of a mirrored object share the references of the main replica
+// and are skipped. Files other than objects are skipped as well.
+func ReleaseTrashed(mi *fs.MountpathInfo, fqn string) {
+	b, err := fs.GetXattr(fqn, XattrLOM)
+	if err != nil || len(b) == 0 {
+		return
+	}
+	md := &lmeta{}
+	if err := md.unmarshal(b); err != nil {
+		return
+	}
+	if _, ok := md.customMD[DedupObjMD]; !ok {
+		return
+	}
+	if len(md.copies) > 1 && !isMainReplica(mi, md) {
+		return
+	}
+	recipe, err := dedup.ReadRecipe(fqn)
+	if err != nil {
+		glog.Errorf("%s: %v", fqn, err)
+		return
+	}
+	recipe.Release()
+}
+
+// the main replica is the one on the HRW mountpath - the latter is computed
+// from the original location of any copy (all copies are recorded in each)
+func isMainReplica(mi *fs.MountpathInfo, md *lmeta) bool {
+	for copyFQN := range md.copies {
+		parsed, err := fs.ParseFQN(copyFQN)
+		if err != nil {
+			continue
+		}
+		hmi, _, err := HrwMpath(NewBckEmbed(parsed.Bck).MakeUname(parsed.ObjName))
+		if err != nil {
+			return false
+		}
+		return hmi.Path == mi.Path
+	}
+	return false
+}
@@ -0,0 +1,102 @@
+// Package cluster provides common interfaces and local access to cluster-level metadata
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package cluster
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+//
+// Object lock (see cmn.ObjLockConf): an object is retained for the bucket's
+// retention period counting from the time it was written - the end of the
+// period is stored with the object (and does not depend on its mtime that
+// may change when the object is migrated) - and for as long as it is under
+// legal hold. Retained objects cannot be deleted, overwritten, renamed, or
+// evicted.
+//
+
+func (lom *LOM) LegalHold() bool {
+	_, ok := lom.md.customMD[LegalHoldObjMD]
+	return ok
+}
+
+func (lom *LOM) SetLegalHold(on bool) {
+	if on {
+		lom.setObjLockMD(LegalHoldObjMD, "on")
+	} else {
+		lom.setObjLockMD(LegalHoldObjMD, "")
+	}
+}
+
+// SetRetainUntil is called when the object is written (zero time - no retention)
+func (lom *LOM) SetRetainUntil(until time.Time) {
+	if until.IsZero() {
+		lom.setObjLockMD(RetainUntilObjMD, "")
+	} else {
+		lom.setObjLockMD(RetainUntilObjMD, strconv.FormatInt(until.Unix(), 10))
+	}
+}
+
+// empty value deletes the key
+func (lom *LOM) setObjLockMD(key, value string) {
+	custom := make(cmn.SimpleKVs, len(lom.md.customMD)+1)
+	for k, v := range lom.md.customMD {
+		custom[k] = v
+	}
+	if value != "" {
+		custom[key] = value
+	} else {
+		delete(custom, key)
+	}
+	if len(custom) == 0 {
+		custom = nil
+	}
+	lom.md.customMD = custom
+}
+
+// ObjLockErr returns cmn.ObjLockedErr if the (loaded) object is retained.
+// Governance-mode retention (but not legal hold) can be bypassed.
+func (lom *LOM) ObjLockErr(bypassGovernance bool) error {
+	return lom.objLockErr(&lom.md, bypassGovernance)
+}
+
+// OverwriteErr is ObjLockErr for the object that is currently stored at lom.FQN
+// (to be overwritten) - lom itself may already contain the new metadata.
+// Returns nil if there is no such object.
+func (lom *LOM) OverwriteErr(bypassGovernance bool) error {
+	md, err := lom.lmfs(false)
+	if err != nil {
+		return nil // (does not exist or has no metadata)
+	}
+	return lom.objLockErr(md, bypassGovernance)
+}
+
+func (lom *LOM) objLockErr(md *lmeta, bypassGovernance bool) error {
+	if _, ok := md.customMD[LegalHoldObjMD]; ok {
+		return cmn.NewObjLockedErr(lom.String(), "legal hold")
+	}
+	conf := &lom.Bprops().ObjLock
+	if !conf.Enabled {
+		return nil
+	}
+	if bypassGovernance && conf.Mode == cmn.ObjLockGovernance {
+		return nil
+	}
+	v, ok := md.customMD[RetainUntilObjMD]
+	if !ok {
+		return nil // written when retention was not configured
+	}
+	secs, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return nil
+	}
+	if until := time.Unix(secs, 0); time.Now().Before(until) {
+		return cmn.NewObjLockedErr(lom.String(), conf.Mode+" retention until "+until.Format(time.RFC3339))
+	}
+	return nil
+}
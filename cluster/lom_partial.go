@@ -0,0 +1,148 @@
+// Package cluster provides common interfaces and local access to cluster-level metadata
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package cluster
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+//
+// Partial caching (see cmn.BckPartialCacheConf): a large cloud object is stored
+// as a sparse file of its entire size with only some of its chunks present.
+// The present chunks are tracked by PartialMD kept in the custom metadata
+// under PartialObjMD: "<chunk size>,<base64 bitmap>,<cloud etag>".
+//
+
+// MaxPartialChunks bounds the size of the bitmap (and, therefore, of the metadata):
+// the chunk size of a larger object is increased accordingly
+const MaxPartialChunks = 8192
+
+type PartialMD struct {
+	Size      int64  // entire object
+	ChunkSize int64  // (the last chunk may be shorter)
+	ETag      string // of the cloud object the chunks have been read from
+	bitmap    []byte
+}
+
+func NewPartialMD(size, chunkSize int64, etag string) *PartialMD {
+	if minSize := cmn.DivCeil(size, MaxPartialChunks); chunkSize < minSize {
+		chunkSize = minSize
+	}
+	pmd := &PartialMD{Size: size, ChunkSize: chunkSize, ETag: etag}
+	pmd.bitmap = make([]byte, (pmd.NumChunks()+7)/8)
+	return pmd
+}
+
+func (pmd *PartialMD) NumChunks() int { return int(cmn.DivCeil(pmd.Size, pmd.ChunkSize)) }
+
+// Chunk returns the offset and the length of a given chunk
+func (pmd *PartialMD) Chunk(i int) (offset, length int64) {
+	offset = int64(i) * pmd.ChunkSize
+	return offset, cmn.MinI64(pmd.ChunkSize, pmd.Size-offset)
+}
+
+// Chunks returns the first and the last chunks of a given (non-empty) byte range
+func (pmd *PartialMD) Chunks(offset, length int64) (first, last int) {
+	return int(offset / pmd.ChunkSize), int((offset + length - 1) / pmd.ChunkSize)
+}
+
+func (pmd *PartialMD) Has(i int) bool { return pmd.bitmap[i/8]&(1<<uint(i%8)) != 0 }
+func (pmd *PartialMD) Set(i int)      { pmd.bitmap[i/8] |= 1 << uint(i%8) }
+func (pmd *PartialMD) Clear(i int)    { pmd.bitmap[i/8] &^= 1 << uint(i%8) }
+
+func (pmd *PartialMD) Complete() bool {
+	for i := 0; i < pmd.NumChunks(); i++ {
+		if !pmd.Has(i) {
+			return false
+		}
+	}
+	return true
+}
+
+// Cached returns the total size of the present chunks
+func (pmd *PartialMD) Cached() (size int64) {
+	for i := 0; i < pmd.NumChunks(); i++ {
+		if pmd.Has(i) {
+			_, length := pmd.Chunk(i)
+			size += length
+		}
+	}
+	return
+}
+
+func (pmd *PartialMD) pack() string {
+	return strconv.FormatInt(pmd.ChunkSize, 10) + "," + base64.RawStdEncoding.EncodeToString(pmd.bitmap) + "," + pmd.ETag
+}
+
+func unpackPartialMD(s string, size int64) (pmd *PartialMD, err error) {
+	var (
+		chunkSize int64
+		bitmap    []byte
+		fields    = strings.SplitN(s, ",", 3)
+	)
+	if len(fields) != 3 {
+		return nil, fmt.Errorf("invalid partial metadata %q", s)
+	}
+	if chunkSize, err = strconv.ParseInt(fields[0], 10, 64); err != nil || chunkSize <= 0 {
+		return nil, fmt.Errorf("invalid partial metadata %q: chunk size", s)
+	}
+	if bitmap, err = base64.RawStdEncoding.DecodeString(fields[1]); err != nil {
+		return nil, fmt.Errorf("invalid partial metadata %q: %v", s, err)
+	}
+	pmd = &PartialMD{Size: size, ChunkSize: chunkSize, ETag: fields[2], bitmap: bitmap}
+	if len(bitmap) != (pmd.NumChunks()+7)/8 {
+		return nil, fmt.Errorf("invalid partial metadata %q: size %d", s, size)
+	}
+	return pmd, nil
+}
+
+func (lom *LOM) IsPartial() bool {
+	_, ok := lom.md.customMD[PartialObjMD]
+	return ok
+}
+
+// PartialMD returns nil if the object is cached in its entirety
+func (lom *LOM) PartialMD() (*PartialMD, error) {
+	s, ok := lom.md.customMD[PartialObjMD]
+	if !ok {
+		return nil, nil
+	}
+	return unpackPartialMD(s, lom.md.size)
+}
+
+// SetPartialMD updates the partial metadata; nil marks the object as complete
+func (lom *LOM) SetPartialMD(pmd *PartialMD) {
+	if pmd == nil && !lom.IsPartial() {
+		return
+	}
+	// copy-on-write: the map may be shared with the cached metadata
+	custom := make(cmn.SimpleKVs, len(lom.md.customMD)+1)
+	for k, v := range lom.md.customMD {
+		custom[k] = v
+	}
+	if pmd == nil {
+		delete(custom, PartialObjMD)
+	} else {
+		custom[PartialObjMD] = pmd.pack()
+	}
+	if len(custom) == 0 {
+		custom = nil
+	}
+	lom.md.customMD = custom
+}
+
+// CachedSize returns the size of the locally stored content: the entire
+// object, or the present chunks of the partially cached one
+func (lom *LOM) CachedSize() int64 {
+	if pmd, err := lom.PartialMD(); err == nil && pmd != nil {
+		return pmd.Cached()
+	}
+	return lom.md.size
+}
@@ -0,0 +1,74 @@
+// Package cluster provides common interfaces and local access to cluster-level metadata
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package cluster
+
+import (
+	"github.com/NVIDIA/aistore/cmn"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PartialMD", func() {
+	It("should track chunks", func() {
+		pmd := NewPartialMD(10*cmn.MiB+1, cmn.MiB, "etag")
+		Expect(pmd.NumChunks()).To(Equal(11))
+		offset, length := pmd.Chunk(10)
+		Expect(offset).To(BeEquivalentTo(10 * cmn.MiB))
+		Expect(length).To(BeEquivalentTo(1))
+
+		first, last := pmd.Chunks(cmn.MiB-1, 2)
+		Expect(first).To(Equal(0))
+		Expect(last).To(Equal(1))
+
+		pmd.Set(0)
+		pmd.Set(10)
+		Expect(pmd.Has(0)).To(BeTrue())
+		Expect(pmd.Has(1)).To(BeFalse())
+		Expect(pmd.Cached()).To(BeEquivalentTo(cmn.MiB + 1))
+		Expect(pmd.Complete()).To(BeFalse())
+		for i := 0; i < pmd.NumChunks(); i++ {
+			pmd.Set(i)
+		}
+		Expect(pmd.Complete()).To(BeTrue())
+		pmd.Clear(10)
+		Expect(pmd.Complete()).To(BeFalse())
+	})
+
+	It("should limit the number of chunks", func() {
+		pmd := NewPartialMD(100*cmn.GiB, cmn.MiB, "")
+		Expect(pmd.NumChunks()).To(BeNumerically("<=", MaxPartialChunks))
+		Expect(pmd.ChunkSize).To(BeNumerically(">", cmn.MiB))
+	})
+
+	It("should pack and unpack", func() {
+		pmd := NewPartialMD(100*cmn.MiB, 4*cmn.MiB, `"with,comma"`)
+		pmd.Set(3)
+		pmd.Set(24)
+		lom := &LOM{}
+		lom.SetSize(pmd.Size)
+		Expect(lom.IsPartial()).To(BeFalse())
+		Expect(lom.CachedSize()).To(BeEquivalentTo(100 * cmn.MiB))
+
+		lom.SetPartialMD(pmd)
+		Expect(lom.IsPartial()).To(BeTrue())
+		unpacked, err := lom.PartialMD()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(unpacked).To(Equal(pmd))
+		Expect(lom.CachedSize()).To(BeEquivalentTo(8 * cmn.MiB))
+
+		lom.SetDirty("1")
+		Expect(lom.IsPartial()).To(BeFalse())
+	})
+
+	It("should fail to unpack invalid metadata", func() {
+		for _, s := range []string{"", "0,AA,", "1024,!!,", "1024,AA"} {
+			_, err := unpackPartialMD(s, 4096)
+			Expect(err).To(HaveOccurred())
+		}
+		// bitmap does not match the size
+		_, err := unpackPartialMD("1024,AAAA,", 4096)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,71 @@
+// Package cluster provides common interfaces and local access to cluster-level metadata
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package cluster
+
+import (
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+//
+// Server-side encryption (see cmn.BckSSEConf): the size and checksum of an
+// encrypted object are those of its ciphertext. Same as with deduplication,
+// encrypted content is recognized by the custom metadata - the content itself
+// may not be trusted to tell plaintext from ciphertext.
+//
+
+func (lom *LOM) IsEncrypted() bool {
+	_, ok := lom.md.customMD[EncryptedObjMD]
+	return ok
+}
+
+func (lom *LOM) SetEncrypted(encrypted bool) {
+	if encrypted == lom.IsEncrypted() {
+		return
+	}
+	custom := make(cmn.SimpleKVs, len(lom.md.customMD)+1)
+	for k, v := range lom.md.customMD {
+		custom[k] = v
+	}
+	if encrypted {
+		custom[EncryptedObjMD] = "1"
+	} else {
+		delete(custom, EncryptedObjMD)
+	}
+	if len(custom) == 0 {
+		custom = nil
+	}
+	lom.md.customMD = custom
+}
+
+// IsEncoded returns true if the content of the object is encrypted or
+// compressed (see also LOM.Compressed).
+func (lom *LOM) IsEncoded() bool {
+	if lom.IsEncrypted() {
+		return true
+	}
+	_, _, _, compressed := lom.Compressed()
+	return compressed
+}
+
+// StoredAsIs returns false if the content of the object must be decoded
+// (decrypted, decompressed, or reassembled from deduplicated chunks) to be read.
+func (lom *LOM) StoredAsIs() bool { return !lom.IsEncoded() && !lom.IsDeduped() }
+
+// PortableCustomMD returns the custom metadata that goes along with the object
+// when the latter gets copied to another target - all but the recipe marker:
+// the content of a deduplicated object is sent instead of its recipe.
+func (lom *LOM) PortableCustomMD() cmn.SimpleKVs {
+	var md cmn.SimpleKVs
+	for k, v := range lom.md.customMD {
+		if k == DedupObjMD {
+			continue
+		}
+		if md == nil {
+			md = make(cmn.SimpleKVs, len(lom.md.customMD))
+		}
+		md[k] = v
+	}
+	return md
+}
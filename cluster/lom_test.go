@@ -34,6 +34,11 @@ var _ = Describe("LOM", func() {
 		bucketCloudB = "LOM_TEST_Cloud_B"
 
 		sameBucketName = "LOM_TEST_Local_and_Cloud"
+
+		bucketVersioned = "LOM_TEST_Versioned"
+
+		bucketGovernance = "LOM_TEST_Governance"
+		bucketCompliance = "LOM_TEST_Compliance"
 	)
 
 	var (
@@ -68,6 +73,7 @@ var _ = Describe("LOM", func() {
 
 	_ = fs.CSM.RegisterContentType(fs.ObjectType, &fs.ObjectContentResolver{})
 	_ = fs.CSM.RegisterContentType(fs.WorkfileType, &fs.WorkfileContentResolver{})
+	_ = fs.CSM.RegisterContentType(fs.VersionType, &fs.VersionContentResolver{})
 
 	var (
 		bmd = cluster.NewBaseBownerMock(
@@ -89,6 +95,27 @@ var _ = Describe("LOM", func() {
 				},
 			),
 			cluster.NewBck(sameBucketName, cmn.ProviderAIS, cmn.NsGlobal, &cmn.BucketProps{}),
+			cluster.NewBck(
+				bucketVersioned, cmn.ProviderAIS, cmn.NsGlobal,
+				&cmn.BucketProps{
+					Cksum:      cmn.CksumConf{Type: cmn.ChecksumNone},
+					Versioning: cmn.VersionConf{Enabled: true, History: true},
+				},
+			),
+			cluster.NewBck(
+				bucketGovernance, cmn.ProviderAIS, cmn.NsGlobal,
+				&cmn.BucketProps{
+					Cksum:   cmn.CksumConf{Type: cmn.ChecksumNone},
+					ObjLock: cmn.ObjLockConf{Enabled: true, Mode: cmn.ObjLockGovernance, RetentionDays: 1},
+				},
+			),
+			cluster.NewBck(
+				bucketCompliance, cmn.ProviderAIS, cmn.NsGlobal,
+				&cmn.BucketProps{
+					Cksum:   cmn.CksumConf{Type: cmn.ChecksumNone},
+					ObjLock: cmn.ObjLockConf{Enabled: true, Mode: cmn.ObjLockCompliance, RetentionDays: 1},
+				},
+			),
 			cluster.NewBck(bucketCloudA, cmn.ProviderAmazon, cmn.NsGlobal, &cmn.BucketProps{}),
 			cluster.NewBck(bucketCloudB, cmn.ProviderAmazon, cmn.NsGlobal, &cmn.BucketProps{}),
 			cluster.NewBck(sameBucketName, cmn.ProviderAmazon, cmn.NsGlobal, &cmn.BucketProps{}),
@@ -577,6 +604,40 @@ var _ = Describe("LOM", func() {
 				_, exists = lom.GetCustomMD("unknown")
 				Expect(exists).To(BeFalse())
 			})
+
+			It("should not modify metadata shared with another LOM", func() {
+				lom := filePut(localFQN, 4*cmn.KiB, tMock)
+				lom.SetCustomMD(cmn.SimpleKVs{cluster.SourceObjMD: cluster.SourceGoogleObjMD})
+				clone := lom.Clone(localFQN)
+				pmd := cluster.NewPartialMD(lom.Size(), cmn.KiB, "etag")
+				pmd.Set(0)
+				clone.SetPartialMD(pmd)
+				Expect(clone.IsPartial()).To(BeTrue())
+				Expect(lom.IsPartial()).To(BeFalse())
+				clone.SetPartialMD(nil)
+				Expect(clone.IsPartial()).To(BeFalse())
+				_, exists := clone.GetCustomMD(cluster.SourceObjMD)
+				Expect(exists).To(BeTrue())
+			})
+		})
+
+		Describe("LogicalSize", func() {
+			testObject := "foldr/test-obj.ext"
+			localFQN := mis[0].MakePathFQN(localBckA, fs.ObjectType, testObject)
+
+			It("should report the size of the stored object", func() {
+				lom := filePut(localFQN, cmn.KiB, tMock)
+				Expect(lom.LogicalSize()).To(BeEquivalentTo(cmn.KiB))
+			})
+
+			It("should report the original size of the compressed object", func() {
+				lom := filePut(localFQN, cmn.KiB, tMock)
+				lom.SetCompressed("lz4", 4*cmn.KiB, nil)
+				Expect(lom.Persist()).NotTo(HaveOccurred())
+				Expect(lom.Load(false)).NotTo(HaveOccurred())
+				Expect(lom.Size()).To(BeEquivalentTo(cmn.KiB))
+				Expect(lom.LogicalSize()).To(BeEquivalentTo(4 * cmn.KiB))
+			})
 		})
 	})
 
@@ -908,6 +969,176 @@ var _ = Describe("LOM", func() {
 		})
 	})
 
+	Describe("noncurrent versions", func() {
+		var (
+			versionedBck = cmn.Bck{Name: bucketVersioned, Provider: cmn.ProviderAIS, Ns: cmn.NsGlobal}
+			testObject   = "foldr/test-obj.ext"
+			past         = time.Now().Add(-48 * time.Hour)
+		)
+
+		// writes the object (over the existing one) the way PUT does
+		overwrite := func(lom *cluster.LOM, size int) (vfqn string) {
+			var err error
+			vfqn, err = lom.ArchiveVersion()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(lom.IncVersion()).NotTo(HaveOccurred())
+			createTestFile(lom.FQN, size)
+			lom.SetSize(int64(size))
+			Expect(lom.Persist()).NotTo(HaveOccurred())
+			lom.CommitVersion(vfqn)
+			return
+		}
+
+		It("should archive, list, load, and restore versions", func() {
+			fqn := mis[0].MakePathFQN(versionedBck, fs.ObjectType, testObject)
+			lom := filePut(fqn, 10, tMock)
+			Expect(lom.Version()).To(Equal("1"))
+			Expect(os.Chtimes(fqn, past, past)).NotTo(HaveOccurred())
+
+			// failed overwrite leaves the object as it was
+			vfqn, err := lom.ArchiveVersion()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(vfqn).To(Equal(lom.VersionFQN("1")))
+			_, err = os.Stat(fqn)
+			Expect(os.IsNotExist(err)).To(BeTrue())
+			Expect(lom.UnarchiveVersion(vfqn)).NotTo(HaveOccurred())
+			finfo, err := os.Stat(fqn)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(finfo.ModTime().Unix()).To(Equal(past.Unix()))
+
+			vfqn = overwrite(lom, 20)
+			Expect(lom.Version()).To(Equal("2"))
+			finfo, err = os.Stat(vfqn)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(finfo.ModTime()).To(BeTemporally("~", time.Now(), time.Minute))
+			Expect(finfo.Size()).To(BeEquivalentTo(10))
+
+			vloms, err := lom.ListVersions()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(vloms).To(HaveLen(1))
+			Expect(vloms[0].Version()).To(Equal("1"))
+			Expect(vloms[0].Size()).To(BeEquivalentTo(10))
+
+			vlom, err := lom.LoadVersion("1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(vlom.FQN).To(Equal(vfqn))
+			_, err = lom.LoadVersion("7")
+			Expect(cmn.IsObjNotExist(err)).To(BeTrue())
+
+			// the current object gets deleted: the latest noncurrent version becomes current
+			overwrite(lom, 30)
+			Expect(lom.Version()).To(Equal("3"))
+			Expect(lom.Remove()).NotTo(HaveOccurred())
+			restored, err := lom.RestoreLatestVersion()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(restored).To(BeTrue())
+			current := NewBasicLom(fqn, tMock)
+			Expect(current.Load(false)).NotTo(HaveOccurred())
+			Expect(current.Version()).To(Equal("2"))
+			Expect(current.Size()).To(BeEquivalentTo(20))
+
+			// re-created object continues the versions
+			Expect(current.Remove()).NotTo(HaveOccurred())
+			recreated := NewBasicLom(fqn, tMock)
+			Expect(recreated.IncVersion()).NotTo(HaveOccurred())
+			Expect(recreated.Version()).To(Equal("2"))
+
+			Expect(lom.DelVersion("1", false)).NotTo(HaveOccurred())
+			vloms, err = lom.ListVersions()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(vloms).To(BeEmpty())
+		})
+
+		It("should add migrated versions", func() {
+			fqn := mis[0].MakePathFQN(versionedBck, fs.ObjectType, testObject)
+			lom := NewBasicLom(fqn, tMock)
+			for _, ver := range []string{"4", "12"} {
+				workFQN := fs.CSM.GenContentParsedFQN(lom.ParsedFQN, fs.WorkfileType, fs.WorkfilePut)
+				createTestFile(workFQN, 10)
+				vlom := NewBasicLom(fqn, tMock)
+				vlom.SetVersion(ver)
+				vlom.SetSize(10)
+				Expect(vlom.AddVersion(workFQN, past)).NotTo(HaveOccurred())
+			}
+			vloms, err := lom.ListVersions()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(vloms).To(HaveLen(2))
+			Expect(vloms[0].Version()).To(Equal("12"))
+			Expect(vloms[1].Version()).To(Equal("4"))
+			finfo, err := os.Stat(vloms[0].FQN)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(finfo.ModTime().Unix()).To(Equal(past.Unix()))
+
+			// the object (re)created after migration continues the versions
+			Expect(lom.IncVersion()).NotTo(HaveOccurred())
+			Expect(lom.Version()).To(Equal("13"))
+		})
+
+		It("should not delete retained versions", func() {
+			fqn := mis[0].MakePathFQN(versionedBck, fs.ObjectType, testObject)
+			lom := NewBasicLom(fqn, tMock)
+			workFQN := fs.CSM.GenContentParsedFQN(lom.ParsedFQN, fs.WorkfileType, fs.WorkfilePut)
+			createTestFile(workFQN, 10)
+			vlom := NewBasicLom(fqn, tMock)
+			vlom.SetVersion("5")
+			vlom.SetSize(10)
+			vlom.SetLegalHold(true)
+			Expect(vlom.AddVersion(workFQN, past)).NotTo(HaveOccurred())
+
+			err := lom.DelVersion("5", true /*bypassGovernance*/)
+			Expect(cmn.IsErrObjLocked(err)).To(BeTrue())
+			_, err = lom.LoadVersion("5")
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Describe("object lock", func() {
+		var (
+			testObject    = "foldr/test-obj.ext"
+			governanceBck = cmn.Bck{Name: bucketGovernance, Provider: cmn.ProviderAIS, Ns: cmn.NsGlobal}
+			complianceBck = cmn.Bck{Name: bucketCompliance, Provider: cmn.ProviderAIS, Ns: cmn.NsGlobal}
+			tomorrow      = time.Now().Add(24 * time.Hour)
+		)
+
+		It("should bypass governance but not compliance retention", func() {
+			lom := filePut(mis[0].MakePathFQN(governanceBck, fs.ObjectType, testObject), 10, tMock)
+			lom.SetRetainUntil(tomorrow)
+			Expect(cmn.IsErrObjLocked(lom.ObjLockErr(false))).To(BeTrue())
+			Expect(lom.ObjLockErr(true)).NotTo(HaveOccurred())
+
+			lom = filePut(mis[0].MakePathFQN(complianceBck, fs.ObjectType, testObject), 10, tMock)
+			lom.SetRetainUntil(tomorrow)
+			Expect(cmn.IsErrObjLocked(lom.ObjLockErr(true))).To(BeTrue())
+		})
+
+		It("should not retain objects past their retention or written without it", func() {
+			lom := filePut(mis[0].MakePathFQN(complianceBck, fs.ObjectType, testObject), 10, tMock)
+			Expect(lom.ObjLockErr(false)).NotTo(HaveOccurred())
+			lom.SetRetainUntil(time.Now().Add(-time.Minute))
+			Expect(lom.ObjLockErr(false)).NotTo(HaveOccurred())
+		})
+
+		It("should not bypass legal hold", func() {
+			lom := filePut(mis[0].MakePathFQN(governanceBck, fs.ObjectType, testObject), 10, tMock)
+			lom.SetLegalHold(true)
+			Expect(cmn.IsErrObjLocked(lom.ObjLockErr(true))).To(BeTrue())
+			lom.SetLegalHold(false)
+			Expect(lom.ObjLockErr(false)).NotTo(HaveOccurred())
+		})
+
+		It("should check the stored object to be overwritten", func() {
+			fqn := mis[0].MakePathFQN(complianceBck, fs.ObjectType, testObject)
+			lom := filePut(fqn, 10, tMock)
+			lom.SetRetainUntil(tomorrow)
+			Expect(lom.Persist()).NotTo(HaveOccurred())
+
+			// new content (and metadata) of the same object
+			lom = NewBasicLom(fqn, tMock)
+			Expect(lom.ObjLockErr(false)).NotTo(HaveOccurred())
+			Expect(cmn.IsErrObjLocked(lom.OverwriteErr(true))).To(BeTrue())
+		})
+	})
+
 	Describe("local and cloud bucket with the same name", func() {
 		It("should have different fqn", func() {
 			testObject := "foldr/test-obj.ext"
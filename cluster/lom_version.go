@@ -0,0 +1,242 @@
+// Package cluster provides common interfaces and local access to cluster-level metadata
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package cluster
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/fs"
+)
+
+//
+// Noncurrent versions (see cmn.VersionConf.History) are stored as a separate
+// content type (fs.VersionType) - the object name with the version appended.
+// Each noncurrent version keeps the content and the metadata that the object
+// had at the time it was overwritten; the mtime of a noncurrent version is
+// the time it became noncurrent.
+//
+// All methods below require the caller to hold the object's lock.
+//
+
+// VersionFQN returns the FQN of a given noncurrent version on the object's mountpath
+func (lom *LOM) VersionFQN(ver string) string {
+	return fs.CSM.GenContentParsedFQN(lom.ParsedFQN, fs.VersionType, ver)
+}
+
+func (lom *LOM) versionFQN(mpathInfo *fs.MountpathInfo, ver string) string {
+	parsedFQN := lom.ParsedFQN
+	parsedFQN.MpathInfo = mpathInfo
+	return fs.CSM.GenContentParsedFQN(parsedFQN, fs.VersionType, ver)
+}
+
+// ArchiveVersion moves the current content of the object aside as a noncurrent
+// version - to be called right before overwriting the object and followed by
+// either CommitVersion or UnarchiveVersion. Returns the FQN of the noncurrent
+// version or "" if the object does not exist.
+// NOTE: also syncs lom's version with the (archived) one on disk so that
+// the subsequent lom.IncVersion() never reuses an existing version.
+func (lom *LOM) ArchiveVersion() (vfqn string, err error) {
+	md, err := lom.lmfs(false)
+	if err != nil {
+		if os.IsNotExist(err) {
+			lom.SetVersion("")
+			err = nil
+		}
+		return
+	}
+	lom.SetVersion(md.version)
+	if md.version == "" {
+		return
+	}
+	vfqn = lom.VersionFQN(md.version)
+	if err = cmn.CreateDir(filepath.Dir(vfqn)); err != nil {
+		return "", err
+	}
+	// (the version is on the same mountpath; the object must not share its
+	// inode with the version as the latter gets its own mtime)
+	if err = os.Rename(lom.FQN, vfqn); err != nil {
+		return "", err
+	}
+	return
+}
+
+// CommitVersion sets the time the archived version became noncurrent - once
+// the object has been overwritten.
+func (lom *LOM) CommitVersion(vfqn string) {
+	now := time.Now()
+	if err := os.Chtimes(vfqn, now, now); err != nil {
+		glog.Errorf("%s: failed to set mtime of version %s: %v", lom, vfqn, err)
+	}
+}
+
+// UnarchiveVersion makes the version archived by ArchiveVersion current
+// again - when the object could not be overwritten.
+func (lom *LOM) UnarchiveVersion(vfqn string) error {
+	return os.Rename(vfqn, lom.FQN)
+}
+
+// AddVersion makes a given workfile a noncurrent version of the object that
+// became noncurrent at a given time; the version and the rest of the metadata
+// are lom's (e.g., the noncurrent version migrated by rebalance).
+func (lom *LOM) AddVersion(workFQN string, archived time.Time) (err error) {
+	vfqn := lom.VersionFQN(lom.Version())
+	if err = cmn.CreateDir(filepath.Dir(vfqn)); err != nil {
+		return
+	}
+	if err = cmn.Rename(workFQN, vfqn); err != nil {
+		return
+	}
+	vlom := lom.Clone(vfqn)
+	vlom.md.copies = nil
+	if err = vlom.Persist(); err == nil {
+		err = os.Chtimes(vfqn, archived, archived)
+	}
+	if err != nil {
+		if errRemove := cmn.RemoveFile(vfqn); errRemove != nil {
+			glog.Errorf("Nested error: %v => (remove %s => err: %v)", err, vfqn, errRemove)
+		}
+	}
+	return
+}
+
+// LoadVersion loads a given noncurrent version of the object. The returned
+// LOM is read-only and must never be cached (or persisted).
+func (lom *LOM) LoadVersion(ver string) (vlom *LOM, err error) {
+	availablePaths, _ := fs.Get()
+	for _, mpathInfo := range availablePaths {
+		fqn := lom.versionFQN(mpathInfo, ver)
+		if _, err = os.Stat(fqn); err != nil {
+			continue
+		}
+		return lom.VersionLOM(fqn, mpathInfo)
+	}
+	if err == nil {
+		err = &os.PathError{Op: "load-version", Path: lom.VersionFQN(ver), Err: os.ErrNotExist}
+	}
+	return
+}
+
+// VersionLOM loads the noncurrent version stored at a given FQN (see LoadVersion).
+func (lom *LOM) VersionLOM(fqn string, mpathInfo *fs.MountpathInfo) (*LOM, error) {
+	vlom := lom.Clone(fqn)
+	vlom.HrwFQN = fqn
+	vlom.ParsedFQN.MpathInfo = mpathInfo
+	vlom.ParsedFQN.ContentType = fs.VersionType
+	vlom.md = lmeta{uname: lom.md.uname}
+	if err := vlom.FromFS(); err != nil {
+		return nil, err
+	}
+	vlom.md.copies = nil
+	vlom.loaded = true
+	return vlom, nil
+}
+
+// ListVersions returns all noncurrent versions of the object, latest first.
+func (lom *LOM) ListVersions() (vloms []*LOM, err error) {
+	availablePaths, _ := fs.Get()
+	for _, mpathInfo := range availablePaths {
+		var (
+			names     []string
+			dir, base = filepath.Split(fs.CSM.FQN(mpathInfo, lom.bck.Bck, fs.VersionType, lom.ObjName))
+		)
+		if names, err = readDirNames(dir); err != nil {
+			return
+		}
+		for _, name := range names {
+			objBase, _, ok := fs.SplitVersion(name)
+			if !ok || objBase != base {
+				continue
+			}
+			vlom, err := lom.VersionLOM(filepath.Join(dir, name), mpathInfo)
+			if err != nil {
+				glog.Errorf("%s: %v", lom, err)
+				continue
+			}
+			vloms = append(vloms, vlom)
+		}
+	}
+	sort.Slice(vloms, func(i, j int) bool { return versionLess(vloms[j].Version(), vloms[i].Version()) })
+	return
+}
+
+func readDirNames(dir string) ([]string, error) {
+	f, err := os.Open(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	names, err := f.Readdirnames(-1)
+	f.Close()
+	return names, err
+}
+
+func versionLess(a, b string) bool {
+	va, _ := strconv.ParseUint(a, 10, 64)
+	vb, _ := strconv.ParseUint(b, 10, 64)
+	return va < vb
+}
+
+// DelVersion removes a given noncurrent version of the object unless the
+// version is retained (see ObjLockErr).
+func (lom *LOM) DelVersion(ver string, bypassGovernance bool) error {
+	vlom, err := lom.LoadVersion(ver)
+	if err != nil {
+		return err
+	}
+	if err = vlom.ObjLockErr(bypassGovernance); err != nil {
+		return err
+	}
+	return vlom.Remove()
+}
+
+// RestoreLatestVersion makes the latest noncurrent version (if any) current -
+// to be called after the current version of the object has been deleted.
+func (lom *LOM) RestoreLatestVersion() (restored bool, err error) {
+	vloms, err := lom.ListVersions()
+	if err != nil || len(vloms) == 0 {
+		return
+	}
+	latest := vloms[0]
+	if latest.ParsedFQN.MpathInfo.Path == lom.ParsedFQN.MpathInfo.Path {
+		err = cmn.Rename(latest.FQN, lom.FQN) // carries the metadata over
+	} else {
+		buf, slab := lom.T.GetMMSA().Alloc(latest.Size())
+		workFQN := fs.CSM.GenContentParsedFQN(lom.ParsedFQN, fs.WorkfileType, fs.WorkfilePut)
+		if _, _, err = cmn.CopyFile(latest.FQN, workFQN, buf, cmn.ChecksumNone); err == nil {
+			if err = cmn.Rename(workFQN, lom.FQN); err == nil {
+				uname := lom.md.uname
+				lom.md = latest.md
+				lom.md.uname = uname
+				if err = lom.Persist(); err == nil {
+					err = cmn.RemoveFile(latest.FQN)
+				}
+			}
+		}
+		slab.Free(buf)
+	}
+	if err != nil {
+		return
+	}
+	lom.Uncache()
+	return true, nil
+}
+
+// latestVersion returns the latest noncurrent version or 0 if there are none.
+func (lom *LOM) latestVersion() (ver uint64) {
+	vloms, err := lom.ListVersions()
+	if err != nil || len(vloms) == 0 {
+		return
+	}
+	ver, _ = strconv.ParseUint(vloms[0].Version(), 10, 64)
+	return
+}
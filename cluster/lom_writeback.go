@@ -0,0 +1,50 @@
+// Package cluster provides common interfaces and local access to cluster-level metadata
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package cluster
+
+import (
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+//
+// Write-back (see cmn.BckWriteBackConf): an object that is yet to be written
+// to the cloud is marked "dirty" - the mark must survive until the object
+// gets written, and is cleared only if the object has not been overwritten
+// in the meantime.
+//
+
+func (lom *LOM) IsDirty() bool {
+	_, ok := lom.md.customMD[DirtyObjMD]
+	return ok
+}
+
+// DirtyGen returns the value that identifies the PUT that has stored the dirty object
+func (lom *LOM) DirtyGen() string { return lom.md.customMD[DirtyObjMD] }
+
+// SetDirty marks the object as not yet written to the cloud (the cloud
+// source and version, if any, are no longer valid - and the object is complete)
+func (lom *LOM) SetDirty(gen string) {
+	md := cmn.SimpleKVs{DirtyObjMD: gen}
+	for k, v := range lom.md.customMD {
+		if k != DirtyObjMD && k != SourceObjMD && k != VersionObjMD && k != PartialObjMD {
+			md[k] = v
+		}
+	}
+	lom.md.customMD = md
+}
+
+// SetFlushed clears the mark upon writing the object to the cloud
+func (lom *LOM) SetFlushed(provider, version string) {
+	md := cmn.SimpleKVs{SourceObjMD: provider}
+	if version != "" {
+		md[VersionObjMD] = version
+	}
+	for k, v := range lom.md.customMD {
+		if k != DirtyObjMD && k != SourceObjMD && k != VersionObjMD {
+			md[k] = v
+		}
+	}
+	lom.md.customMD = md
+}
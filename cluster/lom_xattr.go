@@ -68,11 +68,35 @@ const (
 	SourceAmazonObjMD = cmn.ProviderAmazon
 	SourceGoogleObjMD = cmn.ProviderGoogle
 	SourceAzureObjMD  = cmn.ProviderAzure
+	SourceHDFSObjMD   = cmn.ProviderHDFS
+	SourceHTTPObjMD   = cmn.ProviderHTTP
+	SourcePosixObjMD  = cmn.ProviderPosix
 	SourceWebObjMD    = "web"
 
 	VersionObjMD = "v"
 	CRC32CObjMD  = cmn.ChecksumCRC32C
 	MD5ObjMD     = cmn.ChecksumMD5
+
+	// user-defined metadata and tags are prefixed to avoid clashing with the keys above
+	UserObjMDPrefix = "user."
+	TagObjMDPrefix  = "tag."
+
+	LegalHoldObjMD   = "legal_hold"   // see cmn.ObjLockConf
+	RetainUntilObjMD = "retain_until" // ditto: end of retention, Unix seconds
+
+	// original size and checksum of the compressed object (see cmn.BckCompressionConf)
+	CompressedObjMDPrefix = "compressed."
+
+	EncryptedObjMD = "encrypted" // the content is encrypted (see cmn.BckSSEConf)
+
+	DedupObjMD = "dedup" // the object is stored as a dedup recipe (see cmn.BckDedupConf)
+
+	// not yet written to the cloud (see cmn.BckWriteBackConf); the value
+	// identifies the PUT that has stored the object
+	DirtyObjMD = "dirty"
+
+	// the object is cached partially (see cmn.BckPartialCacheConf and PartialMD)
+	PartialObjMD = "partial"
 )
 
 func (lom *LOM) LoadMetaFromFS() error { _, err := lom.lmfs(true); return err }
@@ -357,7 +381,7 @@ func _marshCustomMD(mm *memsys.MMSA, buf []byte, md cmn.SimpleKVs) []byte {
 		num = len(md)
 	)
 	for k, v := range md {
-		cmn.Assert(k != "" && v != "")
+		cmn.Assert(k != "") // NOTE: empty values are allowed (e.g., S3 tags)
 		i++
 		buf = mm.Append(buf, k)
 		buf = mm.Append(buf, customMDSepa)
@@ -44,6 +44,20 @@ type CloudProvider interface {
 	ListBuckets(ctx context.Context, query cmn.QueryBcks) (buckets cmn.BucketNames, err error, errCode int)
 }
 
+// CloudRangeReader is implemented by the cloud providers capable of reading
+// a byte range of an object (see cmn.BckPartialCacheConf)
+type CloudRangeReader interface {
+	GetObjRange(ctx context.Context, lom *LOM, offset, length int64) (r io.ReadCloser, attrs CloudObjAttrs, err error, errCode int)
+}
+
+// CloudObjAttrs - attributes of the entire cloud object returned along with its range;
+// ETag changes whenever the object gets overwritten.
+type CloudObjAttrs struct {
+	Size    int64
+	Version string
+	ETag    string
+}
+
 // a callback called by EC PUT jogger after the object is processed and
 // all its slices/replicas are sent to other targets
 type OnFinishObj = func(lom *LOM, err error)
@@ -89,10 +103,12 @@ type Target interface {
 	GetObject(w io.Writer, lom *LOM, started time.Time) error
 	PutObject(params PutObjectParams) error
 	EvictObject(lom *LOM) error
+	ObjectDeleted(lom *LOM) // post-delete (e.g., replication), see also EvictObject
+	ObjectWritten(lom *LOM) // post-write (e.g., replication)
 	CopyObject(lom *LOM, bckTo *Bck, buf []byte, localOnly bool) (bool, error)
 	GetCold(ctx context.Context, lom *LOM, prefetch bool) (error, int)
 	PromoteFile(srcFQN string, bck *Bck, objName string, cksum *cmn.Cksum,
-		overwrite, safe, verbose bool) (lom *LOM, err error)
+		overwrite, safe, verbose, bypassGovernance bool) (lom *LOM, err error)
 	LookupRemoteSingle(lom *LOM, si *Snode) bool
 	CheckCloudVersion(ctx context.Context, lom *LOM) (vchanged bool, err error, errCode int)
 
@@ -46,9 +46,11 @@ func (*TargetMock) GetSmallMMSA() *memsys.MMSA                                {
 func (*TargetMock) PutObject(_ PutObjectParams) error                         { return nil }
 func (*TargetMock) GetObject(_ io.Writer, _ *LOM, _ time.Time) error          { return nil }
 func (*TargetMock) EvictObject(_ *LOM) error                                  { return nil }
+func (*TargetMock) ObjectDeleted(_ *LOM)                                      {}
+func (*TargetMock) ObjectWritten(_ *LOM)                                      {}
 func (*TargetMock) GetCold(_ context.Context, _ *LOM, _ bool) (error, int)    { return nil, http.StatusOK }
 func (*TargetMock) CopyObject(_ *LOM, _ *Bck, _ []byte, _ bool) (bool, error) { return false, nil }
-func (*TargetMock) PromoteFile(_ string, _ *Bck, _ string, _ *cmn.Cksum, _, _, _ bool) (*LOM, error) {
+func (*TargetMock) PromoteFile(_ string, _ *Bck, _ string, _ *cmn.Cksum, _, _, _, _ bool) (*LOM, error) {
 	return nil, nil
 }
 func (*TargetMock) GetDB() dbdriver.Driver                             { return nil }
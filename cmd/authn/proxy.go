@@ -23,6 +23,20 @@ func (m *userManager) broadcastRevoked(token string) {
 	m.broadcast(http.MethodDelete, cmn.Tokens, body)
 }
 
+// send new access keys to all clusters; every key is signed, otherwise
+// clusters reject it
+func (m *userManager) broadcastAccessKeys(keys ...*cmn.AuthAccessKey) {
+	signed := make([]*cmn.AuthAccessKey, 0, len(keys))
+	for _, key := range keys {
+		k := *key
+		k.Signature = cmn.AccessKeySignature(conf.Auth.Secret, &k)
+		signed = append(signed, &k)
+	}
+	tokenList := ais.TokenList{AccessKeys: signed}
+	body := cmn.MustMarshal(tokenList)
+	m.broadcast(http.MethodPut, cmn.Tokens, body)
+}
+
 // broadcast the request to all clusters. If a cluster has a few URLS,
 // it sends to the first working one. Clusters are processed in parallel.
 func (m *userManager) broadcast(method, path string, body []byte) {
@@ -43,7 +57,7 @@ func (m *userManager) broadcast(method, path string, body []byte) {
 				}
 			}
 			if err != nil {
-				glog.Errorf("Failed to sync revoked tokens with %q: %v", clu.ID, err)
+				glog.Errorf("Failed to sync tokens with %q: %v", clu.ID, err)
 			}
 		}(clu)
 	}
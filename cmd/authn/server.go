@@ -20,6 +20,7 @@ const (
 	pathUsers    = "users"
 	pathTokens   = "tokens"
 	pathClusters = "clusters"
+	pathKeys     = "accesskeys"
 )
 
 // a message to generate token
@@ -109,6 +110,7 @@ func (a *authServ) registerPublicHandlers() {
 	a.registerHandler(cmn.URLPath(cmn.Version, pathTokens), a.tokenHandler)
 	a.registerHandler(cmn.URLPath(cmn.Version, pathClusters), a.clusterHandler)
 	a.registerHandler(cmn.URLPath(cmn.Version, pathRoles), a.roleHandler)
+	a.registerHandler(cmn.URLPath(cmn.Version, pathKeys), a.accessKeyHandler)
 }
 
 func (a *authServ) userHandler(w http.ResponseWriter, r *http.Request) {
@@ -520,3 +522,84 @@ func (a *authServ) httpRolePut(w http.ResponseWriter, r *http.Request) {
 		cmn.InvalidHandlerWithMsg(w, r, err.Error())
 	}
 }
+
+func (a *authServ) accessKeyHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		a.httpKeyPost(w, r)
+	case http.MethodDelete:
+		a.httpKeyDel(w, r)
+	case http.MethodGet:
+		a.httpKeyGet(w, r)
+	default:
+		cmn.InvalidHandlerWithMsg(w, r, "Unsupported method for /accesskeys handler")
+	}
+}
+
+// Generates a new S3 access key for a user with valid credentials:
+// POST <version>/<pathKeys>/<username>
+//		Body: <loginMsg>
+//	Returns: cmn.AuthAccessKey (the only time the secret is returned)
+func (a *authServ) httpKeyPost(w http.ResponseWriter, r *http.Request) {
+	apiItems, err := checkRESTItems(w, r, 1, cmn.Version, pathKeys)
+	if err != nil {
+		return
+	}
+	msg := &loginMsg{}
+	if err = cmn.ReadJSON(w, r, msg); err != nil {
+		glog.Errorf("Failed to read request body: %v\n", err)
+		return
+	}
+	if msg.Password == "" {
+		cmn.InvalidHandlerWithMsg(w, r, "Not authorized", http.StatusUnauthorized)
+		return
+	}
+	key, err := a.users.addAccessKey(apiItems[0], msg.Password)
+	if err != nil {
+		glog.Errorf("Failed to generate access key: %v\n", err)
+		cmn.InvalidHandlerWithMsg(w, r, "Not authorized", http.StatusUnauthorized)
+		return
+	}
+	a.writeJSON(w, &cmn.AuthAccessKey{ID: key.ID, Secret: key.Secret, UserID: key.UserID, Expires: key.Expires}, "accesskey")
+}
+
+func (a *authServ) httpKeyDel(w http.ResponseWriter, r *http.Request) {
+	apiItems, err := checkRESTItems(w, r, 1, cmn.Version, pathKeys)
+	if err != nil {
+		return
+	}
+	if err = a.checkAuthorization(w, r); err != nil {
+		glog.Errorf("Not authorized: %v", err)
+		return
+	}
+	if err := a.users.delAccessKey(apiItems[0]); err != nil {
+		errCode := http.StatusInternalServerError
+		if err == errKeyNotFound {
+			errCode = http.StatusNotFound
+		}
+		cmn.InvalidHandlerWithMsg(w, r, err.Error(), errCode)
+	}
+}
+
+// Returns the list of access keys: all or only the keys of a given user
+// GET <version>/<pathKeys>[/<username>]
+func (a *authServ) httpKeyGet(w http.ResponseWriter, r *http.Request) {
+	apiItems, err := checkRESTItems(w, r, 0, cmn.Version, pathKeys)
+	if err != nil {
+		return
+	}
+	if err = a.checkAuthorization(w, r); err != nil {
+		glog.Errorf("Not authorized: %v", err)
+		return
+	}
+	var userID string
+	if len(apiItems) != 0 {
+		userID = apiItems[0]
+	}
+	keys, err := a.users.accessKeyList(userID)
+	if err != nil {
+		cmn.InvalidHandlerWithMsg(w, r, err.Error())
+		return
+	}
+	a.writeJSON(w, keys, "accesskeys")
+}
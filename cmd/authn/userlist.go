@@ -6,6 +6,8 @@
 package main
 
 import (
+	"crypto/rand"
+	"encoding/base64"
 	"encoding/hex"
 	"errors"
 	"fmt"
@@ -27,6 +29,7 @@ const (
 	tokensCollection   = "token"
 	revokedCollection  = "revoked"
 	clustersCollection = "cluster"
+	keysCollection     = "accesskey"
 
 	adminID   = "admin"
 	adminPass = "admin"
@@ -48,6 +51,7 @@ var (
 	errInvalidCredentials = errors.New("invalid credentials")
 	errTokenNotFound      = errors.New("token not found")
 	errTokenExpired       = errors.New("token expired")
+	errKeyNotFound        = errors.New("access key not found")
 
 	predefinedRoles = []struct {
 		prefix string
@@ -74,6 +78,31 @@ func isSamePassword(password, hashed string) bool {
 	return bcrypt.CompareHashAndPassword(b, []byte(password)) == nil
 }
 
+// Generates an access key ID that looks like AWS one: 20 characters
+// of uppercase letters and digits
+func genAccessKeyID() string {
+	const (
+		alphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+		keyLen   = 20
+	)
+	b := make([]byte, keyLen)
+	_, err := rand.Read(b)
+	cmn.AssertNoErr(err)
+	for i := range b {
+		b[i] = alphabet[int(b[i])%len(alphabet)]
+	}
+	return string(b)
+}
+
+// Generates a 40-character secret access key
+func genSecret() (string, error) {
+	b := make([]byte, 30)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(b), nil
+}
+
 // If the DB is empty, the function prefills some data
 func initializeDB(driver dbdriver.Driver) error {
 	users, err := driver.List(usersCollection, "")
@@ -200,6 +229,10 @@ func (m *userManager) addCluster(info *cmn.AuthCluster) error {
 		return err
 	}
 	m.createRolesForCluster(info)
+	// a new cluster must know all existing access keys
+	if keys, err := m.activeAccessKeys(); err == nil && len(keys) != 0 {
+		go m.broadcastAccessKeys(keys...)
+	}
 	return nil
 }
 
@@ -220,6 +253,12 @@ func (m *userManager) delUser(userID string) error {
 		_ = m.db.Delete(tokensCollection, userID)
 		err = m.db.SetString(revokedCollection, token.Token, userID)
 	}
+	for _, keyToken := range m.delUserAccessKeys(userID) {
+		go m.broadcastRevoked(keyToken)
+		if errKey := m.db.SetString(revokedCollection, keyToken, userID); errKey != nil && err == nil {
+			err = errKey
+		}
+	}
 
 	return err
 }
@@ -366,21 +405,37 @@ func (m *userManager) issueToken(userID, pwd string, ttl ...time.Duration) (stri
 	}
 	expires = issued.Add(expDelta)
 
-	// put all useful info into token: who owns the token, when it was issued,
-	// when it expires and credentials to log in AWS, GCP etc.
-	// If a user is a super user, it is enough to pass only isAdmin marker
+	tokenString, err := m.genToken(uInfo, expires)
+	if err != nil {
+		return "", err
+	}
+
+	// TODO: multiple tokens per user
+	tInfo = &cmn.AuthToken{
+		UserID:  userID,
+		Expires: expires,
+		Token:   tokenString,
+	}
+	err = m.db.Set(tokensCollection, userID, tInfo)
+	return tokenString, err
+}
+
+// Put all useful info into token: who owns the token, when it was issued,
+// when it expires and credentials to log in AWS, GCP etc.
+// If a user is a super user, it is enough to pass only isAdmin marker
+func (m *userManager) genToken(uInfo *cmn.AuthUser, expires time.Time) (string, error) {
 	var t *jwt.Token
 	if uInfo.IsAdmin() {
 		t = jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
 			"expires":  expires,
-			"username": userID,
+			"username": uInfo.ID,
 			"admin":    true,
 		})
 	} else {
 		m.fixClusterIDs(uInfo.Clusters)
 		t = jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
 			"expires":  expires,
-			"username": userID,
+			"username": uInfo.ID,
 			"buckets":  uInfo.Buckets,
 			"clusters": uInfo.Clusters,
 		})
@@ -389,15 +444,105 @@ func (m *userManager) issueToken(userID, pwd string, ttl ...time.Duration) (stri
 	if err != nil {
 		return "", fmt.Errorf("failed to generate token: %v", err)
 	}
+	return tokenString, nil
+}
 
-	// TODO: multiple tokens per user
-	tInfo = &cmn.AuthToken{
+// Generates a new S3 access key for a user if user credentials are valid.
+// Every key gets its own token with the current user permissions, so a key
+// can be revoked without touching other keys and tokens of the user.
+// The new key is sent to all registered clusters.
+func (m *userManager) addAccessKey(userID, pwd string, ttl ...time.Duration) (*cmn.AuthAccessKey, error) {
+	uInfo := &cmn.AuthUser{}
+	if err := m.db.Get(usersCollection, userID, uInfo); err != nil {
+		glog.Error(err)
+		return nil, errInvalidCredentials
+	}
+	if !isSamePassword(pwd, uInfo.Password) {
+		return nil, errInvalidCredentials
+	}
+
+	expDelta := foreverTokenTime
+	if len(ttl) != 0 && ttl[0] != 0 {
+		expDelta = ttl[0]
+	}
+	expires := time.Now().Add(expDelta)
+	token, err := m.genToken(uInfo, expires)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := genSecret()
+	if err != nil {
+		return nil, err
+	}
+	key := &cmn.AuthAccessKey{
+		ID:      genAccessKeyID(),
+		Secret:  secret,
 		UserID:  userID,
+		Token:   token,
 		Expires: expires,
-		Token:   tokenString,
 	}
-	err = m.db.Set(tokensCollection, userID, tInfo)
-	return tokenString, err
+	if err := m.db.Set(keysCollection, key.ID, key); err != nil {
+		return nil, err
+	}
+	go m.broadcastAccessKeys(key)
+	return key, nil
+}
+
+// Deletes an access key and revokes its token
+func (m *userManager) delAccessKey(keyID string) error {
+	key := &cmn.AuthAccessKey{}
+	if err := m.db.Get(keysCollection, keyID, key); err != nil {
+		if dbdriver.IsErrNotFound(err) {
+			return errKeyNotFound
+		}
+		return err
+	}
+	if err := m.db.Delete(keysCollection, keyID); err != nil {
+		return err
+	}
+	return m.revokeToken(key.Token)
+}
+
+// Returns all access keys of a user, or all keys if userID is empty.
+// Neither secrets nor tokens are returned.
+func (m *userManager) accessKeyList(userID string) ([]*cmn.AuthAccessKey, error) {
+	recs, err := m.db.GetAll(keysCollection, "")
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]*cmn.AuthAccessKey, 0, len(recs))
+	for _, str := range recs {
+		key := &cmn.AuthAccessKey{}
+		err := jsoniter.Unmarshal([]byte(str), key)
+		cmn.AssertNoErr(err)
+		if userID != "" && key.UserID != userID {
+			continue
+		}
+		key.Secret, key.Token = "", ""
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// Returns all valid access keys to push to clusters
+func (m *userManager) activeAccessKeys() ([]*cmn.AuthAccessKey, error) {
+	recs, err := m.db.GetAll(keysCollection, "")
+	if err != nil {
+		return nil, err
+	}
+	var (
+		now  = time.Now()
+		keys = make([]*cmn.AuthAccessKey, 0, len(recs))
+	)
+	for _, str := range recs {
+		key := &cmn.AuthAccessKey{}
+		err := jsoniter.Unmarshal([]byte(str), key)
+		cmn.AssertNoErr(err)
+		if key.Expires.After(now) {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
 }
 
 // Delete existing token, a.k.a log out
@@ -422,6 +567,28 @@ func (m *userManager) revokeToken(token string) error {
 	return nil
 }
 
+// Removes all access keys of a user (the tokens are revoked by the caller)
+func (m *userManager) delUserAccessKeys(userID string) (tokens []string) {
+	recs, err := m.db.GetAll(keysCollection, "")
+	if err != nil {
+		glog.Errorf("Failed to read access keys: %v", err)
+		return nil
+	}
+	for keyID, str := range recs {
+		key := &cmn.AuthAccessKey{}
+		err := jsoniter.Unmarshal([]byte(str), key)
+		cmn.AssertNoErr(err)
+		if key.UserID != userID {
+			continue
+		}
+		tokens = append(tokens, key.Token)
+		if err := m.db.Delete(keysCollection, keyID); err != nil {
+			glog.Errorf("Failed to delete access key %s: %v", keyID, err)
+		}
+	}
+	return tokens
+}
+
 func (m *userManager) userByToken(token string) (*cmn.AuthUser, error) {
 	recs, err := m.db.GetAll(tokensCollection, "")
 	if err != nil {
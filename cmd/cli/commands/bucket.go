@@ -187,6 +187,7 @@ func listBucketObj(c *cli.Context, bck cmn.Bck) error {
 		Prefix:      prefix,
 		Cached:      flagIsSet(c, cachedFlag),
 		Passthrough: flagIsSet(c, passthroughFlag),
+		Delimiter:   parseStrFlag(c, delimiterFlag),
 	}
 
 	if flagIsSet(c, fastFlag) {
@@ -419,11 +420,22 @@ func printBckHeadTable(c *cli.Context, props *cmn.BucketProps, section string) e
 		propList = []prop{
 			{"created", time.Unix(0, props.Created).Format(time.RFC3339)},
 			{"provider", props.Provider},
+			{"backend_chain", props.BackendChain.String()},
 			{"access", props.Access.Describe()},
 			{"checksum", props.Cksum.String()},
 			{"mirror", props.Mirror.String()},
 			{"ec", props.EC.String()},
 			{"lru", props.LRU.String()},
+			{"lifecycle", props.Lifecycle.String()},
+			{"object_lock", props.ObjLock.String()},
+			{"quota", props.Quota.String()},
+			{"rate_limit", props.RateLimit.String()},
+			{"sse", props.SSE.String()},
+			{"compression", props.Compression.String()},
+			{"dedup", props.Dedup.String()},
+			{"replication", props.Replication.String()},
+			{"write_back", props.WriteBack.String()},
+			{"partial_cache", props.PartialCache.String()},
 			{"versioning", props.Versioning.String()},
 		}
 	}
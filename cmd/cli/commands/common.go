@@ -29,6 +29,7 @@ const (
 	commandJoin      = "join"
 	commandList      = "ls"
 	commandPrefetch  = cmn.ActPrefetch
+	commandPresign   = "presign"
 	commandPromote   = "promote"
 	commandPut       = "put"
 	commandRemove    = "rm"
@@ -38,6 +39,7 @@ const (
 	commandShow      = "show"
 	commandStart     = cmn.ActXactStart
 	commandStop      = cmn.ActXactStop
+	commandSync      = cmn.ActSyncBck
 	commandWait      = "wait"
 	commandSearch    = "search"
 	commandTransform = "transform"
@@ -64,17 +66,18 @@ const (
 	subcmdStop      = "stop"
 
 	// Show subcommands
-	subcmdShowBucket    = subcmdBucket
-	subcmdShowDisk      = subcmdDisk
-	subcmdShowDownload  = subcmdDownload
-	subcmdShowDsort     = subcmdDsort
-	subcmdShowObject    = subcmdObject
-	subcmdShowXaction   = subcmdXaction
-	subcmdShowRebalance = subcmdRebalance
-	subcmdShowBckProps  = subcmdProps
-	subcmdShowConfig    = subcmdConfig
-	subcmdShowRemoteAIS = subcmdRemoteAIS
-	subcmdShowCluster   = subcmdCluster
+	subcmdShowBucket      = subcmdBucket
+	subcmdShowDisk        = subcmdDisk
+	subcmdShowDownload    = subcmdDownload
+	subcmdShowDsort       = subcmdDsort
+	subcmdShowObject      = subcmdObject
+	subcmdShowXaction     = subcmdXaction
+	subcmdShowRebalance   = subcmdRebalance
+	subcmdShowBckProps    = subcmdProps
+	subcmdShowConfig      = subcmdConfig
+	subcmdShowRemoteAIS   = subcmdRemoteAIS
+	subcmdShowCluster     = subcmdCluster
+	subcmdShowReplication = cmn.GetWhatReplication
 
 	// Create subcommands
 	subcmdCreateBucket = subcmdBucket
@@ -202,7 +205,7 @@ const (
 	deleteRoleArgument        = "ROLE"
 
 	// Search
-	searchArgument = "KEYWORD [KEYWORD...]"
+	searchArgument = "KEYWORD [KEYWORD...] | BUCKET_NAME"
 )
 
 // Flags
@@ -236,6 +239,8 @@ var (
 	activeFlag        = cli.BoolFlag{Name: "active", Usage: "show only running xactions"}
 	dataSlicesFlag    = cli.IntFlag{Name: "data-slices,data,d", Usage: "number of data slices", Required: true}
 	paritySlicesFlag  = cli.IntFlag{Name: "parity-slices,parity,p", Usage: "number of parity slices", Required: true}
+	evictChangedFlag  = cli.BoolFlag{Name: "evict-changed", Usage: "evict (rather than re-fetch) objects that have changed in the cloud"}
+	fetchNewFlag      = cli.BoolFlag{Name: "fetch-new", Usage: "fetch objects that are not cached"}
 
 	// Daeclu
 	countFlag = cli.IntFlag{Name: "count", Usage: "total number of generated reports", Value: countDefault}
@@ -273,7 +278,15 @@ var (
 	chunkSizeFlag    = cli.StringFlag{Name: "chunk-size", Usage: "chunk size used for each request, can contain prefix 'b', 'KiB', 'MB'", Value: "10MB"}
 	computeCksumFlag = cli.BoolFlag{Name: "compute-cksum", Usage: "compute the checksum with the type configured for the bucket"}
 	passthroughFlag  = cli.BoolFlag{Name: "passthrough", Usage: "read the bucket object list bypassing a proxy cache"}
+	delimiterFlag    = cli.StringFlag{Name: "delimiter", Usage: "roll up object names that contain the delimiter after the prefix into directories, eg. '/'"}
+	methodFlag       = cli.StringFlag{Name: "method", Usage: "HTTP method the presigned URL is valid for: GET or PUT", Value: "GET"}
+	expireFlag       = cli.DurationFlag{Name: "expire", Usage: "lifetime of the presigned URL, eg. '30m'", Value: cmn.PresignDefaultExpires}
 	checksumFlags    = getCksumFlags()
+	// Search
+	mdFlag         = cli.StringFlag{Name: "md", Usage: "comma separated custom metadata KEY=VALUE pairs to match, eg. 'user.split=train'"}
+	mdExistsFlag   = cli.StringFlag{Name: "md-exists", Usage: "comma separated custom metadata keys that must exist"}
+	mdPrefixFlag   = cli.StringFlag{Name: "md-prefix", Usage: "comma separated custom metadata KEY=PREFIX pairs to match"}
+	cksumValueFlag = cli.StringFlag{Name: "cksum", Usage: "object checksum value to match"}
 	// AuthN
 	tokenFileFlag = cli.StringFlag{Name: "file,f", Value: "", Usage: "save token to file"}
 	passwordFlag  = cli.StringFlag{Name: "password,p", Value: "", Usage: "user password"}
@@ -38,6 +38,11 @@ var (
 			baseLstRngFlags,
 			dryRunFlag,
 		),
+		commandSync: {
+			prefixFlag,
+			evictChangedFlag,
+			fetchNewFlag,
+		},
 	}
 
 	stopCmdsFlags = map[string][]cli.Flag{
@@ -59,6 +64,14 @@ var (
 					Action:       prefetchHandler,
 					BashComplete: bucketCompletions(bckCompletionsOpts{multiple: true, provider: cmn.AnyCloud}),
 				},
+				{
+					Name:         commandSync,
+					Usage:        "synchronize cached objects of a cloud bucket with the cloud",
+					ArgsUsage:    bucketArgument,
+					Flags:        startCmdsFlags[commandSync],
+					Action:       syncHandler,
+					BashComplete: bucketCompletions(bckCompletionsOpts{provider: cmn.AnyCloud}),
+				},
 				{
 					Name:      subcmdStartDownload,
 					Usage:     "start a download job (downloads objects from external source)",
@@ -118,7 +131,7 @@ func xactionCmds() cli.Commands {
 
 	splCmdKinds := make(cmn.StringSet)
 	// Add any xaction which requires a separate handler here.
-	splCmdKinds.Add(cmn.ActPrefetch, cmn.ActECEncode, cmn.ActMakeNCopies)
+	splCmdKinds.Add(cmn.ActPrefetch, cmn.ActECEncode, cmn.ActMakeNCopies, cmn.ActSyncBck)
 
 	startable := listXactions(true)
 	for _, xaction := range startable {
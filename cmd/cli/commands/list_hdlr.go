@@ -29,6 +29,7 @@ var (
 		markerFlag,
 		cachedFlag,
 		passthroughFlag,
+		delimiterFlag,
 	}
 
 	listCmds = []cli.Command{
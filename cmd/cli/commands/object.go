@@ -121,6 +121,16 @@ func promoteFileOrDir(c *cli.Context, bck cmn.Bck, objName, fqn string) (err err
 	return
 }
 
+func presignObject(c *cli.Context, bck cmn.Bck, objName string) (err error) {
+	method := strings.ToUpper(parseStrFlag(c, methodFlag))
+	signedURL, err := api.PresignObject(defaultAPIParams, bck, objName, method, parseDurationFlag(c, expireFlag))
+	if err != nil {
+		return
+	}
+	fmt.Fprintln(c.App.Writer, signedURL)
+	return
+}
+
 // PUT methods
 
 func putSingleObject(c *cli.Context, bck cmn.Bck, objName, path string) (err error) {
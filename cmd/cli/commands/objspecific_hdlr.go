@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/NVIDIA/aistore/api"
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/urfave/cli"
 )
@@ -52,6 +53,10 @@ var (
 			lengthFlag,
 			checksumFlag,
 		},
+		commandPresign: {
+			methodFlag,
+			expireFlag,
+		},
 	}
 
 	objectSpecificCmds = []cli.Command{
@@ -102,6 +107,14 @@ var (
 			Action:       catHandler,
 			BashComplete: bucketCompletions(bckCompletionsOpts{separator: true}),
 		},
+		{
+			Name:         commandPresign,
+			Usage:        "generate a URL that allows to get or put the object without authentication until it expires",
+			ArgsUsage:    objectArgument,
+			Flags:        objectSpecificCmdsFlags[commandPresign],
+			Action:       presignHandler,
+			BashComplete: bucketCompletions(bckCompletionsOpts{separator: true}),
+		},
 	}
 )
 
@@ -141,6 +154,39 @@ func prefetchHandler(c *cli.Context) (err error) {
 	return missingArgumentsError(c, "object list or range")
 }
 
+func syncHandler(c *cli.Context) (err error) {
+	var (
+		bck        cmn.Bck
+		objectName string
+		xactID     string
+	)
+	if c.NArg() == 0 {
+		return missingArgumentsError(c, bucketArgument)
+	}
+	if bck, objectName, err = parseBckObjectURI(c.Args().First()); err != nil {
+		return
+	}
+	if bck.IsAIS() {
+		return fmt.Errorf("sync command doesn't support local buckets")
+	}
+	if objectName != "" {
+		return objectNameArgumentNotSupported(c, objectName)
+	}
+	if bck, _, err = validateBucket(c, bck, "", false); err != nil {
+		return
+	}
+	msg := &cmn.SyncBckMsg{
+		Prefix:       parseStrFlag(c, prefixFlag),
+		EvictChanged: flagIsSet(c, evictChangedFlag),
+		FetchNew:     flagIsSet(c, fetchNewFlag),
+	}
+	if xactID, err = api.SyncBucket(defaultAPIParams, bck, msg); err != nil {
+		return
+	}
+	fmt.Fprintf(c.App.Writer, "Synchronizing %s with the cloud, xaction ID: %q\n", bck, xactID)
+	return
+}
+
 func evictHandler(c *cli.Context) error {
 	printDryRunHeader(c)
 
@@ -320,3 +366,28 @@ func catHandler(c *cli.Context) (err error) {
 	}
 	return getObject(c, bck, objName, fileStdIO)
 }
+
+func presignHandler(c *cli.Context) (err error) {
+	var (
+		bck         cmn.Bck
+		objName     string
+		fullObjName = c.Args().Get(0) // empty string if arg not given
+	)
+	if c.NArg() < 1 {
+		return missingArgumentsError(c, "object name in the form bucket/object")
+	}
+	if c.NArg() > 1 {
+		return incorrectUsageError(c, fmt.Errorf("too many arguments"))
+	}
+	bck, objName, err = parseBckObjectURI(fullObjName)
+	if err != nil {
+		return
+	}
+	if bck, _, err = validateBucket(c, bck, fullObjName, false); err != nil {
+		return
+	}
+	if objName == "" {
+		return incorrectUsageMsg(c, "%q: missing object name", fullObjName)
+	}
+	return presignObject(c, bck, objName)
+}
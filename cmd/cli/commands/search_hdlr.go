@@ -11,16 +11,26 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/NVIDIA/aistore/api"
 	"github.com/NVIDIA/aistore/cmd/cli/templates"
 	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/query"
 	"github.com/urfave/cli"
 )
 
 var (
 	searchCmdFlags = []cli.Flag{
 		regexFlag,
+		templateFlag,
+		mdFlag,
+		mdExistsFlag,
+		mdPrefixFlag,
+		cksumValueFlag,
+		noHeaderFlag,
 	}
 
+	objSearchFlags = []cli.Flag{templateFlag, mdFlag, mdExistsFlag, mdPrefixFlag, cksumValueFlag}
+
 	searchCommands []cli.Command
 
 	similarWords = map[string][]string{
@@ -45,7 +55,7 @@ func initSearch(app *cli.App) {
 	searchCommands = []cli.Command{
 		{
 			Name:         commandSearch,
-			Usage:        "search ais commands",
+			Usage:        "search ais commands or, given a bucket, objects matching the filters",
 			ArgsUsage:    searchArgument,
 			Action:       searchCmdHdlr,
 			Flags:        searchCmdFlags,
@@ -104,6 +114,9 @@ func findCmdMatching(pattern string) []string {
 }
 
 func searchCmdHdlr(c *cli.Context) error {
+	if isObjSearch(c) {
+		return searchObjHdlr(c)
+	}
 	if !flagIsSet(c, regexFlag) && c.NArg() == 0 {
 		return missingArgumentsError(c, "keyword")
 	}
@@ -119,6 +132,81 @@ func searchCmdHdlr(c *cli.Context) error {
 	return templates.DisplayOutput(commands, c.App.Writer, templates.SearchTmpl)
 }
 
+// searching objects when a bucket is given or any of the object filters is set
+func isObjSearch(c *cli.Context) bool {
+	if strings.Contains(c.Args().First(), cmn.BckProviderSeparator) {
+		return true
+	}
+	for _, flag := range objSearchFlags {
+		if flagIsSet(c, flag) {
+			return true
+		}
+	}
+	return false
+}
+
+func searchObjHdlr(c *cli.Context) error {
+	if c.NArg() == 0 {
+		return missingArgumentsError(c, "bucket name")
+	}
+	bck, err := parseBck(c, c.Args().First())
+	if err != nil {
+		return err
+	}
+	if *bck, _, err = validateBucket(c, *bck, "", false); err != nil {
+		return err
+	}
+	filter, err := buildObjFilter(c)
+	if err != nil {
+		return err
+	}
+	entries, err := api.QueryObjects(defaultAPIParams, *bck, parseStrFlag(c, templateFlag), filter)
+	if err != nil {
+		return err
+	}
+	tmpl := buildOutputTemplate("name,size", !flagIsSet(c, noHeaderFlag))
+	return templates.DisplayOutput(entries, c.App.Writer, tmpl)
+}
+
+func buildObjFilter(c *cli.Context) (*query.FilterMsg, error) {
+	var filters []*query.FilterMsg
+	if flagIsSet(c, regexFlag) {
+		filters = append(filters, query.NameRegexFilterMsg(parseStrFlag(c, regexFlag)))
+	}
+	if flagIsSet(c, cksumValueFlag) {
+		filters = append(filters, query.CksumFilterMsg(parseStrFlag(c, cksumValueFlag)))
+	}
+	if flagIsSet(c, mdExistsFlag) {
+		for _, key := range makeList(parseStrFlag(c, mdExistsFlag), ",") {
+			filters = append(filters, query.CustomMDExistsFilterMsg(key))
+		}
+	}
+	for _, flag := range []cli.Flag{mdFlag, mdPrefixFlag} {
+		if !flagIsSet(c, flag) {
+			continue
+		}
+		for _, pair := range makeList(parseStrFlag(c, flag), ",") {
+			kv := strings.SplitN(pair, "=", 2)
+			if len(kv) != 2 || kv[0] == "" {
+				return nil, fmt.Errorf("invalid %s %q: expecting KEY=VALUE", flag.GetName(), pair)
+			}
+			if flag.GetName() == mdFlag.Name {
+				filters = append(filters, query.CustomMDFilterMsg(kv[0], kv[1]))
+			} else {
+				filters = append(filters, query.CustomMDPrefixFilterMsg(kv[0], kv[1]))
+			}
+		}
+	}
+	switch len(filters) {
+	case 0:
+		return nil, nil
+	case 1:
+		return filters[0], nil
+	default:
+		return query.NewAndFilter(filters...), nil
+	}
+}
+
 func searchBashCmplt(c *cli.Context) {
 	for key := range keywordMap {
 		fmt.Println(key)
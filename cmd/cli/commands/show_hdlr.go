@@ -10,6 +10,7 @@ import (
 	"sort"
 	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/NVIDIA/aistore/api"
 	"github.com/NVIDIA/aistore/cluster"
@@ -86,6 +87,9 @@ var (
 		subcmdShowRemoteAIS: {
 			noHeaderFlag,
 		},
+		subcmdShowReplication: {
+			noHeaderFlag,
+		},
 	}
 
 	showCmds = []cli.Command{
@@ -203,6 +207,13 @@ var (
 					Action:       showRemoteAISHandler,
 					BashComplete: daemonCompletions(completeTargets),
 				},
+				{
+					Name:      subcmdShowReplication,
+					Usage:     "show the state of replication to remote AIS clusters",
+					ArgsUsage: "",
+					Flags:     showCmdsFlags[subcmdShowReplication],
+					Action:    showReplicationHandler,
+				},
 			},
 		},
 	}
@@ -423,3 +434,32 @@ func showRemoteAISHandler(c *cli.Context) (err error) {
 	tw.Flush()
 	return
 }
+
+func showReplicationHandler(c *cli.Context) (err error) {
+	replStats, err := api.GetReplicationStats(defaultAPIParams)
+	if err != nil {
+		return err
+	}
+	tids := make([]string, 0, len(replStats))
+	for tid := range replStats {
+		tids = append(tids, tid)
+	}
+	sort.Strings(tids)
+	tw := &tabwriter.Writer{}
+	tw.Init(c.App.Writer, 0, 8, 2, ' ', 0)
+	if !flagIsSet(c, noHeaderFlag) {
+		fmt.Fprintln(tw, "TARGET\tPENDING\tLAG\tPUT\tSIZE\tDELETED\tERRORS\tLAST ERROR")
+	}
+	for _, tid := range tids {
+		st := replStats[tid]
+		lastErr := st.LastErr
+		if lastErr == "" {
+			lastErr = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%v\t%d\t%s\t%d\t%d\t%s\n", tid, st.Pending,
+			time.Duration(st.Lag).Round(time.Second), st.PutCount, cmn.B2S(st.PutSize, 2),
+			st.DelCount, st.ErrCount, lastErr)
+	}
+	tw.Flush()
+	return
+}
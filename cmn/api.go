@@ -5,10 +5,13 @@
 package cmn
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
+	"time"
 
 	jsoniter "github.com/json-iterator/go"
 )
@@ -28,6 +31,13 @@ type ActValPromote struct {
 	Verbose   bool   `json:"verbose"`
 }
 
+// ActValPresign is the value of ActPresignObject action message:
+// HTTP method (GET or PUT) the URL is signed for and its lifetime
+type ActValPresign struct {
+	Method  string       `json:"method"`
+	Expires DurationJSON `json:"expires"`
+}
+
 // SelectMsg represents properties and options for requests which fetch entities
 // Note: if Fast is `true` then paging is disabled - all items are returned
 //       in one response. The result list is unsorted and contains only object
@@ -43,6 +53,7 @@ type SelectMsg struct {
 	Fast        bool   `json:"fast"`        // performs a fast traversal of the bucket contents (returns only names)
 	Cached      bool   `json:"cached"`      // for cloud buckets - list only cached objects
 	Passthrough bool   `json:"passthrough"` // do not use cache - always request targets for fresh data
+	Delimiter   string `json:"delimiter"`   // roll up names that contain delimiter after prefix into pseudo-directories
 }
 
 type PageMarker string
@@ -57,6 +68,15 @@ type RangeMsg struct {
 	Template string `json:"template"`
 }
 
+// SyncBckMsg contains parameters to synchronize cached objects with the cloud:
+// objects deleted in the cloud are evicted, objects changed in the cloud are
+// re-fetched (or evicted), and, optionally, new objects get fetched
+type SyncBckMsg struct {
+	Prefix       string `json:"prefix"`        // synchronize only objects which name starts with prefix
+	EvictChanged bool   `json:"evict_changed"` // evict (rather than re-fetch) changed objects
+	FetchNew     bool   `json:"fetch_new"`     // fetch objects that are not cached
+}
+
 type InitTaskRespMsg struct {
 	UUID   string `json:"uuid"`
 	Handle string `json:"handle"`
@@ -151,6 +171,7 @@ func PageMarkerIncludesObject(pageMarker, objName string) bool {
 // 0-2: objects status, all statuses are mutually exclusive, so it can hold up
 //      to 8 different statuses. Now only OK=0, Moved=1, Deleted=2 are supported
 // 3:   CheckExists (for cloud bucket it shows if the object in local cache)
+// 4:   IsDir (the entry is a pseudo-directory when listing with delimiter)
 type BucketEntry struct {
 	Name      string `json:"name"`                  // name of the object - note: does not include the bucket name
 	Size      int64  `json:"size,string,omitempty"` // size in bytes
@@ -169,6 +190,10 @@ func (be *BucketEntry) SetExists() {
 	be.Flags |= EntryIsCached
 }
 
+func (be *BucketEntry) IsDir() bool {
+	return be.Flags&EntryIsDir != 0
+}
+
 func (be *BucketEntry) IsStatusOK() bool {
 	return be.Flags&EntryStatusMask == 0
 }
@@ -180,6 +205,16 @@ type BucketList struct {
 	Handle     string         `json:"handle"`
 }
 
+// ObjVersionEntry describes a single (current or noncurrent) version of an object
+type ObjVersionEntry struct {
+	Name     string `json:"name"`
+	Version  string `json:"version"`
+	Size     int64  `json:"size,string"`
+	Checksum string `json:"checksum,omitempty"`
+	Mtime    int64  `json:"mtime,string"` // noncurrent versions: the time the version was superseded
+	Current  bool   `json:"current"`
+}
+
 type BucketSummary struct {
 	Bck
 	ObjCount       uint64  `json:"count,string"`
@@ -247,6 +282,9 @@ type BucketProps struct {
 	// BackendBck if set it contains cloud bucket to which AIS bucket points to.
 	BackendBck Bck `json:"backend_bck,omitempty"`
 
+	// BackendChain is an ordered list of backend buckets to fall through on miss
+	BackendChain BackendChainConf `json:"backend_chain"`
+
 	// Versioning can be enabled or disabled on a per-bucket basis
 	Versioning VersionConf `json:"versioning"`
 
@@ -256,6 +294,36 @@ type BucketProps struct {
 	// LRU is the embedded struct of the same name
 	LRU LRUConf `json:"lru"`
 
+	// Lifecycle rules (expiration and eviction) enforced periodically by targets
+	Lifecycle LifecycleConf `json:"lifecycle"`
+
+	// Object lock (WORM): retention of newly written objects
+	ObjLock ObjLockConf `json:"object_lock"`
+
+	// Quota limits the total size and number of objects in the bucket
+	Quota QuotaConf `json:"quota"`
+
+	// RateLimit overrides the cluster-wide per-bucket request rate limit
+	RateLimit BckRateLimitConf `json:"rate_limit"`
+
+	// SSE enables server-side encryption of object content at rest
+	SSE BckSSEConf `json:"sse"`
+
+	// Compression enables compression of object content at rest
+	Compression BckCompressionConf `json:"compression"`
+
+	// Dedup enables deduplication of object content at rest
+	Dedup BckDedupConf `json:"dedup"`
+
+	// Replication to a bucket of a remote AIS cluster
+	Replication BckReplicationConf `json:"replication"`
+
+	// WriteBack enables asynchronous writing of PUT objects to the cloud
+	WriteBack BckWriteBackConf `json:"write_back"`
+
+	// PartialCache enables chunk-granular caching of large cloud objects
+	PartialCache BckPartialCacheConf `json:"partial_cache"`
+
 	// Mirror defines local-mirroring policy for the bucket
 	Mirror MirrorConf `json:"mirror"`
 
@@ -276,13 +344,24 @@ type BucketProps struct {
 }
 
 type BucketPropsToUpdate struct {
-	BackendBck *BckToUpdate         `json:"backend_bck"`
-	Versioning *VersionConfToUpdate `json:"versioning"`
-	Cksum      *CksumConfToUpdate   `json:"checksum"`
-	LRU        *LRUConfToUpdate     `json:"lru"`
-	Mirror     *MirrorConfToUpdate  `json:"mirror"`
-	EC         *ECConfToUpdate      `json:"ec"`
-	Access     *AccessAttrs         `json:"access,string"`
+	BackendBck   *BckToUpdate                 `json:"backend_bck"`
+	BackendChain *BackendChainConfToUpdate    `json:"backend_chain"`
+	Versioning   *VersionConfToUpdate         `json:"versioning"`
+	Cksum        *CksumConfToUpdate           `json:"checksum"`
+	LRU          *LRUConfToUpdate             `json:"lru"`
+	Lifecycle    *LifecycleConfToUpdate       `json:"lifecycle"`
+	ObjLock      *ObjLockConfToUpdate         `json:"object_lock"`
+	Quota        *QuotaConfToUpdate           `json:"quota"`
+	RateLimit    *BckRateLimitConfToUpdate    `json:"rate_limit"`
+	SSE          *BckSSEConfToUpdate          `json:"sse"`
+	Compression  *BckCompressionConfToUpdate  `json:"compression"`
+	Dedup        *BckDedupConfToUpdate        `json:"dedup"`
+	Replication  *BckReplicationConfToUpdate  `json:"replication"`
+	WriteBack    *BckWriteBackConfToUpdate    `json:"write_back"`
+	PartialCache *BckPartialCacheConfToUpdate `json:"partial_cache"`
+	Mirror       *MirrorConfToUpdate          `json:"mirror"`
+	EC           *ECConfToUpdate              `json:"ec"`
+	Access       *AccessAttrs                 `json:"access,string"`
 }
 
 type BckToUpdate struct {
@@ -290,6 +369,21 @@ type BckToUpdate struct {
 	Provider *string `json:"provider"`
 }
 
+// BackendChainConf - ordered list of backends (cloud or remote AIS buckets)
+// of an ais bucket. Cold GET (and HEAD) tries the backends in order: a miss or
+// an error falls through to the next one. The first backend is also the
+// bucket's BackendBck; WritePolicy determines which backends receive PUTs
+// and deletes (see WritePolicyPrimary, etc. enum).
+type BackendChainConf struct {
+	Bcks        []Bck  `json:"bcks"`
+	WritePolicy string `json:"write_policy"`
+}
+
+type BackendChainConfToUpdate struct {
+	Bcks        *[]Bck  `json:"bcks"`
+	WritePolicy *string `json:"write_policy"`
+}
+
 // ECConfig - per-bucket erasure coding configuration
 type ECConf struct {
 	ObjSizeLimit int64  `json:"objsize_limit"` // objects below this size are replicated instead of EC'ed
@@ -308,6 +402,176 @@ type ECConfToUpdate struct {
 	Compression  *string `json:"compression"`
 }
 
+// LifecycleConf - per-bucket lifecycle rules. An object is subject to the rules
+// whose prefix matches its name.
+type LifecycleConf struct {
+	Rules   []LifecycleRule `json:"rules"`
+	Enabled bool            `json:"enabled"`
+}
+
+type LifecycleRule struct {
+	ID     string `json:"id"`
+	Prefix string `json:"prefix,omitempty"`
+	// ais buckets: delete objects written (or overwritten) more than ExpireDays ago
+	ExpireDays int `json:"expire_days,omitempty"`
+	// ais buckets: delete noncurrent versions NoncurrentDays after they were replaced
+	NoncurrentDays int `json:"noncurrent_days,omitempty"`
+	// remote buckets: evict cached objects that have not been accessed for this long (e.g., "72h")
+	EvictIdleTime string `json:"evict_idle_time,omitempty"`
+}
+
+type LifecycleConfToUpdate struct {
+	Rules   *[]LifecycleRule `json:"rules"`
+	Enabled *bool            `json:"enabled"`
+}
+
+// ObjLockConf - per-bucket object lock (WORM). An object cannot be deleted,
+// overwritten, renamed, or evicted for RetentionDays after it was written,
+// and for as long as it is under legal hold. Once enabled, object lock
+// cannot be disabled; in compliance mode, the retention cannot be shortened.
+type ObjLockConf struct {
+	Mode          string `json:"mode"`           // ObjLockGovernance or ObjLockCompliance
+	RetentionDays int    `json:"retention_days"` // default retention of newly written objects
+	Enabled       bool   `json:"enabled"`
+}
+
+type ObjLockConfToUpdate struct {
+	Mode          *string `json:"mode"`
+	RetentionDays *int    `json:"retention_days"`
+	Enabled       *bool   `json:"enabled"`
+}
+
+// QuotaConf - per-bucket quota. Zero means no limit. The limits are cluster-wide:
+// each target checks writes against its own usage plus the (periodically
+// refreshed) usage of the bucket on the other targets.
+type QuotaConf struct {
+	MaxBytes   int64 `json:"max_bytes"`
+	MaxObjects int64 `json:"max_objects"`
+	Enabled    bool  `json:"enabled"`
+}
+
+// QuotaUsage - local usage of a bucket with enabled quota (intra-cluster)
+type QuotaUsage struct {
+	Objs int64 `json:"objs"`
+	Size int64 `json:"size"`
+}
+
+type QuotaConfToUpdate struct {
+	MaxBytes   *int64 `json:"max_bytes"`
+	MaxObjects *int64 `json:"max_objects"`
+	Enabled    *bool  `json:"enabled"`
+}
+
+// BckRateLimitConf - when enabled, replaces RateLimitConf.BckRate and BckBurst
+// for the bucket (zero rate - no limit)
+type BckRateLimitConf struct {
+	Rate    int  `json:"rate"`
+	Burst   int  `json:"burst"`
+	Enabled bool `json:"enabled"`
+}
+
+type BckRateLimitConfToUpdate struct {
+	Rate    *int  `json:"rate"`
+	Burst   *int  `json:"burst"`
+	Enabled *bool `json:"enabled"`
+}
+
+// BckSSEConf - when enabled, newly written objects are encrypted with
+// AES-256-GCM using per-object data keys wrapped by the cluster master key
+// (see SSEConf). Supported only for ais buckets. Encrypted objects remain
+// readable after SSE is disabled.
+type BckSSEConf struct {
+	Enabled bool `json:"enabled"`
+}
+
+type BckSSEConfToUpdate struct {
+	Enabled *bool `json:"enabled"`
+}
+
+// BckCompressionConf - when enabled, newly written objects are compressed in
+// chunks using LZ4Compression or ZstdCompression. An object is stored as is
+// unless its first chunk compresses at least MinRatio times (zero - any
+// reduction in size warrants compression). Supported only for ais buckets.
+type BckCompressionConf struct {
+	Algorithm string  `json:"algorithm"`
+	MinRatio  float64 `json:"min_ratio"`
+	Enabled   bool    `json:"enabled"`
+}
+
+type BckCompressionConfToUpdate struct {
+	Algorithm *string  `json:"algorithm"`
+	MinRatio  *float64 `json:"min_ratio"`
+	Enabled   *bool    `json:"enabled"`
+}
+
+// BckDedupConf - when enabled, the content of newly written objects is split
+// into content-defined chunks that are stored once per target and shared
+// between objects. Supported only for ais buckets. Deduplicated objects remain
+// readable after dedup is disabled.
+type BckDedupConf struct {
+	Enabled bool `json:"enabled"`
+}
+
+type BckDedupConfToUpdate struct {
+	Enabled *bool `json:"enabled"`
+}
+
+// BckReplicationConf - when enabled, PUTs and deletes of the objects in an ais
+// bucket are asynchronously replicated to the bucket named `Bucket` (by
+// default, same name) of the remote AIS cluster attached via AttachRemoteAIS;
+// `Cluster` is either the alias or the UUID of the remote cluster.
+type BckReplicationConf struct {
+	Cluster string `json:"cluster"`
+	Bucket  string `json:"bucket"`
+	Enabled bool   `json:"enabled"`
+}
+
+type BckReplicationConfToUpdate struct {
+	Cluster *string `json:"cluster"`
+	Bucket  *string `json:"bucket"`
+	Enabled *bool   `json:"enabled"`
+}
+
+// BckWriteBackConf - when enabled, PUTs into a cloud bucket are acknowledged
+// once the object is stored locally, while the object gets written to the
+// cloud asynchronously (see also ActFlushBck)
+type BckWriteBackConf struct {
+	Enabled bool `json:"enabled"`
+}
+
+type BckWriteBackConfToUpdate struct {
+	Enabled *bool `json:"enabled"`
+}
+
+// BckPartialCacheConf - when enabled, a cold GET of a byte range of a cloud
+// object (of at least MinObjSize) fetches and stores only the chunks that
+// contain the range, plus ReadAhead chunks; the rest of the object is
+// fetched lazily, when (and if) requested. LRU evicts such objects chunk by chunk.
+type BckPartialCacheConf struct {
+	ChunkSize  int64 `json:"chunk_size"`
+	ReadAhead  int   `json:"read_ahead"`   // number of chunks
+	MinObjSize int64 `json:"min_obj_size"` // smaller objects are always cached in their entirety
+	Enabled    bool  `json:"enabled"`
+}
+
+type BckPartialCacheConfToUpdate struct {
+	ChunkSize  *int64 `json:"chunk_size"`
+	ReadAhead  *int   `json:"read_ahead"`
+	MinObjSize *int64 `json:"min_obj_size"`
+	Enabled    *bool  `json:"enabled"`
+}
+
+// ReplicationStats - state of the (per-target) replication queue
+type ReplicationStats struct {
+	Pending  int64        `json:"pending,string"` // number of queued PUTs and deletes
+	Lag      DurationJSON `json:"lag"`            // time the oldest pending entry has been queued
+	PutCount int64        `json:"put.n,string"`
+	PutSize  int64        `json:"put.size,string"`
+	DelCount int64        `json:"del.n,string"`
+	ErrCount int64        `json:"err.n,string"`
+	LastErr  string       `json:"last_error,omitempty"`
+}
+
 func (c *VersionConf) String() string {
 	if !c.Enabled {
 		return "Disabled"
@@ -319,6 +583,9 @@ func (c *VersionConf) String() string {
 	} else {
 		text += "no"
 	}
+	if c.History {
+		text += " | History"
+	}
 
 	return text
 }
@@ -355,6 +622,206 @@ func (c *LRUConf) String() string {
 		c.LowWM, c.HighWM, c.DontEvictTimeStr, c.OOS)
 }
 
+func (c *LifecycleConf) String() string {
+	if !c.Enabled || len(c.Rules) == 0 {
+		return "Disabled"
+	}
+	ids := make([]string, 0, len(c.Rules))
+	for _, rule := range c.Rules {
+		ids = append(ids, rule.ID)
+	}
+	return fmt.Sprintf("%d rule(s): %s", len(c.Rules), strings.Join(ids, ","))
+}
+
+// ExpireAge and EvictIdle return zero if the rule does not expire (evict) objects
+func (r *LifecycleRule) ExpireAge() time.Duration {
+	return time.Duration(r.ExpireDays) * 24 * time.Hour
+}
+
+func (r *LifecycleRule) NoncurrentAge() time.Duration {
+	return time.Duration(r.NoncurrentDays) * 24 * time.Hour
+}
+
+func (r *LifecycleRule) EvictIdle() time.Duration {
+	if r.EvictIdleTime == "" {
+		return 0
+	}
+	d, err := time.ParseDuration(r.EvictIdleTime)
+	AssertNoErr(err) // validated (see `LifecycleConf.ValidateAsProps`)
+	return d
+}
+
+func (c *ObjLockConf) String() string {
+	if !c.Enabled {
+		return "Disabled"
+	}
+	return fmt.Sprintf("Mode: %s | Retention: %dd", c.Mode, c.RetentionDays)
+}
+
+func (c *ObjLockConf) Retention() time.Duration {
+	return time.Duration(c.RetentionDays) * 24 * time.Hour
+}
+
+// ValidateUpdate checks that the object lock is not weakened (see ObjLockConf)
+func (c *ObjLockConf) ValidateUpdate(nc *ObjLockConf) error {
+	if !c.Enabled {
+		return nil
+	}
+	if !nc.Enabled {
+		return errors.New("once enabled, object lock cannot be disabled")
+	}
+	if c.Mode == ObjLockCompliance {
+		if nc.Mode != ObjLockCompliance {
+			return errors.New("object lock compliance mode cannot be changed")
+		}
+		if nc.RetentionDays < c.RetentionDays {
+			return fmt.Errorf("object lock compliance mode: retention cannot be shortened (%dd < %dd)",
+				nc.RetentionDays, c.RetentionDays)
+		}
+	}
+	return nil
+}
+
+func (c *QuotaConf) String() string {
+	if !c.Enabled {
+		return "Disabled"
+	}
+	bytes, objects := "unlimited", "unlimited"
+	if c.MaxBytes > 0 {
+		bytes = B2S(c.MaxBytes, 2)
+	}
+	if c.MaxObjects > 0 {
+		objects = strconv.FormatInt(c.MaxObjects, 10)
+	}
+	return fmt.Sprintf("Max size: %s | Max objects: %s", bytes, objects)
+}
+
+func (c *BckRateLimitConf) String() string {
+	if !c.Enabled {
+		return "Disabled"
+	}
+	if c.Rate == 0 {
+		return "Unlimited"
+	}
+	return fmt.Sprintf("%d req/s | Burst: %d", c.Rate, c.Burst)
+}
+
+func (c *BckSSEConf) String() string {
+	if !c.Enabled {
+		return "Disabled"
+	}
+	return "AES-256-GCM"
+}
+
+func (c *BckCompressionConf) String() string {
+	if !c.Enabled {
+		return "Disabled"
+	}
+	if c.MinRatio == 0 {
+		return c.Algorithm
+	}
+	return fmt.Sprintf("%s | Min ratio: %.2f", c.Algorithm, c.MinRatio)
+}
+
+func (c *BckDedupConf) String() string {
+	if !c.Enabled {
+		return "Disabled"
+	}
+	return "Enabled"
+}
+
+func (c *BckReplicationConf) String() string {
+	if !c.Enabled {
+		return "Disabled"
+	}
+	bucket := c.Bucket
+	if bucket == "" {
+		bucket = "<same>"
+	}
+	return fmt.Sprintf("%s://%c%s/%s", ProviderAIS, NsUUIDPrefix, c.Cluster, bucket)
+}
+
+// RemoteBck returns the bucket of the remote cluster that a given (local) bucket replicates to
+func (c *BckReplicationConf) RemoteBck(bck Bck) Bck {
+	name := c.Bucket
+	if name == "" {
+		name = bck.Name
+	}
+	return Bck{Name: name, Provider: ProviderAIS, Ns: Ns{UUID: c.Cluster}}
+}
+
+func (c *BackendChainConf) String() string {
+	if len(c.Bcks) == 0 {
+		return "Disabled"
+	}
+	names := make([]string, 0, len(c.Bcks))
+	for _, bck := range c.Bcks {
+		names = append(names, bck.String())
+	}
+	return fmt.Sprintf("%s (write: %s)", strings.Join(names, " => "), c.writePolicy())
+}
+
+func (c *BackendChainConf) writePolicy() string {
+	if c.WritePolicy == "" {
+		return WritePolicyPrimary
+	}
+	return c.WritePolicy
+}
+
+// WriteBcks returns the backends that receive PUTs and deletes
+func (c *BackendChainConf) WriteBcks() []Bck {
+	switch c.writePolicy() {
+	case WritePolicyAll:
+		return c.Bcks
+	case WritePolicyNone:
+		return nil
+	default:
+		return c.Bcks[:1]
+	}
+}
+
+// validate checks the chain; cloudProvider is the 3rd party Cloud this cluster
+// is configured with (the only one, other than remote ais, it can reach)
+func (c *BackendChainConf) validate(cloudProvider string) error {
+	switch c.WritePolicy {
+	case "", WritePolicyPrimary, WritePolicyAll, WritePolicyNone:
+	default:
+		return fmt.Errorf("invalid backend_chain.write_policy %q, expecting one of: %s, %s, %s",
+			c.WritePolicy, WritePolicyPrimary, WritePolicyAll, WritePolicyNone)
+	}
+	for i, bck := range c.Bcks {
+		if bck.Name == "" {
+			return fmt.Errorf("backend_chain: bucket name should not be empty")
+		}
+		if !bck.IsRemote() {
+			return fmt.Errorf("backend_chain: %s is neither cloud nor remote ais bucket", bck)
+		}
+		if !bck.IsRemoteAIS() && bck.Provider != cloudProvider {
+			return fmt.Errorf("backend_chain: %s - cloud provider %q is not configured", bck, bck.Provider)
+		}
+		for _, other := range c.Bcks[:i] {
+			if other.Equal(bck) {
+				return fmt.Errorf("backend_chain: duplicate bucket %s", bck)
+			}
+		}
+	}
+	return nil
+}
+
+func (c *BckPartialCacheConf) String() string {
+	if !c.Enabled {
+		return "Disabled"
+	}
+	return fmt.Sprintf("chunk %s, read-ahead %d, min %s", B2S(c.ChunkSize, 0), c.ReadAhead, B2S(c.MinObjSize, 0))
+}
+
+func (c *BckWriteBackConf) String() string {
+	if !c.Enabled {
+		return "Disabled"
+	}
+	return "Enabled"
+}
+
 func (c *MirrorConf) String() string {
 	if !c.Enabled {
 		return "Disabled"
@@ -401,6 +868,7 @@ type ObjectProps struct {
 	ParitySlices int              `list:"omit"`
 	IsECCopy     bool             `list:"omit"`
 	Present      bool             `json:"present"`
+	UserMD       SimpleKVs        `json:"user_md,omitempty" list:"omit"`
 }
 
 type ObjectCksumProps struct {
@@ -420,6 +888,14 @@ func DefaultBucketProps() *BucketProps {
 		Versioning: c.Versioning,
 		Access:     AllAccess(),
 		EC:         c.EC,
+		Compression: BckCompressionConf{
+			Algorithm: LZ4Compression,
+		},
+		PartialCache: BckPartialCacheConf{
+			ChunkSize:  DefaultPartialChunkSize,
+			ReadAhead:  DefaultPartialReadAhead,
+			MinObjSize: DefaultPartialMinObjSize,
+		},
 	}
 }
 
@@ -467,12 +943,22 @@ func (bp *BucketProps) Validate(targetCnt int) error {
 	if !IsValidProvider(bp.Provider) {
 		return fmt.Errorf("invalid cloud provider: %s, must be one of (%s)", bp.Provider, allProviders)
 	}
+	if len(bp.BackendChain.Bcks) > 0 {
+		if bp.Provider != ProviderAIS {
+			return fmt.Errorf("backend chain can only be set for AIS buckets")
+		}
+		if err := bp.BackendChain.validate(GCO.Get().Cloud.Provider); err != nil {
+			return err
+		}
+		// the first backend in the chain is the bucket's backend
+		bp.BackendBck = bp.BackendChain.Bcks[0]
+	}
 	if !bp.BackendBck.IsEmpty() {
 		if bp.BackendBck.Name == "" {
 			return fmt.Errorf("backend bucket name should not be empty")
 		}
-		if !bp.BackendBck.IsCloud() {
-			return fmt.Errorf("backend bucket should point to cloud bucket")
+		if !bp.BackendBck.IsRemote() {
+			return fmt.Errorf("backend bucket should point to cloud or remote ais bucket")
 		}
 		if bp.Provider != ProviderAIS {
 			return fmt.Errorf("backend bucket can only be set for AIS buckets")
@@ -480,12 +966,84 @@ func (bp *BucketProps) Validate(targetCnt int) error {
 	}
 
 	validationArgs := &ValidationArgs{TargetCnt: targetCnt}
-	validators := []PropsValidator{&bp.Cksum, &bp.LRU, &bp.Mirror, &bp.EC}
+	validators := []PropsValidator{&bp.Cksum, &bp.LRU, &bp.Lifecycle, &bp.ObjLock, &bp.Quota, &bp.RateLimit,
+		&bp.Compression, &bp.Replication, &bp.PartialCache, &bp.Mirror, &bp.EC}
 	for _, validator := range validators {
 		if err := validator.ValidateAsProps(validationArgs); err != nil {
 			return err
 		}
 	}
+	if bp.Versioning.History {
+		if !bp.Versioning.Enabled {
+			return fmt.Errorf("versioning.history requires versioning to be enabled")
+		}
+		if bp.Provider != ProviderAIS || !bp.BackendBck.IsEmpty() {
+			return fmt.Errorf("versioning.history is supported only for ais buckets")
+		}
+	}
+	if bp.SSE.Enabled && (bp.Provider != ProviderAIS || !bp.BackendBck.IsEmpty()) {
+		return fmt.Errorf("server-side encryption is supported only for ais buckets")
+	}
+	if bp.Compression.Enabled {
+		if bp.Provider != ProviderAIS || !bp.BackendBck.IsEmpty() {
+			return fmt.Errorf("compression is supported only for ais buckets")
+		}
+		if bp.SSE.Enabled {
+			return fmt.Errorf("cannot enable compression and server-side encryption at the same time for the same bucket")
+		}
+	}
+	if bp.Dedup.Enabled {
+		if bp.Provider != ProviderAIS || !bp.BackendBck.IsEmpty() {
+			return fmt.Errorf("dedup is supported only for ais buckets")
+		}
+		if bp.SSE.Enabled || bp.Compression.Enabled {
+			return fmt.Errorf("cannot enable dedup along with server-side encryption or compression for the same bucket")
+		}
+		if bp.EC.Enabled {
+			return fmt.Errorf("cannot enable dedup and ec at the same time for the same bucket")
+		}
+		if bp.Mirror.Enabled {
+			return fmt.Errorf("cannot enable dedup and mirroring at the same time for the same bucket")
+		}
+	}
+	if bp.Replication.Enabled && (bp.Provider != ProviderAIS || !bp.BackendBck.IsEmpty()) {
+		return fmt.Errorf("replication to remote cluster is supported only for ais buckets")
+	}
+	if bp.WriteBack.Enabled {
+		provider := bp.Provider
+		if !bp.BackendBck.IsEmpty() {
+			provider = bp.BackendBck.Provider
+		}
+		if provider == ProviderAIS {
+			return fmt.Errorf("write-back is supported only for cloud buckets")
+		}
+		if IsReadOnlyProvider(provider) {
+			return fmt.Errorf("write-back is not supported for read-only cloud provider %q", provider)
+		}
+	}
+	if bp.PartialCache.Enabled {
+		if bp.Provider == ProviderAIS && bp.BackendBck.IsEmpty() {
+			return fmt.Errorf("partial caching is supported only for cloud buckets")
+		}
+		if bp.Mirror.Enabled || bp.EC.Enabled {
+			return fmt.Errorf("cannot enable partial caching along with mirroring or ec for the same bucket")
+		}
+		// (chunks are written in place)
+		if bp.SSE.Enabled || bp.Compression.Enabled || bp.Dedup.Enabled {
+			return fmt.Errorf("cannot enable partial caching along with encryption, compression, or dedup")
+		}
+	}
+	if bp.Lifecycle.Enabled {
+		remote := bp.Provider != ProviderAIS || !bp.BackendBck.IsEmpty()
+		for _, rule := range bp.Lifecycle.Rules {
+			if remote && (rule.ExpireDays > 0 || rule.NoncurrentDays > 0) {
+				return fmt.Errorf("lifecycle rule %q: expiration is supported only for ais buckets", rule.ID)
+			}
+			if !remote && rule.EvictIdleTime != "" {
+				return fmt.Errorf("lifecycle rule %q: eviction is supported only for remote buckets", rule.ID)
+			}
+		}
+	}
 
 	if bp.Mirror.Enabled && bp.EC.Enabled {
 		return fmt.Errorf("cannot enable mirroring and ec at the same time for the same bucket")
@@ -36,6 +36,7 @@ const (
 	ActRebalance      = "rebalance"
 	ActResilver       = "resilver"
 	ActLRU            = "lru"
+	ActLifecycle      = "lifecycle"
 	ActSyncLB         = "synclb"
 	ActCreateLB       = "createlb"
 	ActDestroyLB      = "destroylb"
@@ -52,6 +53,8 @@ const (
 	ActSummaryBucket  = "summarybck"
 	ActRenameObject   = "renameobj"
 	ActPromote        = "promote"
+	ActPresignObject  = "presignobj"
+	ActLegalHold      = "legalhold"
 	ActEvictObjects   = "evictobj"
 	ActDelete         = "delete"
 	ActPrefetch       = "prefetch"
@@ -62,14 +65,19 @@ const (
 	ActUnregProxy     = "unregproxy"
 	ActNewPrimary     = "newprimary"
 	ActRevokeToken    = "revoketoken"
+	ActAddAccessKey   = "addaccesskey"
 	ActElection       = "election"
 	ActPutCopies      = "putcopies"
 	ActMakeNCopies    = "makencopies"
 	ActLoadLomCache   = "loadlomcache"
-	ActECGet          = "ecget"    // erasure decode objects
-	ActECPut          = "ecput"    // erasure encode objects
-	ActECRespond      = "ecresp"   // respond to other targets' EC requests
-	ActECEncode       = "ecencode" // erasure code a bucket
+	ActECGet          = "ecget"     // erasure decode objects
+	ActECPut          = "ecput"     // erasure encode objects
+	ActECRespond      = "ecresp"    // respond to other targets' EC requests
+	ActECEncode       = "ecencode"  // erasure code a bucket
+	ActDedup          = "dedup"     // deduplicate a bucket
+	ActWriteBack      = "writeback" // write PUT objects to the cloud (see BckWriteBackConf)
+	ActFlushBck       = "flush"     // write all not-yet-written objects of a write-back bucket
+	ActSyncBck        = "sync"      // synchronize cached objects with the cloud
 	ActStartGFN       = "metasync-start-gfn"
 	ActRecoverBck     = "recoverbck"
 	ActTar2Tf         = "tar2tf"
@@ -123,6 +131,7 @@ const (
 	HeaderObjCksumVal  = "checksum.value" // Checksum Value
 	HeaderObjAtime     = "atime"          // Object access time
 	HeaderObjCustomMD  = "custom_md"      // Object custom metadata
+	HeaderObjUserMD    = "user_md"        // Object user-defined metadata (repeated "key=value" entries)
 	HeaderObjSize      = "size"           // Object size (bytes)
 	HeaderObjVersion   = "version"        // Object version/generation - ais or Cloud
 	HeaderObjECMeta    = "ec_meta"        // Info about EC object/slice/replica
@@ -138,6 +147,9 @@ const (
 	// custom
 	HeaderAppendHandle = "append.handle"
 
+	// rate limiting: number of seconds to wait before retrying
+	HeaderRetryAfter = "Retry-After"
+
 	// intra-cluster: streams
 	HeaderSessID   = "session.id"
 	HeaderCompress = "compress" // LZ4Compression, etc.
@@ -147,7 +159,8 @@ const (
 
 // supported compressions (alg-s)
 const (
-	LZ4Compression = "lz4"
+	LZ4Compression  = "lz4"
+	ZstdCompression = "zstd" // at-rest only (see BckCompressionConf)
 )
 
 // URL Query "?name1=val1&name2=..."
@@ -158,8 +171,11 @@ const (
 	URLParamCheckExists = "check_cached" // true: check if object exists
 	URLParamProvider    = "provider"     // cloud provider
 	URLParamNamespace   = "namespace"
-	URLParamPrefix      = "prefix" // prefix for list objects in a bucket
-	URLParamRegex       = "regex"  // dsort/downloader regex
+	URLParamPrefix      = "prefix"  // prefix for list objects in a bucket
+	URLParamRegex       = "regex"   // dsort/downloader regex
+	URLParamVersion     = "version" // GET, HEAD, or DELETE a given version of an object
+
+	URLParamBypassGovernance = "bypass_governance" // true: bypass governance-mode retention (admin only)
 	// internal use
 	URLParamCheckExistsAny   = "cea" // true: lookup object in all mountpaths (NOTE: compare with URLParamCheckExists)
 	URLParamProxyID          = "pid" // ID of the redirecting proxy
@@ -176,6 +192,7 @@ const (
 	URLParamTaskAction       = "tac" // "start", "status", "result"
 	URLParamClusterInfo      = "cii" // true: Health to return ais.clusterInfo
 	URLParamRecvType         = "rtp" // to tell real PUT from migration PUT
+	URLParamUserWrite        = "usw" // true: migration PUT on behalf of the user (rename, promote): object lock and quota apply
 
 	URLParamAppendType   = "appendty"
 	URLParamAppendHandle = "handle"
@@ -194,6 +211,10 @@ const (
 
 	// notification target's node ID (usually, the node that initiates the operation)
 	URLParamNotifyMe = "nft"
+
+	// presigned object URL
+	URLParamSignExpires = "sign_expires" // Unix time (seconds) when the URL expires
+	URLParamSignature   = "signature"
 )
 
 // enum: task action (cmn.URLParamTaskAction)
@@ -216,8 +237,11 @@ const (
 	GetWhatDiskStats    = "disk"
 	GetWhatDaemonStatus = "status"
 	GetWhatRemoteAIS    = "remote"
-	GetWhatXactStats    = "getxstats" // stats(xaction-by-uuid)
-	QueryXactStats      = "qryxstats" // stats(all-matching-xactions)
+	GetWhatReplication  = "replication"
+	GetWhatVersions     = "versions"    // versions of an object (or of all objects in a bucket)
+	GetWhatQuotaUsage   = "quota_usage" // local usage of a bucket with enabled quota (intra-cluster)
+	GetWhatXactStats    = "getxstats"   // stats(xaction-by-uuid)
+	QueryXactStats      = "qryxstats"   // stats(all-matching-xactions)
 )
 
 // SelectMsg.TimeFormat enum
@@ -251,6 +275,7 @@ const (
 	EntryStatusBits = 5                          // N bits
 	EntryStatusMask = (1 << EntryStatusBits) - 1 // mask for N low bits
 	EntryIsCached   = 1 << (EntryStatusBits + 1) // StatusMaskBits + 1
+	EntryIsDir      = 1 << (EntryStatusBits + 2) // pseudo-directory: names rolled up by delimiter
 )
 
 // List objects default page size
@@ -263,29 +288,30 @@ const (
 	// l1
 	Version = "v1"
 	// l2
-	Buckets   = "buckets"
-	Objects   = "objects"
-	EC        = "ec"
-	Download  = "download"
-	Daemon    = "daemon"
-	Cluster   = "cluster"
-	Tokens    = "tokens"
-	Metasync  = "metasync"
-	Health    = "health"
-	Vote      = "vote"
-	Transport = "transport"
-	Reverse   = "reverse"
-	Rebalance = "rebalance"
-	Xactions  = "xactions"
-	S3        = "s3"
-	Txn       = "txn"      // 2PC
-	Notifs    = "notifs"   // intra-cluster notifications
-	Tar2Tf    = "tar2tf"   // TODO: move out
-	Users     = "users"    // AuthN
-	Clusters  = "clusters" // AuthN
-	Roles     = "roles"    // AuthN
-	Query     = "query"
-	Jtx       = "jtx"
+	Buckets    = "buckets"
+	Objects    = "objects"
+	EC         = "ec"
+	Download   = "download"
+	Daemon     = "daemon"
+	Cluster    = "cluster"
+	Tokens     = "tokens"
+	Metasync   = "metasync"
+	Health     = "health"
+	Vote       = "vote"
+	Transport  = "transport"
+	Reverse    = "reverse"
+	Rebalance  = "rebalance"
+	Xactions   = "xactions"
+	S3         = "s3"
+	Txn        = "txn"        // 2PC
+	Notifs     = "notifs"     // intra-cluster notifications
+	Tar2Tf     = "tar2tf"     // TODO: move out
+	Users      = "users"      // AuthN
+	Clusters   = "clusters"   // AuthN
+	Roles      = "roles"      // AuthN
+	AccessKeys = "accesskeys" // AuthN
+	Query      = "query"
+	Jtx        = "jtx"
 
 	// l3
 	SyncSmap     = "syncsmap"
@@ -315,6 +341,8 @@ const (
 	Peek        = "peek"
 	Discard     = "discard"
 	WorkerOwner = "worker" // TODO: it should be removed once get-next-bytes endpoint is ready
+	Select      = "select"
+	Aggregate   = "aggregate"
 
 	// CLI
 	Target = "target"
@@ -335,6 +363,19 @@ const (
 	CompressRatio  = "ratio=%d" // adaptive: min ratio that warrants compression
 )
 
+// enum: object lock mode (see ObjLockConf)
+const (
+	ObjLockGovernance = "governance" // retention can be bypassed by admins
+	ObjLockCompliance = "compliance" // retention cannot be bypassed
+)
+
+// enum: backend chain write policy (see BackendChainConf)
+const (
+	WritePolicyPrimary = "primary" // the first backend in the chain (default)
+	WritePolicyAll     = "all"     // all backends in the chain
+	WritePolicyNone    = "none"    // none: objects are stored only locally
+)
+
 // AuthN consts
 const (
 	HeaderAuthorization = "Authorization"
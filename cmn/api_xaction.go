@@ -48,6 +48,7 @@ type (
 var XactsDtor = map[string]XactDescriptor{
 	// bucket-less (aka "global") xactions with scope = (target | cluster)
 	ActLRU:       {Type: XactTypeGlobal, Startable: true},
+	ActLifecycle: {Type: XactTypeGlobal, Startable: true},
 	ActElection:  {Type: XactTypeGlobal, Startable: false},
 	ActResilver:  {Type: XactTypeGlobal, Startable: true},
 	ActRebalance: {Type: XactTypeGlobal, Startable: true, Metasync: true, Owned: false},
@@ -62,6 +63,10 @@ var XactsDtor = map[string]XactDescriptor{
 	ActRenameLB:      {Type: XactTypeBck, Startable: false, Metasync: true, Owned: false},
 	ActCopyBucket:    {Type: XactTypeBck, Startable: false, Metasync: true, Owned: false},
 	ActECEncode:      {Type: XactTypeBck, Startable: true, Metasync: true, Owned: false},
+	ActDedup:         {Type: XactTypeBck, Startable: true},
+	ActWriteBack:     {Type: XactTypeBck, Startable: false},
+	ActFlushBck:      {Type: XactTypeBck, Startable: true},
+	ActSyncBck:       {Type: XactTypeBck, Startable: true},
 	ActEvictObjects:  {Type: XactTypeBck, Startable: false},
 	ActDelete:        {Type: XactTypeBck, Startable: false},
 	ActLoadLomCache:  {Type: XactTypeBck, Startable: false},
@@ -5,8 +5,13 @@
 package cmn
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/NVIDIA/aistore/cmn/debug"
@@ -20,6 +25,12 @@ const (
 	AuthGuestRole        = "Guest"
 )
 
+// presigned object URLs
+const (
+	PresignDefaultExpires = time.Hour
+	PresignMaxExpires     = 7 * 24 * time.Hour
+)
+
 type (
 	// list of types used in AuthN and its API
 
@@ -59,6 +70,17 @@ type (
 		Buckets  []*AuthBucket  `json:"buckets,omitempty"`
 		IsAdmin  bool           `json:"admin"`
 	}
+	// S3 access key: the secret is returned only once - when the key is
+	// created, and the token defines permissions of the key. AuthN signs
+	// the key when it pushes the key to clusters (see AccessKeySignature)
+	AuthAccessKey struct {
+		ID        string    `json:"id"`
+		Secret    string    `json:"secret,omitempty"`
+		UserID    string    `json:"username"`
+		Token     string    `json:"token,omitempty"`
+		Expires   time.Time `json:"expires"`
+		Signature string    `json:"sig,omitempty"`
+	}
 	AuthClusterList struct {
 		Clusters map[string]*AuthCluster `json:"clusters,omitempty"`
 	}
@@ -179,3 +201,25 @@ func DecryptToken(tokenStr, secret string) (*AuthToken, error) {
 	}
 	return tInfo, nil
 }
+
+// PresignSignature calculates HMAC of a presigned object request. The
+// signature covers HTTP method, URL path, bucket provider and namespace,
+// and expiration time (Unix seconds), so the URL cannot be reused to access
+// another object or with another method.
+func PresignSignature(secret, method, urlPath string, bck Bck, expires int64) string {
+	msg := strings.Join([]string{method, urlPath, bck.Provider, bck.Ns.Uname(), strconv.FormatInt(expires, 10)}, "\n")
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(msg))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// AccessKeySignature calculates HMAC of an access key with the secret shared
+// by AuthN and clusters. A cluster accepts only keys signed by AuthN, so
+// a client cannot register its own key or replace the secret of another one.
+func AccessKeySignature(secret string, key *AuthAccessKey) string {
+	msg := strings.Join([]string{key.ID, key.Secret, key.UserID, key.Token,
+		strconv.FormatInt(key.Expires.UnixNano(), 10)}, "\n")
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(msg))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,35 @@
+// Package cmn provides common low-level types and utilities for all aistore projects
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package cmn
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestPresignSignature(t *testing.T) {
+	const (
+		secret  = "aBitLongSecretKey"
+		urlPath = "/v1/objects/bck/obj"
+		expires = int64(1593100800)
+	)
+	bck := Bck{Name: "bck", Provider: ProviderAIS}
+	sig := PresignSignature(secret, http.MethodGet, urlPath, bck, expires)
+	if sig != PresignSignature(secret, http.MethodGet, urlPath, bck, expires) {
+		t.Fatal("signature must be deterministic")
+	}
+	other := []string{
+		PresignSignature("another"+secret, http.MethodGet, urlPath, bck, expires),
+		PresignSignature(secret, http.MethodPut, urlPath, bck, expires),
+		PresignSignature(secret, http.MethodGet, urlPath+"2", bck, expires),
+		PresignSignature(secret, http.MethodGet, urlPath, Bck{Name: "bck", Provider: ProviderAmazon}, expires),
+		PresignSignature(secret, http.MethodGet, urlPath, bck, expires+1),
+	}
+	for idx, s := range other {
+		if s == sig {
+			t.Errorf("case %d: signatures of different requests must not match", idx)
+		}
+	}
+}
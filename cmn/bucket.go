@@ -18,7 +18,10 @@ const (
 	ProviderGoogle = "gcp"
 	ProviderAIS    = "ais"
 	ProviderAzure  = "azure"
-	allProviders   = "aws, gcp, ais, azure"
+	ProviderHDFS   = "hdfs"
+	ProviderHTTP   = "ht"
+	ProviderPosix  = "posix"
+	allProviders   = "aws, gcp, ais, azure, hdfs, ht, posix"
 
 	NsUUIDPrefix = '@' // BEWARE: used by on-disk layout
 	NsNamePrefix = '#' // BEWARE: used by on-disk layout
@@ -64,6 +67,9 @@ var (
 		ProviderGoogle: {},
 		ProviderAmazon: {},
 		ProviderAzure:  {},
+		ProviderHDFS:   {},
+		ProviderHTTP:   {},
+		ProviderPosix:  {},
 	}
 )
 
@@ -212,6 +218,12 @@ func IsValidProvider(provider string) bool {
 	return ok
 }
 
+// IsReadOnlyProvider returns true for the cloud providers that support
+// read-through access only (GET, HEAD, and list)
+func IsReadOnlyProvider(provider string) bool {
+	return provider == ProviderHDFS || provider == ProviderHTTP
+}
+
 func (query QueryBcks) IsAIS() bool        { return Bck(query).IsAIS() }
 func (query QueryBcks) IsRemoteAIS() bool  { return Bck(query).IsRemoteAIS() }
 func (query QueryBcks) Equal(bck Bck) bool { return Bck(query).Equal(bck) }
@@ -9,6 +9,7 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"net/url"
 	"path/filepath"
 	"strings"
 	"sync"
@@ -36,6 +37,20 @@ const (
 	// EC
 	MinSliceCount = 1  // minimum number of data or parity slices
 	MaxSliceCount = 32 // maximum number of data or parity slices
+
+	// uploading to 3rd party Cloud in parts (see CloudUploadConf)
+	MinUploadPartSize        = 5 * MiB   // S3 minimum
+	MaxUploadPartSize        = 100 * MiB // Azure maximum (block size)
+	DefaultUploadPartSize    = 16 * MiB
+	DefaultUploadThreshold   = 64 * MiB
+	DefaultUploadParallelism = 4
+
+	// partial caching of cloud objects (see BckPartialCacheConf)
+	MinPartialChunkSize      = 64 * KiB
+	MaxPartialChunkSize      = GiB
+	DefaultPartialChunkSize  = 4 * MiB
+	DefaultPartialReadAhead  = 1
+	DefaultPartialMinObjSize = 64 * MiB
 )
 
 const (
@@ -236,9 +251,16 @@ var (
 	_ Validator = &FSPathsConf{}
 	_ Validator = &TestfspathConf{}
 	_ Validator = &CompressionConf{}
+	_ Validator = &RateLimitConf{}
+	_ Validator = &SSEConf{}
 
 	_ PropsValidator = &CksumConf{}
 	_ PropsValidator = &LRUConf{}
+	_ PropsValidator = &LifecycleConf{}
+	_ PropsValidator = &ObjLockConf{}
+	_ PropsValidator = &QuotaConf{}
+	_ PropsValidator = &BckRateLimitConf{}
+	_ PropsValidator = &BckCompressionConf{}
 	_ PropsValidator = &MirrorConf{}
 	_ PropsValidator = &ECConf{}
 
@@ -278,6 +300,8 @@ type Config struct {
 	Downloader       DownloaderConf  `json:"downloader"`
 	DSort            DSortConf       `json:"distributed_sort"`
 	Compression      CompressionConf `json:"compression"`
+	RateLimit        RateLimitConf   `json:"rate_limit"`
+	SSE              SSEConf         `json:"sse"`
 }
 
 type CloudConf struct {
@@ -288,6 +312,35 @@ type CloudConf struct {
 	Provider string `json:"-"`
 }
 
+// CloudConf3P is the configuration of a 3rd party Cloud (`aws`, `gcp`, or `azure`)
+type CloudConf3P struct {
+	Upload CloudUploadConf `json:"upload"`
+}
+
+// CloudUploadConf configures uploading large objects in parts (S3 multipart
+// upload, GCS resumable upload, Azure block list). Each part is retried
+// separately so that a transient error does not restart the upload.
+type CloudUploadConf struct {
+	Threshold   int64 `json:"threshold"`   // objects of this size or larger are uploaded in parts
+	PartSize    int64 `json:"part_size"`   // size of each part (except the last one)
+	Parallelism int   `json:"parallelism"` // max number of parts uploaded concurrently (n/a for GCS)
+}
+
+// CloudConfHDFS is the configuration of the (read-only) HDFS Cloud accessed
+// via WebHDFS; each bucket is a top-level directory
+type CloudConfHDFS struct {
+	URL  string `json:"url"`  // NameNode HTTP address, e.g. http://namenode:9870
+	User string `json:"user"` // user.name (pseudo authentication)
+}
+
+// CloudConfHTTP is the configuration of the (read-only) HTTP(S) Cloud:
+// bucket name => base URL of the directory index served by an HTTP server
+type CloudConfHTTP map[string]string
+
+// CloudConfPosix is the configuration of the Posix Cloud:
+// bucket name => directory (e.g., NFS mount) that must be accessible by every target
+type CloudConfPosix map[string]string
+
 type RemoteAISInfo struct {
 	URL     string `json:"url"`
 	Alias   string `json:"alias"`
@@ -463,11 +516,15 @@ type VersionConf struct {
 
 	// Validate object version upon warm GET.
 	ValidateWarmGet bool `json:"validate_warm_get"`
+
+	// Keep noncurrent versions of overwritten objects (ais buckets only).
+	History bool `json:"history"`
 }
 
 type VersionConfToUpdate struct {
 	Enabled         *bool `json:"enabled"`
 	ValidateWarmGet *bool `json:"validate_warm_get"`
+	History         *bool `json:"history"`
 }
 
 type TestfspathConf struct {
@@ -564,6 +621,21 @@ type CompressionConf struct {
 	Checksum     bool `json:"checksum"`   // true: checksum lz4 frames
 }
 
+// RateLimitConf - token-bucket request rate limits enforced by each proxy (zero rate - no limit,
+// zero burst - same as rate); the per-bucket limit can be overridden via bucket properties
+type RateLimitConf struct {
+	UserRate  int  `json:"user_rate"`    // requests per second per (AuthN) user
+	UserBurst int  `json:"user_burst"`   // max burst per user
+	BckRate   int  `json:"bucket_rate"`  // requests per second per bucket
+	BckBurst  int  `json:"bucket_burst"` // max burst per bucket
+	Enabled   bool `json:"enabled"`
+}
+
+// SSEConf - server-side encryption (see BckSSEConf)
+type SSEConf struct {
+	KeyFile string `json:"keyfile"` // 256-bit master key, raw or hex-encoded
+}
+
 //==============================
 //
 // config functions
@@ -700,7 +772,56 @@ func (c *CloudConf) validateConf(provider string) (err error) {
 		}
 		conf = aisConf
 	case ProviderAmazon, ProviderGoogle, ProviderAzure:
+		var conf3P CloudConf3P
+		if err := jsoniter.Unmarshal(b, &conf3P); err != nil {
+			return fmt.Errorf("invalid cloud specification: %v", err)
+		}
+		if err := conf3P.Upload.validate(); err != nil {
+			return err
+		}
+		c.Ns = NsGlobal
+		conf = conf3P
+	case ProviderHDFS:
+		var hdfsConf CloudConfHDFS
+		if err := jsoniter.Unmarshal(b, &hdfsConf); err != nil {
+			return fmt.Errorf("invalid cloud specification: %v", err)
+		}
+		if _, err := url.ParseRequestURI(hdfsConf.URL); err != nil {
+			return fmt.Errorf("invalid HDFS NameNode URL %q: %v", hdfsConf.URL, err)
+		}
+		c.Ns = NsGlobal
+		conf = hdfsConf
+	case ProviderHTTP:
+		var httpConf CloudConfHTTP
+		if err := jsoniter.Unmarshal(b, &httpConf); err != nil {
+			return fmt.Errorf("invalid cloud specification: %v", err)
+		}
+		for bckName, baseURL := range httpConf {
+			if err := ValidateBckName(bckName); err != nil {
+				return err
+			}
+			if _, err := url.ParseRequestURI(baseURL); err != nil {
+				return fmt.Errorf("invalid base URL %q of bucket %q: %v", baseURL, bckName, err)
+			}
+		}
 		c.Ns = NsGlobal
+		conf = httpConf
+	case ProviderPosix:
+		var posixConf CloudConfPosix
+		if err := jsoniter.Unmarshal(b, &posixConf); err != nil {
+			return fmt.Errorf("invalid cloud specification: %v", err)
+		}
+		for bckName, dir := range posixConf {
+			if err := ValidateBckName(bckName); err != nil {
+				return err
+			}
+			if !filepath.IsAbs(dir) {
+				return fmt.Errorf("directory %q of bucket %q must be an absolute path", dir, bckName)
+			}
+			posixConf[bckName] = filepath.Clean(dir)
+		}
+		c.Ns = NsGlobal
+		conf = posixConf
 	default:
 		AssertMsg(false, "unknown cloud provider "+provider)
 	}
@@ -718,6 +839,44 @@ func (c *CloudConf) ProviderConf(provider string, newConf ...interface{}) (conf
 	return
 }
 
+// UploadConf returns the upload configuration of the 3rd party Cloud
+func (c *CloudConf) UploadConf(provider string) CloudUploadConf {
+	if conf3P, ok := c.Conf[provider].(CloudConf3P); ok {
+		return conf3P.Upload
+	}
+	conf := CloudUploadConf{}
+	conf.setDefaults()
+	return conf
+}
+
+func (c *CloudUploadConf) setDefaults() {
+	if c.PartSize == 0 {
+		c.PartSize = DefaultUploadPartSize
+	}
+	if c.Threshold == 0 {
+		c.Threshold = DefaultUploadThreshold
+	}
+	if c.Parallelism == 0 {
+		c.Parallelism = DefaultUploadParallelism
+	}
+}
+
+func (c *CloudUploadConf) validate() error {
+	c.setDefaults()
+	if c.PartSize < MinUploadPartSize || c.PartSize > MaxUploadPartSize || c.PartSize%(256*KiB) != 0 {
+		return fmt.Errorf("invalid cloud.upload.part_size %d (expecting a multiple of 256KiB in the range [%s, %s])",
+			c.PartSize, B2S(MinUploadPartSize, 0), B2S(MaxUploadPartSize, 0))
+	}
+	if c.Threshold < c.PartSize {
+		return fmt.Errorf("invalid cloud.upload.threshold %d (must be greater than or equal part_size %d)",
+			c.Threshold, c.PartSize)
+	}
+	if c.Parallelism < 1 || c.Parallelism > 64 {
+		return fmt.Errorf("invalid cloud.upload.parallelism %d (expecting range [1, 64])", c.Parallelism)
+	}
+	return nil
+}
+
 func (c *DiskConf) Validate(_ *Config) (err error) {
 	lwm, hwm, maxwm := c.DiskUtilLowWM, c.DiskUtilHighWM, c.DiskUtilMaxWM
 	if lwm <= 0 || hwm <= lwm || maxwm <= hwm || maxwm > 100 {
@@ -765,6 +924,104 @@ func (c *LRUConf) ValidateAsProps(args *ValidationArgs) (err error) {
 	return c.Validate(nil)
 }
 
+func (c *ObjLockConf) ValidateAsProps(_ *ValidationArgs) error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.Mode != ObjLockGovernance && c.Mode != ObjLockCompliance {
+		return fmt.Errorf("invalid object_lock.mode %q (expecting %q or %q)", c.Mode, ObjLockGovernance, ObjLockCompliance)
+	}
+	if c.RetentionDays < 0 {
+		return fmt.Errorf("invalid object_lock.retention_days %d (expecting non-negative)", c.RetentionDays)
+	}
+	return nil
+}
+
+func (c *QuotaConf) ValidateAsProps(_ *ValidationArgs) error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.MaxBytes < 0 || c.MaxObjects < 0 {
+		return fmt.Errorf("invalid quota (max_bytes %d, max_objects %d): expecting non-negative values",
+			c.MaxBytes, c.MaxObjects)
+	}
+	if c.MaxBytes == 0 && c.MaxObjects == 0 {
+		return errors.New("invalid quota: max_bytes and/or max_objects must be specified")
+	}
+	return nil
+}
+
+func (c *BckRateLimitConf) ValidateAsProps(_ *ValidationArgs) error {
+	if c.Rate < 0 || c.Burst < 0 {
+		return fmt.Errorf("invalid rate_limit (rate %d, burst %d): expecting non-negative values", c.Rate, c.Burst)
+	}
+	return nil
+}
+
+func (c *BckCompressionConf) ValidateAsProps(_ *ValidationArgs) error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.Algorithm != LZ4Compression && c.Algorithm != ZstdCompression {
+		return fmt.Errorf("invalid compression.algorithm %q (expecting %q or %q)",
+			c.Algorithm, LZ4Compression, ZstdCompression)
+	}
+	if c.MinRatio < 0 {
+		return fmt.Errorf("invalid compression.min_ratio %.2f: expecting non-negative value", c.MinRatio)
+	}
+	return nil
+}
+
+func (c *BckPartialCacheConf) ValidateAsProps(_ *ValidationArgs) error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.ChunkSize < MinPartialChunkSize || c.ChunkSize > MaxPartialChunkSize {
+		return fmt.Errorf("invalid partial_cache.chunk_size %d (expecting the range [%s, %s])",
+			c.ChunkSize, B2S(MinPartialChunkSize, 0), B2S(MaxPartialChunkSize, 0))
+	}
+	if c.ReadAhead < 0 || c.MinObjSize < 0 {
+		return fmt.Errorf("invalid partial_cache (read_ahead %d, min_obj_size %d): expecting non-negative values",
+			c.ReadAhead, c.MinObjSize)
+	}
+	return nil
+}
+
+func (c *BckReplicationConf) ValidateAsProps(_ *ValidationArgs) error {
+	if c.Enabled && c.Cluster == "" {
+		return errors.New("invalid replication: remote cluster (alias or UUID) must be specified")
+	}
+	return nil
+}
+
+func (c *LifecycleConf) ValidateAsProps(_ *ValidationArgs) error {
+	if !c.Enabled {
+		return nil
+	}
+	ids := make(StringSet, len(c.Rules))
+	for _, rule := range c.Rules {
+		if rule.ID == "" {
+			return errors.New("lifecycle rule id cannot be empty")
+		}
+		if ids.Contains(rule.ID) {
+			return fmt.Errorf("duplicate lifecycle rule id %q", rule.ID)
+		}
+		ids.Add(rule.ID)
+		if rule.ExpireDays < 0 || rule.NoncurrentDays < 0 {
+			return fmt.Errorf("lifecycle rule %q: invalid number of days (expecting non-negative)", rule.ID)
+		}
+		if rule.EvictIdleTime != "" {
+			if d, err := time.ParseDuration(rule.EvictIdleTime); err != nil || d <= 0 {
+				return fmt.Errorf("lifecycle rule %q: invalid evict_idle_time %q", rule.ID, rule.EvictIdleTime)
+			}
+		}
+		if rule.ExpireDays == 0 && rule.NoncurrentDays == 0 && rule.EvictIdleTime == "" {
+			return fmt.Errorf("lifecycle rule %q does not specify any action", rule.ID)
+		}
+	}
+	return nil
+}
+
 func (c *CksumConf) Validate(_ *Config) (err error) {
 	return ValidateCksumType(c.Type)
 }
@@ -777,6 +1034,9 @@ func (c *VersionConf) Validate(_ *Config) error {
 	if !c.Enabled && c.ValidateWarmGet {
 		return errors.New("versioning.validate_warm_get requires versioning to be enabled")
 	}
+	if !c.Enabled && c.History {
+		return errors.New("versioning.history requires versioning to be enabled")
+	}
 	return nil
 }
 func (c *VersionConf) ValidateAsProps() error { return c.Validate(nil) }
@@ -1102,6 +1362,21 @@ func (c *CompressionConf) Validate(_ *Config) (err error) {
 	return nil
 }
 
+func (c *RateLimitConf) Validate(_ *Config) (err error) {
+	if c.UserRate < 0 || c.UserBurst < 0 || c.BckRate < 0 || c.BckBurst < 0 {
+		return fmt.Errorf("invalid rate_limit (user %d/%d, bucket %d/%d): expecting non-negative values",
+			c.UserRate, c.UserBurst, c.BckRate, c.BckBurst)
+	}
+	return nil
+}
+
+func (c *SSEConf) Validate(_ *Config) (err error) {
+	if c.KeyFile != "" && !filepath.IsAbs(c.KeyFile) {
+		return fmt.Errorf("invalid sse.keyfile %q: expecting absolute path", c.KeyFile)
+	}
+	return nil
+}
+
 // setGLogVModule sets glog's vmodule flag
 // sets 'v' as is, no verificaton is done here
 // syntax for v: target=5,proxy=1, p*=3, etc
@@ -61,6 +61,15 @@ type (
 		name string // object's name
 		err  error  // underlying error
 	}
+	ObjLockedErr struct {
+		name   string // object's name
+		reason string // legal hold or retention
+	}
+	QuotaExceededErr struct {
+		bck   string // bucket's name
+		what  string // "size" or "number of objects"
+		limit int64
+	}
 	AbortedError struct {
 		what    string
 		details string
@@ -169,6 +178,28 @@ func (e ObjMetaErr) Error() string {
 
 func NewObjMetaErr(name string, err error) ObjMetaErr { return ObjMetaErr{name: name, err: err} }
 
+func (e *ObjLockedErr) Error() string {
+	return fmt.Sprintf("%s is locked (%s)", e.name, e.reason)
+}
+func NewObjLockedErr(name, reason string) *ObjLockedErr {
+	return &ObjLockedErr{name: name, reason: reason}
+}
+func IsErrObjLocked(err error) bool {
+	_, ok := err.(*ObjLockedErr)
+	return ok
+}
+
+func (e *QuotaExceededErr) Error() string {
+	return fmt.Sprintf("bucket %s: quota exceeded (%s limit %d)", e.bck, e.what, e.limit)
+}
+func NewQuotaExceededErr(bck, what string, limit int64) *QuotaExceededErr {
+	return &QuotaExceededErr{bck: bck, what: what, limit: limit}
+}
+func IsErrQuotaExceeded(err error) bool {
+	_, ok := err.(*QuotaExceededErr)
+	return ok
+}
+
 func NewAbortedError(what string) AbortedError {
 	return AbortedError{
 		what:    what,
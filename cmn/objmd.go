@@ -0,0 +1,101 @@
+// Package cmn provides common API constants and types, and low-level utilities for all aistore projects
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package cmn
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+// User-defined object metadata and tags are stored alongside the system
+// metadata (in the same xattr) and are therefore limited in size. The limits
+// below follow S3 except for the total size of the tag set that S3 doesn't have.
+const (
+	MaxUserMDSize  = 2 * KiB // total size of all user metadata keys and values
+	MaxObjTags     = 10
+	MaxTagKeyLen   = 128
+	MaxTagValueLen = 256
+	MaxObjTagsSize = KiB // total size of all tag keys and values
+)
+
+func AddUserMDToHdr(hdr http.Header, md SimpleKVs) {
+	for k, v := range md {
+		hdr.Add(HeaderObjUserMD, k+"="+v)
+	}
+}
+
+func UserMDFromHdr(hdr http.Header) (md SimpleKVs, err error) {
+	entries := hdr[http.CanonicalHeaderKey(HeaderObjUserMD)]
+	if len(entries) == 0 {
+		return
+	}
+	md = make(SimpleKVs, len(entries))
+	for _, entry := range entries {
+		kv := strings.SplitN(entry, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid user metadata entry %q (expecting key=value)", entry)
+		}
+		md[kv[0]] = kv[1]
+	}
+	err = ValidateUserMD(md)
+	return
+}
+
+func ValidateUserMD(md SimpleKVs) error {
+	var size int
+	for k, v := range md {
+		if k == "" || strings.ContainsRune(k, '=') {
+			return fmt.Errorf("invalid user metadata key %q", k)
+		}
+		if err := validateObjMDStr(k, v); err != nil {
+			return err
+		}
+		size += len(k) + len(v)
+	}
+	if size > MaxUserMDSize {
+		return fmt.Errorf("user metadata size %d exceeds the limit (%d)", size, MaxUserMDSize)
+	}
+	return nil
+}
+
+func ValidateObjTags(tags SimpleKVs) error {
+	var size int
+	if len(tags) > MaxObjTags {
+		return fmt.Errorf("number of tags %d exceeds the limit (%d)", len(tags), MaxObjTags)
+	}
+	for k, v := range tags {
+		if k == "" || utf8.RuneCountInString(k) > MaxTagKeyLen {
+			return fmt.Errorf("invalid tag key %q (must be 1 to %d characters long)", k, MaxTagKeyLen)
+		}
+		if utf8.RuneCountInString(v) > MaxTagValueLen {
+			return fmt.Errorf("tag %q: value is too long (max %d characters)", k, MaxTagValueLen)
+		}
+		if err := validateObjMDStr(k, v); err != nil {
+			return err
+		}
+		size += len(k) + len(v)
+	}
+	if size > MaxObjTagsSize {
+		return fmt.Errorf("tag set size %d exceeds the limit (%d)", size, MaxObjTagsSize)
+	}
+	return nil
+}
+
+// control characters are reserved for packing metadata on disk
+func validateObjMDStr(k, v string) error {
+	for _, s := range []string{k, v} {
+		if !utf8.ValidString(s) {
+			return fmt.Errorf("%q: invalid UTF-8", k)
+		}
+		for _, r := range s {
+			if r < 0x20 {
+				return fmt.Errorf("%q: control characters are not allowed", k)
+			}
+		}
+	}
+	return nil
+}
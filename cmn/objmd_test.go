@@ -0,0 +1,53 @@
+// Package cmn provides common API constants and types, and low-level utilities for all aistore projects
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package cmn
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestValidateUserMD(t *testing.T) {
+	tests := []struct {
+		md    SimpleKVs
+		valid bool
+	}{
+		{md: nil, valid: true},
+		{md: SimpleKVs{"color": "blue", "empty": ""}, valid: true},
+		{md: SimpleKVs{"": "value"}, valid: false},
+		{md: SimpleKVs{"a=b": "value"}, valid: false},
+		{md: SimpleKVs{"key": "val\x01ue"}, valid: false},
+		{md: SimpleKVs{"key": strings.Repeat("x", MaxUserMDSize)}, valid: false},
+	}
+	for i, test := range tests {
+		if err := ValidateUserMD(test.md); (err == nil) != test.valid {
+			t.Errorf("test #%d: expected valid=%t, got err: %v", i, test.valid, err)
+		}
+	}
+}
+
+func TestValidateObjTags(t *testing.T) {
+	tooMany := make(SimpleKVs, MaxObjTags+1)
+	for i := 0; i <= MaxObjTags; i++ {
+		tooMany[strconv.Itoa(i)] = "v"
+	}
+	tests := []struct {
+		tags  SimpleKVs
+		valid bool
+	}{
+		{tags: SimpleKVs{"project": "ais", "tier": ""}, valid: true},
+		{tags: SimpleKVs{"": "value"}, valid: false},
+		{tags: SimpleKVs{strings.Repeat("k", MaxTagKeyLen+1): "v"}, valid: false},
+		{tags: SimpleKVs{"k": strings.Repeat("v", MaxTagValueLen+1)}, valid: false},
+		{tags: SimpleKVs{"k": "\xff"}, valid: false},
+		{tags: tooMany, valid: false},
+	}
+	for i, test := range tests {
+		if err := ValidateObjTags(test.tags); (err == nil) != test.valid {
+			t.Errorf("test #%d: expected valid=%t, got err: %v", i, test.valid, err)
+		}
+	}
+}
@@ -6,6 +6,8 @@ package cmn
 
 import (
 	"sort"
+	"strings"
+	"unicode/utf8"
 )
 
 func sortBckEntries(bckEntries []*BucketEntry) {
@@ -138,3 +140,46 @@ func MergeObjLists(lists []*BucketList, maxSize uint) (objs *BucketList) {
 	bckList.PageMarker = pageMarker
 	return bckList
 }
+
+// DirMarker returns the page marker that includes all objects of the
+// pseudo-directory, so the next page starts right after the directory.
+func DirMarker(dir string) string {
+	return dir + string(utf8.MaxRune)
+}
+
+// RollupObjList rolls up the entries which names contain the delimiter
+// after the prefix into pseudo-directories (CommonPrefixes in terms of S3):
+// all such entries are replaced with a single one that has EntryIsDir flag
+// and the name "prefix" + "the rest of the name up to the delimiter inclusive".
+// The list must be sorted.
+func RollupObjList(list *BucketList, prefix, delimiter string) {
+	if delimiter == "" || len(list.Entries) == 0 {
+		return
+	}
+	var (
+		entries = make([]*BucketEntry, 0, len(list.Entries))
+		lastDir string
+	)
+	for _, e := range list.Entries {
+		if !strings.HasPrefix(e.Name, prefix) {
+			entries = append(entries, e)
+			continue
+		}
+		idx := strings.Index(e.Name[len(prefix):], delimiter)
+		if idx < 0 {
+			entries = append(entries, e)
+			continue
+		}
+		dir := e.Name[:len(prefix)+idx+len(delimiter)]
+		if dir == lastDir {
+			continue
+		}
+		lastDir = dir
+		entries = append(entries, &BucketEntry{Name: dir, Flags: EntryIsDir})
+	}
+	list.Entries = entries
+	// the page ends inside a pseudo-directory - skip the rest of it
+	if list.PageMarker != "" && lastDir != "" && strings.HasPrefix(list.PageMarker, lastDir) {
+		list.PageMarker = DirMarker(lastDir)
+	}
+}
@@ -0,0 +1,104 @@
+// Package cmn provides common API constants and types, and low-level utilities for all aistore projects
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package cmn
+
+import (
+	"testing"
+)
+
+func TestRollupObjList(t *testing.T) {
+	newList := func(marker string, names ...string) *BucketList {
+		list := &BucketList{PageMarker: marker}
+		for _, name := range names {
+			list.Entries = append(list.Entries, &BucketEntry{Name: name})
+		}
+		return list
+	}
+	tests := []struct {
+		name      string
+		list      *BucketList
+		prefix    string
+		delimiter string
+		dirs      []string
+		objs      []string
+		marker    string
+	}{
+		{
+			name:      "no-delimiter",
+			list:      newList("", "a/1", "a/2", "b"),
+			delimiter: "",
+			objs:      []string{"a/1", "a/2", "b"},
+		},
+		{
+			name:      "root",
+			list:      newList("", "a/1", "a/b/2", "b", "c/3"),
+			delimiter: "/",
+			dirs:      []string{"a/", "c/"},
+			objs:      []string{"b"},
+		},
+		{
+			name:      "prefix",
+			list:      newList("", "a/1", "a/b/2", "a/b/3", "a/c"),
+			prefix:    "a/",
+			delimiter: "/",
+			dirs:      []string{"a/b/"},
+			objs:      []string{"a/1", "a/c"},
+		},
+		{
+			name:      "page-ends-inside-dir",
+			list:      newList("a/b/3", "a/1", "a/b/2", "a/b/3"),
+			prefix:    "a/",
+			delimiter: "/",
+			dirs:      []string{"a/b/"},
+			objs:      []string{"a/1"},
+			marker:    DirMarker("a/b/"),
+		},
+		{
+			name:      "page-ends-with-object",
+			list:      newList("a/c", "a/b/2", "a/c"),
+			prefix:    "a/",
+			delimiter: "/",
+			dirs:      []string{"a/b/"},
+			objs:      []string{"a/c"},
+			marker:    "a/c",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			RollupObjList(test.list, test.prefix, test.delimiter)
+			var dirs, objs []string
+			for _, e := range test.list.Entries {
+				if e.IsDir() {
+					dirs = append(dirs, e.Name)
+				} else {
+					objs = append(objs, e.Name)
+				}
+			}
+			if !StrSlicesEqual(dirs, test.dirs) {
+				t.Errorf("expected directories %v, got %v", test.dirs, dirs)
+			}
+			if !StrSlicesEqual(objs, test.objs) {
+				t.Errorf("expected objects %v, got %v", test.objs, objs)
+			}
+			if test.list.PageMarker != test.marker {
+				t.Errorf("expected page marker %q, got %q", test.marker, test.list.PageMarker)
+			}
+		})
+	}
+}
+
+func TestDirMarker(t *testing.T) {
+	marker := DirMarker("a/b/")
+	for _, name := range []string{"a/b/", "a/b/c", "a/b/~~~", "a/b/\u00ff/x"} {
+		if !PageMarkerIncludesObject(marker, name) {
+			t.Errorf("marker must include %q", name)
+		}
+	}
+	for _, name := range []string{"a/b0", "a/c", "b"} {
+		if PageMarkerIncludesObject(marker, name) {
+			t.Errorf("marker must not include %q", name)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+// Package cmn provides common low-level types and utilities for all aistore projects
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package cmn
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// TokenBucket is a non-blocking token-bucket rate limiter: the bucket holds up
+// to `burst` tokens and is refilled at `rate` tokens per second; each admitted
+// request takes one token. Rate and burst are passed with each call so that
+// configuration changes take effect immediately.
+type TokenBucket struct {
+	last   time.Time // last refill
+	tokens float64
+	mtx    sync.Mutex
+}
+
+// Take takes one token if available. Otherwise, it returns false and the time
+// until the next token becomes available. Zero rate means no limit; zero burst
+// defaults to the rate.
+func (tb *TokenBucket) Take(now time.Time, rate, burst int) (ok bool, retryAfter time.Duration) {
+	if rate <= 0 {
+		return true, 0
+	}
+	if burst <= 0 {
+		burst = rate
+	}
+	tb.mtx.Lock()
+	defer tb.mtx.Unlock()
+	if tb.last.IsZero() {
+		tb.tokens = float64(burst)
+	} else if elapsed := now.Sub(tb.last); elapsed > 0 {
+		tb.tokens = math.Min(float64(burst), tb.tokens+elapsed.Seconds()*float64(rate))
+	}
+	tb.last = now
+	if tb.tokens >= 1 {
+		tb.tokens--
+		return true, 0
+	}
+	retryAfter = time.Duration((1 - tb.tokens) / float64(rate) * float64(time.Second))
+	return false, retryAfter
+}
+
+// Idle returns true if the bucket has not been used since `since`.
+func (tb *TokenBucket) Idle(since time.Time) bool {
+	tb.mtx.Lock()
+	defer tb.mtx.Unlock()
+	return tb.last.Before(since)
+}
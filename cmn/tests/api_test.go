@@ -177,6 +177,133 @@ var _ = Describe("API", func() {
 					Access: 1024,
 				},
 			),
+			Entry("lifecycle rules",
+				cmn.BucketProps{},
+				cmn.BucketPropsToUpdate{
+					Lifecycle: &cmn.LifecycleConfToUpdate{
+						Enabled: api.Bool(true),
+						Rules:   &[]cmn.LifecycleRule{{ID: "tmp", Prefix: "tmp/", ExpireDays: 7}},
+					},
+				},
+				cmn.BucketProps{
+					Lifecycle: cmn.LifecycleConf{
+						Enabled: true,
+						Rules:   []cmn.LifecycleRule{{ID: "tmp", Prefix: "tmp/", ExpireDays: 7}},
+					},
+				},
+			),
 		)
 	})
+
+	Describe("Validate", func() {
+		var (
+			gcp    = cmn.Bck{Name: "name", Provider: cmn.ProviderGoogle}
+			lz4    = cmn.BckCompressionConf{Enabled: true, Algorithm: cmn.LZ4Compression}
+			govern = cmn.ObjLockConf{Enabled: true, Mode: cmn.ObjLockGovernance, RetentionDays: 7}
+			comply = cmn.ObjLockConf{Enabled: true, Mode: cmn.ObjLockCompliance, RetentionDays: 7}
+		)
+		DescribeTable("should validate bucket props",
+			func(provider string, update func(props *cmn.BucketProps), valid bool) {
+				props := cmn.DefaultBucketProps()
+				props.Provider = provider
+				update(props)
+				if valid {
+					Expect(props.Validate(1)).NotTo(HaveOccurred())
+				} else {
+					Expect(props.Validate(1)).To(HaveOccurred())
+				}
+			},
+			Entry("lifecycle: rule with no id", cmn.ProviderAIS, func(p *cmn.BucketProps) {
+				p.Lifecycle = cmn.LifecycleConf{Enabled: true, Rules: []cmn.LifecycleRule{{ExpireDays: 1}}}
+			}, false),
+			Entry("lifecycle: duplicate rule id", cmn.ProviderAIS, func(p *cmn.BucketProps) {
+				p.Lifecycle = cmn.LifecycleConf{Enabled: true,
+					Rules: []cmn.LifecycleRule{{ID: "a", ExpireDays: 1}, {ID: "a", ExpireDays: 2}}}
+			}, false),
+			Entry("lifecycle: eviction in ais bucket", cmn.ProviderAIS, func(p *cmn.BucketProps) {
+				p.Lifecycle = cmn.LifecycleConf{Enabled: true, Rules: []cmn.LifecycleRule{{ID: "a", EvictIdleTime: "72h"}}}
+			}, false),
+			Entry("lifecycle: expiration in cloud bucket", cmn.ProviderAmazon, func(p *cmn.BucketProps) {
+				p.Lifecycle = cmn.LifecycleConf{Enabled: true, Rules: []cmn.LifecycleRule{{ID: "a", ExpireDays: 7}}}
+			}, false),
+			Entry("object lock: no mode", cmn.ProviderAIS, func(p *cmn.BucketProps) {
+				p.ObjLock = cmn.ObjLockConf{Enabled: true, RetentionDays: 1}
+			}, false),
+			Entry("quota: no limits", cmn.ProviderAIS, func(p *cmn.BucketProps) {
+				p.Quota = cmn.QuotaConf{Enabled: true}
+			}, false),
+			Entry("rate limit: negative burst", cmn.ProviderAIS, func(p *cmn.BucketProps) {
+				p.RateLimit = cmn.BckRateLimitConf{Enabled: true, Rate: 10, Burst: -1}
+			}, false),
+			Entry("encryption of cloud bucket", cmn.ProviderAmazon, func(p *cmn.BucketProps) {
+				p.SSE.Enabled = true
+			}, false),
+			Entry("compression along with encryption", cmn.ProviderAIS, func(p *cmn.BucketProps) {
+				p.SSE.Enabled, p.Compression = true, lz4
+			}, false),
+			Entry("dedup of mirrored bucket", cmn.ProviderAIS, func(p *cmn.BucketProps) {
+				p.Dedup.Enabled, p.Mirror.Enabled = true, true
+			}, false),
+			Entry("replication with no remote cluster", cmn.ProviderAIS, func(p *cmn.BucketProps) {
+				p.Replication.Enabled = true
+			}, false),
+			Entry("write-back of ais bucket", cmn.ProviderAIS, func(p *cmn.BucketProps) {
+				p.WriteBack.Enabled = true
+			}, false),
+			Entry("write-back of read-only cloud bucket", cmn.ProviderHTTP, func(p *cmn.BucketProps) {
+				p.WriteBack.Enabled = true
+			}, false),
+			Entry("partial caching of ais bucket", cmn.ProviderAIS, func(p *cmn.BucketProps) {
+				p.PartialCache.Enabled = true
+			}, false),
+			Entry("partial caching of ais bucket with cloud backend", cmn.ProviderAIS, func(p *cmn.BucketProps) {
+				p.PartialCache.Enabled, p.BackendBck = true, gcp
+			}, true),
+		)
+		DescribeTable("should not weaken object lock",
+			func(from, to cmn.ObjLockConf, ok bool) {
+				err := from.ValidateUpdate(&to)
+				if ok {
+					Expect(err).NotTo(HaveOccurred())
+				} else {
+					Expect(err).To(HaveOccurred())
+				}
+			},
+			Entry("disable", govern, cmn.ObjLockConf{}, false),
+			Entry("governance => compliance", govern, comply, true),
+			Entry("compliance => governance", comply, govern, false),
+			Entry("compliance: shorten retention", comply,
+				cmn.ObjLockConf{Enabled: true, Mode: cmn.ObjLockCompliance, RetentionDays: 1}, false),
+		)
+
+		It("should resolve backend chain", func() {
+			var (
+				remais = cmn.Bck{Name: "name", Provider: cmn.ProviderAIS, Ns: cmn.Ns{UUID: "remais"}}
+				props  = cmn.DefaultBucketProps()
+				config = cmn.GCO.BeginUpdate()
+				orig   = config.Cloud.Provider
+			)
+			config.Cloud.Provider = cmn.ProviderGoogle
+			cmn.GCO.CommitUpdate(config)
+			defer func() {
+				config := cmn.GCO.BeginUpdate()
+				config.Cloud.Provider = orig
+				cmn.GCO.CommitUpdate(config)
+			}()
+
+			props.Provider = cmn.ProviderAIS
+			props.BackendChain.Bcks = []cmn.Bck{remais, gcp}
+			Expect(props.Validate(1)).NotTo(HaveOccurred())
+			Expect(props.BackendBck).To(Equal(remais))
+			Expect(props.BackendChain.WriteBcks()).To(Equal([]cmn.Bck{remais}))
+			props.BackendChain.WritePolicy = cmn.WritePolicyAll
+			Expect(props.BackendChain.WriteBcks()).To(Equal([]cmn.Bck{remais, gcp}))
+			props.BackendChain.WritePolicy = cmn.WritePolicyNone
+			Expect(props.BackendChain.WriteBcks()).To(BeEmpty())
+
+			props.BackendChain.WritePolicy = ""
+			props.BackendChain.Bcks = []cmn.Bck{gcp, gcp}
+			Expect(props.Validate(1)).To(HaveOccurred())
+		})
+	})
 })
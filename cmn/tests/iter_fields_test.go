@@ -58,6 +58,9 @@ var _ = Describe("IterFields", func() {
 					"backend_bck.name":     "name",
 					"backend_bck.provider": cmn.ProviderGoogle,
 
+					"backend_chain.bcks":         []cmn.Bck(nil),
+					"backend_chain.write_policy": "",
+
 					"mirror.enabled":      false,
 					"mirror.copies":       int64(0),
 					"mirror.util_thresh":  int64(0),
@@ -73,6 +76,7 @@ var _ = Describe("IterFields", func() {
 
 					"versioning.enabled":           false,
 					"versioning.validate_warm_get": false,
+					"versioning.history":           false,
 
 					"checksum.type":              cmn.ChecksumXXHash,
 					"checksum.validate_warm_get": false,
@@ -87,6 +91,40 @@ var _ = Describe("IterFields", func() {
 					"lru.dont_evict_time":   "",
 					"lru.capacity_upd_time": "",
 
+					"lifecycle.rules":   []cmn.LifecycleRule(nil),
+					"lifecycle.enabled": false,
+
+					"object_lock.mode":           "",
+					"object_lock.retention_days": 0,
+					"object_lock.enabled":        false,
+
+					"quota.max_bytes":   int64(0),
+					"quota.max_objects": int64(0),
+					"quota.enabled":     false,
+
+					"rate_limit.rate":    0,
+					"rate_limit.burst":   0,
+					"rate_limit.enabled": false,
+
+					"sse.enabled": false,
+
+					"compression.algorithm": "",
+					"compression.min_ratio": float64(0),
+					"compression.enabled":   false,
+
+					"dedup.enabled": false,
+
+					"replication.cluster": "",
+					"replication.bucket":  "",
+					"replication.enabled": false,
+
+					"write_back.enabled": false,
+
+					"partial_cache.chunk_size":   int64(0),
+					"partial_cache.read_ahead":   0,
+					"partial_cache.min_obj_size": int64(0),
+					"partial_cache.enabled":      false,
+
 					"access":  cmn.AccessAttrs(0),
 					"created": int64(0),
 				},
@@ -107,6 +145,9 @@ var _ = Describe("IterFields", func() {
 					"backend_bck.name":     (*string)(nil),
 					"backend_bck.provider": (*string)(nil),
 
+					"backend_chain.bcks":         (*[]cmn.Bck)(nil),
+					"backend_chain.write_policy": (*string)(nil),
+
 					"mirror.enabled":      (*bool)(nil),
 					"mirror.copies":       (*int64)(nil),
 					"mirror.util_thresh":  (*int64)(nil),
@@ -121,6 +162,7 @@ var _ = Describe("IterFields", func() {
 
 					"versioning.enabled":           (*bool)(nil),
 					"versioning.validate_warm_get": (*bool)(nil),
+					"versioning.history":           (*bool)(nil),
 
 					"checksum.type":              api.String(cmn.ChecksumXXHash),
 					"checksum.validate_warm_get": (*bool)(nil),
@@ -133,6 +175,40 @@ var _ = Describe("IterFields", func() {
 					"lru.highwm":       (*int64)(nil),
 					"lru.out_of_space": (*int64)(nil),
 
+					"lifecycle.rules":   (*[]cmn.LifecycleRule)(nil),
+					"lifecycle.enabled": (*bool)(nil),
+
+					"object_lock.mode":           (*string)(nil),
+					"object_lock.retention_days": (*int)(nil),
+					"object_lock.enabled":        (*bool)(nil),
+
+					"quota.max_bytes":   (*int64)(nil),
+					"quota.max_objects": (*int64)(nil),
+					"quota.enabled":     (*bool)(nil),
+
+					"rate_limit.rate":    (*int)(nil),
+					"rate_limit.burst":   (*int)(nil),
+					"rate_limit.enabled": (*bool)(nil),
+
+					"sse.enabled": (*bool)(nil),
+
+					"compression.algorithm": (*string)(nil),
+					"compression.min_ratio": (*float64)(nil),
+					"compression.enabled":   (*bool)(nil),
+
+					"dedup.enabled": (*bool)(nil),
+
+					"replication.cluster": (*string)(nil),
+					"replication.bucket":  (*string)(nil),
+					"replication.enabled": (*bool)(nil),
+
+					"write_back.enabled": (*bool)(nil),
+
+					"partial_cache.chunk_size":   (*int64)(nil),
+					"partial_cache.read_ahead":   (*int)(nil),
+					"partial_cache.min_obj_size": (*int64)(nil),
+					"partial_cache.enabled":      (*bool)(nil),
+
 					"access": api.AccessAttrs(1024),
 				},
 			),
@@ -216,6 +292,19 @@ var _ = Describe("IterFields", func() {
 					Access: 12,
 				},
 			),
+			Entry("update BucketProps lifecycle rules (JSON-encoded)",
+				&cmn.BucketProps{},
+				map[string]interface{}{
+					"lifecycle.enabled": "true",
+					"lifecycle.rules":   `[{"id": "tmp", "prefix": "tmp/", "expire_days": 7}]`,
+				},
+				&cmn.BucketProps{
+					Lifecycle: cmn.LifecycleConf{
+						Enabled: true,
+						Rules:   []cmn.LifecycleRule{{ID: "tmp", Prefix: "tmp/", ExpireDays: 7}},
+					},
+				},
+			),
 			Entry("update some BucketPropsToUpdate",
 				&cmn.BucketPropsToUpdate{
 					Cksum: &cmn.CksumConfToUpdate{
@@ -0,0 +1,56 @@
+// Package test provides tests for common low-level types and utilities for all aistore projects
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+func TestTokenBucket(t *testing.T) {
+	var (
+		tb  = &cmn.TokenBucket{}
+		now = time.Now()
+	)
+	// full burst is available right away
+	for i := 0; i < 5; i++ {
+		if ok, _ := tb.Take(now, 2, 5); !ok {
+			t.Fatalf("request %d: expected to be admitted", i)
+		}
+	}
+	ok, retryAfter := tb.Take(now, 2, 5)
+	if ok {
+		t.Fatal("expected to be rejected")
+	}
+	if retryAfter <= 0 || retryAfter > time.Second/2 {
+		t.Errorf("unexpected retry-after %v", retryAfter)
+	}
+	// refills at the rate
+	if ok, _ := tb.Take(now.Add(time.Second/2), 2, 5); !ok {
+		t.Error("expected to be admitted after refill")
+	}
+	if ok, _ := tb.Take(now.Add(time.Second/2), 2, 5); ok {
+		t.Error("expected to be rejected")
+	}
+	// never exceeds the burst
+	later := now.Add(time.Hour)
+	for i := 0; i < 5; i++ {
+		if ok, _ := tb.Take(later, 2, 5); !ok {
+			t.Fatalf("request %d: expected to be admitted", i)
+		}
+	}
+	if ok, _ := tb.Take(later, 2, 5); ok {
+		t.Error("expected to be rejected")
+	}
+	// zero rate - no limit
+	if ok, _ := tb.Take(later, 0, 0); !ok {
+		t.Error("expected to be admitted")
+	}
+	if !tb.Idle(later.Add(time.Second)) || tb.Idle(later) {
+		t.Error("unexpected idle state")
+	}
+}
@@ -0,0 +1,128 @@
+// Package compression provides at-rest compression of object content.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package compression
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/klauspost/compress/zstd"
+	"github.com/pierrec/lz4/v3"
+)
+
+const (
+	algoLZ4 = iota + 1
+	algoZstd
+)
+
+const lz4HashTableSize = 1 << 16
+
+type (
+	codec interface {
+		id() byte
+		// compress returns nil if the content is incompressible
+		compress(dst, src []byte) []byte
+		decompress(dst, src []byte, size int) ([]byte, error)
+	}
+	lz4Codec struct {
+		hashTable []int
+	}
+	zstdCodec struct{}
+)
+
+var (
+	zstdOnce sync.Once
+	zstdEnc  *zstd.Encoder
+	zstdDec  *zstd.Decoder
+)
+
+// interface guard
+var (
+	_ codec = &lz4Codec{}
+	_ codec = &zstdCodec{}
+)
+
+func algoID(algo string) (byte, error) {
+	switch algo {
+	case cmn.LZ4Compression:
+		return algoLZ4, nil
+	case cmn.ZstdCompression:
+		return algoZstd, nil
+	default:
+		return 0, fmt.Errorf("unsupported compression algorithm %q (expecting %q or %q)",
+			algo, cmn.LZ4Compression, cmn.ZstdCompression)
+	}
+}
+
+// returns nil if the algorithm is unknown
+func newCodec(id byte) codec {
+	switch id {
+	case algoLZ4:
+		return &lz4Codec{}
+	case algoZstd:
+		zstdOnce.Do(func() {
+			var err error
+			// both are safe for concurrent use via EncodeAll and DecodeAll
+			zstdEnc, err = zstd.NewWriter(nil)
+			cmn.AssertNoErr(err)
+			zstdDec, err = zstd.NewReader(nil)
+			cmn.AssertNoErr(err)
+		})
+		return &zstdCodec{}
+	default:
+		return nil
+	}
+}
+
+//////////////
+// lz4Codec //
+//////////////
+
+func (*lz4Codec) id() byte { return algoLZ4 }
+
+func (c *lz4Codec) compress(dst, src []byte) []byte {
+	if c.hashTable == nil {
+		c.hashTable = make([]int, lz4HashTableSize)
+	}
+	bound := lz4.CompressBlockBound(len(src))
+	if cap(dst) < bound {
+		dst = make([]byte, bound)
+	}
+	n, err := lz4.CompressBlock(src, dst[:bound], c.hashTable)
+	if err != nil || n == 0 {
+		return nil
+	}
+	return dst[:n]
+}
+
+func (*lz4Codec) decompress(dst, src []byte, size int) ([]byte, error) {
+	if cap(dst) < size {
+		dst = make([]byte, size)
+	}
+	n, err := lz4.UncompressBlock(src, dst[:size])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCorrupted, err)
+	}
+	return dst[:n], nil
+}
+
+///////////////
+// zstdCodec //
+///////////////
+
+func (*zstdCodec) id() byte { return algoZstd }
+
+func (*zstdCodec) compress(dst, src []byte) []byte {
+	return zstdEnc.EncodeAll(src, dst[:0])
+}
+
+func (*zstdCodec) decompress(dst, src []byte, _ int) ([]byte, error) {
+	b, err := zstdDec.DecodeAll(src, dst[:0])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCorrupted, err)
+	}
+	return b, nil
+}
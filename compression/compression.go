@@ -0,0 +1,352 @@
+// Package compression provides at-rest compression of object content.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package compression
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+// Compressed object is stored as a self-describing file:
+//
+//   header:  magic | algorithm (uint8) | chunk size (uint32)
+//   chunks:  independently compressed chunks of (up to) ChunkSize bytes of the original content each
+//   index:   stored size of each chunk (uint32); the most significant bit marks chunks stored as is
+//   trailer: original size (uint64) | number of chunks (uint32) | magic
+//
+// The index makes it possible to read any range of the original content by
+// decompressing only the chunks that overlap the range. Data movers copy
+// compressed files as is.
+
+const (
+	ChunkSize = 256 * 1024
+
+	magic       = "AISCMPR\x01"
+	hdrSize     = len(magic) + 1 + 4
+	trailerSize = 8 + 4 + len(magic)
+	rawChunk    = 1 << 31
+)
+
+var (
+	ErrNotCompressed = errors.New("not compressed")
+	ErrCorrupted     = errors.New("corrupted compressed content")
+)
+
+type (
+	Writer struct {
+		w        io.Writer
+		codec    codec
+		minRatio float64
+		buf      []byte // original content of the current chunk
+		cbuf     []byte
+		index    []uint32
+		size     int64
+		written  int64
+		started  bool // true: compressing
+		asis     bool // true: the object is stored uncompressed
+	}
+	Reader struct {
+		r         io.ReaderAt
+		codec     codec
+		chunkSize int64
+		size      int64   // original size
+		offs      []int64 // chunk offsets in the file (plus the offset of the index)
+		raw       []bool
+	}
+	rangeReader struct {
+		cr       *Reader
+		off, end int64
+		chunk    []byte // decompressed chunk that contains `off`
+		chunkIdx int64
+		cbuf     []byte
+	}
+)
+
+// IsSupported returns true if a given compression algorithm is supported.
+func IsSupported(algo string) bool { _, err := algoID(algo); return err == nil }
+
+////////////
+// Writer //
+////////////
+
+// NewWriter returns the writer that compresses everything written to it using
+// a given algorithm (cmn.LZ4Compression or cmn.ZstdCompression). The decision
+// whether to compress is made upon the first chunk: if it doesn't compress at
+// least `minRatio` times, the content is written as is. Close must be called
+// to flush the last chunk and write the index.
+func NewWriter(w io.Writer, algo string, minRatio float64) (*Writer, error) {
+	id, err := algoID(algo)
+	if err != nil {
+		return nil, err
+	}
+	if minRatio < 1 {
+		minRatio = 1
+	}
+	cw := &Writer{
+		w:        w,
+		codec:    newCodec(id),
+		minRatio: minRatio,
+		buf:      make([]byte, 0, ChunkSize),
+	}
+	return cw, nil
+}
+
+func (cw *Writer) write(b []byte) error {
+	n, err := cw.w.Write(b)
+	cw.written += int64(n)
+	return err
+}
+
+// returns true if compressed chunk `c` is worth storing
+func (cw *Writer) worth(c []byte) bool {
+	return c != nil && float64(len(cw.buf)) >= cw.minRatio*float64(len(c))
+}
+
+func (cw *Writer) flush() (err error) {
+	if len(cw.buf) == 0 {
+		return
+	}
+	c := cw.codec.compress(cw.cbuf[:0], cw.buf)
+	if c != nil {
+		cw.cbuf = c
+	}
+	if !cw.started {
+		if !cw.worth(c) {
+			cw.asis = true
+			err = cw.write(cw.buf)
+			cw.buf = cw.buf[:0]
+			return
+		}
+		hdr := make([]byte, hdrSize)
+		copy(hdr, magic)
+		hdr[len(magic)] = cw.codec.id()
+		binary.BigEndian.PutUint32(hdr[len(magic)+1:], ChunkSize)
+		if err = cw.write(hdr); err != nil {
+			return
+		}
+		cw.started = true
+	}
+	if cw.worth(c) {
+		cw.index = append(cw.index, uint32(len(c)))
+		err = cw.write(c)
+	} else {
+		cw.index = append(cw.index, uint32(len(cw.buf))|rawChunk)
+		err = cw.write(cw.buf)
+	}
+	cw.buf = cw.buf[:0]
+	return
+}
+
+func (cw *Writer) Write(p []byte) (n int, err error) {
+	if cw.asis {
+		n, err = cw.w.Write(p)
+		cw.written += int64(n)
+		cw.size += int64(n)
+		return
+	}
+	for len(p) > 0 {
+		l := cmn.Min(ChunkSize-len(cw.buf), len(p))
+		cw.buf = append(cw.buf, p[:l]...)
+		p = p[l:]
+		n += l
+		cw.size += int64(l)
+		if len(cw.buf) == ChunkSize {
+			if err = cw.flush(); err != nil {
+				return
+			}
+			if cw.asis && len(p) > 0 {
+				var m int
+				m, err = cw.Write(p)
+				n += m
+				return
+			}
+		}
+	}
+	return
+}
+
+// Close flushes the last chunk and writes the index; it does not close the
+// underlying writer.
+func (cw *Writer) Close() (err error) {
+	if err = cw.flush(); err != nil || !cw.started {
+		return
+	}
+	b := make([]byte, 4*len(cw.index)+trailerSize)
+	for i, l := range cw.index {
+		binary.BigEndian.PutUint32(b[4*i:], l)
+	}
+	t := b[4*len(cw.index):]
+	binary.BigEndian.PutUint64(t, uint64(cw.size))
+	binary.BigEndian.PutUint32(t[8:], uint32(len(cw.index)))
+	copy(t[12:], magic)
+	return cw.write(b)
+}
+
+// Compressed returns false if the content was written as is.
+func (cw *Writer) Compressed() bool { return cw.started }
+
+// Size returns the original (uncompressed) size.
+func (cw *Writer) Size() int64 { return cw.size }
+
+// Written returns the number of bytes written to the underlying writer.
+func (cw *Writer) Written() int64 { return cw.written }
+
+////////////
+// Reader //
+////////////
+
+// IsCompressed returns true if the content starts with the compression header.
+// NOTE: not to tell whether a stored object is compressed - plain content may
+// start with the same bytes; the object metadata is the source of truth
+// (see cluster.LOM.Compressed).
+func IsCompressed(r io.ReaderAt) bool {
+	b := make([]byte, len(magic))
+	if _, err := r.ReadAt(b, 0); err != nil {
+		return false
+	}
+	return bytes.Equal(b, []byte(magic))
+}
+
+func readTrailer(r io.ReaderAt, fsize int64) (size int64, nchunks int, err error) {
+	if fsize < int64(hdrSize+trailerSize) {
+		return 0, 0, ErrNotCompressed
+	}
+	t := make([]byte, trailerSize)
+	if _, err = r.ReadAt(t, fsize-int64(trailerSize)); err != nil && err != io.EOF {
+		return
+	}
+	if !bytes.Equal(t[12:], []byte(magic)) {
+		return 0, 0, ErrCorrupted
+	}
+	size = int64(binary.BigEndian.Uint64(t))
+	nchunks = int(binary.BigEndian.Uint32(t[8:]))
+	return size, nchunks, nil
+}
+
+// OrigSize returns the original size given the compressed content and its size.
+func OrigSize(r io.ReaderAt, fsize int64) (int64, error) {
+	if !IsCompressed(r) {
+		return 0, ErrNotCompressed
+	}
+	size, _, err := readTrailer(r, fsize)
+	return size, err
+}
+
+// NewReader parses the header, the index, and the trailer of the compressed
+// content of a given (compressed) size.
+func NewReader(r io.ReaderAt, fsize int64) (*Reader, error) {
+	hdr := make([]byte, hdrSize)
+	if _, err := r.ReadAt(hdr, 0); err != nil || !bytes.Equal(hdr[:len(magic)], []byte(magic)) {
+		return nil, ErrNotCompressed
+	}
+	codec := newCodec(hdr[len(magic)])
+	if codec == nil {
+		return nil, fmt.Errorf("%w: unknown algorithm %d", ErrCorrupted, hdr[len(magic)])
+	}
+	size, nchunks, err := readTrailer(r, fsize)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		chunkSize = int64(binary.BigEndian.Uint32(hdr[len(magic)+1:]))
+		indexOff  = fsize - int64(trailerSize) - 4*int64(nchunks)
+	)
+	if chunkSize == 0 || nchunks == 0 || indexOff < int64(hdrSize) ||
+		size <= int64(nchunks-1)*chunkSize || size > int64(nchunks)*chunkSize {
+		return nil, ErrCorrupted
+	}
+	index := make([]byte, 4*nchunks)
+	if _, err := r.ReadAt(index, indexOff); err != nil {
+		return nil, err
+	}
+	cr := &Reader{
+		r:         r,
+		codec:     codec,
+		chunkSize: chunkSize,
+		size:      size,
+		offs:      make([]int64, nchunks+1),
+		raw:       make([]bool, nchunks),
+	}
+	off := int64(hdrSize)
+	for i := 0; i < nchunks; i++ {
+		l := binary.BigEndian.Uint32(index[4*i:])
+		cr.offs[i], cr.raw[i] = off, l&rawChunk != 0
+		off += int64(l &^ rawChunk)
+	}
+	if cr.offs[nchunks] = off; off != indexOff {
+		return nil, ErrCorrupted
+	}
+	return cr, nil
+}
+
+// Size returns the original (uncompressed) size.
+func (cr *Reader) Size() int64 { return cr.size }
+
+// NewRangeReader returns the reader of a given range of the original content.
+func (cr *Reader) NewRangeReader(off, length int64) io.Reader {
+	return &rangeReader{cr: cr, off: off, end: off + length, chunkIdx: -1}
+}
+
+func (cr *Reader) readChunk(idx int64, cbuf, dst []byte) (chunk, cb []byte, err error) {
+	var (
+		off  = cr.offs[idx]
+		l    = cr.offs[idx+1] - off
+		size = cmn.MinI64(cr.chunkSize, cr.size-idx*cr.chunkSize)
+	)
+	if int64(cap(cbuf)) < l {
+		cbuf = make([]byte, l)
+	}
+	cb = cbuf[:l]
+	if _, err = cr.r.ReadAt(cb, off); err != nil && err != io.EOF {
+		return
+	}
+	if cr.raw[idx] {
+		chunk = append(dst[:0], cb...)
+	} else {
+		chunk, err = cr.codec.decompress(dst[:0], cb, int(size))
+	}
+	if err == nil && int64(len(chunk)) != size {
+		err = fmt.Errorf("%w: chunk %d", ErrCorrupted, idx)
+	}
+	return
+}
+
+func (rr *rangeReader) Read(p []byte) (n int, err error) {
+	cr := rr.cr
+	for n < len(p) && rr.off < rr.end {
+		idx := rr.off / cr.chunkSize
+		if idx != rr.chunkIdx {
+			if rr.chunk == nil {
+				rr.chunk = make([]byte, 0, cr.chunkSize)
+			}
+			if rr.chunk, rr.cbuf, err = cr.readChunk(idx, rr.cbuf, rr.chunk); err != nil {
+				return
+			}
+			rr.chunkIdx = idx
+		}
+		var (
+			start = int(rr.off - idx*cr.chunkSize)
+			l     = cmn.Min(len(rr.chunk)-start, len(p)-n)
+		)
+		if rem := rr.end - rr.off; int64(l) > rem {
+			l = int(rem)
+		}
+		if l <= 0 {
+			return n, ErrCorrupted
+		}
+		copy(p[n:], rr.chunk[start:start+l])
+		n += l
+		rr.off += int64(l)
+	}
+	if rr.off >= rr.end {
+		err = io.EOF
+	}
+	return
+}
@@ -0,0 +1,119 @@
+// Package compression provides at-rest compression of object content.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package compression
+
+import (
+	"bytes"
+	"crypto/rand"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+func compress(t *testing.T, content []byte, algo string, minRatio float64) ([]byte, *Writer) {
+	out := &bytes.Buffer{}
+	w, err := NewWriter(out, algo, minRatio)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// write in pieces that are not aligned with chunks
+	for b := content; len(b) > 0; {
+		n := cmn.Min(10000, len(b))
+		if _, err = w.Write(b[:n]); err != nil {
+			t.Fatal(err)
+		}
+		b = b[n:]
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if w.Written() != int64(out.Len()) || w.Size() != int64(len(content)) {
+		t.Fatalf("written %d (%d), size %d (%d)", w.Written(), out.Len(), w.Size(), len(content))
+	}
+	return out.Bytes(), w
+}
+
+func text(size int) []byte {
+	return []byte(strings.Repeat(`{"key": "value", "number": 12345, "list": [1, 2, 3]}`+"\n", size/52+1)[:size])
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, algo := range []string{cmn.LZ4Compression, cmn.ZstdCompression} {
+		for _, size := range []int{1, 1000, ChunkSize, ChunkSize + 1, 3*ChunkSize + 777} {
+			content := text(size)
+			b, w := compress(t, content, algo, 1.2)
+			if size < 100 {
+				if w.Compressed() || !bytes.Equal(b, content) {
+					t.Fatalf("%s, size %d: expected to be stored as is", algo, size)
+				}
+				continue
+			}
+			if !w.Compressed() || !IsCompressed(bytes.NewReader(b)) || len(b) >= size {
+				t.Fatalf("%s, size %d: expected to be compressed (%d)", algo, size, len(b))
+			}
+			if orig, err := OrigSize(bytes.NewReader(b), int64(len(b))); err != nil || orig != int64(size) {
+				t.Fatalf("%s, size %d: orig size %d, err %v", algo, size, orig, err)
+			}
+			r, err := NewReader(bytes.NewReader(b), int64(len(b)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			dec, err := ioutil.ReadAll(r.NewRangeReader(0, r.Size()))
+			if err != nil {
+				t.Fatalf("%s, size %d: %v", algo, size, err)
+			}
+			if !bytes.Equal(dec, content) {
+				t.Fatalf("%s, size %d: decompressed content differs", algo, size)
+			}
+		}
+	}
+}
+
+func TestRange(t *testing.T) {
+	content := text(4*ChunkSize + 100)
+	// incompressible chunk in the middle is stored as is
+	rand.Read(content[ChunkSize : 2*ChunkSize])
+	b, _ := compress(t, content, cmn.ZstdCompression, 1.5)
+	r, err := NewReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !r.raw[1] || r.raw[0] || r.raw[2] {
+		t.Fatalf("unexpected raw chunks %v", r.raw)
+	}
+	tests := []struct{ off, length int64 }{
+		{0, 1},
+		{10, 100},
+		{ChunkSize - 10, 20},
+		{ChunkSize, ChunkSize},
+		{ChunkSize / 2, 3 * ChunkSize},
+		{int64(len(content)) - 1, 1},
+	}
+	for _, test := range tests {
+		dec, err := ioutil.ReadAll(r.NewRangeReader(test.off, test.length))
+		if err != nil {
+			t.Fatalf("range %v: %v", test, err)
+		}
+		if !bytes.Equal(dec, content[test.off:test.off+test.length]) {
+			t.Fatalf("range %v: decompressed content differs", test)
+		}
+	}
+}
+
+func TestMinRatio(t *testing.T) {
+	content := make([]byte, 2*ChunkSize)
+	rand.Read(content)
+	for _, algo := range []string{cmn.LZ4Compression, cmn.ZstdCompression} {
+		b, w := compress(t, content, algo, 1.1)
+		if w.Compressed() || IsCompressed(bytes.NewReader(b)) || !bytes.Equal(b, content) {
+			t.Fatalf("%s: incompressible content expected to be stored as is", algo)
+		}
+	}
+	if _, err := NewWriter(ioutil.Discard, "gzip", 0); err == nil {
+		t.Error("expected unsupported algorithm error")
+	}
+}
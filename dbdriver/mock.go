@@ -7,12 +7,14 @@ package dbdriver
 import (
 	"sort"
 	"strings"
+	"sync"
 
 	"github.com/NVIDIA/aistore/cmn"
 	jsoniter "github.com/json-iterator/go"
 )
 
 type DBMock struct {
+	mtx    sync.Mutex
 	values map[string]string
 }
 
@@ -47,13 +49,17 @@ func (bd *DBMock) Get(collection, key string, object interface{}) error {
 
 func (bd *DBMock) SetString(collection, key, data string) error {
 	name := bd.makePath(collection, key)
+	bd.mtx.Lock()
 	bd.values[name] = data
+	bd.mtx.Unlock()
 	return nil
 }
 
 func (bd *DBMock) GetString(collection, key string) (string, error) {
 	name := bd.makePath(collection, key)
+	bd.mtx.Lock()
 	value, ok := bd.values[name]
+	bd.mtx.Unlock()
 	if !ok {
 		return "", NewErrNotFound(collection, key)
 	}
@@ -62,6 +68,8 @@ func (bd *DBMock) GetString(collection, key string) (string, error) {
 
 func (bd *DBMock) Delete(collection, key string) error {
 	name := bd.makePath(collection, key)
+	bd.mtx.Lock()
+	defer bd.mtx.Unlock()
 	_, ok := bd.values[name]
 	if !ok {
 		return NewErrNotFound(collection, key)
@@ -76,6 +84,8 @@ func (bd *DBMock) List(collection, pattern string) ([]string, error) {
 		filter string
 	)
 	filter = bd.makePath(collection, pattern)
+	bd.mtx.Lock()
+	defer bd.mtx.Unlock()
 	for k := range bd.values {
 		if strings.HasPrefix(k, filter) {
 			_, key := parsePath(k)
@@ -93,9 +103,11 @@ func (bd *DBMock) DeleteCollection(collection string) error {
 	if err != nil || len(keys) == 0 {
 		return err
 	}
+	bd.mtx.Lock()
 	for _, k := range keys {
 		delete(bd.values, k)
 	}
+	bd.mtx.Unlock()
 	return nil
 }
 
@@ -105,6 +117,8 @@ func (bd *DBMock) GetAll(collection, pattern string) (map[string]string, error)
 		filter string
 	)
 	filter = bd.makePath(collection, pattern)
+	bd.mtx.Lock()
+	defer bd.mtx.Unlock()
 	for k, v := range bd.values {
 		if strings.HasPrefix(k, filter) {
 			_, key := parsePath(k)
@@ -0,0 +1,58 @@
+// Package dedup provides content-defined deduplication of object content at rest.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package dedup
+
+import (
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+// Content-defined chunking: chunk boundaries are determined by the content
+// itself (via the "gear" rolling hash) rather than by offsets, so that
+// inserting or removing bytes affects only the chunks around the change.
+// A boundary is declared once the top `cutBits` bits of the hash are all
+// zeros, which yields chunks of MinChunkSize + 64KiB on average.
+
+const (
+	MinChunkSize = 16 * cmn.KiB
+	MaxChunkSize = 256 * cmn.KiB
+
+	cutBits = 16
+	cutMask = uint64(1<<cutBits-1) << (64 - cutBits)
+)
+
+// NOTE: must never change - otherwise, the same content will be chunked differently
+var gear [256]uint64
+
+func init() {
+	// splitmix64 with a fixed seed
+	seed := uint64(0x6a09e667f3bcc908)
+	for i := range gear {
+		seed += 0x9e3779b97f4a7c15
+		z := seed
+		z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+		z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+		gear[i] = z ^ (z >> 31)
+	}
+}
+
+// cut returns the size of the first chunk of b; unless b is the tail of the
+// content, it must contain at least MaxChunkSize bytes
+func cut(b []byte) int {
+	n := len(b)
+	if n <= MinChunkSize {
+		return n
+	}
+	if n > MaxChunkSize {
+		n = MaxChunkSize
+	}
+	var fp uint64
+	for i := MinChunkSize; i < n; i++ {
+		fp = fp<<1 + gear[b[i]]
+		if fp&cutMask == 0 {
+			return i + 1
+		}
+	}
+	return n
+}
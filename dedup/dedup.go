@@ -0,0 +1,163 @@
+// Package dedup provides content-defined deduplication of object content at rest.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package dedup
+
+import (
+	"crypto/sha256"
+	"io"
+	"sort"
+)
+
+type (
+	Writer struct {
+		w       io.Writer
+		buf     []byte // content that is yet to be chunked
+		recipe  Recipe
+		stored  int64
+		written int64
+	}
+	Reader struct {
+		recipe *Recipe
+		offs   []int64 // offsets of the chunks in the original content (plus the size)
+	}
+	rangeReader struct {
+		dr       *Reader
+		off, end int64
+		chunk    []byte // content of the chunk that contains `off`
+		chunkIdx int
+	}
+	handle struct {
+		io.Reader
+		recipe *Recipe
+	}
+)
+
+////////////
+// Writer //
+////////////
+
+// NewWriter returns the writer that splits everything written to it into
+// chunks and stores the chunks in the chunk store. Close must be called to
+// store the last chunk and write the recipe to w; on error, Abort releases
+// the chunks stored so far.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w, buf: make([]byte, 0, 2*MaxChunkSize)}
+}
+
+func (dw *Writer) Write(p []byte) (n int, err error) {
+	for len(p) > 0 {
+		l := cap(dw.buf) - len(dw.buf)
+		if l > len(p) {
+			l = len(p)
+		}
+		dw.buf = append(dw.buf, p[:l]...)
+		p = p[l:]
+		n += l
+		for len(dw.buf) >= MaxChunkSize {
+			if err = dw.flush(); err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+
+func (dw *Writer) flush() error {
+	var (
+		l = cut(dw.buf)
+		h = Hash(sha256.Sum256(dw.buf[:l]))
+	)
+	stored, err := putChunk(&h, dw.buf[:l])
+	if err != nil {
+		return err
+	}
+	if stored {
+		dw.stored += int64(l)
+	}
+	dw.recipe.add(h, int64(l))
+	dw.buf = append(dw.buf[:0], dw.buf[l:]...)
+	return nil
+}
+
+// Close stores the remaining content and writes the recipe; it does not close
+// the underlying writer.
+func (dw *Writer) Close() error {
+	for len(dw.buf) > 0 {
+		if err := dw.flush(); err != nil {
+			return err
+		}
+	}
+	n, err := dw.w.Write(dw.recipe.marshal())
+	dw.written = int64(n)
+	return err
+}
+
+// Abort releases all the chunks referenced by the content written so far.
+func (dw *Writer) Abort() {
+	dw.recipe.Release()
+	dw.recipe = Recipe{}
+}
+
+// Recipe returns the recipe of the content written so far.
+func (dw *Writer) Recipe() *Recipe { return &dw.recipe }
+
+// Size returns the original size.
+func (dw *Writer) Size() int64 { return dw.recipe.size }
+
+// Stored returns the number of bytes of the chunks that were not stored before.
+func (dw *Writer) Stored() int64 { return dw.stored }
+
+// Written returns the size of the recipe written to the underlying writer.
+func (dw *Writer) Written() int64 { return dw.written }
+
+////////////
+// Reader //
+////////////
+
+// Size returns the original size.
+func (dr *Reader) Size() int64 { return dr.recipe.size }
+
+// NewRangeReader returns the reader of a given range of the original content.
+func (dr *Reader) NewRangeReader(off, length int64) io.Reader {
+	return &rangeReader{dr: dr, off: off, end: off + length, chunkIdx: -1}
+}
+
+func (rr *rangeReader) Read(p []byte) (n int, err error) {
+	dr := rr.dr
+	for n < len(p) && rr.off < rr.end {
+		idx := sort.Search(len(dr.recipe.Chunks), func(i int) bool { return dr.offs[i+1] > rr.off })
+		if idx >= len(dr.recipe.Chunks) {
+			return n, ErrCorrupted
+		}
+		if idx != rr.chunkIdx {
+			c := &dr.recipe.Chunks[idx]
+			if rr.chunk, err = readChunk(&c.Hash, c.Size, rr.chunk); err != nil {
+				return
+			}
+			rr.chunkIdx = idx
+		}
+		var (
+			start = rr.off - dr.offs[idx]
+			l     = int64(len(rr.chunk)) - start
+		)
+		if rem := rr.end - rr.off; l > rem {
+			l = rem
+		}
+		m := copy(p[n:], rr.chunk[start:start+l])
+		n += m
+		rr.off += int64(m)
+	}
+	if rr.off >= rr.end {
+		err = io.EOF
+	}
+	return
+}
+
+////////////
+// handle //
+////////////
+
+func (h *handle) Open() (io.ReadCloser, error) { return h.recipe.NewHandle(), nil }
+func (*handle) Close() error                   { return nil }
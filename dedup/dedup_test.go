@@ -0,0 +1,165 @@
+// Package dedup provides content-defined deduplication of object content at rest.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package dedup
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/fs"
+)
+
+func initTestFS(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dedup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	config := cmn.GCO.BeginUpdate()
+	config.TestFSP.Count = 1
+	cmn.GCO.CommitUpdate(config)
+
+	fs.Init()
+	fs.DisableFsIDCheck()
+	for _, name := range []string{"mp1", "mp2"} {
+		mpath := filepath.Join(dir, name)
+		if err := cmn.CreateDir(mpath); err != nil {
+			t.Fatal(err)
+		}
+		if err := fs.Add(mpath); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func dedup(t *testing.T, content []byte) (*Recipe, int64) {
+	out := &bytes.Buffer{}
+	w := NewWriter(out)
+	if _, err := w.Write(content); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if w.Written() != int64(out.Len()) || w.Size() != int64(len(content)) {
+		t.Fatalf("written %d (%d), size %d (%d)", w.Written(), out.Len(), w.Size(), len(content))
+	}
+	recipe, err := ParseRecipe(bytes.NewReader(out.Bytes()), int64(out.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return recipe, w.Stored()
+}
+
+func read(t *testing.T, recipe *Recipe, off, length int64) []byte {
+	b, err := ioutil.ReadAll(recipe.NewReader().NewRangeReader(off, length))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestRoundTrip(t *testing.T) {
+	initTestFS(t)
+	for _, size := range []int{0, 1, MinChunkSize, MaxChunkSize, MaxChunkSize + 1, 5*MaxChunkSize + 777} {
+		content := make([]byte, size)
+		rand.Read(content)
+		recipe, stored := dedup(t, content)
+		if stored != int64(size) {
+			t.Fatalf("size %d: stored %d", size, stored)
+		}
+		for _, c := range recipe.Chunks {
+			if c.Size > MaxChunkSize {
+				t.Fatalf("size %d: chunk size %d", size, c.Size)
+			}
+		}
+		if b := read(t, recipe, 0, recipe.Size()); !bytes.Equal(b, content) {
+			t.Fatalf("size %d: content differs", size)
+		}
+		recipe.Release()
+	}
+}
+
+func TestSharedChunks(t *testing.T) {
+	initTestFS(t)
+	content := make([]byte, 4*cmn.MiB)
+	rand.Read(content)
+	recipe, stored := dedup(t, content)
+	if stored != int64(len(content)) {
+		t.Fatalf("stored %d", stored)
+	}
+	// same content shifted by a few bytes shares all but the first chunk or two
+	shifted := append([]byte("prefix"), content...)
+	recipe2, stored2 := dedup(t, shifted)
+	if stored2 > 2*MaxChunkSize {
+		t.Fatalf("shifted: stored %d", stored2)
+	}
+	if b := read(t, recipe2, 0, recipe2.Size()); !bytes.Equal(b, shifted) {
+		t.Fatal("shifted: content differs")
+	}
+	// released chunks remain readable while referenced
+	recipe.Release()
+	if b := read(t, recipe2, 0, recipe2.Size()); !bytes.Equal(b, shifted) {
+		t.Fatal("shifted: content differs after release")
+	}
+	recipe2.Release()
+	for _, c := range recipe2.Chunks {
+		if fqn, _ := lookup(&c.Hash); fqn != "" {
+			t.Fatalf("chunk %s is not removed", c.Hash.String())
+		}
+	}
+}
+
+func TestAddRef(t *testing.T) {
+	initTestFS(t)
+	content := make([]byte, MaxChunkSize*3)
+	rand.Read(content)
+	recipe, _ := dedup(t, content)
+	if err := recipe.AddRef(); err != nil {
+		t.Fatal(err)
+	}
+	recipe.Release()
+	if b := read(t, recipe, 0, recipe.Size()); !bytes.Equal(b, content) {
+		t.Fatal("content differs")
+	}
+	recipe.Release()
+	if _, err := ioutil.ReadAll(recipe.NewReader().NewRangeReader(0, recipe.Size())); err == nil {
+		t.Fatal("expected chunks to be removed")
+	}
+}
+
+func TestRangeRead(t *testing.T) {
+	initTestFS(t)
+	content := make([]byte, 3*MaxChunkSize+12345)
+	rand.Read(content)
+	recipe, _ := dedup(t, content)
+	defer recipe.Release()
+	for _, r := range [][2]int64{{0, 1}, {100, 0}, {MinChunkSize - 10, 20}, {MaxChunkSize - 1, MaxChunkSize + 2}, {int64(len(content)) - 5, 5}} {
+		if b := read(t, recipe, r[0], r[1]); !bytes.Equal(b, content[r[0]:r[0]+r[1]]) {
+			t.Fatalf("range %v: content differs", r)
+		}
+	}
+}
+
+func TestParseRecipe(t *testing.T) {
+	if _, err := ParseRecipe(bytes.NewReader([]byte("not a recipe at all")), 19); err != ErrNotRecipe {
+		t.Fatalf("expected %v, got %v", ErrNotRecipe, err)
+	}
+	recipe := &Recipe{}
+	recipe.add(Hash{1}, 100)
+	b := recipe.marshal()
+	if _, err := ParseRecipe(bytes.NewReader(b[:len(b)-1]), int64(len(b)-1)); err != ErrCorrupted {
+		t.Fatalf("expected %v, got %v", ErrCorrupted, err)
+	}
+	parsed, err := ParseRecipe(bytes.NewReader(b), int64(len(b)))
+	if err != nil || parsed.Size() != 100 || len(parsed.Chunks) != 1 || parsed.Chunks[0].Hash != recipe.Chunks[0].Hash {
+		t.Fatalf("parsed %v, err %v", parsed, err)
+	}
+}
@@ -0,0 +1,173 @@
+// Package dedup provides content-defined deduplication of object content at rest.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package dedup
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/debug"
+)
+
+// Deduplicated object is stored as a recipe - the list of its chunks:
+//
+//   header: magic | original size (uint64) | number of chunks (uint32)
+//   chunks: SHA-256 of the chunk's content | chunk size (uint32)
+//
+// The chunks themselves are stored in the per-target chunk store (see store.go).
+
+const (
+	magic     = "AISDDUP\x01"
+	hdrSize   = len(magic) + 8 + 4
+	entrySize = sha256.Size + 4
+)
+
+var (
+	ErrNotRecipe = errors.New("not a dedup recipe")
+	ErrCorrupted = errors.New("corrupted dedup recipe")
+)
+
+type (
+	Hash  [sha256.Size]byte
+	Chunk struct {
+		Hash Hash
+		Size int64
+	}
+	Recipe struct {
+		Chunks []Chunk
+		size   int64
+	}
+)
+
+func (h *Hash) String() string { return hex.EncodeToString(h[:]) }
+
+// Size returns the original size of the content.
+func (r *Recipe) Size() int64 { return r.size }
+
+func (r *Recipe) add(h Hash, size int64) {
+	r.Chunks = append(r.Chunks, Chunk{Hash: h, Size: size})
+	r.size += size
+}
+
+func (r *Recipe) marshal() []byte {
+	b := make([]byte, hdrSize+entrySize*len(r.Chunks))
+	copy(b, magic)
+	binary.BigEndian.PutUint64(b[len(magic):], uint64(r.size))
+	binary.BigEndian.PutUint32(b[len(magic)+8:], uint32(len(r.Chunks)))
+	off := hdrSize
+	for _, c := range r.Chunks {
+		copy(b[off:], c.Hash[:])
+		binary.BigEndian.PutUint32(b[off+sha256.Size:], uint32(c.Size))
+		off += entrySize
+	}
+	return b
+}
+
+// ParseRecipe parses the recipe given its content and size.
+func ParseRecipe(r io.ReaderAt, fsize int64) (*Recipe, error) {
+	if fsize < int64(hdrSize) {
+		return nil, ErrNotRecipe
+	}
+	hdr := make([]byte, hdrSize)
+	if _, err := r.ReadAt(hdr, 0); err != nil && err != io.EOF {
+		return nil, err
+	}
+	if !bytes.Equal(hdr[:len(magic)], []byte(magic)) {
+		return nil, ErrNotRecipe
+	}
+	var (
+		size    = int64(binary.BigEndian.Uint64(hdr[len(magic):]))
+		nchunks = int64(binary.BigEndian.Uint32(hdr[len(magic)+8:]))
+	)
+	if fsize != int64(hdrSize)+nchunks*int64(entrySize) {
+		return nil, ErrCorrupted
+	}
+	b := make([]byte, fsize-int64(hdrSize))
+	if _, err := r.ReadAt(b, int64(hdrSize)); err != nil && err != io.EOF {
+		return nil, err
+	}
+	recipe := &Recipe{Chunks: make([]Chunk, 0, nchunks)}
+	for off := 0; off < len(b); off += entrySize {
+		var h Hash
+		copy(h[:], b[off:])
+		csize := int64(binary.BigEndian.Uint32(b[off+sha256.Size:]))
+		if csize == 0 || csize > MaxChunkSize {
+			return nil, ErrCorrupted
+		}
+		recipe.add(h, csize)
+	}
+	if recipe.size != size {
+		return nil, ErrCorrupted
+	}
+	return recipe, nil
+}
+
+// ReadRecipe reads and parses the recipe stored in a given file.
+func ReadRecipe(fqn string) (*Recipe, error) {
+	file, err := os.Open(fqn)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { debug.AssertNoErr(file.Close()) }()
+	finfo, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	return ParseRecipe(file, finfo.Size())
+}
+
+// AddRef increments the reference counts of all the chunks, e.g. when the
+// recipe gets copied to represent another object.
+func (r *Recipe) AddRef() (err error) {
+	for i := range r.Chunks {
+		if err = addRef(&r.Chunks[i].Hash, 1); err != nil {
+			for j := 0; j < i; j++ {
+				if errRel := addRef(&r.Chunks[j].Hash, -1); errRel != nil {
+					glog.Errorf("nested err: %v", errRel)
+				}
+			}
+			return
+		}
+	}
+	return
+}
+
+// Release decrements the reference counts of all the chunks and removes the
+// chunks that are not referenced anymore.
+func (r *Recipe) Release() {
+	for i := range r.Chunks {
+		if err := addRef(&r.Chunks[i].Hash, -1); err != nil {
+			glog.Errorf("failed to release dedup chunk %s: %v", r.Chunks[i].Hash.String(), err)
+		}
+	}
+}
+
+// NewReader returns the reader of the original content.
+func (r *Recipe) NewReader() *Reader {
+	dr := &Reader{recipe: r, offs: make([]int64, len(r.Chunks)+1)}
+	for i, c := range r.Chunks {
+		dr.offs[i+1] = dr.offs[i] + c.Size
+	}
+	return dr
+}
+
+// NewHandle returns the (re-openable) reader of the original content - to
+// send the latter to other nodes.
+func (r *Recipe) NewHandle() cmn.ReadOpenCloser {
+	dr := r.NewReader()
+	return &handle{Reader: dr.NewRangeReader(0, dr.Size()), recipe: r}
+}
+
+func (r *Recipe) String() string {
+	return fmt.Sprintf("recipe[size %d, chunks %d]", r.size, len(r.Chunks))
+}
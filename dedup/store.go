@@ -0,0 +1,190 @@
+// Package dedup provides content-defined deduplication of object content at rest.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package dedup
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/debug"
+	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/xoshiro256"
+)
+
+// Chunk store: each chunk is stored once per target in the file named after
+// its hash, under fs.DedupDir of the mountpath selected by HRW. The number of
+// recipes that reference the chunk is kept in the chunk's xattr; the chunk is
+// removed when the count drops to zero.
+
+const xattrRefc = "user.ais.dedup.refc"
+
+var (
+	ErrChunkNotFound = errors.New("dedup chunk not found")
+
+	locks [256]sync.Mutex // protects reference counts; indexed by the first byte of the hash
+)
+
+func chunkFQN(mi *fs.MountpathInfo, h *Hash) string {
+	name := h.String()
+	return filepath.Join(mi.MakePathDedup(), name[:2], name)
+}
+
+func hrwMpath(h *Hash) (mi *fs.MountpathInfo, err error) {
+	var (
+		max               uint64
+		digest            = binary.BigEndian.Uint64(h[:8])
+		availablePaths, _ = fs.Get()
+	)
+	if len(availablePaths) == 0 {
+		return nil, errors.New(cmn.NoMountpaths)
+	}
+	for _, mpathInfo := range availablePaths {
+		cs := xoshiro256.Hash(mpathInfo.PathDigest ^ digest)
+		if cs >= max {
+			max = cs
+			mi = mpathInfo
+		}
+	}
+	return
+}
+
+// lookup returns the FQN of the stored chunk or empty string if the chunk is not stored
+func lookup(h *Hash) (string, error) {
+	mi, err := hrwMpath(h)
+	if err != nil {
+		return "", err
+	}
+	fqn := chunkFQN(mi, h)
+	if err := fs.Access(fqn); err == nil {
+		return fqn, nil
+	}
+	// mountpaths may have changed since the chunk was stored
+	availablePaths, _ := fs.Get()
+	for _, mpathInfo := range availablePaths {
+		if mpathInfo == mi {
+			continue
+		}
+		fqn := chunkFQN(mpathInfo, h)
+		if err := fs.Access(fqn); err == nil {
+			return fqn, nil
+		}
+	}
+	return "", nil
+}
+
+func getRefc(fqn string) (int64, error) {
+	b, err := fs.GetXattr(fqn, xattrRefc)
+	if err != nil {
+		return 0, err
+	}
+	if len(b) != 8 {
+		return 0, fmt.Errorf("%s: invalid reference count", fqn)
+	}
+	return int64(binary.BigEndian.Uint64(b)), nil
+}
+
+func setRefc(fqn string, refc int64) error {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, uint64(refc))
+	return fs.SetXattr(fqn, xattrRefc, b)
+}
+
+// putChunk stores the chunk unless it is already stored, and increments its
+// reference count; returns true if the chunk was stored
+func putChunk(h *Hash, b []byte) (stored bool, err error) {
+	mu := &locks[h[0]]
+	mu.Lock()
+	defer mu.Unlock()
+	fqn, err := lookup(h)
+	if err != nil {
+		return
+	}
+	if fqn != "" {
+		refc, err := getRefc(fqn)
+		if err != nil {
+			return false, err
+		}
+		return false, setRefc(fqn, refc+1)
+	}
+	mi, err := hrwMpath(h)
+	if err != nil {
+		return
+	}
+	fqn = chunkFQN(mi, h)
+	tmpFQN := fqn + ".tmp"
+	file, err := cmn.CreateFile(tmpFQN)
+	if err != nil {
+		return
+	}
+	if _, err = file.Write(b); err == nil {
+		err = setRefc(tmpFQN, 1)
+	}
+	if errClose := file.Close(); err == nil {
+		err = errClose
+	}
+	if err == nil {
+		err = os.Rename(tmpFQN, fqn)
+	}
+	if err != nil {
+		if errRemove := cmn.RemoveFile(tmpFQN); errRemove != nil {
+			err = fmt.Errorf("%v (nested: %v)", err, errRemove)
+		}
+		return
+	}
+	return true, nil
+}
+
+// addRef changes the reference count of the stored chunk by a given delta and
+// removes the chunk if the count drops to zero
+func addRef(h *Hash, delta int64) error {
+	mu := &locks[h[0]]
+	mu.Lock()
+	defer mu.Unlock()
+	fqn, err := lookup(h)
+	if err != nil {
+		return err
+	}
+	if fqn == "" {
+		return ErrChunkNotFound
+	}
+	refc, err := getRefc(fqn)
+	if err != nil {
+		return err
+	}
+	if refc += delta; refc <= 0 {
+		return cmn.RemoveFile(fqn)
+	}
+	return setRefc(fqn, refc)
+}
+
+// readChunk reads the content of the stored chunk into dst
+func readChunk(h *Hash, size int64, dst []byte) ([]byte, error) {
+	fqn, err := lookup(h)
+	if err != nil {
+		return nil, err
+	}
+	if fqn == "" {
+		return nil, fmt.Errorf("%w: %s", ErrChunkNotFound, h.String())
+	}
+	file, err := os.Open(fqn)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { debug.AssertNoErr(file.Close()) }()
+	if int64(cap(dst)) < size {
+		dst = make([]byte, size)
+	}
+	dst = dst[:size]
+	if _, err = io.ReadFull(file, dst); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrCorrupted, fqn, err)
+	}
+	return dst, nil
+}
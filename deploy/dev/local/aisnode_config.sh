@@ -81,9 +81,20 @@ cat > $AIS_CONF_FILE <<EOL
 		"block_size": ${BLOCK_SIZE:-262144},
 		"checksum":   ${CHECKSUM:-false}
 	},
+	"rate_limit": {
+		"user_rate":    0,
+		"user_burst":   0,
+		"bucket_rate":  0,
+		"bucket_burst": 0,
+		"enabled":      false
+	},
+	"sse": {
+		"keyfile": "${AIS_SSE_KEYFILE:-}"
+	},
 	"versioning": {
 		"enabled":           true,
-		"validate_warm_get": false
+		"validate_warm_get": false,
+		"history":           false
 	},
 	"fspaths": {
 		$AIS_FS_PATHS
@@ -42,7 +42,10 @@ type (
 	}
 
 	CloudResource struct {
-		ObjName string
+		ObjName  string
+		Version  string // optional (as listed by the cloud)
+		Size     int64
+		Checksum string
 	}
 
 	WebResource struct {
@@ -51,9 +54,11 @@ type (
 	}
 
 	DstElement struct {
-		ObjName string
-		Version string
-		Link    string
+		ObjName  string
+		Version  string
+		Link     string
+		Size     int64
+		Checksum string
 	}
 
 	DiffResolverResult struct {
@@ -163,7 +168,10 @@ func (dr *DiffResolver) PushDst(v interface{}) {
 	switch x := v.(type) {
 	case *CloudResource:
 		d = &DstElement{
-			ObjName: x.ObjName,
+			ObjName:  x.ObjName,
+			Version:  x.Version,
+			Size:     x.Size,
+			Checksum: x.Checksum,
 		}
 	case *WebResource:
 		d = &DstElement{
@@ -250,6 +250,12 @@ func roiFromObjMeta(objMeta cmn.SimpleKVs) (roi remoteObjInfo) {
 		roi.md[cluster.SourceObjMD] = cluster.SourceAmazonObjMD
 	case cmn.ProviderAzure:
 		roi.md[cluster.SourceObjMD] = cluster.SourceAzureObjMD
+	case cmn.ProviderHDFS:
+		roi.md[cluster.SourceObjMD] = cluster.SourceHDFSObjMD
+	case cmn.ProviderHTTP:
+		roi.md[cluster.SourceObjMD] = cluster.SourceHTTPObjMD
+	case cmn.ProviderPosix:
+		roi.md[cluster.SourceObjMD] = cluster.SourcePosixObjMD
 	default:
 		return
 	}
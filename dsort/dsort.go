@@ -174,6 +174,11 @@ func (m *Manager) extractShard(name string, metrics *LocalExtraction) func() err
 			}
 			return err
 		}
+		// the shard is read directly from the file - see cluster.LOM.StoredAsIs
+		if !lom.StoredAsIs() {
+			return errors.Errorf("%s: %s is not supported for encrypted, compressed, or deduplicated shards",
+				lom, cmn.DSortName)
+		}
 
 		phaseInfo.adjuster.acquireSema(lom.ParsedFQN.MpathInfo)
 		if m.aborted() {
@@ -0,0 +1,95 @@
+// Package extract provides provides functions for working with compressed files
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package extract
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/debug"
+)
+
+// RecordIterFunc is called for each record (regular file) of a shard. The
+// reader is valid only until the function returns.
+type RecordIterFunc func(name string, size int64, r io.Reader) error
+
+// ShardExt returns the shard extension (one of the supported archive formats)
+// or empty string if the object is not a shard.
+func ShardExt(objName string) string {
+	for _, ext := range []string{cmn.ExtTarTgz, cmn.ExtTgz, cmn.ExtTar, cmn.ExtZip} {
+		if strings.HasSuffix(objName, ext) {
+			return ext
+		}
+	}
+	return ""
+}
+
+// IterRecords reads the shard sequentially, without extracting anything, and
+// calls `cb` for each record. Iteration stops upon the first error returned
+// by `cb`.
+func IterRecords(r *io.SectionReader, ext string, cb RecordIterFunc) error {
+	switch ext {
+	case cmn.ExtTar:
+		return iterTarRecords(r, cb)
+	case cmn.ExtTgz, cmn.ExtTarTgz:
+		gzr, err := gzip.NewReader(r)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			debug.AssertNoErr(gzr.Close())
+		}()
+		return iterTarRecords(gzr, cb)
+	case cmn.ExtZip:
+		return iterZipRecords(r, cb)
+	default:
+		return fmt.Errorf("unsupported shard extension %q", ext)
+	}
+}
+
+func iterTarRecords(r io.Reader, cb RecordIterFunc) error {
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		if err := cb(header.Name, header.Size, tr); err != nil {
+			return err
+		}
+	}
+}
+
+func iterZipRecords(r *io.SectionReader, cb RecordIterFunc) error {
+	zr, err := zip.NewReader(r, r.Size())
+	if err != nil {
+		return err
+	}
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		file, err := f.Open()
+		if err != nil {
+			return err
+		}
+		err = cb(f.Name, int64(f.UncompressedSize64), file)
+		debug.AssertNoErr(file.Close())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
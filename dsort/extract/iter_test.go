@@ -0,0 +1,88 @@
+// Package extract provides provides functions for working with compressed files
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package extract
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+
+	"github.com/NVIDIA/aistore/cmn"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("IterRecords", func() {
+	records := map[string]string{
+		"0001.jpg": "image",
+		"0001.cls": "3",
+		"0002.jpg": "another image",
+	}
+
+	createTar := func(w io.Writer) {
+		tw := tar.NewWriter(w)
+		Expect(tw.WriteHeader(&tar.Header{Name: "dir/", Typeflag: tar.TypeDir, Mode: 0755})).To(Succeed())
+		for name, content := range records {
+			hdr := &tar.Header{Name: name, Typeflag: tar.TypeReg, Size: int64(len(content)), Mode: 0644}
+			Expect(tw.WriteHeader(hdr)).To(Succeed())
+			_, err := tw.Write([]byte(content))
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(tw.Close()).To(Succeed())
+	}
+
+	iterAll := func(b []byte, ext string) map[string]string {
+		result := make(map[string]string)
+		err := IterRecords(io.NewSectionReader(bytes.NewReader(b), 0, int64(len(b))), ext,
+			func(name string, size int64, r io.Reader) error {
+				content, err := ioutil.ReadAll(r)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(int64(len(content))).To(Equal(size))
+				result[name] = string(content)
+				return nil
+			},
+		)
+		Expect(err).NotTo(HaveOccurred())
+		return result
+	}
+
+	It("should iterate over tar records", func() {
+		buf := &bytes.Buffer{}
+		createTar(buf)
+		Expect(iterAll(buf.Bytes(), cmn.ExtTar)).To(Equal(records))
+	})
+
+	It("should iterate over tgz records", func() {
+		buf := &bytes.Buffer{}
+		gzw := gzip.NewWriter(buf)
+		createTar(gzw)
+		Expect(gzw.Close()).To(Succeed())
+		Expect(iterAll(buf.Bytes(), cmn.ExtTgz)).To(Equal(records))
+	})
+
+	It("should iterate over zip records", func() {
+		buf := &bytes.Buffer{}
+		zw := zip.NewWriter(buf)
+		for name, content := range records {
+			w, err := zw.Create(name)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = w.Write([]byte(content))
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(zw.Close()).To(Succeed())
+		Expect(iterAll(buf.Bytes(), cmn.ExtZip)).To(Equal(records))
+	})
+
+	It("should detect shard extension", func() {
+		Expect(ShardExt("shard-1.tar")).To(Equal(cmn.ExtTar))
+		Expect(ShardExt("shard-1.tar.gz")).To(Equal(cmn.ExtTarTgz))
+		Expect(ShardExt("shard-1.tgz")).To(Equal(cmn.ExtTgz))
+		Expect(ShardExt("shard-1.zip")).To(Equal(cmn.ExtZip))
+		Expect(ShardExt("image.jpg")).To(Equal(""))
+	})
+})
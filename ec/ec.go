@@ -329,6 +329,18 @@ func requestECMeta(bck cmn.Bck, objName string, si *cluster.Snode, client *http.
 	return md, err
 }
 
+// setObjMD restores the custom metadata of the object (a copy thereof)
+func setObjMD(lom *cluster.LOM, meta *Metadata) {
+	if len(meta.ObjMD) == 0 {
+		return
+	}
+	md := make(cmn.SimpleKVs, len(meta.ObjMD))
+	for k, v := range meta.ObjMD {
+		md[k] = v
+	}
+	lom.SetCustomMD(md)
+}
+
 // Saves the main replica to local drives
 func WriteObject(t cluster.Target, lom *cluster.LOM, reader io.Reader, size int64, cksumType string) error {
 	if size > 0 {
@@ -389,6 +401,9 @@ func LomFromHeader(t cluster.Target, hdr transport.Header) (*cluster.LOM, error)
 
 // Saves replica and its metafile
 func WriteReplicaAndMeta(t cluster.Target, lom *cluster.LOM, data io.Reader, md []byte, cksumType, cksumValue string) error {
+	if meta, err := StringToMeta(string(md)); err == nil {
+		setObjMD(lom, meta)
+	}
 	err := WriteObject(t, lom, data, lom.Size(), cksumType)
 	if err != nil {
 		return err
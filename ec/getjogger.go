@@ -280,6 +280,7 @@ func (c *getJogger) restoreReplicatedFromDisk(req *Request, meta *Metadata, node
 		return err
 	}
 
+	setObjMD(req.LOM, meta)
 	if err := req.LOM.Persist(); err != nil {
 		return err
 	}
@@ -24,6 +24,9 @@ type Metadata struct {
 	Parity     int    `json:"parity"`                    // the number of parity slices
 	SliceID    int    `json:"sliceid,omitempty"`         // 0 for full replica, 1 to N for slices
 	IsCopy     bool   `json:"copy"`                      // object is replicated(true) or encoded(false)
+	// custom metadata of the object (see cluster.LOM.PortableCustomMD) that, in
+	// particular, tells how its content is stored (encrypted, compressed)
+	ObjMD cmn.SimpleKVs `json:"obj_md,omitempty"`
 }
 
 var (
@@ -105,7 +108,26 @@ func (md *Metadata) Unpack(unpacker *cmn.ByteUnpack) (err error) {
 	if md.CksumType, err = unpacker.ReadString(); err != nil {
 		return
 	}
-	md.CksumValue, err = unpacker.ReadString()
+	if md.CksumValue, err = unpacker.ReadString(); err != nil {
+		return
+	}
+	var (
+		cnt    uint16
+		key, v string
+	)
+	if cnt, err = unpacker.ReadUint16(); err != nil || cnt == 0 {
+		return
+	}
+	md.ObjMD = make(cmn.SimpleKVs, cnt)
+	for i := 0; i < int(cnt); i++ {
+		if key, err = unpacker.ReadString(); err != nil {
+			return
+		}
+		if v, err = unpacker.ReadString(); err != nil {
+			return
+		}
+		md.ObjMD[key] = v
+	}
 	return
 }
 
@@ -119,11 +141,20 @@ func (md *Metadata) Pack(packer *cmn.BytePack) {
 	packer.WriteString(md.ObjVersion)
 	packer.WriteString(md.CksumType)
 	packer.WriteString(md.CksumValue)
+	packer.WriteUint16(uint16(len(md.ObjMD)))
+	for key, v := range md.ObjMD {
+		packer.WriteString(key)
+		packer.WriteString(v)
+	}
 }
 
 // int16 is sufficient to keep Data,Parity, and SliceID, so:
-//    int64 + 3*int16 + bool + 4 strings
+//    int64 + 3*int16 + bool + 4 strings + int16 + 2 strings per custom metadata entry
 func (md *Metadata) PackedSize() int {
-	return cmn.SizeofI64 + cmn.SizeofI16*3 + 1 + cmn.SizeofLen*4 +
-		len(md.ObjCksum) + len(md.ObjVersion) + len(md.CksumType) + len(md.CksumValue)
+	total := cmn.SizeofI64 + cmn.SizeofI16*3 + 1 + cmn.SizeofLen*4 +
+		len(md.ObjCksum) + len(md.ObjVersion) + len(md.CksumType) + len(md.CksumValue) + cmn.SizeofI16
+	for key, v := range md.ObjMD {
+		total += 2*cmn.SizeofLen + len(key) + len(v)
+	}
+	return total
 }
@@ -167,6 +167,7 @@ func (c *putJogger) encode(req *Request) error {
 		IsCopy:    req.IsCopy,
 		ObjCksum:  cksumValue,
 		CksumType: cksumType,
+		ObjMD:     req.LOM.PortableCustomMD(),
 	}
 
 	// calculate the number of targets required to encode the object
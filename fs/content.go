@@ -33,6 +33,7 @@ const (
 	contentTypeLen = 2
 	ObjectType     = "ob"
 	WorkfileType   = "wk"
+	VersionType    = "vr" // noncurrent versions of objects (see VersionConf.History)
 )
 
 type (
@@ -160,6 +161,7 @@ func (f *ContentSpecMgr) PermToProcess(fqn string) (ok bool) {
 type (
 	ObjectContentResolver   struct{}
 	WorkfileContentResolver struct{}
+	VersionContentResolver  struct{}
 )
 
 func (wf *ObjectContentResolver) PermToMove() bool    { return true }
@@ -211,3 +213,32 @@ func (wf *WorkfileContentResolver) ParseUniqueFQN(base string) (orig string, old
 
 	return base[:tieIndex], filePID != pid, true
 }
+
+// Noncurrent versions are not moved by rebalance and are not evicted by LRU:
+// they are removed explicitly or by bucket lifecycle rules (noncurrent_days).
+func (vr *VersionContentResolver) PermToMove() bool    { return false }
+func (vr *VersionContentResolver) PermToEvict() bool   { return false }
+func (vr *VersionContentResolver) PermToProcess() bool { return false }
+
+// GenUniqueFQN appends the version (here: prefix) to the object's base name
+func (vr *VersionContentResolver) GenUniqueFQN(base, ver string) string {
+	return base + "." + ver
+}
+
+func (vr *VersionContentResolver) ParseUniqueFQN(base string) (orig string, old, ok bool) {
+	orig, _, ok = SplitVersion(base)
+	return
+}
+
+// SplitVersion splits the name of a noncurrent version (see GenUniqueFQN)
+// into the object name and the version.
+func SplitVersion(name string) (objName, ver string, ok bool) {
+	idx := strings.LastIndex(name, ".")
+	if idx <= 0 || idx == len(name)-1 {
+		return
+	}
+	if _, err := strconv.ParseUint(name[idx+1:], 10, 64); err != nil {
+		return
+	}
+	return name[:idx], name[idx+1:], true
+}
@@ -26,6 +26,7 @@ const (
 	WorkfilePut     = "put"    // object PUT
 	WorkfileAppend  = "append" // object APPEND
 	WorkfileFSHC    = "fshc"   // FSHC test file
+	WorkfileMpt     = "mpt"    // S3 multipart upload: uploaded part
 )
 
 type ParsedFQN struct {
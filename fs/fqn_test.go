@@ -345,3 +345,25 @@ func BenchmarkParseFQN(b *testing.B) {
 		parsedFQN, _ = fs.ParseFQN(fqn)
 	}
 }
+
+func TestSplitVersion(t *testing.T) {
+	tests := []struct {
+		name        string
+		wantObjName string
+		wantVer     string
+		wantOk      bool
+	}{
+		{"obj.1", "obj", "1", true},
+		{"dir/file.tar.42", "dir/file.tar", "42", true},
+		{"file.tar", "", "", false},
+		{"obj.", "", "", false},
+		{"obj", "", "", false},
+	}
+	for _, tt := range tests {
+		objName, ver, ok := fs.SplitVersion(tt.name)
+		if ok != tt.wantOk || objName != tt.wantObjName || ver != tt.wantVer {
+			t.Errorf("SplitVersion(%q) = (%q, %q, %v), want (%q, %q, %v)",
+				tt.name, objName, ver, ok, tt.wantObjName, tt.wantVer, tt.wantOk)
+		}
+	}
+}
@@ -38,6 +38,7 @@ const (
 
 const (
 	TrashDir = "$trash"
+	DedupDir = "$dedup" // chunks of deduplicated objects (see package dedup)
 )
 
 // global singleton
@@ -168,6 +169,7 @@ func (mi *MountpathInfo) evictLomCache() {
 }
 
 func (mi *MountpathInfo) MakePathTrash() string { return filepath.Join(mi.Path, TrashDir) }
+func (mi *MountpathInfo) MakePathDedup() string { return filepath.Join(mi.Path, DedupDir) }
 
 // MoveToTrash removes directory in steps:
 // 1. Synchronously gets temporary directory name
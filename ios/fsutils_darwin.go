@@ -6,6 +6,7 @@
 package ios
 
 import (
+	"errors"
 	"os"
 	"syscall"
 	"time"
@@ -28,3 +29,7 @@ func GetATime(osfi os.FileInfo) time.Time {
 	// NOTE: see https://en.wikipedia.org/wiki/Stat_(system_call)#Criticism_of_atime
 	return atime
 }
+
+func PunchHole(_ *os.File, _, _ int64) error {
+	return errors.New("punching holes is not supported")
+}
@@ -0,0 +1,302 @@
+// Package lifecycle enforces per-bucket lifecycle rules: expiration of objects
+// in ais buckets and eviction of idle cached objects in remote buckets.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package lifecycle
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/stats"
+)
+
+// Lifecycle rules are stored in bucket properties (see cmn.LifecycleConf).
+// Each target periodically (every HkInterval) runs the lifecycle xaction that
+// walks the local objects of the buckets with enabled rules - one jogger per
+// mountpath - and removes the objects that are due:
+//   - expired: written more than rule.ExpireDays ago (ais buckets)
+//   - noncurrent versions: replaced more than rule.NoncurrentDays ago (ais buckets)
+//   - evicted: not accessed for more than rule.EvictIdleTime (remote buckets)
+// Removed objects are accounted in the target's stats (stats.Lc*).
+
+const (
+	HkName     = "lifecycle"
+	HkInterval = time.Hour
+)
+
+type (
+	InitLifecycle struct {
+		T       cluster.Target
+		Xaction *Xaction
+		StatsT  stats.Tracker
+	}
+
+	Xaction struct {
+		cmn.XactBase
+	}
+
+	// lcJ represents a single /jogger/ that traverses a given bucket on a
+	// given mountpath.
+	lcJ struct {
+		ini       *InitLifecycle
+		mpathInfo *fs.MountpathInfo
+		bck       *cluster.Bck
+		rules     []cmn.LifecycleRule
+		config    *cmn.Config
+		now       time.Time
+		// stats
+		expired, expiredSize int64
+		evicted, evictedSize int64
+	}
+)
+
+func NewXaction(id string) *Xaction {
+	return &Xaction{XactBase: *cmn.NewXactBase(cmn.XactBaseID(id), cmn.ActLifecycle)}
+}
+
+func (r *Xaction) IsMountpathXact() bool { return true }
+
+// Run is blocking
+func Run(ini *InitLifecycle) {
+	var (
+		bcks      []*cluster.Bck
+		mpaths, _ = fs.Get()
+		config    = cmn.GCO.Get()
+	)
+	ini.T.GetBowner().Get().Range(nil, nil, func(bck *cluster.Bck) bool {
+		if bck.Props.Lifecycle.Enabled && len(bck.Props.Lifecycle.Rules) > 0 {
+			bcks = append(bcks, bck)
+		}
+		return false
+	})
+	if len(bcks) == 0 {
+		return
+	}
+	if len(mpaths) == 0 {
+		glog.Errorln(cmn.NoMountpaths)
+		return
+	}
+	glog.Infof("%s: %s started: %d bucket(s)", ini.T.Snode(), ini.Xaction, len(bcks))
+	for _, bck := range bcks {
+		var (
+			wg      = &sync.WaitGroup{}
+			joggers = make([]*lcJ, 0, len(mpaths))
+		)
+		for _, mpathInfo := range mpaths {
+			j := &lcJ{
+				ini:       ini,
+				mpathInfo: mpathInfo,
+				bck:       bck,
+				rules:     bck.Props.Lifecycle.Rules,
+				config:    config,
+				now:       time.Now(),
+			}
+			joggers = append(joggers, j)
+			wg.Add(1)
+			go j.jog(wg)
+		}
+		wg.Wait()
+		for _, j := range joggers {
+			j.report()
+		}
+		if ini.Xaction.Aborted() {
+			return
+		}
+	}
+}
+
+/////////
+// lcJ //
+/////////
+
+func (j *lcJ) String() string {
+	return fmt.Sprintf("%s: (%s, %s, %s)", j.ini.T.Snode(), j.ini.Xaction, j.bck, j.mpathInfo)
+}
+
+func (j *lcJ) jog(wg *sync.WaitGroup) {
+	defer wg.Done()
+	cts := []string{fs.ObjectType}
+	if j.bck.IsAIS() && j.hasNoncurrent() {
+		cts = append(cts, fs.VersionType)
+	}
+	opts := &fs.Options{
+		Mpath:    j.mpathInfo,
+		Bck:      j.bck.Bck,
+		CTs:      cts,
+		Callback: j.walk,
+		Sorted:   false,
+	}
+	if err := fs.Walk(opts); err != nil {
+		if _, ok := err.(cmn.AbortedError); !ok {
+			glog.Errorf("%s: %v", j, err)
+		}
+	}
+}
+
+func (j *lcJ) walk(fqn string, de fs.DirEntry) error {
+	if de.IsDir() {
+		return nil
+	}
+	if j.ini.Xaction.Aborted() {
+		return cmn.NewAbortedError(j.ini.Xaction.String())
+	}
+	if parsedFQN, err := fs.ParseFQN(fqn); err == nil && parsedFQN.ContentType == fs.VersionType {
+		j.walkVersion(fqn, parsedFQN)
+		return nil
+	}
+	lom := &cluster.LOM{T: j.ini.T, FQN: fqn}
+	if err := lom.Init(j.bck.Bck, j.config); err != nil {
+		return nil
+	}
+	if !j.matches(lom.ObjName) {
+		return nil
+	}
+	if err := lom.Load(false); err != nil {
+		return nil
+	}
+	if lom.IsCopy() || !lom.IsHRW() {
+		return nil
+	}
+	if j.due(lom) {
+		j.remove(lom)
+	}
+	return nil
+}
+
+// walkVersion removes the noncurrent version if it is due
+func (j *lcJ) walkVersion(fqn string, parsedFQN fs.ParsedFQN) {
+	objName, _, ok := fs.SplitVersion(parsedFQN.ObjName)
+	if !ok {
+		return
+	}
+	var age time.Duration
+	for i := range j.rules {
+		rule := &j.rules[i]
+		if strings.HasPrefix(objName, rule.Prefix) && rule.NoncurrentDays > 0 {
+			if age == 0 || rule.NoncurrentAge() < age {
+				age = rule.NoncurrentAge()
+			}
+		}
+	}
+	if age == 0 {
+		return
+	}
+	finfo, err := os.Stat(fqn)
+	if err != nil || j.now.Sub(finfo.ModTime()) <= age {
+		return
+	}
+	lom := &cluster.LOM{T: j.ini.T, ObjName: objName}
+	if err := lom.Init(j.bck.Bck, j.config); err != nil {
+		return
+	}
+	lom.Lock(true)
+	defer lom.Unlock(true)
+	vlom, err := lom.VersionLOM(fqn, parsedFQN.MpathInfo)
+	if err != nil {
+		return
+	}
+	if vlom.ObjLockErr(false) != nil {
+		return // retained
+	}
+	if err := vlom.Remove(); err != nil {
+		glog.Errorf("%s: failed to remove %s: %v", j, fqn, err)
+		return
+	}
+	j.expired++
+	j.expiredSize += vlom.Size()
+}
+
+func (j *lcJ) hasNoncurrent() bool {
+	for i := range j.rules {
+		if j.rules[i].NoncurrentDays > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (j *lcJ) matches(objName string) bool {
+	for i := range j.rules {
+		if strings.HasPrefix(objName, j.rules[i].Prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// due returns true if any of the matching rules requires to remove the object
+func (j *lcJ) due(lom *cluster.LOM) bool {
+	for i := range j.rules {
+		rule := &j.rules[i]
+		if !strings.HasPrefix(lom.ObjName, rule.Prefix) {
+			continue
+		}
+		if age := rule.ExpireAge(); age > 0 {
+			finfo, err := os.Stat(lom.FQN)
+			if err == nil && j.now.Sub(finfo.ModTime()) > age {
+				return true
+			}
+		}
+		if idle := rule.EvictIdle(); idle > 0 && j.now.Sub(lom.Atime()) > idle {
+			return true
+		}
+	}
+	return false
+}
+
+func (j *lcJ) remove(lom *cluster.LOM) {
+	lom.Lock(true)
+	defer lom.Unlock(true)
+	// re-check under lock: the object may have been accessed or overwritten
+	if err := lom.Load(false); err != nil || !j.due(lom) {
+		return
+	}
+	if err := lom.ObjLockErr(false); err != nil {
+		if glog.FastV(4, glog.SmoduleAIS) {
+			glog.Infof("%s: %v", j, err)
+		}
+		return
+	}
+	// not yet written back to the cloud (see xaction/writeback.go)
+	if lom.IsDirty() {
+		return
+	}
+	if err := lom.Remove(); err != nil {
+		glog.Errorf("%s: failed to remove %s: %v", j, lom, err)
+		return
+	}
+	if j.bck.IsRemote() {
+		j.evicted++
+		j.evictedSize += lom.Size()
+	} else {
+		j.expired++
+		j.expiredSize += lom.Size()
+		j.ini.T.ObjectDeleted(lom)
+	}
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("%s: removed %s", j, lom)
+	}
+}
+
+func (j *lcJ) report() {
+	if j.expired == 0 && j.evicted == 0 {
+		return
+	}
+	j.ini.StatsT.AddMany(
+		stats.NamedVal64{Name: stats.LcExpireCount, Value: j.expired},
+		stats.NamedVal64{Name: stats.LcExpireSize, Value: j.expiredSize},
+		stats.NamedVal64{Name: stats.LcEvictCount, Value: j.evicted},
+		stats.NamedVal64{Name: stats.LcEvictSize, Value: j.evictedSize},
+	)
+	j.ini.Xaction.ObjectsAdd(j.expired + j.evicted)
+	j.ini.Xaction.BytesAdd(j.expiredSize + j.evictedSize)
+}
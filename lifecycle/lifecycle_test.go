@@ -0,0 +1,181 @@
+// Package lifecycle enforces per-bucket lifecycle rules: expiration of objects
+// in ais buckets and eviction of idle cached objects in remote buckets.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package lifecycle
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/stats"
+	"github.com/NVIDIA/aistore/tutils/tassert"
+)
+
+// targetMock records the objects reported deleted (see replication)
+type targetMock struct {
+	*cluster.TargetMock
+	mtx     sync.Mutex
+	deleted []string
+}
+
+var past = time.Now().Add(-30 * 24 * time.Hour)
+
+func (t *targetMock) ObjectDeleted(lom *cluster.LOM) {
+	t.mtx.Lock()
+	t.deleted = append(t.deleted, lom.ObjName)
+	t.mtx.Unlock()
+}
+
+func newTarget(t *testing.T, bck *cluster.Bck) (tMock *targetMock, mpath string) {
+	mpath, err := ioutil.TempDir("", "lifecycle-")
+	tassert.CheckFatal(t, err)
+	fs.Init()
+	fs.DisableFsIDCheck()
+	tassert.CheckFatal(t, fs.Add(mpath))
+	fs.CSM.RegisterContentType(fs.ObjectType, &fs.ObjectContentResolver{})
+	fs.CSM.RegisterContentType(fs.WorkfileType, &fs.WorkfileContentResolver{})
+	fs.CSM.RegisterContentType(fs.VersionType, &fs.VersionContentResolver{})
+	cluster.InitTarget()
+	return &targetMock{TargetMock: cluster.NewTargetMock(cluster.NewBaseBownerMock(bck))}, mpath
+}
+
+// putObj stores an object accessed and written at a given time
+func putObj(t *testing.T, tMock cluster.Target, bck *cluster.Bck, objName string, mtime time.Time,
+	setMD func(*cluster.LOM)) *cluster.LOM {
+	lom := &cluster.LOM{T: tMock, ObjName: objName}
+	tassert.CheckFatal(t, lom.Init(bck.Bck))
+	tassert.CheckFatal(t, os.MkdirAll(filepath.Dir(lom.FQN), 0755))
+	tassert.CheckFatal(t, ioutil.WriteFile(lom.FQN, []byte(objName), 0644))
+	lom.SetSize(int64(len(objName)))
+	lom.SetAtimeUnix(mtime.UnixNano())
+	if setMD != nil {
+		setMD(lom)
+	}
+	tassert.CheckFatal(t, lom.Persist())
+	tassert.CheckFatal(t, os.Chtimes(lom.FQN, mtime, mtime))
+	return lom
+}
+
+// putVersion stores a noncurrent version of the object replaced at a given time
+func putVersion(t *testing.T, tMock cluster.Target, bck *cluster.Bck, objName, ver string, archived time.Time,
+	setMD func(*cluster.LOM)) {
+	lom := &cluster.LOM{T: tMock, ObjName: objName}
+	tassert.CheckFatal(t, lom.Init(bck.Bck))
+	workFQN := fs.CSM.GenContentParsedFQN(lom.ParsedFQN, fs.WorkfileType, fs.WorkfilePut)
+	tassert.CheckFatal(t, os.MkdirAll(filepath.Dir(workFQN), 0755))
+	tassert.CheckFatal(t, ioutil.WriteFile(workFQN, []byte(ver), 0644))
+	lom.SetVersion(ver)
+	lom.SetSize(int64(len(ver)))
+	if setMD != nil {
+		setMD(lom)
+	}
+	tassert.CheckFatal(t, lom.AddVersion(workFQN, archived))
+}
+
+func hasVersion(t *testing.T, tMock cluster.Target, bck *cluster.Bck, objName, ver string) bool {
+	lom := &cluster.LOM{T: tMock, ObjName: objName}
+	tassert.CheckFatal(t, lom.Init(bck.Bck))
+	_, err := lom.LoadVersion(ver)
+	return err == nil
+}
+
+func exists(lom *cluster.LOM) bool {
+	_, err := os.Stat(lom.FQN)
+	return err == nil
+}
+
+func run(tMock cluster.Target) {
+	Run(&InitLifecycle{T: tMock, Xaction: NewXaction(cmn.GenUUID()), StatsT: stats.NewTrackerMock()})
+}
+
+func TestEvictIdle(t *testing.T) {
+	cmn.InitShortID(0)
+	bck := cluster.NewBck("lc-evict", cmn.ProviderAmazon, cmn.NsGlobal, &cmn.BucketProps{
+		Cksum:     cmn.CksumConf{Type: cmn.ChecksumNone},
+		WriteBack: cmn.BckWriteBackConf{Enabled: true},
+		Lifecycle: cmn.LifecycleConf{
+			Enabled: true,
+			Rules:   []cmn.LifecycleRule{{ID: "idle", Prefix: "tmp/", EvictIdleTime: "24h"}},
+		},
+	})
+	tMock, mpath := newTarget(t, bck)
+	defer os.RemoveAll(mpath)
+
+	var (
+		idle     = putObj(t, tMock, bck, "tmp/idle", past, nil)
+		recent   = putObj(t, tMock, bck, "tmp/recent", time.Now(), nil)
+		other    = putObj(t, tMock, bck, "other/idle", past, nil)
+		dirty    = putObj(t, tMock, bck, "tmp/dirty", past, func(lom *cluster.LOM) { lom.SetDirty("1") })
+		retained = putObj(t, tMock, bck, "tmp/held", past, func(lom *cluster.LOM) { lom.SetLegalHold(true) })
+	)
+	run(tMock)
+
+	tassert.Errorf(t, !exists(idle), "%s must be evicted", idle)
+	tassert.Errorf(t, exists(recent), "%s was accessed recently", recent)
+	tassert.Errorf(t, exists(other), "%s does not match the rule", other)
+	tassert.Errorf(t, exists(dirty), "%s is yet to be written back", dirty)
+	tassert.Errorf(t, exists(retained), "%s is under legal hold", retained)
+}
+
+func TestExpire(t *testing.T) {
+	cmn.InitShortID(0)
+	bck := cluster.NewBck("lc-expire", cmn.ProviderAIS, cmn.NsGlobal, &cmn.BucketProps{
+		Cksum: cmn.CksumConf{Type: cmn.ChecksumNone},
+		Lifecycle: cmn.LifecycleConf{
+			Enabled: true,
+			Rules:   []cmn.LifecycleRule{{ID: "tmp", Prefix: "tmp/", ExpireDays: 7}},
+		},
+	})
+	tMock, mpath := newTarget(t, bck)
+	defer os.RemoveAll(mpath)
+
+	var (
+		old      = putObj(t, tMock, bck, "tmp/old", past, nil)
+		recent   = putObj(t, tMock, bck, "tmp/recent", time.Now(), nil)
+		other    = putObj(t, tMock, bck, "other/old", past, nil)
+		retained = putObj(t, tMock, bck, "tmp/held", past, func(lom *cluster.LOM) { lom.SetLegalHold(true) })
+	)
+	run(tMock)
+
+	tassert.Errorf(t, !exists(old), "%s must expire", old)
+	tassert.Errorf(t, exists(recent), "%s was written recently", recent)
+	tassert.Errorf(t, exists(other), "%s does not match the rule", other)
+	tassert.Errorf(t, exists(retained), "%s is under legal hold", retained)
+	tassert.Errorf(t, len(tMock.deleted) == 1 && tMock.deleted[0] == old.ObjName,
+		"expected %s to be reported deleted, got %v", old, tMock.deleted)
+}
+
+func TestNoncurrent(t *testing.T) {
+	cmn.InitShortID(0)
+	bck := cluster.NewBck("lc-noncurrent", cmn.ProviderAIS, cmn.NsGlobal, &cmn.BucketProps{
+		Cksum:      cmn.CksumConf{Type: cmn.ChecksumNone},
+		Versioning: cmn.VersionConf{Enabled: true, History: true},
+		Lifecycle: cmn.LifecycleConf{
+			Enabled: true,
+			Rules:   []cmn.LifecycleRule{{ID: "noncurrent", NoncurrentDays: 7}},
+		},
+	})
+	tMock, mpath := newTarget(t, bck)
+	defer os.RemoveAll(mpath)
+
+	current := putObj(t, tMock, bck, "obj", past, func(lom *cluster.LOM) { lom.SetVersion("4") })
+	putVersion(t, tMock, bck, "obj", "1", past, nil)
+	putVersion(t, tMock, bck, "obj", "2", past, func(lom *cluster.LOM) { lom.SetLegalHold(true) })
+	putVersion(t, tMock, bck, "obj", "3", time.Now(), nil)
+	run(tMock)
+
+	tassert.Errorf(t, !hasVersion(t, tMock, bck, "obj", "1"), "version 1 must expire")
+	tassert.Errorf(t, hasVersion(t, tMock, bck, "obj", "2"), "version 2 is under legal hold")
+	tassert.Errorf(t, hasVersion(t, tMock, bck, "obj", "3"), "version 3 was replaced recently")
+	tassert.Errorf(t, exists(current), "current version must not expire")
+	tassert.Errorf(t, len(tMock.deleted) == 0, "noncurrent versions are not replicated, got %v", tMock.deleted)
+}
@@ -254,6 +254,10 @@ func (j *lruJ) removeTrash() (err error) {
 	trashDir := j.mpathInfo.MakePathTrash()
 	err = fs.Scanner(trashDir, func(fqn string, de fs.DirEntry) error {
 		if de.IsDir() {
+			// release deduplicated objects of the destroyed bucket (see cluster.ReleaseTrashed)
+			if err := j.releaseTrashed(fqn); err != nil {
+				return err
+			}
 			return os.RemoveAll(fqn)
 		}
 		if err := j.yieldTerm(); err != nil {
@@ -267,6 +271,24 @@ func (j *lruJ) removeTrash() (err error) {
 	return
 }
 
+func (j *lruJ) releaseTrashed(dir string) error {
+	opts := &fs.Options{
+		Dir: dir,
+		Callback: func(fqn string, de fs.DirEntry) error {
+			if de.IsDir() {
+				return nil
+			}
+			if err := j.yieldTerm(); err != nil {
+				return err
+			}
+			cluster.ReleaseTrashed(j.mpathInfo, fqn)
+			// remove right away to never release twice (e.g., when aborted and restarted)
+			return cmn.RemoveFile(fqn)
+		},
+	}
+	return fs.Walk(opts)
+}
+
 func (j *lruJ) jogBck() (size int64, err error) {
 	// 1. init per-bucket min-heap (and reuse the slice)
 	h := (*j.heap)[:0]
@@ -326,10 +348,16 @@ func (j *lruJ) walk(fqn string, de fs.DirEntry) error {
 	if lom.IsCopy() {
 		return nil
 	}
+	if lom.IsDirty() {
+		return nil // not yet written to the cloud (see cmn.BckWriteBackConf)
+	}
 	if !lom.IsHRW() {
 		j.misplaced = append(j.misplaced, lom)
 		return nil
 	}
+	if lom.ObjLockErr(false) != nil {
+		return nil // retained
+	}
 
 	// do nothing if the heap's curSize >= totalSize and
 	// the file is more recent then the the heap's newest.
@@ -337,7 +365,7 @@ func (j *lruJ) walk(fqn string, de fs.DirEntry) error {
 		return nil
 	}
 	heap.Push(h, lom)
-	j.curSize += lom.Size()
+	j.curSize += lom.CachedSize()
 	if lom.AtimeUnix() > j.newest {
 		j.newest = lom.AtimeUnix()
 	}
@@ -384,7 +412,7 @@ func (j *lruJ) evict() (size int64, err error) {
 		} else if err = lom.DelExtraCopies(); err != nil {
 			glog.Warningf("%s: %v", lom, err)
 		}
-		if capCheck, err = j.postRemove(capCheck, lom); err != nil {
+		if capCheck, err = j.postRemove(capCheck, lom.Size()); err != nil {
 			return
 		}
 	}
@@ -392,11 +420,14 @@ func (j *lruJ) evict() (size int64, err error) {
 	// 3.
 	for h.Len() > 0 && j.totalSize > 0 {
 		lom := heap.Pop(h).(*cluster.LOM)
-		if j.evictObj(lom) {
-			bevicted += lom.Size()
-			size += lom.Size()
+		freed, removed := j.evictObj(lom)
+		if removed {
 			fevicted++
-			if capCheck, err = j.postRemove(capCheck, lom); err != nil {
+		}
+		if removed || freed > 0 {
+			bevicted += freed
+			size += freed
+			if capCheck, err = j.postRemove(capCheck, freed); err != nil {
 				return
 			}
 		}
@@ -408,9 +439,9 @@ func (j *lruJ) evict() (size int64, err error) {
 	return
 }
 
-func (j *lruJ) postRemove(prev int64, lom *cluster.LOM) (capCheck int64, err error) {
-	j.totalSize -= lom.Size()
-	capCheck = prev + lom.Size()
+func (j *lruJ) postRemove(prev, size int64) (capCheck int64, err error) {
+	j.totalSize -= size
+	capCheck = prev + size
 	if err = j.yieldTerm(); err != nil {
 		return
 	}
@@ -439,10 +470,25 @@ func (j *lruJ) postRemove(prev int64, lom *cluster.LOM) (capCheck int64, err err
 }
 
 // remove local copies that "belong" to different LRU joggers; hence, space accounting may be temporarily not precise
-func (j *lruJ) evictObj(lom *cluster.LOM) (ok bool) {
+// (a partially cached object that is larger than the remaining size to free gets evicted chunk by chunk)
+func (j *lruJ) evictObj(lom *cluster.LOM) (freed int64, removed bool) {
 	lom.Lock(true)
+	// re-check under lock: legal hold may have been placed (or the object
+	// overwritten in a write-back bucket) after the object was selected
+	loaded := lom.Load(false) == nil
+	if loaded && (lom.ObjLockErr(false) != nil || lom.IsDirty()) {
+		lom.Unlock(true)
+		return
+	}
+	if loaded && lom.IsPartial() {
+		if pmd, err := lom.PartialMD(); err == nil && pmd.Cached() > j.totalSize {
+			freed = j.evictChunks(lom, pmd)
+			lom.Unlock(true)
+			return
+		}
+	}
 	if err := lom.Remove(); err == nil {
-		ok = true
+		freed, removed = lom.CachedSize(), true
 	} else {
 		glog.Errorf("%s: failed to remove, err: %v", lom, err)
 	}
@@ -450,6 +496,49 @@ func (j *lruJ) evictObj(lom *cluster.LOM) (ok bool) {
 	return
 }
 
+// evictChunks punches holes in place of the present chunks of a partially cached
+// object, the last chunk first, until the remaining size to free is reached
+func (j *lruJ) evictChunks(lom *cluster.LOM, pmd *cluster.PartialMD) (freed int64) {
+	var (
+		chunks []int
+		size   int64
+	)
+	for i := pmd.NumChunks() - 1; i >= 0 && size < j.totalSize; i-- {
+		if pmd.Has(i) {
+			_, length := pmd.Chunk(i)
+			pmd.Clear(i)
+			chunks = append(chunks, i)
+			size += length
+		}
+	}
+	if len(chunks) == 0 {
+		return
+	}
+	// the metadata must never refer to evicted chunks - persist first
+	lom.SetPartialMD(pmd)
+	if err := lom.Persist(); err != nil {
+		glog.Errorf("%s: failed to evict chunks, err: %v", lom, err)
+		lom.Uncache()
+		return
+	}
+	lom.ReCache()
+	file, err := os.OpenFile(lom.FQN, os.O_WRONLY, 0)
+	if err != nil {
+		glog.Errorf("%s: failed to evict chunks, err: %v", lom, err)
+		return
+	}
+	for _, i := range chunks {
+		offset, length := pmd.Chunk(i)
+		if err = ios.PunchHole(file, offset, length); err != nil {
+			glog.Errorf("%s: failed to evict chunk %d, err: %v", lom, i, err)
+			break
+		}
+		freed += length
+	}
+	file.Close()
+	return
+}
+
 func (j *lruJ) evictSize() (err error) {
 	lwm, hwm := j.config.LRU.LowWM, j.config.LRU.HighWM
 	blocks, bavail, bsize, err := j.ini.GetFSStats(j.mpathInfo.Path)
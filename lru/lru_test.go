@@ -256,6 +256,36 @@ var _ = Describe("LRU tests", func() {
 			})
 		})
 
+		Describe("evict chunks", func() {
+			It("should evict the last chunks of a partially cached object", func() {
+				const chunkSize = cmn.MiB
+				fqn := path.Join(filesPath, getRandomFileName(0))
+				saveRandomFile(t, fqn, 4*chunkSize)
+				lom := &cluster.LOM{T: t, FQN: fqn}
+				Expect(lom.Init(cmn.Bck{})).NotTo(HaveOccurred())
+				Expect(lom.Load(false)).NotTo(HaveOccurred())
+				pmd := cluster.NewPartialMD(lom.Size(), chunkSize, "etag")
+				for i := 0; i < pmd.NumChunks(); i++ {
+					pmd.Set(i)
+				}
+				lom.SetPartialMD(pmd)
+				Expect(lom.Persist()).NotTo(HaveOccurred())
+
+				j := &lruJ{totalSize: chunkSize + 1}
+				Expect(j.evictChunks(lom, pmd)).To(BeEquivalentTo(2 * chunkSize))
+
+				// persisted
+				lom = &cluster.LOM{T: t, FQN: fqn}
+				Expect(lom.Init(cmn.Bck{})).NotTo(HaveOccurred())
+				Expect(lom.FromFS()).NotTo(HaveOccurred())
+				pmd, err := lom.PartialMD()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(pmd.Has(0) && pmd.Has(1)).To(BeTrue())
+				Expect(pmd.Has(2) || pmd.Has(3)).To(BeFalse())
+				Expect(pmd.Cached()).To(BeEquivalentTo(2 * chunkSize))
+			})
+		})
+
 		Describe("not evict files", func() {
 			It("should do nothing when disk usage is below hwm", func() {
 				const numberOfFiles = 4
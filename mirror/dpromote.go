@@ -22,6 +22,7 @@ type (
 		xactBckBase
 		dir    string
 		params *cmn.ActValPromote
+		bypass bool // bypass governance-mode retention of the overwritten objects
 	}
 )
 
@@ -29,11 +30,13 @@ type (
 // public methods
 //
 
-func NewXactDirPromote(dir string, bck cmn.Bck, t cluster.Target, params *cmn.ActValPromote) *XactDirPromote {
+func NewXactDirPromote(dir string, bck cmn.Bck, t cluster.Target, params *cmn.ActValPromote,
+	bypassGovernance bool) *XactDirPromote {
 	return &XactDirPromote{
 		xactBckBase: *newXactBckBase("", cmn.ActPromote, bck, t),
 		dir:         dir,
 		params:      params,
+		bypass:      bypassGovernance,
 	}
 }
 
@@ -73,7 +76,7 @@ func (r *XactDirPromote) walk(fqn string, de fs.DirEntry) error {
 	objName := r.params.ObjName + strings.TrimPrefix(strings.TrimPrefix(fqn, r.dir), string(filepath.Separator))
 	objName = strings.Trim(objName, string(filepath.Separator))
 	lom, err := r.Target().PromoteFile(fqn, bck, objName, nil, /*expectedCksum*/
-		r.params.Overwrite, true /*safe*/, r.params.Verbose)
+		r.params.Overwrite, true /*safe*/, r.params.Verbose, r.bypass)
 	if err != nil {
 		if finfo, ers := os.Stat(fqn); ers == nil {
 			if finfo.Mode().IsRegular() {
@@ -185,7 +185,7 @@ func (wi *WalkInfo) lsObject(lom *cluster.LOM, objStatus uint16) *cmn.BucketEntr
 	if wi.needTargetURL() {
 		fileInfo.TargetURL = wi.t.Snode().URL(cmn.NetworkPublic)
 	}
-	fileInfo.Size = lom.Size()
+	fileInfo.Size = lom.LogicalSize()
 	if wi.postCallback != nil {
 		wi.postCallback(lom)
 	}
@@ -0,0 +1,174 @@
+// Package query provides interface to iterate over objects with additional filtering
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package query
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/NVIDIA/aistore/cluster"
+)
+
+// AggregateMsg.GroupBy enum
+const (
+	GroupByPrefix = "prefix"
+	GroupByExt    = "ext"
+)
+
+const (
+	defaultAggrDelimiter = "/"
+	// objects that do not have an extension (or a prefix) are grouped under this name
+	NoGroup = ""
+)
+
+type (
+	AggregateGroup struct {
+		Count     int64   `json:"count"`
+		Size      int64   `json:"size"`
+		SizeHist  []int64 `json:"size_hist,omitempty"`
+		AtimeHist []int64 `json:"atime_hist,omitempty"`
+	}
+
+	AggregateResult struct {
+		Groups map[string]*AggregateGroup `json:"groups"`
+	}
+
+	// Aggregator accumulates the objects selected by a query; not thread-safe.
+	Aggregator struct {
+		msg    *AggregateMsg
+		result *AggregateResult
+	}
+)
+
+//////////////////
+// AggregateMsg //
+//////////////////
+
+func (msg *AggregateMsg) Validate() error {
+	switch msg.GroupBy {
+	case "", GroupByPrefix, GroupByExt:
+	default:
+		return fmt.Errorf("invalid aggregate group_by %q (expecting %q or %q)", msg.GroupBy, GroupByPrefix, GroupByExt)
+	}
+	if err := validateBuckets("size", msg.SizeBuckets); err != nil {
+		return err
+	}
+	return validateBuckets("atime", msg.AtimeBuckets)
+}
+
+func validateBuckets(name string, buckets []int64) error {
+	for i := 1; i < len(buckets); i++ {
+		if buckets[i] <= buckets[i-1] {
+			return fmt.Errorf("invalid aggregate %s buckets %v: expecting strictly increasing boundaries", name, buckets)
+		}
+	}
+	return nil
+}
+
+func (msg *AggregateMsg) groupName(objName string) string {
+	switch msg.GroupBy {
+	case GroupByPrefix:
+		delim := msg.Delimiter
+		if delim == "" {
+			delim = defaultAggrDelimiter
+		}
+		if idx := strings.Index(objName, delim); idx >= 0 {
+			return objName[:idx+len(delim)]
+		}
+		return NoGroup
+	case GroupByExt:
+		return filepath.Ext(objName)
+	default:
+		return NoGroup
+	}
+}
+
+////////////////
+// Aggregator //
+////////////////
+
+func NewAggregator(msg *AggregateMsg) *Aggregator {
+	return &Aggregator{
+		msg:    msg,
+		result: &AggregateResult{Groups: make(map[string]*AggregateGroup, 4)},
+	}
+}
+
+// Add accounts for a single (loaded) object
+func (a *Aggregator) Add(lom *cluster.LOM) { a.add(lom.ObjName, lom.LogicalSize(), lom.Atime()) }
+
+func (a *Aggregator) add(objName string, size int64, atime time.Time) {
+	name := a.msg.groupName(objName)
+	group, ok := a.result.Groups[name]
+	if !ok {
+		group = &AggregateGroup{}
+		if len(a.msg.SizeBuckets) > 0 {
+			group.SizeHist = make([]int64, len(a.msg.SizeBuckets)+1)
+		}
+		if len(a.msg.AtimeBuckets) > 0 {
+			group.AtimeHist = make([]int64, len(a.msg.AtimeBuckets)+1)
+		}
+		a.result.Groups[name] = group
+	}
+	group.Count++
+	group.Size += size
+	if group.SizeHist != nil {
+		group.SizeHist[histBin(a.msg.SizeBuckets, size)]++
+	}
+	if group.AtimeHist != nil {
+		group.AtimeHist[histBin(a.msg.AtimeBuckets, atime.UnixNano())]++
+	}
+}
+
+func (a *Aggregator) Result() *AggregateResult { return a.result }
+
+// returns the index of the first boundary that is >= v (or len(boundaries))
+func histBin(boundaries []int64, v int64) int {
+	return sort.Search(len(boundaries), func(i int) bool { return boundaries[i] >= v })
+}
+
+/////////////////////
+// AggregateResult //
+/////////////////////
+
+// Merge adds up the results computed (by different targets) for the same query
+func (r *AggregateResult) Merge(other *AggregateResult) {
+	if r.Groups == nil {
+		r.Groups = make(map[string]*AggregateGroup, len(other.Groups))
+	}
+	for name, og := range other.Groups {
+		group, ok := r.Groups[name]
+		if !ok {
+			r.Groups[name] = og
+			continue
+		}
+		group.Count += og.Count
+		group.Size += og.Size
+		group.SizeHist = mergeHist(group.SizeHist, og.SizeHist)
+		group.AtimeHist = mergeHist(group.AtimeHist, og.AtimeHist)
+	}
+}
+
+func mergeHist(a, b []int64) []int64 {
+	if a == nil {
+		return b
+	}
+	for i := range b {
+		a[i] += b[i]
+	}
+	return a
+}
+
+// Total returns the counts summed up across all groups
+func (r *AggregateResult) Total() (total AggregateGroup) {
+	for _, group := range r.Groups {
+		total.Count += group.Count
+		total.Size += group.Size
+	}
+	return
+}
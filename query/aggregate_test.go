@@ -0,0 +1,67 @@
+// Package query provides interface to iterate over objects with additional filtering
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package query
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+func TestAggregate(t *testing.T) {
+	var (
+		now = time.Now()
+		msg = &AggregateMsg{
+			GroupBy:      GroupByExt,
+			SizeBuckets:  []int64{cmn.KiB, cmn.MiB},
+			AtimeBuckets: []int64{now.Add(-time.Hour).UnixNano()},
+		}
+		a1, a2 = NewAggregator(msg), NewAggregator(msg)
+	)
+	a1.add("train/0001.jpg", cmn.KiB, now.Add(-2*time.Hour))
+	a1.add("train/0002.jpg", 2*cmn.MiB, now)
+	a1.add("train/labels", 10, now)
+	a2.add("test/0003.jpg", 10*cmn.KiB, now)
+
+	result := a1.Result()
+	result.Merge(a2.Result())
+
+	expected := map[string]*AggregateGroup{
+		".jpg": {Count: 3, Size: cmn.KiB + 2*cmn.MiB + 10*cmn.KiB, SizeHist: []int64{1, 1, 1}, AtimeHist: []int64{1, 2}},
+		"":     {Count: 1, Size: 10, SizeHist: []int64{1, 0, 0}, AtimeHist: []int64{0, 1}},
+	}
+	if !reflect.DeepEqual(result.Groups, expected) {
+		t.Errorf("expected %+v, got %+v", expected, result.Groups)
+	}
+	if total := result.Total(); total.Count != 4 {
+		t.Errorf("expected total count 4, got %d", total.Count)
+	}
+}
+
+func TestAggregateGroupByPrefix(t *testing.T) {
+	a := NewAggregator(&AggregateMsg{GroupBy: GroupByPrefix})
+	for _, name := range []string{"train/0001.jpg", "train/sub/0002.jpg", "test/0003.jpg", "README"} {
+		a.add(name, 1, time.Now())
+	}
+	groups := a.Result().Groups
+	if len(groups) != 3 || groups["train/"].Count != 2 || groups["test/"].Count != 1 || groups[NoGroup].Count != 1 {
+		t.Errorf("unexpected groups: %+v", groups)
+	}
+}
+
+func TestAggregateMsgValidate(t *testing.T) {
+	invalid := []*AggregateMsg{
+		{GroupBy: "owner"},
+		{SizeBuckets: []int64{cmn.MiB, cmn.KiB}},
+		{AtimeBuckets: []int64{1, 1}},
+	}
+	for _, msg := range invalid {
+		if err := msg.Validate(); err == nil {
+			t.Errorf("%+v: expected an error", msg)
+		}
+	}
+}
@@ -7,6 +7,7 @@ package query
 import (
 	"fmt"
 	"math"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -38,7 +39,16 @@ const (
 	VersionLeF = "version_le"
 	VersionGeF = "version_ge"
 
-	ExtF = "ext"
+	ExtF       = "ext"
+	NameRegexF = "name_regex"
+
+	// match custom metadata (`LOM.CustomMD()`); note that user-defined metadata
+	// is stored there under `cluster.UserObjMDPrefix`, e.g.: "user.split"
+	CustomMDF       = "custom_md"        // key equals value
+	CustomMDExistsF = "custom_md_exists" // key exists
+	CustomMDPrefixF = "custom_md_prefix" // value starts with prefix
+
+	CksumF = "cksum" // checksum value equals
 )
 
 var functionMeta = map[string]filterMeta{
@@ -54,7 +64,14 @@ var functionMeta = map[string]filterMeta{
 	VersionLeF: {1, intArg},
 	VersionGeF: {1, intArg},
 
-	ExtF: {1, stringArg},
+	ExtF:       {1, stringArg},
+	NameRegexF: {1, stringArg},
+
+	CustomMDF:       {2, stringArg},
+	CustomMDExistsF: {1, stringArg},
+	CustomMDPrefixF: {2, stringArg},
+
+	CksumF: {1, stringArg},
 }
 
 func NewFilter(fname string, args []string) *FilterMsg {
@@ -136,6 +153,20 @@ func functionFilterMsgToObjectFilter(filterMsg *FilterMsg) (cluster.ObjectFilter
 		switch filterMsg.FName {
 		case ExtF:
 			return ExtFilter(filterMsg.Args[0]), nil
+		case NameRegexF:
+			re, err := regexp.Compile(filterMsg.Args[0])
+			if err != nil {
+				return nil, fmt.Errorf("%s failed: %v", filterMsg.FName, err)
+			}
+			return NameRegexFilter(re), nil
+		case CustomMDF:
+			return CustomMDFilter(filterMsg.Args[0], filterMsg.Args[1]), nil
+		case CustomMDExistsF:
+			return CustomMDExistsFilter(filterMsg.Args[0]), nil
+		case CustomMDPrefixF:
+			return CustomMDPrefixFilter(filterMsg.Args[0], filterMsg.Args[1]), nil
+		case CksumF:
+			return CksumFilter(filterMsg.Args[0]), nil
 		default:
 			cmn.Assert(false)
 			return nil, nil
@@ -216,7 +247,8 @@ func ATimeBeforeFilterMsg(before time.Time) *FilterMsg {
 
 func SizeFilter(min, max int64) cluster.ObjectFilter {
 	return func(lom *cluster.LOM) bool {
-		return lom.Size() >= min && lom.Size() <= max
+		size := lom.LogicalSize()
+		return size >= min && size <= max
 	}
 }
 
@@ -299,6 +331,88 @@ func ExtFilter(ext string) cluster.ObjectFilter {
 	}
 }
 
+func ExtFilterMsg(ext string) *FilterMsg {
+	return &FilterMsg{
+		Type:  FUNCTION,
+		FName: ExtF,
+		Args:  []string{ext},
+	}
+}
+
+func NameRegexFilter(re *regexp.Regexp) cluster.ObjectFilter {
+	return func(lom *cluster.LOM) bool {
+		return re.MatchString(lom.ObjName)
+	}
+}
+
+func NameRegexFilterMsg(pattern string) *FilterMsg {
+	return &FilterMsg{
+		Type:  FUNCTION,
+		FName: NameRegexF,
+		Args:  []string{pattern},
+	}
+}
+
+func CustomMDFilter(key, value string) cluster.ObjectFilter {
+	return func(lom *cluster.LOM) bool {
+		v, ok := lom.GetCustomMD(key)
+		return ok && v == value
+	}
+}
+
+func CustomMDFilterMsg(key, value string) *FilterMsg {
+	return &FilterMsg{
+		Type:  FUNCTION,
+		FName: CustomMDF,
+		Args:  []string{key, value},
+	}
+}
+
+func CustomMDExistsFilter(key string) cluster.ObjectFilter {
+	return func(lom *cluster.LOM) bool {
+		_, ok := lom.GetCustomMD(key)
+		return ok
+	}
+}
+
+func CustomMDExistsFilterMsg(key string) *FilterMsg {
+	return &FilterMsg{
+		Type:  FUNCTION,
+		FName: CustomMDExistsF,
+		Args:  []string{key},
+	}
+}
+
+func CustomMDPrefixFilter(key, prefix string) cluster.ObjectFilter {
+	return func(lom *cluster.LOM) bool {
+		v, ok := lom.GetCustomMD(key)
+		return ok && strings.HasPrefix(v, prefix)
+	}
+}
+
+func CustomMDPrefixFilterMsg(key, prefix string) *FilterMsg {
+	return &FilterMsg{
+		Type:  FUNCTION,
+		FName: CustomMDPrefixF,
+		Args:  []string{key, prefix},
+	}
+}
+
+func CksumFilter(value string) cluster.ObjectFilter {
+	return func(lom *cluster.LOM) bool {
+		cksum := lom.Cksum()
+		return cksum != nil && cksum.Value() == value
+	}
+}
+
+func CksumFilterMsg(value string) *FilterMsg {
+	return &FilterMsg{
+		Type:  FUNCTION,
+		FName: CksumF,
+		Args:  []string{value},
+	}
+}
+
 func And(filters ...cluster.ObjectFilter) cluster.ObjectFilter {
 	return func(lom *cluster.LOM) bool {
 		for _, f := range filters {
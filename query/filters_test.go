@@ -0,0 +1,88 @@
+// Package query provides interface to iterate over objects with additional filtering
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package query
+
+import (
+	"testing"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+func TestCustomFilters(t *testing.T) {
+	lom := &cluster.LOM{ObjName: "shards/shard-001.tar"}
+	lom.SetCustomMD(cmn.SimpleKVs{"user.split": "train", "user.source": "s3://imagenet"})
+	lom.SetCksum(cmn.NewCksum(cmn.ChecksumXXHash, "0123456789abcdef"))
+
+	tests := []struct {
+		filter *FilterMsg
+		match  bool
+	}{
+		{filter: CustomMDFilterMsg("user.split", "train"), match: true},
+		{filter: CustomMDFilterMsg("user.split", "test"), match: false},
+		{filter: CustomMDExistsFilterMsg("user.source"), match: true},
+		{filter: CustomMDExistsFilterMsg("user.label-set"), match: false},
+		{filter: CustomMDPrefixFilterMsg("user.source", "s3://"), match: true},
+		{filter: CustomMDPrefixFilterMsg("user.split", "tr-"), match: false},
+		{filter: NameRegexFilterMsg(`shard-\d+\.tar$`), match: true},
+		{filter: NameRegexFilterMsg(`^shard-`), match: false},
+		{filter: CksumFilterMsg("0123456789abcdef"), match: true},
+		{filter: CksumFilterMsg("fedcba9876543210"), match: false},
+		{
+			filter: NewAndFilter(CustomMDFilterMsg("user.split", "train"), ExtFilterMsg("tar")),
+			match:  true,
+		},
+	}
+	for _, test := range tests {
+		f, err := ObjFilterFromMsg(test.filter)
+		if err != nil {
+			t.Fatalf("%s%v: %v", test.filter.FName, test.filter.Args, err)
+		}
+		if f(lom) != test.match {
+			t.Errorf("%s%v: expected match=%t", test.filter.FName, test.filter.Args, test.match)
+		}
+	}
+}
+
+func TestInvalidFilters(t *testing.T) {
+	invalid := []*FilterMsg{
+		NameRegexFilterMsg("shard-("),
+		NewFilter(CustomMDF, []string{"user.split"}),
+		NewFilter("unknown", nil),
+	}
+	for _, msg := range invalid {
+		if _, err := ObjFilterFromMsg(msg); err == nil {
+			t.Errorf("%s%v: expected an error", msg.FName, msg.Args)
+		}
+	}
+}
+
+func TestRecordFilters(t *testing.T) {
+	rec := &Record{Shard: "shard-001.tar", Name: "0001.jpg", Size: 100}
+	tests := []struct {
+		filter *FilterMsg
+		match  bool
+	}{
+		{filter: nil, match: true},
+		{filter: ExtFilterMsg("jpg"), match: true},
+		{filter: ExtFilterMsg(".cls"), match: false},
+		{filter: NameRegexFilterMsg(`^\d+\.jpg$`), match: true},
+		{filter: SizeLEFilterMsg(99), match: false},
+		{filter: SizeGEFilterMsg(100), match: true},
+		{filter: NewOrFilter(ExtFilterMsg("cls"), SizeFilterMsg(50, 150)), match: true},
+	}
+	for _, test := range tests {
+		f, err := RecordFilterFromMsg(test.filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if f(rec) != test.match {
+			t.Errorf("%v: expected match=%t", test.filter, test.match)
+		}
+	}
+	if _, err := RecordFilterFromMsg(CustomMDExistsFilterMsg("user.split")); err == nil {
+		t.Error("expected custom metadata filter to be rejected for records")
+	}
+}
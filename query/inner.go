@@ -0,0 +1,140 @@
+// Package query provides interface to iterate over objects with additional filtering
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package query
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strings"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/dsort/extract"
+)
+
+type (
+	// Record is a single file inside a shard
+	Record struct {
+		Shard string
+		Name  string
+		Size  int64
+	}
+
+	RecordFilter func(rec *Record) bool
+
+	// SelectRecordFunc is called for each selected record; the reader is
+	// valid only until the function returns.
+	SelectRecordFunc func(rec *Record, r io.Reader) error
+)
+
+// functions applicable to shard records (a subset of `functionMeta`)
+var recordFunctions = cmn.StringSet{ExtF: {}, NameRegexF: {}, SizeF: {}, SizeLeF: {}, SizeGeF: {}}
+
+// FullName returns the name of the record prefixed with its shard name, e.g.:
+// "shard-001.tar/0001.jpg"
+func (rec *Record) FullName() string { return rec.Shard + "/" + rec.Name }
+
+func RecordFilterFromMsg(filter *FilterMsg) (RecordFilter, error) {
+	if filter == nil {
+		return func(*Record) bool { return true }, nil
+	}
+	switch filter.Type {
+	case AND, OR:
+		if len(filter.Filters) < 2 {
+			return nil, fmt.Errorf("expected %s filter to have at least 2 inner filters, got %d", filter.Type, len(filter.Filters))
+		}
+		filters := make([]RecordFilter, 0, len(filter.Filters))
+		for _, msgFilter := range filter.Filters {
+			f, err := RecordFilterFromMsg(msgFilter)
+			if err != nil {
+				return nil, err
+			}
+			filters = append(filters, f)
+		}
+		if filter.Type == AND {
+			return func(rec *Record) bool {
+				for _, f := range filters {
+					if !f(rec) {
+						return false
+					}
+				}
+				return true
+			}, nil
+		}
+		return func(rec *Record) bool {
+			for _, f := range filters {
+				if f(rec) {
+					return true
+				}
+			}
+			return false
+		}, nil
+	case FUNCTION:
+		return recordFunctionFilter(filter)
+	default:
+		return nil, fmt.Errorf("unknown type %s", filter.Type)
+	}
+}
+
+func recordFunctionFilter(filterMsg *FilterMsg) (RecordFilter, error) {
+	if !recordFunctions.Contains(filterMsg.FName) {
+		return nil, fmt.Errorf("function %q is not applicable to records", filterMsg.FName)
+	}
+	fMeta := functionMeta[filterMsg.FName]
+	if len(filterMsg.Args) != fMeta.argsCnt {
+		return nil, fmt.Errorf("%s: expected %d arguments, got %d", filterMsg.FName, fMeta.argsCnt, len(filterMsg.Args))
+	}
+	switch filterMsg.FName {
+	case ExtF:
+		ext := "." + strings.TrimPrefix(filterMsg.Args[0], ".")
+		return func(rec *Record) bool { return strings.HasSuffix(rec.Name, ext) }, nil
+	case NameRegexF:
+		re, err := regexp.Compile(filterMsg.Args[0])
+		if err != nil {
+			return nil, fmt.Errorf("%s failed: %v", filterMsg.FName, err)
+		}
+		return func(rec *Record) bool { return re.MatchString(rec.Name) }, nil
+	}
+
+	v, err := cmn.StringSliceToIntSlice(filterMsg.Args)
+	if err != nil {
+		return nil, fmt.Errorf("%s failed: %v", filterMsg.FName, err)
+	}
+	min, max := int64(0), int64(math.MaxInt64)
+	switch filterMsg.FName {
+	case SizeF:
+		min, max = v[0], v[1]
+	case SizeLeF:
+		max = v[0]
+	case SizeGeF:
+		min = v[0]
+	default:
+		cmn.Assert(false)
+	}
+	return func(rec *Record) bool { return rec.Size >= min && rec.Size <= max }, nil
+}
+
+// SelectRecords iterates over the records of a given shard and calls `cb` for
+// the ones that match the filter. The caller is responsible for loading and
+// locking the LOM and for providing the reader of its (decoded) content.
+func SelectRecords(lom *cluster.LOM, r *io.SectionReader, filter RecordFilter, cb SelectRecordFunc) error {
+	ext := extract.ShardExt(lom.ObjName)
+	if ext == "" {
+		return nil
+	}
+	err := extract.IterRecords(r, ext, func(name string, size int64, r io.Reader) error {
+		rec := &Record{Shard: lom.ObjName, Name: name, Size: size}
+		if !filter(rec) {
+			return nil
+		}
+		return cb(rec, r)
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %v", lom, err)
+	}
+	return nil
+}
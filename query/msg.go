@@ -26,17 +26,38 @@ type (
 
 	// Definition of a query
 	DefMsg struct {
-		OuterSelect OuterSelectMsg `json:"outer_select"`
-		From        FromMsg        `json:"from"`
-		Where       WhereMsg       `json:"where"`
+		OuterSelect OuterSelectMsg  `json:"outer_select"`
+		InnerSelect *InnerSelectMsg `json:"inner_select,omitempty"`
+		From        FromMsg         `json:"from"`
+		Where       WhereMsg        `json:"where"`
+		Aggregate   *AggregateMsg   `json:"aggregate,omitempty"`
 	}
 
 	// OuterSelect -> Look only on objects' metadata.
-	// In the future we might have InnerSelect, which looks into objects' contents
 	OuterSelectMsg struct {
 		Template string `json:"objects_source"`
 	}
 
+	// InnerSelect -> Look into objects' contents: open the shards (tar, tgz, zip)
+	// selected by OuterSelect and Where, and select the records matching the filter.
+	// Supported record filters: ExtF, NameRegexF, SizeF, SizeLeF, SizeGeF.
+	InnerSelectMsg struct {
+		Filter    *FilterMsg `json:"filter"`
+		NamesOnly bool       `json:"names_only"` // list the records instead of streaming them as a tar
+	}
+
+	// Aggregate -> Instead of returning the objects, compute (per target) and
+	// merge (at the proxy) their count, total size, and size and atime histograms,
+	// optionally grouped by the object name prefix or extension.
+	// Histogram boundaries are inclusive upper bounds: N boundaries produce N+1 bins
+	// with the last bin counting everything above the last boundary.
+	AggregateMsg struct {
+		GroupBy      string  `json:"group_by,omitempty"`      // one of: GroupByPrefix, GroupByExt (default: no grouping)
+		Delimiter    string  `json:"delimiter,omitempty"`     // GroupByPrefix only (default: "/")
+		SizeBuckets  []int64 `json:"size_buckets,omitempty"`  // bytes
+		AtimeBuckets []int64 `json:"atime_buckets,omitempty"` // unix nanoseconds
+	}
+
 	FromMsg struct {
 		Bck cmn.Bck `json:"bucket"`
 	}
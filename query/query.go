@@ -24,6 +24,7 @@ type (
 		ObjectsSource *ObjectsSource
 		BckSource     *BucketSource
 		filter        cluster.ObjectFilter
+		recordFilter  RecordFilter // inner select only
 	}
 )
 
@@ -42,6 +43,9 @@ func (q *ObjectsQuery) Filter() cluster.ObjectFilter {
 	return func(*cluster.LOM) bool { return true }
 }
 
+// RecordFilter returns nil if the query does not look into objects' contents
+func (q *ObjectsQuery) RecordFilter() RecordFilter { return q.recordFilter }
+
 func TemplateObjSource(pt *cmn.ParsedTemplate) *ObjectsSource {
 	return &ObjectsSource{Pt: pt}
 }
@@ -71,5 +75,15 @@ func NewQueryFromMsg(msg *DefMsg) (q *ObjectsQuery, err error) {
 	if err != nil {
 		return nil, err
 	}
+	if msg.InnerSelect != nil {
+		if q.recordFilter, err = RecordFilterFromMsg(msg.InnerSelect.Filter); err != nil {
+			return nil, err
+		}
+	}
+	if msg.Aggregate != nil {
+		if err = msg.Aggregate.Validate(); err != nil {
+			return nil, err
+		}
+	}
 	return q, nil
 }
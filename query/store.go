@@ -0,0 +1,88 @@
+// Package query provides interface to iterate over objects with additional filtering
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package query
+
+import (
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/dbdriver"
+	jsoniter "github.com/json-iterator/go"
+)
+
+// Queries initialized via InitMsg are persisted (both by the proxy and by
+// the targets) so that a client can resume iterating with the same handle
+// after a node restarts.
+
+const (
+	queryCollection = "queries"
+	persistedTTL    = 24 * time.Hour // records that were not updated for this long are considered abandoned
+
+	PurgeHkName     = "query-purge"
+	PurgeHkInterval = time.Hour
+)
+
+type (
+	PersistedQuery struct {
+		Msg     InitMsg   `json:"msg"`
+		Updated time.Time `json:"updated"`
+		// proxy only: targets (in order) that the query was initialized on
+		Targets []string `json:"targets,omitempty"`
+		// target only: proxy that gets notified when the query finishes,
+		// and the last discarded (consumed) object name
+		Owner  string `json:"owner,omitempty"`
+		Cursor string `json:"cursor,omitempty"`
+	}
+)
+
+func SavePersisted(db dbdriver.Driver, handle string, pq *PersistedQuery) error {
+	pq.Updated = time.Now()
+	return db.Set(queryCollection, handle, pq)
+}
+
+// LoadPersisted returns dbdriver.ErrNotFound if there is no such query or
+// if the query has been abandoned.
+func LoadPersisted(db dbdriver.Driver, handle string) (*PersistedQuery, error) {
+	pq := &PersistedQuery{}
+	if err := db.Get(queryCollection, handle, pq); err != nil {
+		return nil, err
+	}
+	if time.Since(pq.Updated) > persistedTTL {
+		DeletePersisted(db, handle)
+		return nil, dbdriver.NewErrNotFound(queryCollection, handle)
+	}
+	return pq, nil
+}
+
+func DeletePersisted(db dbdriver.Driver, handle string) {
+	if err := db.Delete(queryCollection, handle); err != nil && !dbdriver.IsErrNotFound(err) {
+		glog.Error(err)
+	}
+}
+
+// PurgeAbandoned removes the records of the queries abandoned by clients: the
+// ones that nobody resumes are never loaded (see LoadPersisted) again.
+// Returns the interval of the next call (housekeeping).
+func PurgeAbandoned(db dbdriver.Driver) time.Duration {
+	records, err := db.GetAll(queryCollection, "")
+	if err != nil {
+		if !dbdriver.IsErrNotFound(err) {
+			glog.Error(err)
+		}
+		return PurgeHkInterval
+	}
+	for handle, record := range records {
+		pq := &PersistedQuery{}
+		if err := jsoniter.UnmarshalFromString(record, pq); err != nil {
+			glog.Errorf("query %q: %v - removing", handle, err)
+			DeletePersisted(db, handle)
+			continue
+		}
+		if time.Since(pq.Updated) > persistedTTL {
+			DeletePersisted(db, handle)
+		}
+	}
+	return PurgeHkInterval
+}
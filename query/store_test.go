@@ -0,0 +1,68 @@
+// Package query provides interface to iterate over objects with additional filtering
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package query
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/dbdriver"
+)
+
+func TestPersistedQuery(t *testing.T) {
+	var (
+		db = dbdriver.NewDBMock()
+		pq = &PersistedQuery{
+			Msg:    InitMsg{QueryMsg: DefMsg{From: FromMsg{Bck: cmn.Bck{Name: "abc", Provider: cmn.ProviderAIS}}}, WorkersCnt: 2},
+			Cursor: "shard-042.tar",
+		}
+	)
+	if err := SavePersisted(db, "handle", pq); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadPersisted(db, "handle")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Cursor != pq.Cursor || loaded.Msg.WorkersCnt != 2 || loaded.Msg.QueryMsg.From.Bck.Name != "abc" {
+		t.Errorf("expected %+v, got %+v", pq, loaded)
+	}
+
+	// abandoned queries are not resumed
+	pq.Updated = time.Now().Add(-persistedTTL - time.Minute)
+	if err := db.Set(queryCollection, "handle", pq); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadPersisted(db, "handle"); !dbdriver.IsErrNotFound(err) {
+		t.Errorf("expected not found, got %v", err)
+	}
+	if _, err := db.GetString(queryCollection, "handle"); !dbdriver.IsErrNotFound(err) {
+		t.Errorf("expected abandoned query to be deleted, got %v", err)
+	}
+}
+
+func TestPurgeAbandoned(t *testing.T) {
+	var (
+		db        = dbdriver.NewDBMock()
+		active    = &PersistedQuery{Cursor: "active"}
+		abandoned = &PersistedQuery{Cursor: "abandoned", Updated: time.Now().Add(-persistedTTL - time.Minute)}
+	)
+	if err := SavePersisted(db, "active", active); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Set(queryCollection, "abandoned", abandoned); err != nil {
+		t.Fatal(err)
+	}
+	if interval := PurgeAbandoned(db); interval != PurgeHkInterval {
+		t.Errorf("expected interval %v, got %v", PurgeHkInterval, interval)
+	}
+	if _, err := db.GetString(queryCollection, "abandoned"); !dbdriver.IsErrNotFound(err) {
+		t.Errorf("expected abandoned query to be purged, got %v", err)
+	}
+	if _, err := LoadPersisted(db, "active"); err != nil {
+		t.Errorf("expected active query to be kept, got %v", err)
+	}
+}
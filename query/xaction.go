@@ -9,9 +9,11 @@ import (
 	"sync"
 	"time"
 
+	"github.com/NVIDIA/aistore/3rdparty/atomic"
 	"github.com/NVIDIA/aistore/3rdparty/glog"
 	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/dbdriver"
 	"github.com/NVIDIA/aistore/fs"
 	"github.com/NVIDIA/aistore/objwalk/walkinfo"
 )
@@ -29,6 +31,12 @@ type (
 		query               *ObjectsQuery
 		resultCh            chan *Result
 		lastDiscardedResult string
+
+		// resumable queries only (see `Persist`)
+		db         dbdriver.Driver
+		persisted  *PersistedQuery
+		startAfter string
+		expired    atomic.Bool
 	}
 
 	Result struct {
@@ -61,6 +69,20 @@ func (r *ObjectsListingXact) stop() {
 
 func (r *ObjectsListingXact) IsMountpathXact() bool { return false } // TODO -- FIXME
 
+// Persist makes the query resumable: its definition and cursor are stored in
+// the DB and updated upon every discard. The xaction skips the objects up to
+// and including the cursor. Must be called before Start.
+func (r *ObjectsListingXact) Persist(db dbdriver.Driver, pq *PersistedQuery) error {
+	r.db, r.persisted = db, pq
+	r.startAfter, r.lastDiscardedResult = pq.Cursor, pq.Cursor
+	return SavePersisted(db, r.ID().String(), pq)
+}
+
+// consumed in one of the previous runs of a resumed query
+func (r *ObjectsListingXact) consumed(objName string) bool {
+	return r.startAfter != "" && cmn.PageMarkerIncludesObject(r.startAfter, objName)
+}
+
 func (r *ObjectsListingXact) Start() {
 	defer func() {
 		r.fetchingDone = true
@@ -90,6 +112,11 @@ func (r *ObjectsListingXact) putResult(res *Result) (end bool) {
 	case <-r.ChanAbort():
 		return true
 	case <-r.timer.C:
+		if r.db != nil {
+			// keep the persisted query: it will be resumed upon the next request
+			r.expired.Store(true)
+			Registry.Delete(r.ID().String())
+		}
 		return true
 	case r.resultCh <- res:
 		r.timer.Reset(xactionTTL)
@@ -120,7 +147,7 @@ func (r *ObjectsListingXact) startFromTemplate() {
 			return
 		}
 
-		if si.ID() != r.t.Snode().ID() {
+		if si.ID() != r.t.Snode().ID() || r.consumed(objName) {
 			continue
 		}
 
@@ -156,6 +183,9 @@ func (r *ObjectsListingXact) startFromBck() {
 		if entry == nil && err == nil {
 			return nil
 		}
+		if entry != nil && r.consumed(entry.Name) {
+			return nil
+		}
 		if r.putResult(&Result{entry: entry, err: err}) {
 			return cmn.NewAbortedError(r.t.Snode().DaemonID + " ResultSetXact")
 		}
@@ -216,10 +246,19 @@ func (r *ObjectsListingXact) discardN(n uint) {
 		size := cmn.Min(int(n), len(r.buff))
 		r.lastDiscardedResult = r.buff[size-1].Name
 		r.buff = r.buff[size:]
+		if r.db != nil {
+			r.persisted.Cursor = r.lastDiscardedResult
+			if err := SavePersisted(r.db, r.ID().String(), r.persisted); err != nil {
+				glog.Errorf("%s: failed to persist cursor %q: %v", r, r.lastDiscardedResult, err)
+			}
+		}
 	}
 
 	if r.fetchingDone && len(r.buff) == 0 {
 		Registry.Delete(r.ID().String())
+		if r.db != nil && !r.expired.Load() {
+			DeletePersisted(r.db, r.ID().String())
+		}
 		r.Finish()
 	}
 }
@@ -16,6 +16,7 @@ import (
 	"github.com/NVIDIA/aistore/3rdparty/glog"
 	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/dedup"
 	"github.com/NVIDIA/aistore/fs"
 	"github.com/NVIDIA/aistore/stats"
 	"github.com/NVIDIA/aistore/transport"
@@ -531,7 +532,7 @@ func (rj *rebalanceJogger) jog(mpathInfo *fs.MountpathInfo) {
 
 	opts := &fs.Options{
 		Mpath:    mpathInfo,
-		CTs:      []string{fs.ObjectType},
+		CTs:      []string{fs.ObjectType, fs.VersionType},
 		Callback: rj.walk,
 		Sorted:   false,
 	}
@@ -604,6 +605,9 @@ func (rj *rebalanceJogger) walk(fqn string, de fs.DirEntry) (err error) {
 	if lom.Bck().Props.EC.Enabled {
 		return filepath.SkipDir
 	}
+	if lom.ParsedFQN.ContentType == fs.VersionType {
+		return rj.walkVersion(lom)
+	}
 
 	// Rebalance, maybe
 	tsi, err = cluster.HrwTarget(lom.Uname(), rj.smap)
@@ -625,6 +629,13 @@ func (rj *rebalanceJogger) walk(fqn string, de fs.DirEntry) (err error) {
 	if err := lom.Load(); err != nil {
 		return err
 	}
+	// the dirty mark does not migrate - write the object to the cloud first
+	if lom.IsDirty() {
+		if err := xaction.Flush(t, lom); err != nil {
+			glog.Errorf("%s: failed to write to the cloud (skipping): %v", lom, err)
+			return nil
+		}
+	}
 	if rj.sema == nil { // rebalance.multiplier == 1
 		err = rj.send(lom, tsi, true /*addAck*/)
 	} else { // // rebalance.multiplier > 1
@@ -639,11 +650,17 @@ func (rj *rebalanceJogger) walk(fqn string, de fs.DirEntry) (err error) {
 	return
 }
 
+// custom metadata goes along with the object (see cluster.LOM.PortableCustomMD)
+func sentCustomMD(lom *cluster.LOM) *objCustomMD {
+	return &objCustomMD{md: lom.PortableCustomMD()}
+}
+
 func (rj *rebalanceJogger) send(lom *cluster.LOM, tsi *cluster.Snode, addAck bool) (err error) {
 	var (
-		file                  *cmn.FileHandle
+		file                  cmn.ReadOpenCloser
 		cksum                 *cmn.Cksum
 		cksumType, cksumValue string
+		size                  int64
 	)
 	lom.Lock(false) // NOTE: unlock in objSentCallback() unless err
 	defer func() {
@@ -666,12 +683,22 @@ func (rj *rebalanceJogger) send(lom *cluster.LOM, tsi *cluster.Snode, addAck boo
 		lom.Unlock(false)
 		return
 	}
-	if cksum, err = lom.ComputeCksumIfMissing(); err != nil {
-		return
-	}
-	cksumType, cksumValue = cksum.Get()
-	if file, err = cmn.NewFileHandle(lom.FQN); err != nil {
-		return
+	if lom.IsDeduped() {
+		// send the content (see cmn.BckDedupConf) - the receiver computes the checksum
+		var recipe *dedup.Recipe
+		if recipe, err = dedup.ReadRecipe(lom.FQN); err != nil {
+			return
+		}
+		file, size = recipe.NewHandle(), recipe.Size()
+	} else {
+		if cksum, err = lom.ComputeCksumIfMissing(); err != nil {
+			return
+		}
+		cksumType, cksumValue = cksum.Get()
+		if file, err = cmn.NewFileHandle(lom.FQN); err != nil {
+			return
+		}
+		size = lom.Size()
 	}
 	if addAck {
 		// cache it as pending-acknowledgement (optimistically - see objSentCallback)
@@ -681,13 +708,13 @@ func (rj *rebalanceJogger) send(lom *cluster.LOM, tsi *cluster.Snode, addAck boo
 	var (
 		ack    = regularAck{rebID: rj.m.RebID(), daemonID: rj.m.t.Snode().ID()}
 		mm     = rj.m.t.GetSmallMMSA()
-		opaque = ack.NewPack(mm)
+		opaque = ack.NewObjPack(mm, sentCustomMD(lom))
 		hdr    = transport.Header{
 			Bck:     lom.Bck().Bck,
 			ObjName: lom.ObjName,
 			Opaque:  opaque,
 			ObjAttrs: transport.ObjectAttrs{
-				Size:       lom.Size(),
+				Size:       size,
 				Atime:      lom.AtimeUnix(),
 				CksumType:  cksumType,
 				CksumValue: cksumValue,
@@ -389,6 +389,11 @@ func (reb *Manager) recvObjRegular(hdr transport.Header, smap *cluster.Smap, unp
 		glog.Warningf("received object %s/%s: %s", hdr.Bck, hdr.ObjName, reb.rebIDMismatchMsg(ack.rebID))
 		return
 	}
+	omd := &objCustomMD{}
+	if err := unpacker.ReadAny(omd); err != nil {
+		glog.Errorf("Failed to parse object metadata: %v", err)
+		return
+	}
 	tsid := ack.daemonID // the sender
 	// Rx
 	lom := &cluster.LOM{T: reb.t, ObjName: hdr.ObjName}
@@ -396,6 +401,7 @@ func (reb *Manager) recvObjRegular(hdr transport.Header, smap *cluster.Smap, unp
 		glog.Error(err)
 		return
 	}
+	lom.SetCustomMD(omd.md)
 	aborted, running := IsRebalancing(cmn.ActRebalance)
 	if aborted || !running {
 		return
@@ -508,6 +514,10 @@ func (reb *Manager) recvObj(w http.ResponseWriter, hdr transport.Header, objRead
 		reb.recvObjRegular(hdr, smap, unpacker, objReader)
 		return
 	}
+	if act == rebMsgVersion {
+		reb.recvVersion(hdr, smap, unpacker, objReader)
+		return
+	}
 
 	if act != rebMsgEC {
 		glog.Errorf("Invalid ACK type %d, expected %d", act, rebMsgEC)
@@ -640,6 +650,10 @@ func (reb *Manager) recvAck(w http.ResponseWriter, hdr transport.Header, _ io.Re
 		reb.recvECAck(hdr, unpacker)
 		return
 	}
+	if act == rebMsgVersion {
+		reb.recvVersionAck(hdr, unpacker)
+		return
+	}
 	if act != rebMsgRegular {
 		glog.Errorf("Invalid ACK type %d, expected %d", act, rebMsgRegular)
 	}
@@ -17,6 +17,7 @@ const (
 	rebMsgRegular   = iota // regular rebalance: acknowledge/Object
 	rebMsgEC               // EC rebalance: acknowledge/CT/Namespace
 	rebMsgPushStage        // push notification of target moved to the next stage
+	rebMsgVersion          // noncurrent version of an object: acknowledge/Version
 )
 const rebMsgKindSize = 1
 
@@ -25,6 +26,11 @@ type (
 		rebID    int64
 		daemonID string // sender's DaemonID
 	}
+	// custom metadata of the object that follows regularAck when the object
+	// is sent (object lock, encryption, compression, etc. - see cluster.LOM)
+	objCustomMD struct {
+		md cmn.SimpleKVs
+	}
 	ecAck struct {
 		rebID    int64
 		daemonID string // sender's DaemonID
@@ -49,6 +55,8 @@ var (
 	_ cmn.Packer   = &ecAck{}
 	_ cmn.Packer   = &pushReq{}
 	_ cmn.Unpacker = &pushReq{}
+	_ cmn.Packer   = &objCustomMD{}
+	_ cmn.Unpacker = &objCustomMD{}
 )
 
 func (rack *regularAck) Unpack(unpacker *cmn.ByteUnpack) (err error) {
@@ -65,10 +73,14 @@ func (rack *regularAck) Pack(packer *cmn.BytePack) {
 }
 
 func (rack *regularAck) NewPack(mm *memsys.MMSA) []byte { // TODO: consider adding as another cmn.Packer interface
+	return rack.newPack(mm, rebMsgRegular)
+}
+
+func (rack *regularAck) newPack(mm *memsys.MMSA, kind byte) []byte {
 	l := rebMsgKindSize + rack.PackedSize()
 	buf, _ := mm.Alloc(int64(l))
 	packer := cmn.NewPacker(buf, l)
-	packer.WriteByte(rebMsgRegular)
+	packer.WriteByte(kind)
 	packer.WriteAny(rack)
 	return packer.Bytes()
 }
@@ -78,6 +90,68 @@ func (rack *regularAck) PackedSize() int {
 	return cmn.SizeofI64 + cmn.SizeofLen + len(rack.daemonID)
 }
 
+// the object being sent: regularAck followed by the object's custom metadata
+func (rack *regularAck) NewObjPack(mm *memsys.MMSA, md *objCustomMD) []byte {
+	l := rebMsgKindSize + rack.PackedSize() + md.PackedSize()
+	buf, _ := mm.Alloc(int64(l))
+	packer := cmn.NewPacker(buf, l)
+	packer.WriteByte(rebMsgRegular)
+	packer.WriteAny(rack)
+	packer.WriteAny(md)
+	return packer.Bytes()
+}
+
+// the noncurrent version being sent: regularAck followed by the time the version
+// became noncurrent and the version's custom metadata
+func (rack *regularAck) NewVersionPack(mm *memsys.MMSA, archived int64, md *objCustomMD) []byte {
+	l := rebMsgKindSize + rack.PackedSize() + cmn.SizeofI64 + md.PackedSize()
+	buf, _ := mm.Alloc(int64(l))
+	packer := cmn.NewPacker(buf, l)
+	packer.WriteByte(rebMsgVersion)
+	packer.WriteAny(rack)
+	packer.WriteInt64(archived)
+	packer.WriteAny(md)
+	return packer.Bytes()
+}
+
+func (omd *objCustomMD) Unpack(unpacker *cmn.ByteUnpack) (err error) {
+	var (
+		cnt    uint16
+		key, v string
+	)
+	if cnt, err = unpacker.ReadUint16(); err != nil || cnt == 0 {
+		return
+	}
+	omd.md = make(cmn.SimpleKVs, cnt)
+	for i := 0; i < int(cnt); i++ {
+		if key, err = unpacker.ReadString(); err != nil {
+			return
+		}
+		if v, err = unpacker.ReadString(); err != nil {
+			return
+		}
+		omd.md[key] = v
+	}
+	return
+}
+
+func (omd *objCustomMD) Pack(packer *cmn.BytePack) {
+	packer.WriteUint16(uint16(len(omd.md)))
+	for key, v := range omd.md {
+		packer.WriteString(key)
+		packer.WriteString(v)
+	}
+}
+
+// number of entries + (length of key + key + length of value + value) for each
+func (omd *objCustomMD) PackedSize() int {
+	total := cmn.SizeofI16
+	for key, v := range omd.md {
+		total += 2*cmn.SizeofLen + len(key) + len(v)
+	}
+	return total
+}
+
 func (eack *ecAck) Unpack(unpacker *cmn.ByteUnpack) (err error) {
 	if eack.rebID, err = unpacker.ReadInt64(); err != nil {
 		return
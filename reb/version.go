@@ -0,0 +1,247 @@
+// Package reb provides resilvering and rebalancing functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package reb
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+	"unsafe"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/dedup"
+	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/stats"
+	"github.com/NVIDIA/aistore/transport"
+)
+
+// Noncurrent versions of objects (see cmn.VersionConf.History) migrate along
+// with the objects: the target that has a version of the object that is no
+// longer its own sends the version to the object's new owner and removes it
+// upon acknowledgement (rebMsgVersion both ways). Unlike objects, versions
+// are not retransmitted - unacknowledged versions stay where they are until
+// the next rebalance.
+
+// walkVersion sends the noncurrent version, if misplaced
+func (rj *rebalanceJogger) walkVersion(vl *cluster.LOM) error {
+	objName, _, ok := fs.SplitVersion(vl.ObjName)
+	if !ok {
+		return nil
+	}
+	t := rj.m.t
+	lom := &cluster.LOM{T: t, ObjName: objName}
+	if err := lom.Init(vl.Bck().Bck); err != nil {
+		return nil
+	}
+	tsi, err := cluster.HrwTarget(lom.Uname(), rj.smap)
+	if err != nil {
+		return err
+	}
+	if tsi.ID() == t.Snode().ID() {
+		return nil
+	}
+	if err := rj.sendVersion(lom, vl.FQN, vl.ParsedFQN.MpathInfo, tsi); err != nil {
+		glog.Errorf("%s: failed to send version %s => %s: %v", t.Snode(), vl, tsi, err)
+	}
+	return nil
+}
+
+func (rj *rebalanceJogger) sendVersion(lom *cluster.LOM, vfqn string, mpathInfo *fs.MountpathInfo,
+	tsi *cluster.Snode) (err error) {
+	var (
+		vlom                  *cluster.LOM
+		finfo                 os.FileInfo
+		file                  cmn.ReadOpenCloser
+		cksumType, cksumValue string
+		size                  int64
+	)
+	lom.Lock(false) // NOTE: unlock in versionSentCallback() unless err
+	defer func() {
+		if err != nil {
+			lom.Unlock(false)
+		}
+	}()
+	if finfo, err = os.Stat(vfqn); err != nil {
+		return
+	}
+	if vlom, err = lom.VersionLOM(vfqn, mpathInfo); err != nil {
+		return
+	}
+	if vlom.IsDeduped() {
+		var recipe *dedup.Recipe
+		if recipe, err = dedup.ReadRecipe(vfqn); err != nil {
+			return
+		}
+		file, size = recipe.NewHandle(), recipe.Size()
+	} else {
+		if cksum := vlom.Cksum(); cksum != nil {
+			cksumType, cksumValue = cksum.Get()
+		}
+		if file, err = cmn.NewFileHandle(vfqn); err != nil {
+			return
+		}
+		size = vlom.Size()
+	}
+	var (
+		ack    = regularAck{rebID: rj.m.RebID(), daemonID: rj.m.t.Snode().ID()}
+		mm     = rj.m.t.GetSmallMMSA()
+		opaque = ack.NewVersionPack(mm, finfo.ModTime().UnixNano(), sentCustomMD(vlom))
+		hdr    = transport.Header{
+			Bck:     lom.Bck().Bck,
+			ObjName: lom.ObjName,
+			Opaque:  opaque,
+			ObjAttrs: transport.ObjectAttrs{
+				Size:       size,
+				Atime:      vlom.AtimeUnix(),
+				CksumType:  cksumType,
+				CksumValue: cksumValue,
+				Version:    vlom.Version(),
+			},
+		}
+		o = transport.Obj{Hdr: hdr, Callback: rj.versionSentCallback, CmplPtr: unsafe.Pointer(lom)}
+	)
+	rj.m.inQueue.Inc()
+	if err = rj.m.streams.Send(o, file, tsi); err != nil {
+		rj.m.inQueue.Dec()
+		mm.Free(opaque)
+		return
+	}
+	rj.m.laterx.Store(true)
+	return
+}
+
+func (rj *rebalanceJogger) versionSentCallback(hdr transport.Header, _ io.ReadCloser, lomptr unsafe.Pointer, err error) {
+	lom := (*cluster.LOM)(lomptr)
+	rj.m.inQueue.Dec()
+	lom.Unlock(false)
+	rj.m.t.GetSmallMMSA().Free(hdr.Opaque)
+	if err != nil {
+		glog.Errorf("%s: failed to send %s version %s, err: %v", rj.m.t.Snode(), lom, hdr.ObjAttrs.Version, err)
+		return
+	}
+	rj.m.statRunner.AddMany(
+		stats.NamedVal64{Name: stats.RebTxCount, Value: 1},
+		stats.NamedVal64{Name: stats.RebTxSize, Value: hdr.ObjAttrs.Size})
+}
+
+func (reb *Manager) recvVersion(hdr transport.Header, smap *cluster.Smap, unpacker *cmn.ByteUnpack, objReader io.Reader) {
+	defer cmn.DrainReader(objReader)
+	var (
+		ack      = &regularAck{}
+		omd      = &objCustomMD{}
+		archived int64
+		err      error
+	)
+	if err = unpacker.ReadAny(ack); err != nil {
+		glog.Errorf("Failed to parse acknowledge: %v", err)
+		return
+	}
+	if ack.rebID != reb.RebID() {
+		glog.Warningf("received version %s/%s: %s", hdr.Bck, hdr.ObjName, reb.rebIDMismatchMsg(ack.rebID))
+		return
+	}
+	if archived, err = unpacker.ReadInt64(); err != nil {
+		glog.Errorf("Failed to parse version time: %v", err)
+		return
+	}
+	if err = unpacker.ReadAny(omd); err != nil {
+		glog.Errorf("Failed to parse object metadata: %v", err)
+		return
+	}
+	lom := &cluster.LOM{T: reb.t, ObjName: hdr.ObjName}
+	if err = lom.Init(hdr.Bck); err != nil {
+		glog.Error(err)
+		return
+	}
+	if err = reb.saveVersion(lom, hdr, omd, objReader, time.Unix(0, archived)); err != nil {
+		glog.Errorf("%s: failed to receive %s version %s: %v", reb.t.Snode(), lom, hdr.ObjAttrs.Version, err)
+		return
+	}
+	reb.statRunner.AddMany(
+		stats.NamedVal64{Name: stats.RebRxCount, Value: 1},
+		stats.NamedVal64{Name: stats.RebRxSize, Value: hdr.ObjAttrs.Size},
+	)
+	// ACK
+	tsi := smap.GetTarget(ack.daemonID)
+	if tsi == nil {
+		glog.Errorf("%s target is not found in smap", ack.daemonID)
+		return
+	}
+	var (
+		rack = &regularAck{rebID: reb.RebID(), daemonID: reb.t.Snode().ID()}
+		mm   = reb.t.GetSmallMMSA()
+	)
+	hdr.Opaque = rack.newPack(mm, rebMsgVersion)
+	hdr.ObjAttrs.Size = 0
+	if err := reb.acks.Send(transport.Obj{Hdr: hdr, Callback: reb.rackSentCallback}, nil, tsi); err != nil {
+		mm.Free(hdr.Opaque)
+		glog.Error(err)
+	}
+}
+
+func (reb *Manager) saveVersion(lom *cluster.LOM, hdr transport.Header, omd *objCustomMD, r io.Reader,
+	archived time.Time) error {
+	var (
+		workFQN   = fs.CSM.GenContentParsedFQN(lom.ParsedFQN, fs.WorkfileType, fs.WorkfilePut)
+		buf, slab = reb.t.GetMMSA().Alloc()
+	)
+	cksum, err := cmn.SaveReader(workFQN, r, buf, hdr.ObjAttrs.CksumType, hdr.ObjAttrs.Size, "")
+	slab.Free(buf)
+	if err != nil {
+		return err
+	}
+	if hdr.ObjAttrs.CksumValue != "" {
+		expected := cmn.NewCksum(hdr.ObjAttrs.CksumType, hdr.ObjAttrs.CksumValue)
+		if !cksum.Equal(expected) {
+			cmn.RemoveFile(workFQN)
+			return fmt.Errorf("checksum mismatch: expected %s, got %s", expected, &cksum.Cksum)
+		}
+	}
+	lom.SetCustomMD(omd.md)
+	lom.SetAtimeUnix(hdr.ObjAttrs.Atime)
+	lom.SetVersion(hdr.ObjAttrs.Version)
+	lom.SetSize(hdr.ObjAttrs.Size)
+	lom.SetCksum(cksum.Clone())
+
+	lom.Lock(true)
+	err = lom.AddVersion(workFQN, archived)
+	lom.Unlock(true)
+	if err != nil {
+		cmn.RemoveFile(workFQN)
+	}
+	return err
+}
+
+func (reb *Manager) recvVersionAck(hdr transport.Header, unpacker *cmn.ByteUnpack) {
+	ack := &regularAck{}
+	if err := unpacker.ReadAny(ack); err != nil {
+		glog.Errorf("Failed to parse acknowledge: %v", err)
+		return
+	}
+	if ack.rebID != reb.rebID.Load() {
+		glog.Warningf("ACK from %s: %s", ack.daemonID, reb.rebIDMismatchMsg(ack.rebID))
+		return
+	}
+	lom := &cluster.LOM{T: reb.t, ObjName: hdr.ObjName}
+	if err := lom.Init(hdr.Bck); err != nil {
+		glog.Error(err)
+		return
+	}
+	lom.Lock(true)
+	defer lom.Unlock(true)
+	vlom, err := lom.LoadVersion(hdr.ObjAttrs.Version)
+	if err != nil {
+		if !cmn.IsObjNotExist(err) {
+			glog.Error(err)
+		}
+		return
+	}
+	if err := vlom.Remove(); err != nil {
+		glog.Errorf("%s: error removing %s version %s, err: %v", reb.t.Snode(), lom, vlom.Version(), err)
+	}
+}
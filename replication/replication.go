@@ -0,0 +1,221 @@
+// Package replication asynchronously replicates ais buckets to remote AIS clusters.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package replication
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/dbdriver"
+	jsoniter "github.com/json-iterator/go"
+)
+
+// Each target maintains a durable queue of the PUTs and deletes (of the
+// objects it stores) that are yet to be replicated (see cmn.BckReplicationConf).
+// The queue is persisted in the target's database and is processed in order
+// by a single worker, so that the remote bucket sees the same sequence of
+// updates. A failed entry is retried with exponential backoff: indefinitely
+// while the remote cluster is unreachable, and up to maxAttempts times
+// otherwise - after which the entry is dropped.
+
+const (
+	OpPut    = "put"
+	OpDelete = "delete"
+
+	collection  = "replication"
+	maxAttempts = 10
+	backoffMin  = time.Second
+	backoffMax  = time.Minute
+)
+
+type (
+	Entry struct {
+		Bck     cmn.Bck `json:"bck"`
+		ObjName string  `json:"obj"`
+		Op      string  `json:"op"`
+		Time    int64   `json:"time,string"` // when queued (Unix nanoseconds)
+		seq     uint64
+	}
+
+	// SendFunc replicates a given entry and returns the number of bytes sent
+	SendFunc func(e *Entry) (size int64, err error)
+
+	Manager struct {
+		db       dbdriver.Driver
+		send     SendFunc
+		mu       sync.Mutex
+		queue    []*Entry
+		seq      uint64
+		attempts int // of the entry at the head of the queue
+		stats    cmn.ReplicationStats
+		wakeCh   chan struct{}
+		stopCh   *cmn.StopCh
+	}
+)
+
+func (e *Entry) String() string {
+	return fmt.Sprintf("%s %s/%s", e.Op, e.Bck, e.ObjName)
+}
+
+func key(seq uint64) string { return fmt.Sprintf("%020d", seq) }
+
+/////////////
+// Manager //
+/////////////
+
+// NewManager loads the entries that were queued prior to restart.
+func NewManager(db dbdriver.Driver, send SendFunc) (*Manager, error) {
+	m := &Manager{db: db, send: send, wakeCh: make(chan struct{}, 1), stopCh: cmn.NewStopCh()}
+	values, err := db.GetAll(collection, "")
+	if err != nil && !dbdriver.IsErrNotFound(err) {
+		return nil, err
+	}
+	m.queue = make([]*Entry, 0, len(values))
+	for k, v := range values {
+		seq, err := strconv.ParseUint(k, 10, 64)
+		if err != nil {
+			glog.Errorf("replication: invalid key %q", k)
+			continue
+		}
+		e := &Entry{seq: seq}
+		if err := jsoniter.Unmarshal([]byte(v), e); err != nil {
+			glog.Errorf("replication: failed to load %q: %v", k, err)
+			continue
+		}
+		m.queue = append(m.queue, e)
+		if seq > m.seq {
+			m.seq = seq
+		}
+	}
+	sort.Slice(m.queue, func(i, j int) bool { return m.queue[i].seq < m.queue[j].seq })
+	m.stats.Pending = int64(len(m.queue))
+	return m, nil
+}
+
+// Enqueue persists the entry and wakes up the worker.
+func (m *Manager) Enqueue(bck cmn.Bck, objName, op string) {
+	e := &Entry{Bck: bck, ObjName: objName, Op: op, Time: time.Now().UnixNano()}
+	m.mu.Lock()
+	m.seq++
+	e.seq = m.seq
+	if err := m.db.Set(collection, key(e.seq), e); err != nil {
+		m.mu.Unlock()
+		glog.Errorf("replication: failed to queue %s: %v", e, err)
+		return
+	}
+	m.queue = append(m.queue, e)
+	m.stats.Pending++
+	m.mu.Unlock()
+	select {
+	case m.wakeCh <- struct{}{}:
+	default:
+	}
+}
+
+// Run processes the queue until stopped.
+func (m *Manager) Run() {
+	var backoff time.Duration
+	glog.Infof("replication: started (pending %d)", m.Stats().Pending)
+	for {
+		e := m.head()
+		if e == nil {
+			select {
+			case <-m.wakeCh:
+				continue
+			case <-m.stopCh.Listen():
+				return
+			}
+		}
+		size, err := m.send(e)
+		if err == nil {
+			backoff = 0
+			m.done(e, size)
+			continue
+		}
+		if !m.failed(e, err) {
+			backoff = 0
+			continue
+		}
+		if backoff = 2 * backoff; backoff < backoffMin {
+			backoff = backoffMin
+		} else if backoff > backoffMax {
+			backoff = backoffMax
+		}
+		select {
+		case <-time.After(backoff):
+		case <-m.stopCh.Listen():
+			return
+		}
+	}
+}
+
+func (m *Manager) Stop(err error) {
+	glog.Infof("replication: stopping, err: %v", err)
+	m.stopCh.Close()
+}
+
+func (m *Manager) Stats() *cmn.ReplicationStats {
+	m.mu.Lock()
+	stats := m.stats
+	if len(m.queue) > 0 {
+		stats.Lag = cmn.DurationJSON(time.Since(time.Unix(0, m.queue[0].Time)))
+	}
+	m.mu.Unlock()
+	return &stats
+}
+
+func (m *Manager) head() (e *Entry) {
+	m.mu.Lock()
+	if len(m.queue) > 0 {
+		e = m.queue[0]
+	}
+	m.mu.Unlock()
+	return
+}
+
+// remove the head of the queue (under lock)
+func (m *Manager) pop(e *Entry) {
+	if err := m.db.Delete(collection, key(e.seq)); err != nil && !dbdriver.IsErrNotFound(err) {
+		glog.Errorf("replication: failed to dequeue %s: %v", e, err)
+	}
+	m.queue[0] = nil
+	m.queue = m.queue[1:]
+	m.stats.Pending--
+	m.attempts = 0
+}
+
+func (m *Manager) done(e *Entry, size int64) {
+	m.mu.Lock()
+	m.pop(e)
+	if e.Op == OpDelete {
+		m.stats.DelCount++
+	} else {
+		m.stats.PutCount++
+		m.stats.PutSize += size
+	}
+	m.mu.Unlock()
+}
+
+// failed returns true if the entry is to be retried
+func (m *Manager) failed(e *Entry, err error) (retry bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stats.ErrCount++
+	m.stats.LastErr = fmt.Sprintf("%s: %v", e, err)
+	if cmn.IsErrConnectionRefused(err) || cmn.IsErrConnectionReset(err) {
+		return true
+	}
+	if m.attempts++; m.attempts < maxAttempts {
+		return true
+	}
+	glog.Errorf("replication: giving up on %s after %d attempts: %v", e, m.attempts, err)
+	m.pop(e)
+	return false
+}
@@ -0,0 +1,133 @@
+// Package replication asynchronously replicates ais buckets to remote AIS clusters.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package replication
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/dbdriver"
+)
+
+type sender struct {
+	mu    sync.Mutex
+	sent  []string
+	fails int // number of calls to fail
+}
+
+func (s *sender) send(e *Entry) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.fails > 0 {
+		s.fails--
+		return 0, errors.New("remote error")
+	}
+	s.sent = append(s.sent, e.Op+":"+e.ObjName)
+	return 10, nil
+}
+
+func (s *sender) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.sent)
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	for deadline := time.Now().Add(10 * time.Second); !cond(); {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+var bck = cmn.Bck{Name: "bck", Provider: cmn.ProviderAIS}
+
+func TestPersistence(t *testing.T) {
+	var (
+		db = dbdriver.NewDBMock()
+		s  = &sender{}
+	)
+	m, err := NewManager(db, s.send)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 12; i++ {
+		m.Enqueue(bck, fmt.Sprintf("obj%d", i), OpPut)
+	}
+	m.Enqueue(bck, "obj3", OpDelete)
+	if stats := m.Stats(); stats.Pending != 13 || stats.Lag == 0 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+
+	// restart
+	m, err = NewManager(db, s.send)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats := m.Stats(); stats.Pending != 13 {
+		t.Fatalf("expected 13 pending, got %d", stats.Pending)
+	}
+	m.Enqueue(bck, "last", OpPut)
+	go m.Run()
+	defer m.Stop(nil)
+	waitFor(t, func() bool { return s.count() == 14 })
+
+	for i := 0; i < 12; i++ {
+		if exp := fmt.Sprintf("put:obj%d", i); s.sent[i] != exp {
+			t.Fatalf("expected %q, got %q", exp, s.sent[i])
+		}
+	}
+	if s.sent[12] != "delete:obj3" || s.sent[13] != "put:last" {
+		t.Fatalf("out of order: %v", s.sent[12:])
+	}
+	waitFor(t, func() bool { return m.Stats().Pending == 0 })
+	stats := m.Stats()
+	if stats.PutCount != 13 || stats.PutSize != 130 || stats.DelCount != 1 || stats.Lag != 0 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+	if keys, _ := db.List(collection, ""); len(keys) != 0 {
+		t.Fatalf("expected empty queue, got %v", keys)
+	}
+}
+
+func TestRetry(t *testing.T) {
+	var (
+		s    = &sender{fails: 1}
+		m, _ = NewManager(dbdriver.NewDBMock(), s.send)
+	)
+	m.Enqueue(bck, "obj1", OpPut)
+	m.Enqueue(bck, "obj2", OpPut)
+	go m.Run()
+	defer m.Stop(nil)
+	waitFor(t, func() bool { return s.count() == 2 })
+	if s.sent[0] != "put:obj1" || s.sent[1] != "put:obj2" {
+		t.Fatalf("out of order: %v", s.sent)
+	}
+	if stats := m.Stats(); stats.ErrCount != 1 || stats.LastErr == "" {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestGiveUp(t *testing.T) {
+	var (
+		s    = &sender{}
+		m, _ = NewManager(dbdriver.NewDBMock(), s.send)
+	)
+	// give up on the first entry without waiting for the backoff
+	m.Enqueue(bck, "obj1", OpPut)
+	for i := 1; i < maxAttempts; i++ {
+		if !m.failed(m.head(), errors.New("remote error")) {
+			t.Fatalf("gave up after %d attempts", i)
+		}
+	}
+	if m.failed(m.head(), errors.New("remote error")) || m.head() != nil {
+		t.Fatal("expected to give up")
+	}
+}
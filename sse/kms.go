@@ -0,0 +1,124 @@
+// Package sse provides server-side encryption of object content at rest.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package sse
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sync"
+
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+var ErrNoKMS = errors.New("server-side encryption is not configured (no master key)")
+
+type (
+	// KMS wraps (encrypts) and unwraps data keys with the cluster master key.
+	// Besides the built-in keyfile KMS, external key management services can be
+	// plugged in via SetKMS.
+	KMS interface {
+		// WrapKey returns the wrapped data key and the ID of the master key that wrapped it
+		WrapKey(dataKey []byte) (wrapped []byte, keyID string, err error)
+		// UnwrapKey returns the data key wrapped by the master key with a given ID
+		UnwrapKey(wrapped []byte, keyID string) (dataKey []byte, err error)
+	}
+
+	// keyfileKMS holds the master key read from a local file
+	keyfileKMS struct {
+		aead  cipher.AEAD
+		keyID string
+	}
+)
+
+var (
+	kms    KMS
+	kmsMtx sync.RWMutex
+)
+
+// interface guard
+var _ KMS = &keyfileKMS{}
+
+// Init loads the master key from the configured keyfile, if any.
+func Init(config *cmn.Config) error {
+	if config.SSE.KeyFile == "" {
+		return nil
+	}
+	k, err := NewKeyfileKMS(config.SSE.KeyFile)
+	if err != nil {
+		return err
+	}
+	SetKMS(k)
+	return nil
+}
+
+func SetKMS(k KMS) {
+	kmsMtx.Lock()
+	kms = k
+	kmsMtx.Unlock()
+}
+
+func GetKMS() (k KMS) {
+	kmsMtx.RLock()
+	k = kms
+	kmsMtx.RUnlock()
+	return
+}
+
+////////////////
+// keyfileKMS //
+////////////////
+
+// NewKeyfileKMS reads the 256-bit master key from a given file; the key is
+// either raw (32 bytes) or hex-encoded.
+func NewKeyfileKMS(path string) (KMS, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	key := b
+	if len(key) != dataKeySize {
+		key, err = hex.DecodeString(string(bytes.TrimSpace(b)))
+		if err != nil || len(key) != dataKeySize {
+			return nil, fmt.Errorf("invalid master key in %q: expecting %d bytes, raw or hex-encoded", path, dataKeySize)
+		}
+	}
+	return newKeyfileKMS(key)
+}
+
+func newKeyfileKMS(key []byte) (*keyfileKMS, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	digest := sha256.Sum256(key)
+	return &keyfileKMS{aead: aead, keyID: hex.EncodeToString(digest[:8])}, nil
+}
+
+func (k *keyfileKMS) WrapKey(dataKey []byte) (wrapped []byte, keyID string, err error) {
+	nonce := make([]byte, k.aead.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return
+	}
+	wrapped = k.aead.Seal(nonce, nonce, dataKey, []byte(k.keyID))
+	return wrapped, k.keyID, nil
+}
+
+func (k *keyfileKMS) UnwrapKey(wrapped []byte, keyID string) ([]byte, error) {
+	if keyID != k.keyID {
+		return nil, fmt.Errorf("data key is wrapped by unknown master key %q", keyID)
+	}
+	ns := k.aead.NonceSize()
+	if len(wrapped) < ns {
+		return nil, ErrCorrupted
+	}
+	return k.aead.Open(nil, wrapped[:ns], wrapped[ns:], []byte(keyID))
+}
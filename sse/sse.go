@@ -0,0 +1,318 @@
+// Package sse provides server-side encryption of object content at rest.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package sse
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+// Encrypted object is stored as a self-describing file:
+//
+//   header: magic | chunk size (uint32) | key ID length (uint16) | wrapped key length (uint16) | key ID | wrapped key
+//   chunks: AES-GCM sealed chunks of (up to) ChunkSize bytes of plaintext each
+//
+// Each object is encrypted with its own random data key that is, in turn,
+// wrapped by the cluster master key (see KMS) and stored in the header.
+// Since the data key is unique, chunk nonces are simply chunk indices; the
+// additional data of each chunk includes its index and whether it is the last
+// one - chunks cannot be reordered and the content cannot be truncated.
+// Fixed-size chunks make it possible to decrypt any range of the content
+// without reading the rest of it.
+//
+// Data movers (rebalance, mirroring, erasure coding, etc.) copy encrypted
+// files as-is - the size and checksum stored in the object's metadata are
+// those of the ciphertext.
+
+const (
+	ChunkSize = 64 * 1024
+	Overhead  = 16 // GCM tag size
+
+	dataKeySize = 32 // AES-256
+	nonceSize   = 12
+	magic       = "AISSSE\x00\x01"
+	fixedHdr    = len(magic) + 4 + 2 + 2
+)
+
+var (
+	ErrNotEncrypted = errors.New("not encrypted")
+	ErrCorrupted    = errors.New("corrupted encrypted content")
+)
+
+type (
+	Writer struct {
+		w       io.Writer
+		aead    cipher.AEAD
+		buf     []byte // plaintext of the current chunk
+		sealed  []byte
+		idx     uint64
+		written int64
+	}
+	Reader struct {
+		r         io.ReaderAt
+		aead      cipher.AEAD
+		hdrSize   int64
+		chunkSize int64
+		nchunks   int64
+		size      int64 // plaintext size
+	}
+	rangeReader struct {
+		sr        *Reader
+		off, end  int64
+		chunk     []byte // decrypted chunk that contains `off`
+		chunkIdx  int64
+		sealedBuf []byte
+	}
+)
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func chunkNonce(idx uint64) []byte {
+	nonce := make([]byte, nonceSize)
+	binary.BigEndian.PutUint64(nonce[nonceSize-8:], idx)
+	return nonce
+}
+
+func chunkAD(idx uint64, last bool) []byte {
+	ad := make([]byte, 9)
+	binary.BigEndian.PutUint64(ad, idx)
+	if last {
+		ad[8] = 1
+	}
+	return ad
+}
+
+////////////
+// Writer //
+////////////
+
+// NewWriter generates a new data key, writes the header, and returns the
+// writer that encrypts everything written to it. Close must be called to
+// write the last chunk.
+func NewWriter(w io.Writer) (*Writer, error) {
+	kms := GetKMS()
+	if kms == nil {
+		return nil, ErrNoKMS
+	}
+	dataKey := make([]byte, dataKeySize)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return nil, err
+	}
+	wrapped, keyID, err := kms.WrapKey(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	hdr := make([]byte, fixedHdr, fixedHdr+len(keyID)+len(wrapped))
+	copy(hdr, magic)
+	binary.BigEndian.PutUint32(hdr[len(magic):], ChunkSize)
+	binary.BigEndian.PutUint16(hdr[len(magic)+4:], uint16(len(keyID)))
+	binary.BigEndian.PutUint16(hdr[len(magic)+6:], uint16(len(wrapped)))
+	hdr = append(hdr, keyID...)
+	hdr = append(hdr, wrapped...)
+	sw := &Writer{
+		w:      w,
+		aead:   aead,
+		buf:    make([]byte, 0, ChunkSize),
+		sealed: make([]byte, 0, ChunkSize+Overhead),
+	}
+	if err := sw.write(hdr); err != nil {
+		return nil, err
+	}
+	return sw, nil
+}
+
+func (sw *Writer) write(b []byte) error {
+	n, err := sw.w.Write(b)
+	sw.written += int64(n)
+	return err
+}
+
+func (sw *Writer) seal(last bool) error {
+	sw.sealed = sw.aead.Seal(sw.sealed[:0], chunkNonce(sw.idx), sw.buf, chunkAD(sw.idx, last))
+	sw.idx++
+	sw.buf = sw.buf[:0]
+	return sw.write(sw.sealed)
+}
+
+func (sw *Writer) Write(p []byte) (n int, err error) {
+	for len(p) > 0 {
+		// a full chunk gets sealed only when there's more to write - the last one is sealed by Close
+		if len(sw.buf) == ChunkSize {
+			if err = sw.seal(false); err != nil {
+				return
+			}
+		}
+		l := cmn.Min(ChunkSize-len(sw.buf), len(p))
+		sw.buf = append(sw.buf, p[:l]...)
+		p = p[l:]
+		n += l
+	}
+	return
+}
+
+// Close seals the last chunk; it does not close the underlying writer.
+func (sw *Writer) Close() error { return sw.seal(true) }
+
+// Written returns the number of bytes written to the underlying writer.
+func (sw *Writer) Written() int64 { return sw.written }
+
+////////////
+// Reader //
+////////////
+
+// IsEncrypted returns true if the content starts with the encryption header.
+// NOTE: not to tell whether a stored object is encrypted - plaintext may start
+// with the same bytes (see cluster.LOM.IsEncrypted).
+func IsEncrypted(r io.ReaderAt) bool {
+	b := make([]byte, len(magic))
+	if _, err := r.ReadAt(b, 0); err != nil {
+		return false
+	}
+	return bytes.Equal(b, []byte(magic))
+}
+
+// readHeader parses the header and returns the wrapped data key (along with
+// the master key ID) and the reader with everything else initialized.
+func readHeader(r io.ReaderAt, size int64) (sr *Reader, wrapped []byte, keyID string, err error) {
+	hdr := make([]byte, fixedHdr)
+	if _, err = r.ReadAt(hdr, 0); err != nil || !bytes.Equal(hdr[:len(magic)], []byte(magic)) {
+		return nil, nil, "", ErrNotEncrypted
+	}
+	var (
+		chunkSize  = int64(binary.BigEndian.Uint32(hdr[len(magic):]))
+		keyIDLen   = int(binary.BigEndian.Uint16(hdr[len(magic)+4:]))
+		wrappedLen = int(binary.BigEndian.Uint16(hdr[len(magic)+6:]))
+		keys       = make([]byte, keyIDLen+wrappedLen)
+	)
+	if chunkSize == 0 {
+		return nil, nil, "", ErrCorrupted
+	}
+	if _, err = r.ReadAt(keys, int64(fixedHdr)); err != nil {
+		return nil, nil, "", ErrCorrupted
+	}
+	sr = &Reader{r: r, hdrSize: int64(fixedHdr + len(keys)), chunkSize: chunkSize}
+	// content is never empty: there's at least one (last) chunk
+	var (
+		payload = size - sr.hdrSize
+		stride  = chunkSize + Overhead
+	)
+	sr.nchunks = (payload + stride - 1) / stride
+	if sr.nchunks <= 0 || payload-(sr.nchunks-1)*stride < Overhead {
+		return nil, nil, "", ErrCorrupted
+	}
+	sr.size = payload - sr.nchunks*Overhead
+	return sr, keys[keyIDLen:], string(keys[:keyIDLen]), nil
+}
+
+// PlainSize returns the size of the plaintext given the encrypted content
+// and its size; unlike NewReader, it does not require the master key.
+func PlainSize(r io.ReaderAt, size int64) (int64, error) {
+	sr, _, _, err := readHeader(r, size)
+	if err != nil {
+		return 0, err
+	}
+	return sr.size, nil
+}
+
+// NewReader parses the header and unwraps the data key of the encrypted
+// content of a given (encrypted) size.
+func NewReader(r io.ReaderAt, size int64) (*Reader, error) {
+	sr, wrapped, keyID, err := readHeader(r, size)
+	if err != nil {
+		return nil, err
+	}
+	kms := GetKMS()
+	if kms == nil {
+		return nil, ErrNoKMS
+	}
+	dataKey, err := kms.UnwrapKey(wrapped, keyID)
+	if err != nil {
+		return nil, err
+	}
+	if sr.aead, err = newAEAD(dataKey); err != nil {
+		return nil, err
+	}
+	return sr, nil
+}
+
+// Size returns the size of the plaintext.
+func (sr *Reader) Size() int64 { return sr.size }
+
+// NewRangeReader returns the reader of a given range of the plaintext.
+func (sr *Reader) NewRangeReader(off, length int64) io.Reader {
+	return &rangeReader{sr: sr, off: off, end: off + length, chunkIdx: -1}
+}
+
+func (sr *Reader) readChunk(idx int64, sealedBuf, dst []byte) ([]byte, error) {
+	var (
+		off  = sr.hdrSize + idx*(sr.chunkSize+Overhead)
+		size = sr.chunkSize + Overhead
+		last = idx == sr.nchunks-1
+	)
+	if last {
+		size = sr.hdrSize + sr.size + sr.nchunks*Overhead - off
+	}
+	sealed := sealedBuf[:size]
+	if _, err := sr.r.ReadAt(sealed, off); err != nil && !(err == io.EOF && last) {
+		return nil, err
+	}
+	chunk, err := sr.aead.Open(dst[:0], chunkNonce(uint64(idx)), sealed, chunkAD(uint64(idx), last))
+	if err != nil {
+		return nil, fmt.Errorf("%w: chunk %d: %v", ErrCorrupted, idx, err)
+	}
+	return chunk, nil
+}
+
+func (rr *rangeReader) Read(p []byte) (n int, err error) {
+	sr := rr.sr
+	for n < len(p) && rr.off < rr.end {
+		idx := rr.off / sr.chunkSize
+		if idx != rr.chunkIdx {
+			if rr.sealedBuf == nil {
+				rr.sealedBuf = make([]byte, sr.chunkSize+Overhead)
+				rr.chunk = make([]byte, 0, sr.chunkSize)
+			}
+			if rr.chunk, err = sr.readChunk(idx, rr.sealedBuf, rr.chunk); err != nil {
+				return
+			}
+			rr.chunkIdx = idx
+		}
+		var (
+			start = int(rr.off - idx*sr.chunkSize)
+			l     = cmn.Min(len(rr.chunk)-start, len(p)-n)
+		)
+		if rem := rr.end - rr.off; int64(l) > rem {
+			l = int(rem)
+		}
+		if l <= 0 {
+			return n, ErrCorrupted
+		}
+		copy(p[n:], rr.chunk[start:start+l])
+		n += l
+		rr.off += int64(l)
+	}
+	if rr.off >= rr.end {
+		err = io.EOF
+	}
+	return
+}
@@ -0,0 +1,191 @@
+// Package sse provides server-side encryption of object content at rest.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package sse
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func initTestKMS(t *testing.T) {
+	key := make([]byte, dataKeySize)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatal(err)
+	}
+	k, err := newKeyfileKMS(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	SetKMS(k)
+}
+
+func encrypt(t *testing.T, plain []byte, wsize int) []byte {
+	var (
+		out = &bytes.Buffer{}
+		w   *Writer
+		err error
+	)
+	if w, err = NewWriter(out); err != nil {
+		t.Fatal(err)
+	}
+	// write in pieces of a given size
+	for b := plain; len(b) > 0; {
+		n := wsize
+		if n > len(b) {
+			n = len(b)
+		}
+		if _, err = w.Write(b[:n]); err != nil {
+			t.Fatal(err)
+		}
+		b = b[n:]
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if w.Written() != int64(out.Len()) {
+		t.Fatalf("written %d != %d", w.Written(), out.Len())
+	}
+	return out.Bytes()
+}
+
+func TestRoundTrip(t *testing.T) {
+	initTestKMS(t)
+	for _, size := range []int{0, 1, 1000, ChunkSize - 1, ChunkSize, ChunkSize + 1, 3 * ChunkSize, 3*ChunkSize + 777} {
+		plain := make([]byte, size)
+		rand.Read(plain)
+		for _, wsize := range []int{1000, ChunkSize, 5 * ChunkSize} {
+			enc := encrypt(t, plain, wsize)
+			if !IsEncrypted(bytes.NewReader(enc)) {
+				t.Fatalf("size %d: expected to be encrypted", size)
+			}
+			if ps, err := PlainSize(bytes.NewReader(enc), int64(len(enc))); err != nil || ps != int64(size) {
+				t.Fatalf("size %d: plain size %d, err %v", size, ps, err)
+			}
+			r, err := NewReader(bytes.NewReader(enc), int64(len(enc)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			dec, err := ioutil.ReadAll(r.NewRangeReader(0, r.Size()))
+			if err != nil {
+				t.Fatalf("size %d: %v", size, err)
+			}
+			if !bytes.Equal(dec, plain) {
+				t.Fatalf("size %d: decrypted content differs", size)
+			}
+		}
+	}
+	if IsEncrypted(bytes.NewReader([]byte("plaintext content"))) {
+		t.Error("plaintext must not be recognized as encrypted")
+	}
+}
+
+func TestRange(t *testing.T) {
+	initTestKMS(t)
+	plain := make([]byte, 4*ChunkSize+100)
+	rand.Read(plain)
+	enc := encrypt(t, plain, ChunkSize/3)
+	r, err := NewReader(bytes.NewReader(enc), int64(len(enc)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct{ off, length int64 }{
+		{0, 1},
+		{10, 100},
+		{ChunkSize - 10, 20},
+		{ChunkSize, ChunkSize},
+		{ChunkSize / 2, 3 * ChunkSize},
+		{4 * ChunkSize, 100},
+		{int64(len(plain)) - 1, 1},
+	}
+	for _, test := range tests {
+		dec, err := ioutil.ReadAll(r.NewRangeReader(test.off, test.length))
+		if err != nil {
+			t.Fatalf("range %v: %v", test, err)
+		}
+		if !bytes.Equal(dec, plain[test.off:test.off+test.length]) {
+			t.Fatalf("range %v: decrypted content differs", test)
+		}
+	}
+}
+
+func TestTamper(t *testing.T) {
+	initTestKMS(t)
+	plain := make([]byte, 2*ChunkSize+10)
+	rand.Read(plain)
+	enc := encrypt(t, plain, ChunkSize)
+
+	// flipped bit
+	bad := append([]byte{}, enc...)
+	bad[len(bad)-ChunkSize] ^= 1
+	r, err := NewReader(bytes.NewReader(bad), int64(len(bad)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.Copy(ioutil.Discard, r.NewRangeReader(0, r.Size())); err == nil {
+		t.Error("expected to fail decrypting modified content")
+	}
+
+	// truncated at the chunk boundary
+	trunc := enc[:len(enc)-(10+Overhead)]
+	if r, err = NewReader(bytes.NewReader(trunc), int64(len(trunc))); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.Copy(ioutil.Discard, r.NewRangeReader(0, r.Size())); err == nil {
+		t.Error("expected to fail decrypting truncated content")
+	}
+
+	// different master key
+	initTestKMS(t)
+	if _, err = NewReader(bytes.NewReader(enc), int64(len(enc))); err == nil {
+		t.Error("expected to fail unwrapping the data key")
+	}
+}
+
+func TestKeyfile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	key := make([]byte, dataKeySize)
+	rand.Read(key)
+
+	raw, hexed := filepath.Join(dir, "raw"), filepath.Join(dir, "hex")
+	if err := ioutil.WriteFile(raw, key, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(hexed, []byte(hex.EncodeToString(key)+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	k1, err := NewKeyfileKMS(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	k2, err := NewKeyfileKMS(hexed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wrapped, keyID, err := k1.WrapKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unwrapped, err := k2.UnwrapKey(wrapped, keyID)
+	if err != nil || !bytes.Equal(unwrapped, key) {
+		t.Fatalf("failed to unwrap: %v", err)
+	}
+
+	if err := ioutil.WriteFile(raw, key[1:], 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewKeyfileKMS(raw); err == nil {
+		t.Error("expected invalid key error")
+	}
+}
@@ -17,6 +17,11 @@ import (
 //
 // NOTE Naming Convention: "*.n" - counter, "*.µs" - latency, "*.size" - size (in bytes)
 //
+const (
+	// KindCounter: requests rejected by rate limits (see cmn.RateLimitConf)
+	RateLimUserCount = "ratelim.user.n"
+	RateLimBckCount  = "ratelim.bck.n"
+)
 
 type (
 	Prunner struct {
@@ -44,6 +49,8 @@ func (r *Prunner) Get(name string) (val int64) { return r.Core.get(name) }
 func (r *Prunner) Init(p cluster.Proxy) *atomic.Bool {
 	r.Core = &CoreStats{}
 	r.Core.init(24)
+	r.Core.Tracker.register(RateLimUserCount, KindCounter)
+	r.Core.Tracker.register(RateLimBckCount, KindCounter)
 	r.Core.statsTime = cmn.GCO.Get().Periodic.StatsTime
 	r.ctracker = make(copyTracker, 24)
 	r.Core.initStatsD(p.Snode())
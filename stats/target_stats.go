@@ -29,6 +29,15 @@ const (
 	LruEvictCount  = "lru.evict.n"
 	VerChangeCount = "vchange.n"
 	VerChangeSize  = "vchange.size"
+	// lifecycle
+	LcExpireCount = "lc.expire.n"
+	LcExpireSize  = "lc.expire.size"
+	LcEvictCount  = "lc.evict.n"
+	LcEvictSize   = "lc.evict.size"
+	// replication to remote clusters
+	ReplPutCount = "repl.put.n"
+	ReplPutSize  = "repl.put.size"
+	ReplDelCount = "repl.del.n"
 	// rebalance
 	RebTxCount = "reb.tx.n"
 	RebTxSize  = "reb.tx.size"
@@ -39,6 +48,7 @@ const (
 	ErrCksumSize     = "err.cksum.size"
 	ErrMetadataCount = "err.md.n"
 	ErrIOCount       = "err.io.n"
+	ErrReplCount     = "err.repl.n"
 	// special
 	RestartCount = "restart.n"
 
@@ -131,6 +141,13 @@ func (r *Trunner) RegisterAll() {
 	r.Register(LruEvictCount, KindCounter)
 	r.Register(VerChangeCount, KindCounter)
 	r.Register(VerChangeSize, KindCounter)
+	r.Register(LcExpireCount, KindCounter)
+	r.Register(LcExpireSize, KindCounter)
+	r.Register(LcEvictCount, KindCounter)
+	r.Register(LcEvictSize, KindCounter)
+	r.Register(ReplPutCount, KindCounter)
+	r.Register(ReplPutSize, KindCounter)
+	r.Register(ReplDelCount, KindCounter)
 	r.Register(GetRedirLatency, KindLatency)
 	r.Register(PutRedirLatency, KindLatency)
 
@@ -139,6 +156,7 @@ func (r *Trunner) RegisterAll() {
 	r.Register(ErrCksumSize, KindCounter)
 	r.Register(ErrMetadataCount, KindCounter)
 	r.Register(ErrIOCount, KindCounter)
+	r.Register(ErrReplCount, KindCounter)
 
 	// rebalance
 	r.Register(RebTxCount, KindCounter)
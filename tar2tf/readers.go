@@ -98,6 +98,10 @@ func extractRecords(target cluster.Target, lom *cluster.LOM) (*extract.RecordMan
 		return nil, fmt.Errorf("%s extension not supported. Please provide one of: %s/%s/%s", ext, cmn.ExtTar, cmn.ExtTarTgz, cmn.ExtTgz)
 	}
 
+	// the shard is read directly from the file - see cluster.LOM.StoredAsIs
+	if !lom.StoredAsIs() {
+		return nil, fmt.Errorf("%s: tar-to-tfrecord is not supported for encrypted, compressed, or deduplicated objects", lom)
+	}
 	keyExtractor, err := extract.NewNameKeyExtractor()
 	cmn.AssertNoErr(err) // err always nil
 	recordManager := extract.NewRecordManager(target, target.Snode().DaemonID, lom.Bck().Name, lom.Bck().Provider, ext, extractCreator, keyExtractor, onDuplicates)
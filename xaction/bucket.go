@@ -231,10 +231,11 @@ type dpromoteEntry struct {
 	xact   *mirror.XactDirPromote
 	dir    string
 	params *cmn.ActValPromote
+	bypass bool
 }
 
 func (e *dpromoteEntry) Start(bck cmn.Bck) error {
-	xact := mirror.NewXactDirPromote(e.dir, bck, e.t, e.params)
+	xact := mirror.NewXactDirPromote(e.dir, bck, e.t, e.params, e.bypass)
 	go xact.Run()
 	e.xact = xact
 	return nil
@@ -242,8 +243,9 @@ func (e *dpromoteEntry) Start(bck cmn.Bck) error {
 func (*dpromoteEntry) Kind() string    { return cmn.ActPromote }
 func (e *dpromoteEntry) Get() cmn.Xact { return e.xact }
 
-func (r *registry) RenewDirPromote(dir string, bck *cluster.Bck, t cluster.Target, params *cmn.ActValPromote) (*mirror.XactDirPromote, error) {
-	e := &dpromoteEntry{t: t, dir: dir, params: params}
+func (r *registry) RenewDirPromote(dir string, bck *cluster.Bck, t cluster.Target, params *cmn.ActValPromote,
+	bypassGovernance bool) (*mirror.XactDirPromote, error) {
+	e := &dpromoteEntry{t: t, dir: dir, params: params, bypass: bypassGovernance}
 	ee, err := r.renewBucketXaction(e, bck)
 	if err == nil {
 		return ee.Get().(*mirror.XactDirPromote), nil
@@ -0,0 +1,178 @@
+// Package xaction provides core functionality for the AIStore extended actions.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package xaction
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/NVIDIA/aistore/3rdparty/atomic"
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/fs"
+)
+
+// Dedup xaction deduplicates (see cmn.BckDedupConf) the objects that were
+// written into a given ais bucket before deduplication got enabled - one
+// jogger per mountpath. Encrypted and compressed objects are skipped.
+
+//
+// dedupEntry & Dedup
+//
+type (
+	dedupEntry struct {
+		baseBckEntry
+		t    cluster.Target
+		xact *Dedup
+	}
+	Dedup struct {
+		cmn.XactBase
+		t cluster.Target
+		// stats
+		size   atomic.Int64 // original size of the deduplicated objects
+		stored atomic.Int64 // size of the chunks that were not stored before
+		saved  atomic.Int64
+	}
+	dedupJ struct { // one per mountpath
+		parent    *Dedup
+		mpathInfo *fs.MountpathInfo
+		config    *cmn.Config
+		buf       []byte
+	}
+
+	DedupTargetStats struct {
+		cmn.BaseXactStats
+		Ext ExtDedupStats `json:"ext"`
+	}
+	ExtDedupStats struct {
+		Size   int64 `json:"dedup.size,string"`
+		Stored int64 `json:"dedup.stored,string"`
+		Saved  int64 `json:"dedup.saved,string"`
+	}
+)
+
+var (
+	// interface guard
+	_ cmn.XactStats = &DedupTargetStats{}
+)
+
+func (e *dedupEntry) Start(bck cmn.Bck) error {
+	e.xact = &Dedup{XactBase: *cmn.NewXactBaseBck(e.uuid, e.Kind(), bck), t: e.t}
+	return nil
+}
+func (e *dedupEntry) Kind() string  { return cmn.ActDedup }
+func (e *dedupEntry) Get() cmn.Xact { return e.xact }
+
+func (e *dedupEntry) preRenewHook(previousEntry bucketEntry) (keep bool, err error) {
+	err = fmt.Errorf("%s is already running", previousEntry.Get())
+	return
+}
+
+func (r *registry) RenewDedup(t cluster.Target, bck *cluster.Bck, uuid string) (*Dedup, error) {
+	if !bck.IsAIS() || !bck.Props.Dedup.Enabled {
+		return nil, fmt.Errorf("%s: dedup is not enabled for the bucket", bck)
+	}
+	e := &dedupEntry{baseBckEntry: baseBckEntry{uuid}, t: t}
+	ee, err := r.renewBucketXaction(e, bck)
+	if err == nil {
+		return ee.Get().(*Dedup), nil
+	}
+	return nil, err
+}
+
+func (r *Dedup) IsMountpathXact() bool { return true }
+
+// Run is blocking
+func (r *Dedup) Run() error {
+	var (
+		wg        = &sync.WaitGroup{}
+		mpaths, _ = fs.Get()
+		config    = cmn.GCO.Get()
+		mm        = r.t.GetMMSA()
+	)
+	glog.Infoln(r.String())
+	for _, mpathInfo := range mpaths {
+		buf, slab := mm.Alloc()
+		j := &dedupJ{parent: r, mpathInfo: mpathInfo, config: config, buf: buf}
+		wg.Add(1)
+		go func() {
+			j.jog()
+			slab.Free(buf)
+			wg.Done()
+		}()
+	}
+	wg.Wait()
+	glog.Infof("%s: deduplicated %d object(s) (%s), saved %s", r, r.ObjCount(),
+		cmn.B2S(r.size.Load(), 2), cmn.B2S(r.saved.Load(), 2))
+	r.Finish()
+	return nil
+}
+
+func (r *Dedup) Stats() cmn.XactStats {
+	baseStats := r.XactBase.Stats().(*cmn.BaseXactStats)
+	dedupStats := DedupTargetStats{BaseXactStats: *baseStats}
+	dedupStats.Ext.Size = r.size.Load()
+	dedupStats.Ext.Stored = r.stored.Load()
+	dedupStats.Ext.Saved = r.saved.Load()
+	return &dedupStats
+}
+
+////////////
+// dedupJ //
+////////////
+
+func (j *dedupJ) String() string {
+	return fmt.Sprintf("%s: (%s, %s)", j.parent.t.Snode(), j.parent, j.mpathInfo)
+}
+
+func (j *dedupJ) jog() {
+	opts := &fs.Options{
+		Mpath:    j.mpathInfo,
+		Bck:      j.parent.Bck(),
+		CTs:      []string{fs.ObjectType},
+		Callback: j.walk,
+		Sorted:   false,
+	}
+	if err := fs.Walk(opts); err != nil {
+		if _, ok := err.(cmn.AbortedError); !ok {
+			glog.Errorf("%s: %v", j, err)
+		}
+	}
+}
+
+func (j *dedupJ) walk(fqn string, de fs.DirEntry) error {
+	if de.IsDir() {
+		return nil
+	}
+	if j.parent.Aborted() {
+		return cmn.NewAbortedError(j.parent.String())
+	}
+	lom := &cluster.LOM{T: j.parent.t, FQN: fqn}
+	if err := lom.Init(j.parent.Bck(), j.config); err != nil {
+		return nil
+	}
+	lom.Lock(true)
+	defer lom.Unlock(true)
+	if err := lom.Load(false); err != nil {
+		return nil
+	}
+	if lom.IsCopy() || lom.IsDeduped() || lom.Size() == 0 || lom.IsEncoded() {
+		return nil
+	}
+	size := lom.Size()
+	stored, err := lom.Dedup(j.buf)
+	if err != nil {
+		glog.Errorf("%s: failed to dedup %s: %v", j, lom, err)
+		return nil
+	}
+	j.parent.ObjectsInc()
+	j.parent.BytesAdd(size)
+	j.parent.size.Add(size)
+	j.parent.stored.Add(stored)
+	j.parent.saved.Add(size - stored - lom.Size())
+	j.parent.t.ObjectWritten(lom)
+	return nil
+}
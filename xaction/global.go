@@ -14,6 +14,7 @@ import (
 	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/downloader"
+	"github.com/NVIDIA/aistore/lifecycle"
 	"github.com/NVIDIA/aistore/lru"
 	"github.com/NVIDIA/aistore/stats"
 	"github.com/NVIDIA/aistore/xaction/demand"
@@ -53,6 +54,26 @@ func (e *lruEntry) Get() cmn.Xact { return e.xact }
 
 func (e *lruEntry) preRenewHook(_ globalEntry) bool { return true }
 
+//
+// lifecycleEntry
+//
+
+type lifecycleEntry struct {
+	baseGlobalEntry
+	id   string
+	xact *lifecycle.Xaction
+}
+
+func (e *lifecycleEntry) Start(_ cmn.Bck) error {
+	e.xact = lifecycle.NewXaction(e.id)
+	return nil
+}
+
+func (e *lifecycleEntry) Kind() string  { return cmn.ActLifecycle }
+func (e *lifecycleEntry) Get() cmn.Xact { return e.xact }
+
+func (e *lifecycleEntry) preRenewHook(_ globalEntry) bool { return true }
+
 //
 // rebalanceEntry
 //
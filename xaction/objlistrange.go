@@ -6,6 +6,7 @@ package xaction
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"os"
 
@@ -60,6 +61,12 @@ func (r *EvictDelete) objDelete(args *DeletePrefetchArgs, lom *cluster.LOM) erro
 		delFromCloud = bck.IsRemote() && !args.Evict
 	)
 	if err := lom.Load(false); err == nil {
+		if err := lom.ObjLockErr(false); err != nil {
+			return err
+		}
+		if args.Evict && lom.IsDirty() {
+			return fmt.Errorf("%s: cannot evict - not yet written to the cloud", lom)
+		}
 		delFromAIS = true
 	} else if !cmn.IsErrObjNought(err) {
 		return err
@@ -82,6 +89,8 @@ func (r *EvictDelete) objDelete(args *DeletePrefetchArgs, lom *cluster.LOM) erro
 		}
 		if args.Evict {
 			cmn.Assert(bck.IsRemote())
+		} else {
+			r.t.ObjectDeleted(lom)
 		}
 	}
 	return cloudErr
@@ -17,6 +17,7 @@ import (
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/downloader"
 	"github.com/NVIDIA/aistore/hk"
+	"github.com/NVIDIA/aistore/lifecycle"
 	"github.com/NVIDIA/aistore/lru"
 	"github.com/NVIDIA/aistore/stats"
 )
@@ -643,6 +644,16 @@ func (r *registry) RenewLRU(id string) *lru.Xaction {
 	return entry.xact
 }
 
+// RenewLifecycle returns nil if the lifecycle xaction is already running
+func (r *registry) RenewLifecycle(id string) *lifecycle.Xaction {
+	e := &lifecycleEntry{id: id}
+	ee, keep, _ := r.renewGlobalXaction(e)
+	if keep {
+		return nil
+	}
+	return ee.(*lifecycleEntry).xact
+}
+
 func (r *registry) RenewRebalance(id int64, statsRunner *stats.Trunner) *Rebalance {
 	e := &rebalanceEntry{id: rebID(id), statsRunner: statsRunner}
 	ee, keep, _ := r.renewGlobalXaction(e)
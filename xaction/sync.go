@@ -0,0 +1,301 @@
+// Package xaction provides core functionality for the AIStore extended actions.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package xaction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/downloader"
+	"github.com/NVIDIA/aistore/fs"
+)
+
+// SyncBck synchronizes the objects of a cloud bucket cached by a given target
+// with the cloud (see cmn.SyncBckMsg). The (sorted) cached objects are compared
+// with the (sorted) cloud listing of the objects that this target owns, using
+// the downloader's DiffResolver. Objects that are yet to be written to the
+// cloud (see WriteBack) are never touched.
+
+type (
+	syncBckEntry struct {
+		baseBckEntry
+		t    cluster.Target
+		bck  *cluster.Bck
+		msg  *cmn.SyncBckMsg
+		xact *SyncBck
+	}
+	SyncBck struct {
+		cmn.XactBase
+		t   cluster.Target
+		bck *cluster.Bck
+		msg *cmn.SyncBckMsg
+		ctx context.Context
+
+		evicted, refetched, fetched int64
+	}
+
+	// implements downloader.DiffResolverCtx
+	syncCtx struct{}
+)
+
+// interface guard
+var _ downloader.DiffResolverCtx = &syncCtx{}
+
+func (e *syncBckEntry) Start(bck cmn.Bck) error {
+	e.xact = &SyncBck{
+		XactBase: *cmn.NewXactBaseBck(e.uuid, cmn.ActSyncBck, bck),
+		t:        e.t,
+		bck:      e.bck,
+		msg:      e.msg,
+		ctx:      context.Background(),
+	}
+	return nil
+}
+func (e *syncBckEntry) Kind() string  { return cmn.ActSyncBck }
+func (e *syncBckEntry) Get() cmn.Xact { return e.xact }
+
+func (e *syncBckEntry) preRenewHook(previousEntry bucketEntry) (keep bool, err error) {
+	err = fmt.Errorf("%s is already running", previousEntry.Get())
+	return
+}
+
+func (r *registry) RenewSyncBck(t cluster.Target, bck *cluster.Bck, uuid string, msg *cmn.SyncBckMsg) (*SyncBck, error) {
+	if !bck.IsCloud() {
+		return nil, fmt.Errorf("%s: not a cloud bucket", bck)
+	}
+	e := &syncBckEntry{baseBckEntry: baseBckEntry{uuid}, t: t, bck: bck, msg: msg}
+	ee, err := r.renewBucketXaction(e, bck)
+	if err != nil {
+		return nil, err
+	}
+	return ee.Get().(*SyncBck), nil
+}
+
+func (r *SyncBck) IsMountpathXact() bool { return false }
+
+// Run is blocking
+func (r *SyncBck) Run() (err error) {
+	glog.Infoln(r.String())
+	defer func() {
+		if err != nil && !r.Aborted() {
+			glog.Errorf("%s: %v", r, err)
+			r.Abort()
+		}
+		r.Finish()
+	}()
+	dr := downloader.NewDiffResolver(&syncCtx{})
+	dr.Start()
+	go r.pushCached(dr)
+	go r.pushListed(dr)
+
+	var failed int
+	for {
+		res, err := dr.Next()
+		if err != nil {
+			dr.Stop()
+			return err
+		}
+		if res.Action == downloader.DiffResolverEOF {
+			break
+		}
+		if r.Aborted() {
+			dr.Stop() // and keep draining
+			continue
+		}
+		switch res.Action {
+		case downloader.DiffResolverDelete:
+			err = r.evict(res.Src)
+		case downloader.DiffResolverRecv:
+			err = r.recv(res.Dst.ObjName)
+		}
+		if err != nil {
+			glog.Errorf("%s: %v", r, err)
+			failed++
+		}
+	}
+	if r.Aborted() {
+		return cmn.NewAbortedError(r.String())
+	}
+	glog.Infof("%s: evicted %d, re-fetched %d, fetched %d", r, r.evicted, r.refetched, r.fetched)
+	if failed > 0 {
+		return fmt.Errorf("failed to synchronize %d object(s)", failed)
+	}
+	return nil
+}
+
+// pushCached walks the bucket in sorted order
+func (r *SyncBck) pushCached(dr *downloader.DiffResolver) {
+	defer dr.CloseSrc()
+	opts := &fs.WalkBckOptions{
+		Options: fs.Options{
+			Bck: r.Bck(),
+			CTs: []string{fs.ObjectType},
+			Callback: func(fqn string, de fs.DirEntry) error {
+				if dr.Stopped() {
+					return cmn.NewAbortedError(r.String())
+				}
+				lom := &cluster.LOM{T: r.t, FQN: fqn}
+				if err := lom.Init(r.Bck()); err != nil {
+					return err
+				}
+				if !strings.HasPrefix(lom.ObjName, r.msg.Prefix) {
+					return nil
+				}
+				dr.PushSrc(lom)
+				return nil
+			},
+			Sorted: true,
+		},
+	}
+	if err := fs.WalkBck(opts); err != nil && !errors.As(err, &cmn.AbortedError{}) {
+		dr.Abort(err)
+	}
+}
+
+// pushListed lists the cloud bucket and pushes the objects that this target owns
+func (r *SyncBck) pushListed(dr *downloader.DiffResolver) {
+	defer dr.CloseDst()
+	var (
+		smap = r.t.GetSowner().Get()
+		sid  = r.t.Snode().ID()
+		msg  = &cmn.SelectMsg{Prefix: r.msg.Prefix, Props: cmn.GetPropsSize + "," + cmn.GetPropsChecksum}
+	)
+	if r.bck.Props.Versioning.Enabled {
+		msg.Props += "," + cmn.GetPropsVersion
+	}
+	for !dr.Stopped() {
+		bckList, err, _ := r.t.Cloud(r.bck).ListObjects(r.ctx, r.bck, msg)
+		if err != nil {
+			dr.Abort(err)
+			return
+		}
+		for _, entry := range bckList.Entries {
+			local, err := isLocalObject(smap, r.Bck(), entry.Name, sid)
+			if err != nil {
+				dr.Abort(err)
+				return
+			}
+			if !local {
+				continue
+			}
+			dr.PushDst(&downloader.CloudResource{
+				ObjName:  entry.Name,
+				Version:  entry.Version,
+				Size:     entry.Size,
+				Checksum: entry.Checksum,
+			})
+		}
+		if bckList.PageMarker == "" {
+			break
+		}
+		msg.PageMarker = bckList.PageMarker
+	}
+}
+
+// evict the object that was deleted in the cloud
+func (r *SyncBck) evict(lom *cluster.LOM) error {
+	if lom.IsDirty() {
+		return nil
+	}
+	if err := r.t.EvictObject(lom); err != nil {
+		if cmn.IsObjNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	r.evicted++
+	r.ObjectsInc()
+	return nil
+}
+
+// recv handles the object that is either not cached or has changed in the cloud
+func (r *SyncBck) recv(objName string) error {
+	lom := &cluster.LOM{T: r.t, ObjName: objName}
+	if err := lom.Init(r.Bck()); err != nil {
+		return err
+	}
+	cached := true
+	if err := lom.Load(); err != nil {
+		if !cmn.IsObjNotExist(err) {
+			return err
+		}
+		cached = false
+	}
+	switch {
+	case cached && lom.IsDirty():
+		return nil
+	case cached && r.msg.EvictChanged:
+		return r.evict(lom)
+	case !cached && !r.msg.FetchNew:
+		return nil
+	}
+	if err, _ := r.t.GetCold(r.ctx, lom, true /*prefetch*/); err != nil {
+		if errors.Is(err, cmn.ErrSkip) {
+			return nil
+		}
+		return err
+	}
+	if cached {
+		r.refetched++
+	} else {
+		r.fetched++
+	}
+	r.ObjectsInc()
+	r.BytesAdd(lom.Size())
+	return nil
+}
+
+/////////////
+// syncCtx //
+/////////////
+
+// CompareObjects returns false if the cached object is known to differ from
+// its cloud counterpart (i.e., when it cannot be proven different it is
+// considered up to date)
+func (*syncCtx) CompareObjects(lom *cluster.LOM, dst *downloader.DstElement) (bool, error) {
+	if err := lom.Load(); err != nil {
+		if cmn.IsObjNotExist(err) {
+			return false, nil
+		}
+		glog.Errorf("%s: %v", lom, err)
+		return true, nil
+	}
+	if lom.IsDirty() {
+		return true, nil
+	}
+	if lom.Size() != dst.Size {
+		return false, nil
+	}
+	if dst.Version != "" && lom.Version() != "" {
+		return lom.Version() == dst.Version, nil
+	}
+	if dst.Checksum != "" {
+		if md5, ok := lom.GetCustomMD(cluster.MD5ObjMD); ok {
+			return md5 == dst.Checksum, nil
+		}
+	}
+	return true, nil
+}
+
+// IsObjFromCloud returns true if the object was fetched from (or written to)
+// the cloud; otherwise, the object is never evicted
+func (*syncCtx) IsObjFromCloud(lom *cluster.LOM) (bool, error) {
+	if err := lom.Load(); err != nil {
+		if !cmn.IsObjNotExist(err) {
+			glog.Errorf("%s: %v", lom, err)
+		}
+		return false, nil
+	}
+	if lom.IsDirty() {
+		return false, nil
+	}
+	source, ok := lom.GetCustomMD(cluster.SourceObjMD)
+	return ok && source != cluster.SourceWebObjMD, nil
+}
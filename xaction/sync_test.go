@@ -0,0 +1,153 @@
+// Package xaction provides core functionality for the AIStore extended actions.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package xaction
+
+import (
+	"context"
+	"os"
+	"sort"
+	"testing"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/tutils/tassert"
+)
+
+type (
+	// syncTargetMock is the only target in the cluster; it records the objects
+	// that get (re-)fetched and evicted
+	syncTargetMock struct {
+		*cloudTargetMock
+		si      *cluster.Snode
+		smap    *cluster.Smap
+		fetched []string
+		evicted []string
+	}
+)
+
+func (t *syncTargetMock) Snode() *cluster.Snode                      { return t.si }
+func (t *syncTargetMock) GetSowner() cluster.Sowner                  { return t }
+func (t *syncTargetMock) Get() *cluster.Smap                         { return t.smap }
+func (t *syncTargetMock) Listeners() cluster.SmapListeners           { return nil }
+func (t *syncTargetMock) EvictObject(lom *cluster.LOM) error         { return t.evict(lom) }
+func (t *syncTargetMock) Cloud(_ *cluster.Bck) cluster.CloudProvider { return t.cloud }
+
+func (t *syncTargetMock) evict(lom *cluster.LOM) error {
+	t.evicted = append(t.evicted, lom.ObjName)
+	return os.Remove(lom.FQN)
+}
+
+func (t *syncTargetMock) GetCold(_ context.Context, lom *cluster.LOM, _ bool) (error, int) {
+	t.fetched = append(t.fetched, lom.ObjName)
+	return nil, 0
+}
+
+func newSyncTarget(t *testing.T) (sMock *syncTargetMock, bck *cluster.Bck, mpath string) {
+	tMock, bck, mpath := newCloudTarget(t)
+	si := &cluster.Snode{DaemonID: "t1", DaemonType: cmn.Target}
+	si.Digest()
+	sMock = &syncTargetMock{
+		cloudTargetMock: tMock,
+		si:              si,
+		smap:            &cluster.Smap{Tmap: cluster.NodeMap{si.ID(): si}},
+	}
+	return
+}
+
+// putCached stores the object as if it was fetched from the cloud
+func putCached(t *testing.T, sMock *syncTargetMock, bck *cluster.Bck, objName, content, version string) {
+	lom := putObj(t, sMock.cloudTargetMock, bck, objName, content, false)
+	lom.Lock(true)
+	defer lom.Unlock(true)
+	lom.SetCustomMD(cmn.SimpleKVs{cluster.SourceObjMD: cluster.SourceAmazonObjMD})
+	lom.SetVersion(version)
+	tassert.CheckFatal(t, lom.Persist())
+	lom.ReCache()
+}
+
+func (t *syncTargetMock) list(entries ...*cmn.BucketEntry) {
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+	t.cloud.listed = entries
+}
+
+func runSync(t *testing.T, sMock *syncTargetMock, bck *cluster.Bck, msg *cmn.SyncBckMsg) {
+	e := &syncBckEntry{t: sMock, bck: bck, msg: msg}
+	tassert.CheckFatal(t, e.Start(bck.Bck))
+	tassert.CheckFatal(t, e.xact.Run())
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func TestSyncBck(t *testing.T) {
+	cluster.InitTarget()
+	sMock, bck, mpath := newSyncTarget(t)
+	defer os.RemoveAll(mpath)
+
+	putCached(t, sMock, bck, "same", "s", "1")
+	putCached(t, sMock, bck, "changed", "c", "1")
+	putCached(t, sMock, bck, "deleted", "d", "1")
+	putCached(t, sMock, bck, "other/deleted", "o", "1")
+	putObj(t, sMock.cloudTargetMock, bck, "dirty", "dd", true)
+	putObj(t, sMock.cloudTargetMock, bck, "local", "l", false) // never written to the cloud
+	sMock.list(
+		&cmn.BucketEntry{Name: "same", Size: 1, Version: "1"},
+		&cmn.BucketEntry{Name: "changed", Size: 1, Version: "2"},
+		&cmn.BucketEntry{Name: "dirty", Size: 1, Version: "2"},
+		&cmn.BucketEntry{Name: "new", Size: 1, Version: "1"},
+	)
+	runSync(t, sMock, bck, &cmn.SyncBckMsg{FetchNew: true})
+
+	tassert.Errorf(t, len(sMock.fetched) == 2 && contains(sMock.fetched, "changed") && contains(sMock.fetched, "new"),
+		"expected changed and new objects to be fetched, got %v", sMock.fetched)
+	tassert.Errorf(t, len(sMock.evicted) == 2 && contains(sMock.evicted, "deleted") &&
+		contains(sMock.evicted, "other/deleted"),
+		"expected objects deleted in the cloud to be evicted, got %v", sMock.evicted)
+}
+
+func TestSyncBckPrefix(t *testing.T) {
+	cluster.InitTarget()
+	sMock, bck, mpath := newSyncTarget(t)
+	defer os.RemoveAll(mpath)
+
+	putCached(t, sMock, bck, "tmp/deleted", "d", "1")
+	putCached(t, sMock, bck, "other/deleted", "o", "1")
+	sMock.list(
+		&cmn.BucketEntry{Name: "other/new", Size: 1, Version: "1"},
+		&cmn.BucketEntry{Name: "tmp/new", Size: 1, Version: "1"},
+	)
+	runSync(t, sMock, bck, &cmn.SyncBckMsg{Prefix: "tmp/"})
+
+	tassert.Errorf(t, len(sMock.fetched) == 0, "new objects must not be fetched, got %v", sMock.fetched)
+	tassert.Errorf(t, len(sMock.evicted) == 1 && sMock.evicted[0] == "tmp/deleted",
+		"expected only the prefixed object to be evicted, got %v", sMock.evicted)
+}
+
+func TestSyncBckEvictChanged(t *testing.T) {
+	cluster.InitTarget()
+	sMock, bck, mpath := newSyncTarget(t)
+	defer os.RemoveAll(mpath)
+
+	putCached(t, sMock, bck, "changed", "c", "1")
+	putCached(t, sMock, bck, "resized", "r", "")
+	putObj(t, sMock.cloudTargetMock, bck, "dirty", "d", true)
+	sMock.list(
+		&cmn.BucketEntry{Name: "changed", Size: 1, Version: "2"},
+		&cmn.BucketEntry{Name: "dirty", Size: 2},
+		&cmn.BucketEntry{Name: "resized", Size: 2},
+	)
+	runSync(t, sMock, bck, &cmn.SyncBckMsg{EvictChanged: true})
+
+	tassert.Errorf(t, len(sMock.fetched) == 0, "changed objects must not be re-fetched, got %v", sMock.fetched)
+	tassert.Errorf(t, len(sMock.evicted) == 2 && contains(sMock.evicted, "changed") &&
+		contains(sMock.evicted, "resized"),
+		"expected changed objects to be evicted, got %v", sMock.evicted)
+}
@@ -0,0 +1,425 @@
+// Package xaction provides core functionality for the AIStore extended actions.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package xaction
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/debug"
+	"github.com/NVIDIA/aistore/dbdriver"
+	"github.com/NVIDIA/aistore/xaction/demand"
+)
+
+// Write-back (see cmn.BckWriteBackConf): objects PUT into a write-back bucket
+// are marked dirty - both in the object's metadata and in the target's
+// database, the latter keyed by the object's uname. The (on-demand) WriteBack
+// xaction writes dirty objects to the cloud retrying failures with exponential
+// backoff; the objects that fail wbMaxAttempts times are left for the next
+// WriteBack xaction to retry (see HasDirty). The FlushBck xaction is a barrier:
+// it finishes once all objects that were dirty when it started are written.
+
+const (
+	wbCollection  = "writeback"
+	wbWorkers     = 8
+	wbMaxAttempts = 5
+	wbBackoffMin  = time.Second
+	wbBackoffMax  = time.Minute
+)
+
+type (
+	writeBackEntry struct {
+		baseBckEntry
+		t    cluster.Target
+		bck  *cluster.Bck
+		xact *WriteBack
+	}
+	WriteBack struct {
+		demand.XactDemandBase
+		t       cluster.Target
+		bck     *cluster.Bck
+		pokeCh  chan struct{}
+		retries map[string]*wbRetry // by object name
+	}
+	wbRetry struct {
+		next     time.Time
+		attempts int
+	}
+
+	flushBckEntry struct {
+		baseBckEntry
+		t    cluster.Target
+		bck  *cluster.Bck
+		xact *FlushBck
+	}
+	FlushBck struct {
+		cmn.XactBase
+		t       cluster.Target
+		bck     *cluster.Bck
+		barrier int64
+	}
+)
+
+//
+// dirty tracking
+//
+
+// MarkDirty must be called under the object's write lock prior to storing
+// the object (so that the mark is never lost).
+func MarkDirty(lom *cluster.LOM) error {
+	gen := strconv.FormatInt(time.Now().UnixNano(), 10)
+	if err := lom.T.GetDB().SetString(wbCollection, lom.Uname(), gen); err != nil {
+		return err
+	}
+	lom.SetDirty(gen)
+	return nil
+}
+
+func unmarkDirty(lom *cluster.LOM) error {
+	err := lom.T.GetDB().Delete(wbCollection, lom.Uname())
+	if err != nil && dbdriver.IsErrNotFound(err) {
+		err = nil
+	}
+	return err
+}
+
+// dirty returns the names and generations of a bucket's dirty objects
+func dirty(t cluster.Target, bck *cluster.Bck) (map[string]int64, error) {
+	prefix := bck.MakeUname("")
+	values, err := t.GetDB().GetAll(wbCollection, prefix)
+	if err != nil && !dbdriver.IsErrNotFound(err) {
+		return nil, err
+	}
+	objs := make(map[string]int64, len(values))
+	for key, value := range values {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		gen, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			glog.Errorf("%s: invalid %s entry %q", bck, wbCollection, key)
+		}
+		objs[strings.TrimPrefix(key, prefix)] = gen
+	}
+	return objs, nil
+}
+
+// HasDirty returns true if the bucket has objects that are yet to be written to the cloud
+func HasDirty(t cluster.Target, bck *cluster.Bck) bool {
+	keys, err := t.GetDB().List(wbCollection, bck.MakeUname(""))
+	return err == nil && len(keys) > 0
+}
+
+// flush writes a given dirty object to the cloud and clears the mark unless
+// the object has been overwritten in the meantime
+func flush(t cluster.Target, bck *cluster.Bck, objName string) (size int64, flushed bool, err error) {
+	var (
+		gen, version string
+		lom          = &cluster.LOM{T: t, ObjName: objName}
+	)
+	if err = lom.Init(bck.Bck); err != nil {
+		return
+	}
+	lom.Lock(false)
+	if err = lom.Load(false); err == nil && lom.IsDirty() {
+		gen, size = lom.DirtyGen(), lom.Size()
+		version, err = putCloud(t, lom)
+	} else if cmn.IsObjNotExist(err) {
+		err = nil
+	}
+	lom.Unlock(false)
+	if err != nil {
+		return
+	}
+
+	lom.Lock(true)
+	defer lom.Unlock(true)
+	if err = lom.Load(false); err != nil {
+		if cmn.IsObjNotExist(err) {
+			err = unmarkDirty(lom) // deleted
+		}
+		return
+	}
+	if !lom.IsDirty() {
+		err = unmarkDirty(lom)
+		return
+	}
+	if gen == "" || lom.DirtyGen() != gen {
+		return // overwritten and yet to be written
+	}
+	lom.SetFlushed(t.Cloud(lom.Bck()).Provider(), version)
+	if lom.VerConf().Enabled {
+		lom.SetVersion(version)
+	}
+	if err = lom.Persist(); err != nil {
+		return
+	}
+	lom.ReCache()
+	return size, true, unmarkDirty(lom)
+}
+
+// Flush writes a given dirty object to the cloud
+func Flush(t cluster.Target, lom *cluster.LOM) error {
+	_, _, err := flush(t, lom.Bck(), lom.ObjName)
+	return err
+}
+
+func putCloud(t cluster.Target, lom *cluster.LOM) (version string, err error) {
+	file, err := os.Open(lom.FQN)
+	if err != nil {
+		return "", err
+	}
+	version, err, _ = t.Cloud(lom.Bck()).PutObj(context.Background(), file, lom)
+	debug.AssertNoErr(file.Close())
+	return
+}
+
+// flushAll writes the named objects using wbWorkers and returns the failures
+func flushAll(t cluster.Target, xact *cmn.XactBase, bck *cluster.Bck, objNames []string) map[string]error {
+	var (
+		wg     = &sync.WaitGroup{}
+		mu     sync.Mutex
+		failed = make(map[string]error)
+		workCh = make(chan string, wbWorkers)
+	)
+	for i := 0; i < wbWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for objName := range workCh {
+				size, flushed, err := flush(t, bck, objName)
+				if err != nil {
+					mu.Lock()
+					failed[objName] = err
+					mu.Unlock()
+				} else if flushed {
+					xact.ObjectsInc()
+					xact.BytesAdd(size)
+				}
+			}
+		}()
+	}
+	for _, objName := range objNames {
+		if xact.Aborted() {
+			break
+		}
+		workCh <- objName
+	}
+	close(workCh)
+	wg.Wait()
+	return failed
+}
+
+func backoff(attempts int) time.Duration {
+	d := wbBackoffMin << uint(attempts-1)
+	if d > wbBackoffMax || d <= 0 {
+		d = wbBackoffMax
+	}
+	return d
+}
+
+////////////////////////////
+// WriteBack (on-demand) //
+////////////////////////////
+
+func (e *writeBackEntry) Start(bck cmn.Bck) error {
+	e.xact = &WriteBack{
+		XactDemandBase: *demand.NewXactDemandBaseBck(cmn.ActWriteBack, bck),
+		t:              e.t,
+		bck:            e.bck,
+		pokeCh:         make(chan struct{}, 1),
+		retries:        make(map[string]*wbRetry),
+	}
+	return nil
+}
+func (e *writeBackEntry) Kind() string  { return cmn.ActWriteBack }
+func (e *writeBackEntry) Get() cmn.Xact { return e.xact }
+
+func (r *registry) RenewWriteBack(t cluster.Target, bck *cluster.Bck) *WriteBack {
+	e := &writeBackEntry{t: t, bck: bck}
+	ee, err := r.renewBucketXaction(e, bck)
+	if err != nil {
+		return nil
+	}
+	x := ee.Get().(*WriteBack)
+	if ee == e {
+		go func() {
+			err := x.Run()
+			x.Finish(err)
+		}()
+	}
+	return x
+}
+
+func (r *WriteBack) IsMountpathXact() bool { return false }
+
+// Poke signals that there are new dirty objects
+func (r *WriteBack) Poke() {
+	r.IncPending()
+	select {
+	case r.pokeCh <- struct{}{}:
+	default:
+	}
+}
+
+func (r *WriteBack) Run() error {
+	glog.Infoln(r.String())
+	for {
+		wait := r.round()
+		var retryCh <-chan time.Time
+		if wait > 0 {
+			retryCh = time.After(wait)
+		}
+		select {
+		case <-r.pokeCh:
+		case <-retryCh:
+		case <-r.IdleTimer():
+			r.Stop()
+			return nil
+		case <-r.ChanAbort():
+			r.Stop()
+			return cmn.NewAbortedError(r.String())
+		}
+	}
+}
+
+// round writes the dirty objects that are not waiting to be retried and
+// returns the time until the earliest retry (zero if none)
+func (r *WriteBack) round() (wait time.Duration) {
+	objs, err := dirty(r.t, r.bck)
+	if err != nil {
+		glog.Errorf("%s: %v", r, err)
+		return wbBackoffMax
+	}
+	var (
+		now      = time.Now()
+		objNames = make([]string, 0, len(objs))
+	)
+	for objName := range r.retries {
+		if _, ok := objs[objName]; !ok {
+			delete(r.retries, objName)
+		}
+	}
+	for objName := range objs {
+		if rt, ok := r.retries[objName]; ok && (rt.attempts >= wbMaxAttempts || now.Before(rt.next)) {
+			continue
+		}
+		objNames = append(objNames, objName)
+	}
+	failed := flushAll(r.t, &r.XactBase, r.bck, objNames)
+	now = time.Now()
+	for _, objName := range objNames {
+		err, ok := failed[objName]
+		if !ok {
+			delete(r.retries, objName)
+			continue
+		}
+		rt, ok := r.retries[objName]
+		if !ok {
+			rt = &wbRetry{}
+			r.retries[objName] = rt
+		}
+		rt.attempts++
+		rt.next = now.Add(backoff(rt.attempts))
+		if rt.attempts >= wbMaxAttempts {
+			glog.Errorf("%s: failed to write %s/%s (%d attempts): %v", r, r.bck, objName, rt.attempts, err)
+		}
+	}
+	for _, rt := range r.retries {
+		if rt.attempts < wbMaxAttempts && (wait == 0 || rt.next.Sub(now) < wait) {
+			if wait = rt.next.Sub(now); wait <= 0 {
+				wait = time.Millisecond
+			}
+		}
+	}
+	// stay around while there's something to retry
+	if wait > 0 {
+		r.SubPending(int(r.Pending()) - 1)
+	} else {
+		r.SubPending(int(r.Pending()))
+	}
+	return
+}
+
+//////////////
+// FlushBck //
+//////////////
+
+func (e *flushBckEntry) Start(bck cmn.Bck) error {
+	e.xact = &FlushBck{
+		XactBase: *cmn.NewXactBaseBck(e.uuid, cmn.ActFlushBck, bck),
+		t:        e.t,
+		bck:      e.bck,
+		barrier:  time.Now().UnixNano(),
+	}
+	return nil
+}
+func (e *flushBckEntry) Kind() string  { return cmn.ActFlushBck }
+func (e *flushBckEntry) Get() cmn.Xact { return e.xact }
+
+func (e *flushBckEntry) preRenewHook(previousEntry bucketEntry) (keep bool, err error) {
+	err = fmt.Errorf("%s is already running", previousEntry.Get())
+	return
+}
+
+func (r *registry) RenewFlushBck(t cluster.Target, bck *cluster.Bck, uuid string) (*FlushBck, error) {
+	if !bck.IsCloud() {
+		return nil, fmt.Errorf("%s: not a cloud bucket", bck)
+	}
+	e := &flushBckEntry{baseBckEntry: baseBckEntry{uuid}, t: t, bck: bck}
+	ee, err := r.renewBucketXaction(e, bck)
+	if err != nil {
+		return nil, err
+	}
+	return ee.Get().(*FlushBck), nil
+}
+
+func (r *FlushBck) IsMountpathXact() bool { return false }
+
+// Run is blocking
+func (r *FlushBck) Run() (err error) {
+	glog.Infoln(r.String())
+	defer func() {
+		if err != nil && !r.Aborted() {
+			glog.Errorf("%s: %v", r, err)
+			r.Abort()
+		}
+		r.Finish()
+	}()
+	for attempt := 1; ; attempt++ {
+		objs, err := dirty(r.t, r.bck)
+		if err != nil {
+			return err
+		}
+		objNames := make([]string, 0, len(objs))
+		for objName, gen := range objs {
+			if gen <= r.barrier {
+				objNames = append(objNames, objName)
+			}
+		}
+		if len(objNames) == 0 {
+			return nil
+		}
+		if attempt > wbMaxAttempts {
+			return fmt.Errorf("failed to write %d object(s)", len(objNames))
+		}
+		if failed := flushAll(r.t, &r.XactBase, r.bck, objNames); len(failed) == 0 {
+			continue
+		}
+		select {
+		case <-time.After(backoff(attempt)):
+		case <-r.ChanAbort():
+			return cmn.NewAbortedError(r.String())
+		}
+	}
+}
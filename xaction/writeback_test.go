@@ -0,0 +1,305 @@
+// Package xaction provides core functionality for the AIStore extended actions.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package xaction
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/dbdriver"
+	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/tutils/tassert"
+)
+
+type (
+	// cloudTargetMock is a target with a database and a (mocked) cloud
+	cloudTargetMock struct {
+		*cluster.TargetMock
+		db    dbdriver.Driver
+		cloud *cloudMock
+	}
+	cloudMock struct {
+		mtx    sync.Mutex
+		puts   map[string]int  // by object name
+		fail   map[string]bool // object names to fail PUT
+		hook   func(lom *cluster.LOM)
+		stored map[string][]byte
+		listed []*cmn.BucketEntry // sorted
+	}
+)
+
+var errCloudMock = errors.New("cloud is unavailable")
+
+func (t *cloudTargetMock) GetDB() dbdriver.Driver                     { return t.db }
+func (t *cloudTargetMock) Cloud(_ *cluster.Bck) cluster.CloudProvider { return t.cloud }
+
+func (c *cloudMock) Provider() string { return cmn.ProviderAmazon }
+func (c *cloudMock) PutObj(_ context.Context, r io.Reader, lom *cluster.LOM) (string, error, int) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return "", err, 0
+	}
+	if c.hook != nil {
+		c.hook(lom)
+	}
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.puts[lom.ObjName]++
+	if c.fail[lom.ObjName] {
+		return "", errCloudMock, 0
+	}
+	c.stored[lom.ObjName] = b
+	return "v1", nil, 0
+}
+func (c *cloudMock) GetObj(_ context.Context, _ string, _ *cluster.LOM) (error, int) { return nil, 0 }
+func (c *cloudMock) HeadObj(_ context.Context, _ *cluster.LOM) (cmn.SimpleKVs, error, int) {
+	return nil, nil, 0
+}
+func (c *cloudMock) DeleteObj(_ context.Context, _ *cluster.LOM) (error, int) { return nil, 0 }
+func (c *cloudMock) HeadBucket(_ context.Context, _ *cluster.Bck) (cmn.SimpleKVs, error, int) {
+	return nil, nil, 0
+}
+func (c *cloudMock) ListObjects(_ context.Context, _ *cluster.Bck, msg *cmn.SelectMsg) (*cmn.BucketList, error, int) {
+	list := &cmn.BucketList{}
+	for _, entry := range c.listed {
+		if strings.HasPrefix(entry.Name, msg.Prefix) {
+			list.Entries = append(list.Entries, entry)
+		}
+	}
+	return list, nil, 0
+}
+func (c *cloudMock) ListBuckets(_ context.Context, _ cmn.QueryBcks) (cmn.BucketNames, error, int) {
+	return nil, nil, 0
+}
+
+func (c *cloudMock) putCount(objName string) int {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.puts[objName]
+}
+
+// newCloudTarget creates a mountpath (to be removed by the caller) and a
+// write-back cloud bucket
+func newCloudTarget(t *testing.T) (tMock *cloudTargetMock, bck *cluster.Bck, mpath string) {
+	mpath, err := ioutil.TempDir("", "xaction-cloud-")
+	tassert.CheckFatal(t, err)
+	fs.Init()
+	fs.DisableFsIDCheck()
+	tassert.CheckFatal(t, fs.Add(mpath))
+	fs.CSM.RegisterContentType(fs.ObjectType, &fs.ObjectContentResolver{})
+	fs.CSM.RegisterContentType(fs.WorkfileType, &fs.WorkfileContentResolver{})
+
+	bck = cluster.NewBck("wb", cmn.ProviderAmazon, cmn.NsGlobal, &cmn.BucketProps{
+		Cksum:     cmn.CksumConf{Type: cmn.ChecksumNone},
+		WriteBack: cmn.BckWriteBackConf{Enabled: true},
+	})
+	tMock = &cloudTargetMock{
+		TargetMock: cluster.NewTargetMock(cluster.NewBaseBownerMock(bck)),
+		db:         dbdriver.NewDBMock(),
+		cloud: &cloudMock{
+			puts: make(map[string]int), fail: make(map[string]bool), stored: make(map[string][]byte),
+		},
+	}
+	return
+}
+
+// putObj stores the object locally (as PUT would)
+func putObj(t *testing.T, tMock *cloudTargetMock, bck *cluster.Bck, objName, content string, dirty bool) *cluster.LOM {
+	lom := &cluster.LOM{T: tMock, ObjName: objName}
+	tassert.CheckFatal(t, lom.Init(bck.Bck))
+	tassert.CheckFatal(t, os.MkdirAll(filepath.Dir(lom.FQN), 0755))
+	tassert.CheckFatal(t, ioutil.WriteFile(lom.FQN, []byte(content), 0644))
+	lom.Lock(true)
+	defer lom.Unlock(true)
+	lom.SetSize(int64(len(content)))
+	if dirty {
+		tassert.CheckFatal(t, MarkDirty(lom))
+	}
+	tassert.CheckFatal(t, lom.Persist())
+	lom.ReCache()
+	return lom
+}
+
+func loadObj(t *testing.T, tMock *cloudTargetMock, bck *cluster.Bck, objName string) *cluster.LOM {
+	lom := &cluster.LOM{T: tMock, ObjName: objName}
+	tassert.CheckFatal(t, lom.Init(bck.Bck))
+	tassert.CheckFatal(t, lom.Load(false))
+	return lom
+}
+
+func TestWriteBackFlush(t *testing.T) {
+	cluster.InitTarget()
+	tMock, bck, mpath := newCloudTarget(t)
+	defer os.RemoveAll(mpath)
+	putObj(t, tMock, bck, "a", "aaaa", true)
+	putObj(t, tMock, bck, "b", "bb", true)
+	putObj(t, tMock, bck, "clean", "c", false)
+	tassert.Fatalf(t, HasDirty(tMock, bck), "expected dirty objects")
+
+	size, flushed, err := flush(tMock, bck, "a")
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, flushed && size == 4, "expected a (4 bytes) to be flushed, got %t (%d)", flushed, size)
+	tassert.Errorf(t, string(tMock.cloud.stored["a"]) == "aaaa", "wrong content in the cloud: %q", tMock.cloud.stored["a"])
+	lom := loadObj(t, tMock, bck, "a")
+	tassert.Errorf(t, !lom.IsDirty(), "%s is still dirty", lom)
+	objs, err := dirty(tMock, bck)
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, len(objs) == 1, "expected 1 dirty object, got %v", objs)
+
+	// clean and deleted objects: nothing to write
+	_, flushed, err = flush(tMock, bck, "clean")
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, !flushed && tMock.cloud.putCount("clean") == 0, "clean object written to the cloud")
+	lom = loadObj(t, tMock, bck, "b")
+	tassert.CheckFatal(t, lom.Remove())
+	_, flushed, err = flush(tMock, bck, "b")
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, !flushed, "deleted object flushed")
+	tassert.Errorf(t, !HasDirty(tMock, bck), "deleted object remains dirty")
+}
+
+func TestWriteBackFlushOverwritten(t *testing.T) {
+	cluster.InitTarget()
+	tMock, bck, mpath := newCloudTarget(t)
+	defer os.RemoveAll(mpath)
+	putObj(t, tMock, bck, "obj", "old", true)
+
+	// the object gets overwritten while being written to the cloud
+	tMock.cloud.hook = func(lom *cluster.LOM) {
+		lom.SetDirty("overwritten")
+		tassert.CheckFatal(t, lom.Persist())
+		lom.ReCache()
+	}
+	_, flushed, err := flush(tMock, bck, "obj")
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, !flushed, "overwritten object must not be marked flushed")
+	lom := loadObj(t, tMock, bck, "obj")
+	tassert.Errorf(t, lom.IsDirty(), "overwritten object must remain dirty")
+	tassert.Errorf(t, HasDirty(tMock, bck), "overwritten object must remain in the database")
+}
+
+func TestWriteBackRetry(t *testing.T) {
+	cluster.InitTarget()
+	tMock, bck, mpath := newCloudTarget(t)
+	defer os.RemoveAll(mpath)
+	putObj(t, tMock, bck, "good", "g", true)
+	putObj(t, tMock, bck, "bad", "b", true)
+	tMock.cloud.fail["bad"] = true
+
+	cmn.InitShortID(0)
+	e := &writeBackEntry{t: tMock, bck: bck}
+	tassert.CheckFatal(t, e.Start(bck.Bck))
+	x := e.xact
+	defer x.Stop()
+
+	x.Poke()
+	wait := x.round()
+	tassert.Errorf(t, tMock.cloud.putCount("good") == 1 && tMock.cloud.putCount("bad") == 1,
+		"expected both objects to be written once, got %v", tMock.cloud.puts)
+	tassert.Errorf(t, wait > 0 && wait <= backoff(1), "expected to retry in %v, got %v", backoff(1), wait)
+	tassert.Fatalf(t, x.retries["bad"] != nil && x.retries["bad"].attempts == 1, "expected 1 failed attempt")
+	tassert.Errorf(t, x.Pending() == 1, "expected to stay around while retrying, pending %d", x.Pending())
+
+	// backing off
+	x.round()
+	tassert.Errorf(t, tMock.cloud.putCount("bad") == 1, "retried before the backoff")
+
+	// due
+	x.retries["bad"].next = time.Now()
+	x.round()
+	tassert.Errorf(t, tMock.cloud.putCount("bad") == 2, "expected to retry")
+	tassert.Errorf(t, x.retries["bad"].attempts == 2, "expected 2 failed attempts")
+
+	// given up: left for the next WriteBack
+	x.retries["bad"].attempts = wbMaxAttempts
+	x.retries["bad"].next = time.Now()
+	wait = x.round()
+	tassert.Errorf(t, tMock.cloud.putCount("bad") == 2, "retried after %d attempts", wbMaxAttempts)
+	tassert.Errorf(t, wait == 0 && x.Pending() == 0, "expected to be done, wait %v, pending %d", wait, x.Pending())
+	tassert.Errorf(t, HasDirty(tMock, bck), "failed object must remain dirty")
+
+	// succeeds eventually
+	tMock.cloud.mtx.Lock()
+	tMock.cloud.fail["bad"] = false
+	tMock.cloud.mtx.Unlock()
+	delete(x.retries, "bad")
+	x.round()
+	tassert.Errorf(t, !HasDirty(tMock, bck), "expected all objects to be written")
+	tassert.Errorf(t, tMock.cloud.putCount("good") == 1, "clean object written again")
+}
+
+func TestWriteBackBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		expected time.Duration
+	}{
+		{1, wbBackoffMin},
+		{2, 2 * wbBackoffMin},
+		{4, 8 * wbBackoffMin},
+		{10, wbBackoffMax},
+		{100, wbBackoffMax},
+	}
+	for _, test := range tests {
+		if d := backoff(test.attempts); d != test.expected {
+			t.Errorf("backoff(%d): expected %v, got %v", test.attempts, test.expected, d)
+		}
+	}
+}
+
+func TestFlushBckBarrier(t *testing.T) {
+	cluster.InitTarget()
+	tMock, bck, mpath := newCloudTarget(t)
+	defer os.RemoveAll(mpath)
+	putObj(t, tMock, bck, "before", "b", true)
+	e := &flushBckEntry{t: tMock, bck: bck}
+	tassert.CheckFatal(t, e.Start(bck.Bck))
+	time.Sleep(time.Millisecond)
+	putObj(t, tMock, bck, "after", "a", true)
+
+	tassert.CheckFatal(t, e.xact.Run())
+	tassert.Errorf(t, tMock.cloud.putCount("before") == 1, "object dirty before the barrier must be written")
+	tassert.Errorf(t, tMock.cloud.putCount("after") == 0, "object dirty after the barrier must be left to WriteBack")
+	objs, err := dirty(tMock, bck)
+	tassert.CheckFatal(t, err)
+	_, ok := objs["after"]
+	tassert.Errorf(t, len(objs) == 1 && ok, "expected only the object dirty after the barrier, got %v", objs)
+}
+
+func TestFlushBckAbort(t *testing.T) {
+	cluster.InitTarget()
+	tMock, bck, mpath := newCloudTarget(t)
+	defer os.RemoveAll(mpath)
+	putObj(t, tMock, bck, "obj", "o", true)
+	tMock.cloud.fail["obj"] = true
+	e := &flushBckEntry{t: tMock, bck: bck}
+	tassert.CheckFatal(t, e.Start(bck.Bck))
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- e.xact.Run() }()
+	time.Sleep(100 * time.Millisecond)
+	e.xact.Abort()
+	select {
+	case err := <-errCh:
+		tassert.Errorf(t, isAborted(err), "expected aborted, got %v", err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("barrier not aborted")
+	}
+	tassert.Errorf(t, HasDirty(tMock, bck), "failed object must remain dirty")
+}
+
+func isAborted(err error) bool {
+	_, ok := err.(cmn.AbortedError)
+	return ok
+}